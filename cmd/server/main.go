@@ -4,28 +4,32 @@ package main
 import (
 	"context"
 	"crypto/rand"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"flag"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/thenexusengine/tne_springwire/internal/adapters"
-	_ "github.com/thenexusengine/tne_springwire/internal/adapters/appnexus"
-	_ "github.com/thenexusengine/tne_springwire/internal/adapters/demo"
-	_ "github.com/thenexusengine/tne_springwire/internal/adapters/pubmatic"
-	_ "github.com/thenexusengine/tne_springwire/internal/adapters/rubicon"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/manifest"
 	pbsconfig "github.com/thenexusengine/tne_springwire/internal/config"
 	"github.com/thenexusengine/tne_springwire/internal/endpoints"
 	"github.com/thenexusengine/tne_springwire/internal/exchange"
+	"github.com/thenexusengine/tne_springwire/internal/floors"
 	"github.com/thenexusengine/tne_springwire/internal/metrics"
 	"github.com/thenexusengine/tne_springwire/internal/middleware"
 	"github.com/thenexusengine/tne_springwire/internal/storage"
+	"github.com/thenexusengine/tne_springwire/internal/usersync"
 	"github.com/thenexusengine/tne_springwire/pkg/logger"
+	"github.com/thenexusengine/tne_springwire/pkg/native"
 	"github.com/thenexusengine/tne_springwire/pkg/redis"
+	"github.com/thenexusengine/tne_springwire/pkg/secrets"
 )
 
 func main() {
@@ -54,6 +58,9 @@ func main() {
 	// Initialize PostgreSQL database connection
 	var db *storage.BidderStore
 	var publisherStore *storage.PublisherStore
+	var secretStore *storage.SecretStore
+	var dbBidders []*storage.Bidder
+	bidderTimeouts := make(map[string]time.Duration) // bidders.timeout_ms
 	dbHost := os.Getenv("DB_HOST")
 	if dbHost != "" {
 		dbPort := getEnvOrDefault("DB_PORT", "5432")
@@ -68,6 +75,7 @@ func main() {
 		} else {
 			db = storage.NewBidderStore(dbConn)
 			publisherStore = storage.NewPublisherStore(dbConn)
+			secretStore = storage.NewSecretStore(dbConn)
 
 			// Test connection
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -78,6 +86,12 @@ func main() {
 			if err != nil {
 				log.Warn().Err(err).Msg("Failed to load bidders from database")
 			} else {
+				dbBidders = bidders
+				for _, b := range bidders {
+					if b.TimeoutMs > 0 {
+						bidderTimeouts[b.BidderCode] = time.Duration(b.TimeoutMs) * time.Millisecond
+					}
+				}
 				log.Info().
 					Int("count", len(bidders)).
 					Msg("Bidders loaded from PostgreSQL")
@@ -97,6 +111,12 @@ func main() {
 		log.Info().Msg("DB_HOST not set, database-backed features disabled")
 	}
 
+	// Resolve secret:// credentials (bidder auth, http_headers) from env, files or the database
+	secretResolver, err := newSecretResolver(secretStore)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to configure secrets provider")
+	}
+
 	// Initialize middleware
 	cors := middleware.NewCORS(middleware.DefaultCORSConfig())
 	security := middleware.NewSecurity(nil) // Uses DefaultSecurityConfig()
@@ -153,14 +173,119 @@ func main() {
 		DefaultCurrency:       "USD",
 	}
 
+	// Dynamic price floors (Prebid floors module compatible)
+	floorsConfig := floors.DefaultConfig()
+	floorsConfig.Enabled = getEnvBoolOrDefault("FLOORS_ENABLED", true)
+	floorsConfig.FetchURL = os.Getenv("FLOORS_FETCH_URL")
+	floorsConfig.FetchInterval = time.Duration(getEnvIntOrDefault("FLOORS_FETCH_INTERVAL_SECONDS", 300)) * time.Second
+	floorsConfig.FetchMaxPublishers = getEnvIntOrDefault("FLOORS_FETCH_MAX_PUBLISHERS", 10000)
+	config.Floors = floorsConfig
+
+	// Server-fired loss notifications (lurl) for bids that lose the auction
+	lossNotifyConfig := exchange.DefaultLossNotifyConfig()
+	lossNotifyConfig.Enabled = getEnvBoolOrDefault("LOSS_NOTIFICATIONS_ENABLED", true)
+	config.LossNotify = lossNotifyConfig
+
+	// Per-bidder timeouts and the tmax sent to bidders
+	config.BidderTimeouts = bidderTimeouts
+	config.MaxConcurrentBidderRequests = getEnvIntOrDefault("MAX_CONCURRENT_BIDDER_REQUESTS", 4)
+	tmaxConfig := exchange.DefaultTMaxConfig()
+	tmaxConfig.UpstreamBuffer = time.Duration(getEnvIntOrDefault("TMAX_UPSTREAM_BUFFER_MS", 50)) * time.Millisecond
+	tmaxConfig.NetworkBuffer = time.Duration(getEnvIntOrDefault("TMAX_NETWORK_BUFFER_MS", 20)) * time.Millisecond
+	tmaxConfig.RTTPercentile = float64(getEnvIntOrDefault("TMAX_RTT_PERCENTILE", 90)) / 100
+	config.TMax = tmaxConfig
+	// Share of each bidder rate limit kept for deal and high-floor requests
+	rateLimitConfig := exchange.DefaultRateLimitConfig()
+	rateLimitConfig.ReserveFraction = float64(getEnvIntOrDefault("BIDDER_RATE_LIMIT_RESERVE_PERCENT", 20)) / 100
+	config.RateLimit = rateLimitConfig
+	// Per-bidder circuit breakers on error and timeout rates
+	healthConfig := exchange.DefaultBidderHealthConfig()
+	healthConfig.Enabled = getEnvBoolOrDefault("BIDDER_HEALTH_ENABLED", true)
+	healthConfig.ErrorRateThreshold = float64(getEnvIntOrDefault("BIDDER_HEALTH_ERROR_RATE_PERCENT", 50)) / 100
+	healthConfig.OpenTimeout = time.Duration(getEnvIntOrDefault("BIDDER_HEALTH_OPEN_SECONDS", 30)) * time.Second
+	healthConfig.ProbeRate = float64(getEnvIntOrDefault("BIDDER_HEALTH_PROBE_PERCENT", 10)) / 100
+	config.BidderHealth = healthConfig
+	// VAST validation and tracker injection for video bids
+	vastConfig := exchange.DefaultVASTConfig()
+	vastConfig.Validate = getEnvBoolOrDefault("VAST_VALIDATION_ENABLED", true)
+	vastConfig.ImpressionURL = os.Getenv("VAST_IMPRESSION_URL")
+	vastConfig.TrackingURL = os.Getenv("VAST_TRACKING_URL")
+	config.VAST = vastConfig
+	// Native request upgrades, response validation and server-side rendering
+	nativeConfig := exchange.DefaultNativeConfig()
+	nativeConfig.Validate = getEnvBoolOrDefault("NATIVE_VALIDATION_ENABLED", true)
+	nativeConfig.UpgradeRequests = getEnvBoolOrDefault("NATIVE_UPGRADE_REQUESTS", true)
+	if path := os.Getenv("NATIVE_RENDER_TEMPLATE_FILE"); path != "" {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatal().Err(err).Str("path", path).Msg("Failed to read native render template")
+		}
+		if _, err := native.ParseTemplate(string(src)); err != nil {
+			log.Fatal().Err(err).Str("path", path).Msg("Invalid native render template")
+		}
+		nativeConfig.RenderTemplate = string(src)
+	}
+	config.Native = nativeConfig
+	log.Info().
+		Bool("enabled", floorsConfig.Enabled).
+		Bool("fetch_enabled", floorsConfig.FetchURL != "").
+		Dur("fetch_interval", floorsConfig.FetchInterval).
+		Msg("Price floors configured")
+
+	// Enable, disable and reconfigure the compiled-in adapters (file + env)
+	adapterConfig, err := adapters.LoadStartupConfig(os.Getenv("ADAPTERS_CONFIG_FILE"), os.Environ())
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid adapter config")
+	}
+	adapterConfig.EnableByDefault(manifest.DefaultEnabled)
+	if err := adapters.DefaultRegistry.ApplyStartupConfig(adapterConfig, manifest.Builders); err != nil {
+		log.Fatal().Err(err).Msg("Failed to apply adapter config")
+	}
+	log.Info().
+		Int("compiled", len(manifest.Builders)).
+		Int("enabled", len(adapters.DefaultRegistry.ListEnabledBidders())).
+		Msg("Adapters configured")
+
+	// Register server-side bidder aliases (JSON array of alias configs)
+	if aliasJSON := os.Getenv("BIDDER_ALIASES"); aliasJSON != "" {
+		aliasConfigs, err := adapters.ParseAliasConfigs([]byte(aliasJSON))
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid BIDDER_ALIASES")
+		}
+		for _, aliasConfig := range aliasConfigs {
+			if err := adapters.DefaultRegistry.RegisterAlias(aliasConfig); err != nil {
+				log.Fatal().Err(err).Msg("Failed to register bidder alias")
+			}
+		}
+		log.Info().Int("count", len(aliasConfigs)).Msg("Bidder aliases registered")
+	}
+
 	// Create exchange with default registry
 	ex := exchange.New(adapters.DefaultRegistry, config)
+	if err := ex.ValidateBidderOutbound(); err != nil {
+		log.Fatal().Err(err).Msg("Invalid bidder outbound options")
+	}
+
+	// Encrypt ${AUCTION_PRICE} macros when keys are configured (web-safe base64)
+	if encKey, intKey := os.Getenv("PRICE_ENCRYPTION_KEY"), os.Getenv("PRICE_INTEGRITY_KEY"); encKey != "" || intKey != "" {
+		priceEncrypter, err := newPriceEncrypter(encKey, intKey)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid price encryption keys")
+		}
+		ex.SetPriceEncrypter(priceEncrypter)
+		log.Info().Msg("Auction price macro encryption enabled")
+	}
+
+	// Stored bidders' http_headers are sent with their requests, secret:// values resolved per request
+	if len(dbBidders) > 0 {
+		ex.SetBidderHeaders(storage.NewBidderHeaders(dbBidders, secretResolver))
+	}
 
 	// Wire up metrics for margin tracking
 	ex.SetMetrics(m)
 	log.Info().Msg("Metrics connected to exchange for margin tracking")
 
-	// Redis is used for API key auth and publisher admin (not dynamic bidders).
+	// Redis is used for API key auth, publisher admin and bidder daily limits (not dynamic bidders).
 	var redisClient *redis.Client
 	redisURL := os.Getenv("REDIS_URL")
 	if redisURL != "" {
@@ -173,6 +298,9 @@ func main() {
 			auth.SetRedisClient(redisClient)
 			publisherAuth.SetRedisClient(redisClient)
 			log.Info().Msg("Redis client set for auth middlewares")
+
+			// Bidder daily rate limits are counted across all instances
+			ex.SetDailyCounter(redisClient)
 		}
 	} else {
 		log.Info().Msg("REDIS_URL not set, Redis-backed features disabled")
@@ -187,9 +315,22 @@ func main() {
 
 	// Create handlers
 	auctionHandler := endpoints.NewAuctionHandler(ex)
+
+	// First-party SharedID minting (consent-gated, published as a pubcid.org EID)
+	sharedIDConfig := usersync.DefaultSharedIDConfig()
+	sharedIDConfig.Enabled = getEnvBoolOrDefault("SHAREDID_ENABLED", true)
+	sharedIDConfig.TTL = time.Duration(getEnvIntOrDefault("SHAREDID_TTL_DAYS", 365)) * 24 * time.Hour
+	sharedIDConfig.RotateAfter = time.Duration(getEnvIntOrDefault("SHAREDID_ROTATE_DAYS", 0)) * 24 * time.Hour
+	auctionHandler.SetSharedIDManager(usersync.NewSharedIDManager(sharedIDConfig))
+	log.Info().
+		Bool("enabled", sharedIDConfig.Enabled).
+		Dur("ttl", sharedIDConfig.TTL).
+		Dur("rotate_after", sharedIDConfig.RotateAfter).
+		Msg("SharedID initialized")
 	statusHandler := endpoints.NewStatusHandler()
 	// Static bidders only (no dynamic registry).
 	biddersHandler := endpoints.NewDynamicInfoBiddersHandler(adapters.DefaultRegistry)
+	bidderParamsHandler := endpoints.NewBidderParamsHandler(adapters.DefaultRegistry)
 
 	// Cookie sync handlers
 	hostURL := os.Getenv("PBS_HOST_URL")
@@ -197,6 +338,7 @@ func main() {
 		hostURL = "https://catalyst.springwire.ai"
 	}
 	cookieSyncConfig := endpoints.DefaultCookieSyncConfig(hostURL)
+	cookieSyncConfig.SyncerKeys = adapters.DefaultRegistry.SyncerKeys()
 	cookieSyncHandler := endpoints.NewCookieSyncHandler(cookieSyncConfig)
 	setuidHandler := endpoints.NewSetUIDHandler(cookieSyncHandler.ListBidders())
 	optoutHandler := endpoints.NewOptOutHandler()
@@ -231,6 +373,7 @@ func main() {
 	mux.Handle("/health", healthHandler())
 	mux.Handle("/health/ready", readyHandler(redisClient, ex))
 	mux.Handle("/info/bidders", biddersHandler)
+	mux.Handle("/bidders/params", bidderParamsHandler)
 
 	// Cookie sync endpoints
 	mux.Handle("/cookie_sync", cookieSyncHandler)
@@ -255,6 +398,8 @@ func main() {
 		}
 	})
 
+	mux.Handle("/admin/bidders/health", endpoints.NewBidderHealthHandler(ex))
+
 	// Live dashboard for monitoring
 	dashboardHandler := endpoints.NewDashboardHandler()
 	metricsAPIHandler := endpoints.NewMetricsAPIHandler()
@@ -489,3 +634,57 @@ func getEnvBoolOrDefault(key string, defaultValue bool) bool {
 	}
 	return value == "true" || value == "1" || value == "yes"
 }
+
+// newPriceEncrypter decodes web-safe base64 price encryption keys
+func newPriceEncrypter(encryptionKey, integrityKey string) (*exchange.HMACPriceEncrypter, error) {
+	encKey, err := base64.URLEncoding.DecodeString(encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("PRICE_ENCRYPTION_KEY: %w", err)
+	}
+	intKey, err := base64.URLEncoding.DecodeString(integrityKey)
+	if err != nil {
+		return nil, fmt.Errorf("PRICE_INTEGRITY_KEY: %w", err)
+	}
+	return exchange.NewHMACPriceEncrypter(encKey, intKey)
+}
+
+// newSecretResolver creates the secret:// resolver selected by SECRETS_PROVIDER
+func newSecretResolver(store *storage.SecretStore) (*secrets.Resolver, error) {
+	var provider secrets.Provider
+	switch name := getEnvOrDefault("SECRETS_PROVIDER", "env"); name {
+	case "env":
+		provider = &secrets.EnvProvider{Prefix: getEnvOrDefault("SECRETS_ENV_PREFIX", "SECRET_")}
+	case "file":
+		provider = &secrets.FileProvider{Dir: getEnvOrDefault("SECRETS_DIR", "/run/secrets")}
+	case "database":
+		if store == nil {
+			return nil, fmt.Errorf("SECRETS_PROVIDER=database requires a database connection")
+		}
+		key, err := secrets.ParseKey(os.Getenv("SECRETS_MASTER_KEY"))
+		if err != nil {
+			return nil, fmt.Errorf("SECRETS_MASTER_KEY: %w", err)
+		}
+		cipher, err := secrets.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("SECRETS_MASTER_KEY: %w", err)
+		}
+		provider = &secrets.EncryptedProvider{Store: store, Cipher: cipher}
+	default:
+		return nil, fmt.Errorf("unknown SECRETS_PROVIDER %q", name)
+	}
+	ttl := time.Duration(getEnvIntOrDefault("SECRETS_CACHE_TTL_SECONDS", int(secrets.DefaultTTL/time.Second))) * time.Second
+	return secrets.NewResolver(provider, ttl), nil
+}
+
+// getEnvIntOrDefault returns the environment variable as int or a default
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return defaultValue
+	}
+	return parsed
+}
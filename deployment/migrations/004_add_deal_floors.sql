@@ -0,0 +1,19 @@
+-- =====================================================
+-- Add Deal Floors to Publishers
+-- =====================================================
+-- This migration adds a deal_floors column so publishers
+-- can set a minimum CPM per private marketplace deal.
+-- The exchange enforces the higher of this value and the
+-- bidfloor sent on imp.pmp.deals[].
+--
+-- Example: {"deal-abc": 5.00, "deal-xyz": 12.50}
+-- =====================================================
+
+ALTER TABLE publishers
+ADD COLUMN deal_floors JSONB DEFAULT '{}'::jsonb;
+
+UPDATE publishers
+SET deal_floors = '{}'::jsonb
+WHERE deal_floors IS NULL;
+
+COMMENT ON COLUMN publishers.deal_floors IS 'Per-deal minimum CPM keyed by deal ID. Enforced alongside imp.pmp.deals[].bidfloor';
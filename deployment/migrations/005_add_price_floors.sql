@@ -0,0 +1,26 @@
+-- =====================================================
+-- Add Price Floor Rules to Publishers
+-- =====================================================
+-- This migration adds a price_floors column holding a
+-- Prebid floors-module rule set for the publisher. It is
+-- used when neither a fetched rule file nor the request
+-- (ext.prebid.floors) provides floor data.
+--
+-- Example:
+-- {
+--   "floorMin": 0.10,
+--   "data": {
+--     "currency": "USD",
+--     "modelGroups": [{
+--       "modelVersion": "v1",
+--       "schema": {"fields": ["mediaType", "size"]},
+--       "values": {"banner|300x250": 1.20, "*|*": 0.50}
+--     }]
+--   }
+-- }
+-- =====================================================
+
+ALTER TABLE publishers
+ADD COLUMN price_floors JSONB;
+
+COMMENT ON COLUMN publishers.price_floors IS 'Prebid floors-module rule set (ext.prebid.floors schema). NULL = no stored floors';
@@ -0,0 +1,31 @@
+-- =====================================================
+-- Add Bid Adjustment Rules to Publishers
+-- =====================================================
+-- This migration adds a bid_adjustments column holding
+-- bid adjustment rules in the ext.prebid.bidadjustments
+-- format, keyed by media type, bidder and deal ID ("*"
+-- matches any). Rules sent on the request replace the
+-- stored rule for the same key.
+--
+-- adjtype values:
+--   multiplier - price * value
+--   cpm        - price - value (fixed fee)
+--   static     - price = value
+--
+-- Example:
+-- {
+--   "mediatype": {
+--     "banner": {
+--       "rubicon": {"*": [{"adjtype": "multiplier", "value": 0.95}]}
+--     },
+--     "*": {
+--       "*": {"deal-123": [{"adjtype": "cpm", "value": 0.10, "currency": "USD"}]}
+--     }
+--   }
+-- }
+-- =====================================================
+
+ALTER TABLE publishers
+ADD COLUMN bid_adjustments JSONB;
+
+COMMENT ON COLUMN publishers.bid_adjustments IS 'Bid adjustment rules (ext.prebid.bidadjustments schema). NULL = no stored adjustments';
@@ -0,0 +1,14 @@
+-- =====================================================
+-- Add Auction Type to Publishers
+-- =====================================================
+-- This migration adds an auction_type column holding the
+-- OpenRTB "at" value used for the publisher's auctions
+-- when the bid request does not set "at".
+--   1 = first price, 2 = second price
+--   NULL = exchange default
+-- =====================================================
+
+ALTER TABLE publishers
+ADD COLUMN auction_type SMALLINT CHECK (auction_type IN (1, 2));
+
+COMMENT ON COLUMN publishers.auction_type IS 'OpenRTB auction type (1=first price, 2=second price). Request "at" overrides; NULL = exchange default';
@@ -0,0 +1,28 @@
+-- =====================================================
+-- Add Response Blocklists to Publishers
+-- =====================================================
+-- This migration adds a blocklists column holding the
+-- publisher's standing blocklists. They are merged with
+-- the bid request's bcat/badv/battr/bapp and enforced on
+-- every bid returned by a bidder.
+--
+-- Fields:
+--   bcat   - blocked content categories
+--   cattax - category taxonomy of bcat (default 1 = IAB 1.0)
+--   badv   - blocked advertiser domains (subdomains match)
+--   battr  - blocked creative attributes
+--   bapp   - blocked app bundles
+--
+-- Example:
+-- {
+--   "bcat": ["IAB25", "IAB26"],
+--   "badv": ["competitor.com"],
+--   "battr": [1, 3],
+--   "bapp": ["com.competitor.app"]
+-- }
+-- =====================================================
+
+ALTER TABLE publishers
+ADD COLUMN blocklists JSONB;
+
+COMMENT ON COLUMN publishers.blocklists IS 'Response blocklists (bcat, cattax, badv, battr, bapp) merged with the request. NULL = request blocklists only';
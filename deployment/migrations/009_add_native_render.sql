@@ -0,0 +1,15 @@
+-- =====================================================
+-- Add Native Rendering to Publishers
+-- =====================================================
+-- This migration adds a native_render flag for publishers
+-- without a native renderer on the page. Their native bids
+-- are validated as usual and returned with the Native
+-- response in adm replaced by rendered HTML.
+--   TRUE  = render native bids server-side
+--   FALSE = return native JSON (default)
+-- =====================================================
+
+ALTER TABLE publishers
+ADD COLUMN native_render BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN publishers.native_render IS 'Render native bid adm to HTML with the exchange native template (for pages without a native renderer)';
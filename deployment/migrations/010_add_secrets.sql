@@ -0,0 +1,23 @@
+-- =====================================================
+-- Add Encrypted Secrets
+-- =====================================================
+-- This migration adds a secrets table for bidder
+-- credentials. Configuration refers to a secret as
+-- secret://<name> instead of holding the plaintext value
+-- (ortb endpoint auth, bidders.http_headers).
+--
+-- Values are encrypted with AES-256-GCM under the local
+-- master key (SECRETS_MASTER_KEY) before they are stored;
+-- the key itself is never stored in the database.
+-- Updating a row rotates the credential without a restart.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS secrets (
+    name VARCHAR(255) PRIMARY KEY,
+    ciphertext BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE secrets IS 'Encrypted credentials referenced as secret://<name>';
+COMMENT ON COLUMN secrets.ciphertext IS 'AES-256-GCM nonce || ciphertext, sealed with the master key and the secret name';
@@ -0,0 +1,17 @@
+-- =====================================================
+-- Add Request Bid Adjustment Opt-In to Publishers
+-- =====================================================
+-- This migration adds an allow_request_bid_adjustments flag.
+-- Requests can change bid prices with
+-- ext.prebid.bidadjustments and bidadjustmentfactors
+-- (multipliers up to 100, static prices), so they are only
+-- applied for publishers that opt in. Stored bid_adjustments
+-- always apply.
+--   TRUE  = apply adjustments sent on the request
+--   FALSE = ignore adjustments sent on the request (default)
+-- =====================================================
+
+ALTER TABLE publishers
+ADD COLUMN allow_request_bid_adjustments BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN publishers.allow_request_bid_adjustments IS 'Apply ext.prebid.bidadjustments and bidadjustmentfactors sent on requests (stored bid_adjustments always apply)';
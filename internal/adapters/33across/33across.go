@@ -3,6 +3,7 @@
 package thirtythreeacross
 
 import (
+	_ "embed"
 	"fmt"
 
 	"github.com/thenexusengine/tne_springwire/internal/adapters"
@@ -30,6 +31,11 @@ func New(endpoint string) *Adapter {
 	}
 }
 
+// paramsSchema is the JSON Schema for imp.ext.33across params
+//
+//go:embed params.json
+var paramsSchema []byte
+
 // Info returns 33Across bidder information
 func Info() adapters.BidderInfo {
 	return adapters.BidderInfo{
@@ -40,6 +46,7 @@ func Info() adapters.BidderInfo {
 		Capabilities: &adapters.CapabilitiesInfo{
 			Site: &adapters.PlatformInfo{MediaTypes: []adapters.BidType{adapters.BidTypeBanner, adapters.BidTypeVideo}},
 		},
+		ParamsSchema: paramsSchema,
 	}
 }
 
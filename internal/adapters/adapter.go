@@ -3,15 +3,18 @@ package adapters
 
 import (
 	"context"
-	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/jsonschema"
 	"github.com/thenexusengine/tne_springwire/pkg/logger"
 )
 
@@ -32,6 +35,8 @@ type ExtraRequestInfo struct {
 	PbsEntryPoint  string
 	GlobalPrivacy  GlobalPrivacy
 	BidderCoreName string
+	// PublisherBidderParams are the publisher's stored params for the bidder (nil = none)
+	PublisherBidderParams map[string]interface{}
 }
 
 // GlobalPrivacy contains privacy settings
@@ -56,6 +61,10 @@ type ResponseData struct {
 	StatusCode int
 	Body       []byte
 	Headers    http.Header
+
+	// Bytes on the wire, after request compression and before response decompression
+	BytesSent     int
+	BytesReceived int
 }
 
 // BidderResponse contains parsed bids from a bidder
@@ -115,6 +124,34 @@ type BidderInfo struct {
 	Endpoint                string
 	ExtraInfo               string
 	DemandType              DemandType // platform (obfuscated) or publisher (transparent)
+
+	// Alias fields, set when the bidder is an alias of a core adapter
+	AliasOf      string        // core bidder code whose adapter the alias reuses
+	SyncerKey    string        // user sync key; empty uses the bidder code
+	Timeout      time.Duration // caps the auction timeout for this bidder; 0 means no cap
+	MetricsLabel string        // bidder label used in metrics; empty uses the bidder code
+
+	// ParamsSchema is the JSON Schema for the bidder's imp.ext.<bidder> params
+	ParamsSchema json.RawMessage
+
+	// RateLimits caps the traffic the exchange sends to the bidder; zero values are unlimited
+	RateLimits RateLimits
+
+	// Outbound sets compression, HTTP/2, connection pooling, mTLS and signing for requests to the bidder
+	Outbound OutboundOptions
+}
+
+// RateLimits are a bidder's contracted traffic caps
+// One auction call to the bidder counts as one request.
+type RateLimits struct {
+	QPS        int `json:"qps,omitempty"`        // Requests per second
+	Daily      int `json:"daily,omitempty"`      // Requests per UTC day
+	Concurrent int `json:"concurrent,omitempty"` // Requests in flight at once
+}
+
+// IsZero reports whether no limit is set
+func (l RateLimits) IsZero() bool {
+	return l.QPS <= 0 && l.Daily <= 0 && l.Concurrent <= 0
 }
 
 // MaintainerInfo contains maintainer info
@@ -126,6 +163,9 @@ type MaintainerInfo struct {
 type CapabilitiesInfo struct {
 	App  *PlatformInfo
 	Site *PlatformInfo
+	// VideoMimes and VideoProtocols restrict the video imps sent to the bidder (empty = any)
+	VideoMimes     []string
+	VideoProtocols []int
 }
 
 // PlatformInfo contains platform capabilities
@@ -145,15 +185,33 @@ type SyncerInfo struct {
 
 // AdapterConfig holds runtime adapter configuration
 type AdapterConfig struct {
-	Endpoint  string
-	Disabled  bool
-	ExtraInfo string
+	Endpoint   string           `json:"endpoint,omitempty"`
+	Disabled   bool             `json:"disabled,omitempty"`
+	ExtraInfo  string           `json:"extraInfo,omitempty"`
+	DemandType DemandType       `json:"demandType,omitempty"`
+	RateLimits *RateLimits      `json:"rateLimits,omitempty"`
+	Outbound   *OutboundOptions `json:"outbound,omitempty"`
+	ModifyVAST *bool            `json:"modifyVast,omitempty"` // Allow tracker injection into the bidder's VAST
 }
 
+// Builder creates an adapter for an endpoint; an empty endpoint uses the adapter's default
+type Builder func(endpoint string) Adapter
+
 // AdapterWithInfo wraps an adapter with its info
 type AdapterWithInfo struct {
 	Adapter Adapter
 	Info    BidderInfo
+
+	params *jsonschema.Schema // compiled Info.ParamsSchema
+}
+
+// ValidateParams checks imp.ext.<bidder> params against the bidder's params schema
+// Bidders without a schema accept any params.
+func (a AdapterWithInfo) ValidateParams(params json.RawMessage) error {
+	if a.params == nil {
+		return nil
+	}
+	return a.params.Validate(params)
 }
 
 // BidderResult contains bidding results
@@ -170,27 +228,70 @@ type HTTPClient interface {
 }
 
 // DefaultHTTPClient implements HTTPClient
+// Requests carrying OutboundOptions that change the transport (HTTP/2, pool sizes, mTLS) use
+// a dedicated client per bidder host; all others share the default pool.
 type DefaultHTTPClient struct {
-	client *http.Client
+	client  *http.Client
+	timeout time.Duration
+
+	mu          sync.Mutex
+	hostClients map[hostTransportKey]*http.Client
+}
+
+// hostTransportKey identifies a dedicated transport
+type hostTransportKey struct {
+	host string
+	opts transportOptions
 }
 
+// Default connection pool sizes
+const (
+	defaultMaxIdleConnsPerHost = 10
+	defaultMaxConnsPerHost     = 50
+)
+
 // NewHTTPClient creates a new HTTP client with connection pooling
 // P1-14: Configure transport for high-performance connection reuse
 // Connection pooling reduces latency by reusing TCP connections and TLS sessions
 // for repeated requests to the same bidder endpoints.
 func NewHTTPClient(timeout time.Duration) *DefaultHTTPClient {
-	transport := &http.Transport{
+	transport, _ := newTransport(transportOptions{}) // the default TLS settings cannot fail
+	return &DefaultHTTPClient{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+		},
+		timeout:     timeout,
+		hostClients: make(map[hostTransportKey]*http.Client),
+	}
+}
+
+// newTransport creates a pooled transport; zero pool sizes use the defaults
+func newTransport(opts transportOptions) (*http.Transport, error) {
+	tlsConfig, err := opts.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	maxIdlePerHost := defaultMaxIdleConnsPerHost
+	if opts.maxIdlePerHost > 0 {
+		maxIdlePerHost = opts.maxIdlePerHost
+	}
+	maxConnsPerHost := defaultMaxConnsPerHost
+	if opts.maxConnsPerHost > 0 {
+		maxConnsPerHost = opts.maxConnsPerHost
+	}
+
+	return &http.Transport{
 		// Connection pooling settings
 		MaxIdleConns:        100,              // Total idle connections across all hosts
-		MaxIdleConnsPerHost: 10,               // Idle connections per bidder endpoint
-		MaxConnsPerHost:     50,               // Max concurrent connections per host
+		MaxIdleConnsPerHost: maxIdlePerHost,   // Idle connections per bidder endpoint
+		MaxConnsPerHost:     maxConnsPerHost,  // Max concurrent connections per host
 		IdleConnTimeout:     90 * time.Second, // Keep idle connections for 90s
 
-		// TLS session caching reduces handshake overhead for repeated connections
-		TLSClientConfig: &tls.Config{
-			ClientSessionCache: tls.NewLRUClientSessionCache(100),
-			MinVersion:         tls.VersionTLS12, // Require TLS 1.2+
-		},
+		// TLS session caching reduces handshake overhead for repeated connections;
+		// bidders with mTLS get their own client certificate and CA pool
+		TLSClientConfig: tlsConfig,
 
 		// Timeouts for connection establishment
 		DialContext: (&net.Dialer{
@@ -201,16 +302,37 @@ func NewHTTPClient(timeout time.Duration) *DefaultHTTPClient {
 		ExpectContinueTimeout: 1 * time.Second,
 		ResponseHeaderTimeout: 10 * time.Second,
 
-		// Disable compression to reduce latency (bidder responses are usually small)
+		// Disable transparent compression; gzip is negotiated per bidder (see OutboundOptions)
 		DisableCompression: true,
+
+		// HTTP/2 is negotiated over TLS (ALPN) for bidders that opt in, else HTTP/1.1
+		ForceAttemptHTTP2: opts.http2,
+	}, nil
+}
+
+// clientFor returns the client for a request's host and outbound options
+func (c *DefaultHTTPClient) clientFor(host string, opts OutboundOptions) (*http.Client, error) {
+	topts := opts.transport()
+	if topts == (transportOptions{}) || c.hostClients == nil {
+		return c.client, nil
 	}
 
-	return &DefaultHTTPClient{
-		client: &http.Client{
-			Timeout:   timeout,
-			Transport: transport,
-		},
+	key := hostTransportKey{host: host, opts: topts}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.hostClients[key]; ok {
+		return client, nil
+	}
+	transport, err := newTransport(topts)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{
+		Timeout:   c.timeout,
+		Transport: transport,
 	}
+	c.hostClients[key] = client
+	return client, nil
 }
 
 // Do executes an HTTP request with proper timeout handling
@@ -236,16 +358,38 @@ func (c *DefaultHTTPClient) Do(ctx context.Context, req *RequestData, timeout ti
 		return nil, err
 	}
 
-	if len(req.Body) > 0 {
-		httpReq.Body = &bodyReader{data: req.Body}
-		httpReq.ContentLength = int64(len(req.Body))
-	}
-
 	for k, v := range req.Headers {
 		httpReq.Header[k] = v
 	}
 
-	resp, err := c.client.Do(httpReq) //nolint:bodyclose
+	opts := outboundOptionsFromContext(ctx)
+	body := req.Body
+	if opts.GzipRequests && len(body) > 0 {
+		if body, err = gzipBody(body); err != nil {
+			return nil, fmt.Errorf("failed to gzip request: %w", err)
+		}
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
+	if opts.AcceptGzip {
+		httpReq.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	if opts.SigningScheme != "" {
+		if err := signRequest(httpReq.Header, body, opts, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(body) > 0 {
+		httpReq.Body = &bodyReader{data: body}
+		httpReq.ContentLength = int64(len(body))
+	}
+
+	client, err := c.clientFor(httpReq.URL.Host, opts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outbound TLS settings: %w", err)
+	}
+	resp, err := client.Do(httpReq) //nolint:bodyclose
 	if err != nil {
 		return nil, err
 	}
@@ -291,10 +435,20 @@ func (c *DefaultHTTPClient) Do(ctx context.Context, req *RequestData, timeout ti
 		if len(result.data) > maxResponseSize {
 			return nil, fmt.Errorf("response too large: exceeded %d bytes", maxResponseSize)
 		}
+		data := result.data
+		if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") && len(data) > 0 {
+			if data, err = gunzipBody(data); err != nil {
+				return nil, err
+			}
+			resp.Header.Del("Content-Encoding")
+			resp.Header.Del("Content-Length")
+		}
 		return &ResponseData{
-			StatusCode: resp.StatusCode,
-			Body:       result.data,
-			Headers:    resp.Header,
+			StatusCode:    resp.StatusCode,
+			Body:          data,
+			Headers:       resp.Header,
+			BytesSent:     len(body),
+			BytesReceived: len(result.data),
 		}, nil
 	}
 }
@@ -0,0 +1,305 @@
+// Package adapterstest runs adapter conformance tests from JSON fixture files
+//
+// Each adapter keeps its fixtures under testdata/exemplary and testdata/supplemental. A fixture
+// holds the bid request, the HTTP calls the adapter must make with their mocked responses, and
+// the bids and errors it must produce:
+//
+//	{
+//	  "mockBidRequest": {...},
+//	  "httpCalls": [{
+//	    "expectedRequest": {"method": "POST", "uri": "...", "headers": {...}, "body": {...}},
+//	    "mockResponse": {"status": 200, "headers": {...}, "body": {...}}
+//	  }],
+//	  "expectedBidResponses": [{"currency": "USD", "bids": [{"bid": {...}, "type": "banner"}]}],
+//	  "expectedMakeRequestsErrors": [{"value": "...", "comparison": "literal"}],
+//	  "expectedMakeBidsErrors": [{"value": "...", "comparison": "regex"}]
+//	}
+//
+// Exemplary fixtures show typical traffic and must not expect errors. Supplemental fixtures cover
+// edge cases and error handling.
+package adapterstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// Fixture is a single adapter test case
+type Fixture struct {
+	BidRequest                 json.RawMessage  `json:"mockBidRequest"`
+	HTTPCalls                  []HTTPCall       `json:"httpCalls"`
+	BidResponses               []BidderResponse `json:"expectedBidResponses"`
+	ExpectedMakeRequestsErrors []ExpectedError  `json:"expectedMakeRequestsErrors"`
+	ExpectedMakeBidsErrors     []ExpectedError  `json:"expectedMakeBidsErrors"`
+}
+
+// HTTPCall is an outgoing request the adapter must build and the response it receives
+type HTTPCall struct {
+	Request  ExpectedRequest `json:"expectedRequest"`
+	Response MockResponse    `json:"mockResponse"`
+}
+
+// ExpectedRequest is compared against the adapter's RequestData
+// Headers are compared exactly when present; an empty method defaults to POST.
+type ExpectedRequest struct {
+	Method  string          `json:"method"`
+	URI     string          `json:"uri"`
+	Headers http.Header     `json:"headers"`
+	Body    json.RawMessage `json:"body"`
+}
+
+// MockResponse is the bidder's HTTP response passed to MakeBids
+// Body may be a JSON value or, for malformed responses, a JSON string holding the raw body.
+type MockResponse struct {
+	Status  int             `json:"status"`
+	Headers http.Header     `json:"headers"`
+	Body    json.RawMessage `json:"body"`
+}
+
+// BidderResponse is an expected MakeBids result
+type BidderResponse struct {
+	Currency string        `json:"currency"`
+	Bids     []ExpectedBid `json:"bids"`
+}
+
+// ExpectedBid is an expected typed bid
+type ExpectedBid struct {
+	Bid          json.RawMessage `json:"bid"`
+	Type         string          `json:"type"`
+	Video        json.RawMessage `json:"video,omitempty"`
+	Meta         json.RawMessage `json:"meta,omitempty"`
+	DealPriority int             `json:"dealPriority,omitempty"`
+}
+
+// ExpectedError matches an adapter error by its message
+type ExpectedError struct {
+	Value      string `json:"value"`
+	Comparison string `json:"comparison"` // literal (default) or regex
+}
+
+// RunJSONBidderTest runs every fixture under dir/exemplary and dir/supplemental against the adapter
+func RunJSONBidderTest(t *testing.T, dir string, bidder adapters.Adapter) {
+	t.Helper()
+
+	var found int
+	for _, kind := range []string{"exemplary", "supplemental"} {
+		files, err := filepath.Glob(filepath.Join(dir, kind, "*.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			found++
+			exemplary := kind == "exemplary"
+			t.Run(kind+"/"+strings.TrimSuffix(filepath.Base(file), ".json"), func(t *testing.T) {
+				fixture, err := LoadFixture(file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if exemplary && (len(fixture.ExpectedMakeRequestsErrors) > 0 || len(fixture.ExpectedMakeBidsErrors) > 0) {
+					t.Fatal("exemplary fixtures must not expect errors; move the fixture to supplemental")
+				}
+				RunFixture(t, fixture, bidder)
+			})
+		}
+	}
+	if found == 0 {
+		t.Fatalf("no fixtures found under %s", dir)
+	}
+}
+
+// LoadFixture reads a fixture file
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var fixture Fixture
+	if err := dec.Decode(&fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	if len(fixture.BidRequest) == 0 {
+		return nil, fmt.Errorf("invalid fixture %s: mockBidRequest is required", path)
+	}
+	return &fixture, nil
+}
+
+// RunFixture runs MakeRequests and MakeBids for one fixture and asserts the results
+func RunFixture(t *testing.T, fixture *Fixture, bidder adapters.Adapter) {
+	t.Helper()
+
+	var request openrtb.BidRequest
+	if err := json.Unmarshal(fixture.BidRequest, &request); err != nil {
+		t.Fatalf("invalid mockBidRequest: %v", err)
+	}
+
+	requests, errs := bidder.MakeRequests(&request, &adapters.ExtraRequestInfo{})
+	assertErrors(t, "MakeRequests", fixture.ExpectedMakeRequestsErrors, errs)
+
+	if len(requests) != len(fixture.HTTPCalls) {
+		t.Fatalf("expected %d outgoing requests, got %d", len(fixture.HTTPCalls), len(requests))
+	}
+
+	var bidResponses []*adapters.BidderResponse
+	var bidErrs []error
+	for i, call := range fixture.HTTPCalls {
+		assertRequest(t, i, call.Request, requests[i])
+
+		resp, errs := bidder.MakeBids(&request, &adapters.ResponseData{
+			StatusCode: call.Response.Status,
+			Body:       responseBody(call.Response.Body),
+			Headers:    call.Response.Headers,
+		})
+		bidErrs = append(bidErrs, errs...)
+		if resp != nil {
+			bidResponses = append(bidResponses, resp)
+		}
+	}
+	assertErrors(t, "MakeBids", fixture.ExpectedMakeBidsErrors, bidErrs)
+
+	if len(bidResponses) != len(fixture.BidResponses) {
+		t.Fatalf("expected %d bid responses, got %d", len(fixture.BidResponses), len(bidResponses))
+	}
+	for i, expected := range fixture.BidResponses {
+		assertBidResponse(t, i, expected, bidResponses[i])
+	}
+}
+
+func assertRequest(t *testing.T, index int, expected ExpectedRequest, actual *adapters.RequestData) {
+	t.Helper()
+
+	method := expected.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	if actual.Method != method {
+		t.Errorf("request %d: expected method %s, got %s", index, method, actual.Method)
+	}
+	if actual.URI != expected.URI {
+		t.Errorf("request %d: expected uri %q, got %q", index, expected.URI, actual.URI)
+	}
+	if expected.Headers != nil && !reflect.DeepEqual(canonicalHeaders(expected.Headers), canonicalHeaders(actual.Headers)) {
+		t.Errorf("request %d: expected headers %v, got %v", index, expected.Headers, actual.Headers)
+	}
+	if err := diffJSON(expected.Body, actual.Body); err != nil {
+		t.Errorf("request %d body: %v", index, err)
+	}
+}
+
+func assertBidResponse(t *testing.T, index int, expected BidderResponse, actual *adapters.BidderResponse) {
+	t.Helper()
+
+	if actual.Currency != expected.Currency {
+		t.Errorf("bid response %d: expected currency %q, got %q", index, expected.Currency, actual.Currency)
+	}
+	if len(actual.Bids) != len(expected.Bids) {
+		t.Errorf("bid response %d: expected %d bids, got %d", index, len(expected.Bids), len(actual.Bids))
+		return
+	}
+	for i, bid := range actual.Bids {
+		got, err := json.Marshal(typedBid(bid))
+		if err != nil {
+			t.Fatalf("failed to marshal bid: %v", err)
+		}
+		want, err := json.Marshal(expected.Bids[i])
+		if err != nil {
+			t.Fatalf("failed to marshal expected bid: %v", err)
+		}
+		if err := diffJSON(want, got); err != nil {
+			t.Errorf("bid response %d, bid %d: %v", index, i, err)
+		}
+	}
+}
+
+// typedBid converts an adapter bid to the fixture's bid format
+func typedBid(bid *adapters.TypedBid) ExpectedBid {
+	out := ExpectedBid{Type: string(bid.BidType), DealPriority: bid.DealPriority}
+	out.Bid, _ = json.Marshal(bid.Bid)
+	if bid.BidVideo != nil {
+		out.Video, _ = json.Marshal(bid.BidVideo)
+	}
+	if bid.BidMeta != nil {
+		out.Meta, _ = json.Marshal(bid.BidMeta)
+	}
+	return out
+}
+
+func assertErrors(t *testing.T, stage string, expected []ExpectedError, actual []error) {
+	t.Helper()
+
+	if len(actual) != len(expected) {
+		t.Errorf("%s: expected %d errors, got %d: %v", stage, len(expected), len(actual), actual)
+		return
+	}
+	for i, want := range expected {
+		got := actual[i].Error()
+		switch want.Comparison {
+		case "", "literal":
+			if got != want.Value {
+				t.Errorf("%s error %d: expected %q, got %q", stage, i, want.Value, got)
+			}
+		case "regex":
+			re, err := regexp.Compile(want.Value)
+			if err != nil {
+				t.Fatalf("%s error %d: invalid regex: %v", stage, i, err)
+			}
+			if !re.MatchString(got) {
+				t.Errorf("%s error %d: %q does not match %q", stage, i, got, want.Value)
+			}
+		default:
+			t.Fatalf("%s error %d: unknown comparison %q", stage, i, want.Comparison)
+		}
+	}
+}
+
+// responseBody returns the raw response body; a JSON string holds a non-JSON body verbatim
+func responseBody(body json.RawMessage) []byte {
+	var raw string
+	if json.Unmarshal(body, &raw) == nil {
+		return []byte(raw)
+	}
+	return body
+}
+
+func canonicalHeaders(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for k, v := range h {
+		out[http.CanonicalHeaderKey(k)] = v
+	}
+	return out
+}
+
+// diffJSON reports whether two JSON documents differ, ignoring formatting and key order
+func diffJSON(expected, actual []byte) error {
+	var want, got interface{}
+	if len(expected) > 0 {
+		if err := json.Unmarshal(expected, &want); err != nil {
+			return fmt.Errorf("invalid expected JSON: %w", err)
+		}
+	}
+	if len(actual) > 0 {
+		if err := json.Unmarshal(actual, &got); err != nil {
+			return fmt.Errorf("invalid actual JSON: %w", err)
+		}
+	}
+	if reflect.DeepEqual(want, got) {
+		return nil
+	}
+	wantPretty, _ := json.MarshalIndent(want, "", "  ")
+	gotPretty, _ := json.MarshalIndent(got, "", "  ")
+	return fmt.Errorf("JSON mismatch\nexpected:\n%s\nactual:\n%s", wantPretty, gotPretty)
+}
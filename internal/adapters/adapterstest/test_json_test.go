@@ -0,0 +1,54 @@
+package adapterstest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+)
+
+func TestRunJSONBidderTest(t *testing.T) {
+	RunJSONBidderTest(t, "testdata", adapters.NewSimpleAdapter("mock", "https://bidder.example.com/rtb", ""))
+}
+
+func TestLoadFixture_Errors(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"unknown field":       `{"mockBidRequest": {"id": "1"}, "httpCall": []}`,
+		"missing bid request": `{"httpCalls": []}`,
+		"invalid JSON":        `{"mockBidRequest":`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name+".json")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadFixture(path); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestDiffJSON(t *testing.T) {
+	if err := diffJSON([]byte(`{"a": 1, "b": [1, 2]}`), []byte(`{"b":[1,2],"a":1}`)); err != nil {
+		t.Errorf("expected key order and formatting to be ignored, got %v", err)
+	}
+	if err := diffJSON([]byte(`{"a": 1}`), []byte(`{"a": 1, "b": 2}`)); err == nil {
+		t.Error("expected extra field to be a mismatch")
+	}
+	if err := diffJSON([]byte(`{"a": 1}`), []byte(`{`)); err == nil {
+		t.Error("expected invalid actual JSON to be an error")
+	}
+}
+
+func TestResponseBody(t *testing.T) {
+	if got := string(responseBody([]byte(`"not json"`))); got != "not json" {
+		t.Errorf("expected raw string body, got %q", got)
+	}
+	if got := string(responseBody([]byte(`{"id":"1"}`))); got != `{"id":"1"}` {
+		t.Errorf("expected JSON body unchanged, got %q", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/adapterstest"
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
 )
 
@@ -203,3 +204,7 @@ func TestInfo(t *testing.T) {
 		t.Fatal("Expected site capabilities")
 	}
 }
+
+func TestJSONSamples(t *testing.T) {
+	adapterstest.RunJSONBidderTest(t, "testdata", New(""))
+}
@@ -0,0 +1,118 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AliasConfig defines a server-side bidder alias
+// An alias reuses the core bidder's adapter but has its own identity; zero fields inherit from the core
+type AliasConfig struct {
+	Alias        string     `json:"alias"`
+	Core         string     `json:"core"`
+	GVLVendorID  int        `json:"gvlVendorId,omitempty"`
+	SyncerKey    string     `json:"syncerKey,omitempty"`
+	DemandType   DemandType `json:"demandType,omitempty"`
+	TimeoutMS    int        `json:"timeoutMs,omitempty"`
+	MetricsLabel string     `json:"metricsLabel,omitempty"`
+	Disabled     bool       `json:"disabled,omitempty"`
+}
+
+// ParseAliasConfigs parses a JSON array of alias configs
+func ParseAliasConfigs(data []byte) ([]AliasConfig, error) {
+	var configs []AliasConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("invalid alias config: %w", err)
+	}
+	return configs, nil
+}
+
+// RegisterAlias registers an alias of an already registered core bidder
+// Aliases of aliases resolve to the root core bidder
+func (r *Registry) RegisterAlias(cfg AliasConfig) error {
+	alias := strings.TrimSpace(cfg.Alias)
+	if alias == "" {
+		return fmt.Errorf("alias name is required")
+	}
+	if cfg.DemandType != "" && cfg.DemandType != DemandTypePlatform && cfg.DemandType != DemandTypePublisher {
+		return fmt.Errorf("alias %s: invalid demand type %q", alias, cfg.DemandType)
+	}
+	if cfg.TimeoutMS < 0 {
+		return fmt.Errorf("alias %s: timeout must not be negative", alias)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.adapters[alias]; exists {
+		return fmt.Errorf("adapter already registered: %s", alias)
+	}
+	core, ok := r.adapters[cfg.Core]
+	if !ok {
+		return fmt.Errorf("alias %s: core bidder not registered: %s", alias, cfg.Core)
+	}
+
+	awi := core
+	awi.Info = AliasInfo(cfg.Core, core.Info, cfg)
+	r.adapters[alias] = awi
+	return nil
+}
+
+// AliasInfo builds an alias's bidder info from its core bidder's info
+func AliasInfo(coreCode string, core BidderInfo, cfg AliasConfig) BidderInfo {
+	info := core
+	info.AliasOf = coreCode
+	if core.AliasOf != "" {
+		info.AliasOf = core.AliasOf
+	}
+	// By default an alias shares its core's user sync key and is labelled by its own code in metrics
+	if info.SyncerKey == "" {
+		info.SyncerKey = info.AliasOf
+	}
+	info.MetricsLabel = ""
+
+	if cfg.Disabled {
+		info.Enabled = false
+	}
+	if cfg.GVLVendorID != 0 {
+		info.GVLVendorID = cfg.GVLVendorID
+	}
+	if cfg.SyncerKey != "" {
+		info.SyncerKey = cfg.SyncerKey
+	}
+	if cfg.DemandType != "" {
+		info.DemandType = cfg.DemandType
+	}
+	if cfg.TimeoutMS > 0 {
+		info.Timeout = time.Duration(cfg.TimeoutMS) * time.Millisecond
+	}
+	if cfg.MetricsLabel != "" {
+		info.MetricsLabel = cfg.MetricsLabel
+	}
+	return info
+}
+
+// CoreBidder returns the bidder code whose adapter builds requests for the given code
+func (r *Registry) CoreBidder(bidderCode string) string {
+	if awi, ok := r.Get(bidderCode); ok && awi.Info.AliasOf != "" {
+		return awi.Info.AliasOf
+	}
+	return bidderCode
+}
+
+// SyncerKeys returns the user sync key of every bidder whose key differs from its code,
+// which are the aliases
+func (r *Registry) SyncerKeys() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make(map[string]string)
+	for code, awi := range r.adapters {
+		if awi.Info.SyncerKey != "" && awi.Info.SyncerKey != code {
+			keys[code] = awi.Info.SyncerKey
+		}
+	}
+	return keys
+}
@@ -0,0 +1,105 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegistry_RegisterAlias(t *testing.T) {
+	r := NewRegistry()
+	core := &mockAdapter{name: "core"}
+	r.Register("appnexus", core, BidderInfo{
+		Enabled:     true,
+		GVLVendorID: 32,
+		Endpoint:    "https://ib.adnxs.com/openrtb2",
+		DemandType:  DemandTypePlatform,
+	})
+
+	err := r.RegisterAlias(AliasConfig{
+		Alias:        "appnexus_video",
+		Core:         "appnexus",
+		GVLVendorID:  999,
+		DemandType:   DemandTypePublisher,
+		TimeoutMS:    400,
+		MetricsLabel: "anx_video",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	awi, ok := r.Get("appnexus_video")
+	if !ok {
+		t.Fatal("expected alias to be registered")
+	}
+	if awi.Adapter != core {
+		t.Error("expected alias to share the core adapter")
+	}
+	info := awi.Info
+	if info.AliasOf != "appnexus" || info.GVLVendorID != 999 || info.DemandType != DemandTypePublisher {
+		t.Errorf("unexpected alias identity: %+v", info)
+	}
+	if info.Timeout != 400*time.Millisecond || info.MetricsLabel != "anx_video" {
+		t.Errorf("unexpected alias timeout or label: %+v", info)
+	}
+	if info.Endpoint != "https://ib.adnxs.com/openrtb2" || !info.Enabled {
+		t.Errorf("expected unset fields to inherit from core: %+v", info)
+	}
+	if info.SyncerKey != "appnexus" {
+		t.Errorf("expected alias to share the core syncer key, got %q", info.SyncerKey)
+	}
+
+	// Core info is untouched
+	if coreInfo, _ := r.Get("appnexus"); coreInfo.Info.AliasOf != "" || coreInfo.Info.GVLVendorID != 32 {
+		t.Errorf("core info modified: %+v", coreInfo.Info)
+	}
+
+	// Aliases of aliases resolve to the root core
+	if err := r.RegisterAlias(AliasConfig{Alias: "anx2", Core: "appnexus_video", SyncerKey: "anx2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.CoreBidder("anx2") != "appnexus" || r.CoreBidder("appnexus") != "appnexus" {
+		t.Error("expected aliases to resolve to the root core bidder")
+	}
+
+	keys := r.SyncerKeys()
+	if keys["appnexus_video"] != "appnexus" || keys["anx2"] != "" || len(keys) != 1 {
+		t.Errorf("unexpected syncer keys: %v", keys)
+	}
+}
+
+func TestRegistry_RegisterAliasErrors(t *testing.T) {
+	r := NewRegistry()
+	r.Register("rubicon", &mockAdapter{}, BidderInfo{Enabled: true})
+
+	tests := []struct {
+		name string
+		cfg  AliasConfig
+	}{
+		{"missing name", AliasConfig{Core: "rubicon"}},
+		{"unknown core", AliasConfig{Alias: "x", Core: "missing"}},
+		{"shadows registered bidder", AliasConfig{Alias: "rubicon", Core: "rubicon"}},
+		{"invalid demand type", AliasConfig{Alias: "x", Core: "rubicon", DemandType: "other"}},
+		{"negative timeout", AliasConfig{Alias: "x", Core: "rubicon", TimeoutMS: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.RegisterAlias(tt.cfg); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestParseAliasConfigs(t *testing.T) {
+	configs, err := ParseAliasConfigs([]byte(`[{"alias":"a","core":"rubicon","gvlVendorId":5,"timeoutMs":300,"disabled":true}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(configs) != 1 || configs[0].GVLVendorID != 5 || configs[0].TimeoutMS != 300 || !configs[0].Disabled {
+		t.Errorf("unexpected configs: %+v", configs)
+	}
+
+	if _, err := ParseAliasConfigs([]byte(`{"alias":"a"}`)); err == nil {
+		t.Error("expected error for non-array config")
+	}
+}
@@ -2,15 +2,20 @@
 package appnexus
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 
 	"github.com/thenexusengine/tne_springwire/internal/adapters"
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
 )
 
 const (
+	bidderCode      = "appnexus"
 	defaultEndpoint = "https://ib.adnxs.com/openrtb2/prebid"
 )
 
@@ -27,17 +32,87 @@ func New(endpoint string) *Adapter {
 	return &Adapter{endpoint: endpoint}
 }
 
+// impParams are the documented imp.ext.appnexus params
+type impParams struct {
+	PlacementID       int                        `json:"placementId"`
+	Member            string                     `json:"member"`
+	InvCode           string                     `json:"invCode"`
+	Keywords          map[string]json.RawMessage `json:"keywords"`
+	TrafficSourceCode string                     `json:"trafficSourceCode"`
+	Reserve           float64                    `json:"reserve"`
+}
+
+// impExt is the imp.ext.appnexus shape the AppNexus endpoint expects
+type impExt struct {
+	PlacementID       int    `json:"placement_id,omitempty"`
+	Keywords          string `json:"keywords,omitempty"`
+	TrafficSourceCode string `json:"traffic_source_code,omitempty"`
+}
+
+// bidExt carries the media type AppNexus reports for each bid
+type bidExt struct {
+	AppNexus struct {
+		BidAdType *int `json:"bid_ad_type"`
+	} `json:"appnexus"`
+}
+
 // MakeRequests builds HTTP requests for AppNexus
+// Placements are sent as imp.ext.appnexus.placement_id; member/invCode placements are routed
+// with a member_id query parameter and the inventory code as imp.tagid. Imps without
+// appnexus params are sent unchanged.
 func (a *Adapter) MakeRequests(request *openrtb.BidRequest, extraInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
 	var errors []error
 
 	// Clone request for modification
 	reqCopy := *request
+	reqCopy.Imp = make([]openrtb.Imp, 0, len(request.Imp))
+
+	var member string
+	for _, imp := range request.Imp {
+		var params impParams
+		ok, err := adapters.UnmarshalBidderParams(&imp, bidderCode, &params)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		if !ok {
+			reqCopy.Imp = append(reqCopy.Imp, imp)
+			continue
+		}
+
+		if params.PlacementID == 0 && (params.Member == "" || params.InvCode == "") {
+			errors = append(errors, fmt.Errorf("imp %s: placementId or member and invCode required", imp.ID))
+			continue
+		}
+		if params.Member != "" {
+			if member == "" {
+				member = params.Member
+			} else if params.Member != member {
+				errors = append(errors, fmt.Errorf("imp %s: member %s does not match request member %s", imp.ID, params.Member, member))
+				continue
+			}
+		}
+
+		shaped, err := shapeImp(imp, params)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		reqCopy.Imp = append(reqCopy.Imp, shaped)
+	}
+
+	if len(reqCopy.Imp) == 0 {
+		return nil, errors
+	}
 
-	// Add AppNexus-specific extensions
 	requestBody, err := json.Marshal(reqCopy)
 	if err != nil {
-		return nil, []error{fmt.Errorf("failed to marshal request: %w", err)}
+		return nil, append(errors, fmt.Errorf("failed to marshal request: %w", err))
+	}
+
+	uri := a.endpoint
+	if member != "" {
+		uri = appendQuery(uri, "member_id", member)
 	}
 
 	headers := http.Header{}
@@ -47,14 +122,80 @@ func (a *Adapter) MakeRequests(request *openrtb.BidRequest, extraInfo *adapters.
 	return []*adapters.RequestData{
 		{
 			Method:  "POST",
-			URI:     a.endpoint,
+			URI:     uri,
 			Body:    requestBody,
 			Headers: headers,
 		},
 	}, errors
 }
 
+// shapeImp maps documented params onto the imp fields AppNexus reads
+func shapeImp(imp openrtb.Imp, params impParams) (openrtb.Imp, error) {
+	if params.InvCode != "" {
+		imp.TagID = params.InvCode
+	}
+	if imp.BidFloor <= 0 && params.Reserve > 0 {
+		imp.BidFloor = params.Reserve
+		imp.BidFloorCur = "USD"
+	}
+
+	keywords, err := formatKeywords(params.Keywords)
+	if err != nil {
+		return imp, fmt.Errorf("imp %s: %w", imp.ID, err)
+	}
+	ext, err := json.Marshal(map[string]impExt{bidderCode: {
+		PlacementID:       params.PlacementID,
+		Keywords:          keywords,
+		TrafficSourceCode: params.TrafficSourceCode,
+	}})
+	if err != nil {
+		return imp, fmt.Errorf("imp %s: %w", imp.ID, err)
+	}
+	imp.Ext = ext
+	return imp, nil
+}
+
+// formatKeywords flattens {"genre": ["rock", "pop"]} to "genre=rock,genre=pop"
+// Keys are sorted; a key with no values is sent on its own.
+func formatKeywords(keywords map[string]json.RawMessage) (string, error) {
+	keys := make([]string, 0, len(keywords))
+	for key := range keywords {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		var values []string
+		if err := json.Unmarshal(keywords[key], &values); err != nil {
+			var value string
+			if err := json.Unmarshal(keywords[key], &value); err != nil {
+				return "", fmt.Errorf("keyword %s: expected string or array of strings", key)
+			}
+			values = []string{value}
+		}
+		if len(values) == 0 {
+			parts = append(parts, key)
+			continue
+		}
+		for _, value := range values {
+			parts = append(parts, key+"="+value)
+		}
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// appendQuery adds a query parameter to the endpoint URL
+func appendQuery(uri, key, value string) string {
+	sep := "?"
+	if strings.Contains(uri, "?") {
+		sep = "&"
+	}
+	return uri + sep + key + "=" + url.QueryEscape(value)
+}
+
 // MakeBids parses AppNexus responses into bids
+// The bid type comes from bid.ext.appnexus.bid_ad_type, falling back to the imp's media type.
 func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.ResponseData) (*adapters.BidderResponse, []error) {
 	if responseData.StatusCode == http.StatusNoContent {
 		return nil, nil
@@ -82,10 +223,15 @@ func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.R
 	// P2-3: Build impression map once for O(1) lookups instead of O(n) per bid
 	impMap := adapters.BuildImpMap(request.Imp)
 
+	var errors []error
 	for _, seatBid := range bidResp.SeatBid {
 		for i := range seatBid.Bid {
 			bid := &seatBid.Bid[i]
-			bidType := adapters.GetBidTypeFromMap(bid, impMap)
+			bidType, err := getBidType(bid, impMap)
+			if err != nil {
+				errors = append(errors, err)
+				continue
+			}
 
 			response.Bids = append(response.Bids, &adapters.TypedBid{
 				Bid:     bid,
@@ -94,9 +240,39 @@ func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.R
 		}
 	}
 
-	return response, nil
+	return response, errors
+}
+
+// getBidType maps bid_ad_type (0 banner, 1 video, 2 audio, 3 native) to a bid type
+func getBidType(bid *openrtb.Bid, impMap map[string]*openrtb.Imp) (adapters.BidType, error) {
+	var ext bidExt
+	if len(bid.Ext) > 0 {
+		if err := json.Unmarshal(bid.Ext, &ext); err != nil {
+			return "", fmt.Errorf("bid %s: invalid ext: %w", bid.ID, err)
+		}
+	}
+	if ext.AppNexus.BidAdType == nil {
+		return adapters.GetBidTypeFromMap(bid, impMap), nil
+	}
+
+	switch *ext.AppNexus.BidAdType {
+	case 0:
+		return adapters.BidTypeBanner, nil
+	case 1:
+		return adapters.BidTypeVideo, nil
+	case 2:
+		return adapters.BidTypeAudio, nil
+	case 3:
+		return adapters.BidTypeNative, nil
+	}
+	return "", fmt.Errorf("bid %s: unknown bid_ad_type %d", bid.ID, *ext.AppNexus.BidAdType)
 }
 
+// paramsSchema is the JSON Schema for imp.ext.appnexus params
+//
+//go:embed params.json
+var paramsSchema []byte
+
 // Info returns bidder information
 func Info() adapters.BidderInfo {
 	return adapters.BidderInfo{
@@ -120,14 +296,15 @@ func Info() adapters.BidderInfo {
 				},
 			},
 		},
-		GVLVendorID: 32,
-		Endpoint:    defaultEndpoint,
-		DemandType:  adapters.DemandTypePlatform, // Platform demand (obfuscated as "thenexusengine")
+		GVLVendorID:  32,
+		Endpoint:     defaultEndpoint,
+		DemandType:   adapters.DemandTypePlatform, // Platform demand (obfuscated as "thenexusengine")
+		ParamsSchema: paramsSchema,
 	}
 }
 
 func init() {
-	if err := adapters.RegisterAdapter("appnexus", New(""), Info()); err != nil {
-		panic(fmt.Sprintf("failed to register appnexus adapter: %v", err))
+	if err := adapters.RegisterAdapter(bidderCode, New(""), Info()); err != nil {
+		panic(fmt.Sprintf("failed to register %s adapter: %v", bidderCode, err))
 	}
 }
@@ -0,0 +1,257 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StartupConfig selects and configures the compiled-in adapters at startup
+type StartupConfig struct {
+	// Enabled, when set, is the only set of bidders left enabled
+	Enabled []string `json:"enabled,omitempty"`
+	// Bidders holds per-bidder overrides keyed by bidder code
+	Bidders map[string]AdapterConfig `json:"bidders,omitempty"`
+}
+
+// LoadStartupConfig reads the adapter config file, when path is set, and applies environment overrides
+// Environment variables take precedence over the file:
+//   - ADAPTERS_ENABLED: comma-separated bidders to keep enabled (all others are disabled)
+//   - ADAPTERS_DISABLED: comma-separated bidders to disable
+//   - ADAPTER_<CODE>_ENDPOINT, ADAPTER_<CODE>_DEMAND_TYPE, ADAPTER_<CODE>_DISABLED
+//   - ADAPTER_<CODE>_QPS_LIMIT, ADAPTER_<CODE>_DAILY_LIMIT, ADAPTER_<CODE>_CONCURRENT_LIMIT
+//   - ADAPTER_<CODE>_GZIP_REQUESTS, ADAPTER_<CODE>_ACCEPT_GZIP, ADAPTER_<CODE>_HTTP2
+//   - ADAPTER_<CODE>_MAX_CONNS_PER_HOST, ADAPTER_<CODE>_MAX_IDLE_CONNS_PER_HOST
+//   - ADAPTER_<CODE>_CLIENT_CERT_FILE, ADAPTER_<CODE>_CLIENT_KEY_FILE, ADAPTER_<CODE>_CA_FILE
+//   - ADAPTER_<CODE>_SIGNING_SCHEME, ADAPTER_<CODE>_SIGNING_KEY, ADAPTER_<CODE>_SIGNING_HEADER,
+//     ADAPTER_<CODE>_SIGNING_TIMESTAMP_HEADER
+//   - ADAPTER_<CODE>_MODIFY_VAST
+func LoadStartupConfig(path string, environ []string) (*StartupConfig, error) {
+	cfg := &StartupConfig{Bidders: make(map[string]AdapterConfig)}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read adapter config: %w", err)
+		}
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("invalid adapter config %s: %w", path, err)
+		}
+		if cfg.Bidders == nil {
+			cfg.Bidders = make(map[string]AdapterConfig)
+		}
+	}
+
+	for _, kv := range environ {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || value == "" {
+			continue
+		}
+		switch {
+		case key == "ADAPTERS_ENABLED":
+			cfg.Enabled = splitBidderList(value)
+		case key == "ADAPTERS_DISABLED":
+			for _, code := range splitBidderList(value) {
+				bc := cfg.Bidders[code]
+				bc.Disabled = true
+				cfg.Bidders[code] = bc
+			}
+		case strings.HasPrefix(key, "ADAPTER_"):
+			rest := strings.TrimPrefix(key, "ADAPTER_")
+			for _, suffix := range []string{"_ENDPOINT", "_DEMAND_TYPE", "_DISABLED", "_QPS_LIMIT", "_DAILY_LIMIT", "_CONCURRENT_LIMIT",
+				"_GZIP_REQUESTS", "_ACCEPT_GZIP", "_HTTP2", "_MAX_CONNS_PER_HOST", "_MAX_IDLE_CONNS_PER_HOST", "_MODIFY_VAST",
+				"_CLIENT_CERT_FILE", "_CLIENT_KEY_FILE", "_CA_FILE",
+				"_SIGNING_SCHEME", "_SIGNING_KEY", "_SIGNING_HEADER", "_SIGNING_TIMESTAMP_HEADER"} {
+				if !strings.HasSuffix(rest, suffix) || len(rest) == len(suffix) {
+					continue
+				}
+				code := strings.ToLower(strings.TrimSuffix(rest, suffix))
+				bc := cfg.Bidders[code]
+				switch suffix {
+				case "_ENDPOINT":
+					bc.Endpoint = value
+				case "_DEMAND_TYPE":
+					bc.DemandType = DemandType(strings.ToLower(value))
+				case "_DISABLED":
+					bc.Disabled = isTrue(value)
+				case "_MODIFY_VAST":
+					allowed := isTrue(value)
+					bc.ModifyVAST = &allowed
+				case "_QPS_LIMIT", "_DAILY_LIMIT", "_CONCURRENT_LIMIT":
+					limit, err := strconv.Atoi(value)
+					if err != nil || limit < 0 {
+						return nil, fmt.Errorf("invalid %s: %q", key, value)
+					}
+					limits := RateLimits{}
+					if bc.RateLimits != nil {
+						limits = *bc.RateLimits
+					}
+					switch suffix {
+					case "_QPS_LIMIT":
+						limits.QPS = limit
+					case "_DAILY_LIMIT":
+						limits.Daily = limit
+					default:
+						limits.Concurrent = limit
+					}
+					bc.RateLimits = &limits
+				case "_GZIP_REQUESTS", "_ACCEPT_GZIP", "_HTTP2", "_MAX_CONNS_PER_HOST", "_MAX_IDLE_CONNS_PER_HOST",
+					"_CLIENT_CERT_FILE", "_CLIENT_KEY_FILE", "_CA_FILE",
+					"_SIGNING_SCHEME", "_SIGNING_KEY", "_SIGNING_HEADER", "_SIGNING_TIMESTAMP_HEADER":
+					outbound := OutboundOptions{}
+					if bc.Outbound != nil {
+						outbound = *bc.Outbound
+					}
+					switch suffix {
+					case "_CLIENT_CERT_FILE":
+						outbound.ClientCertFile = value
+					case "_CLIENT_KEY_FILE":
+						outbound.ClientKeyFile = value
+					case "_CA_FILE":
+						outbound.CAFile = value
+					case "_SIGNING_SCHEME":
+						scheme := strings.ToLower(value)
+						if !ValidSigningScheme(scheme) {
+							return nil, fmt.Errorf("invalid %s: %q", key, value)
+						}
+						outbound.SigningScheme = scheme
+					case "_SIGNING_KEY":
+						outbound.SigningKey = value
+					case "_SIGNING_HEADER":
+						outbound.SigningHeader = value
+					case "_SIGNING_TIMESTAMP_HEADER":
+						outbound.SigningTimestampHeader = value
+					case "_GZIP_REQUESTS":
+						outbound.GzipRequests = isTrue(value)
+					case "_ACCEPT_GZIP":
+						outbound.AcceptGzip = isTrue(value)
+					case "_HTTP2":
+						outbound.HTTP2 = isTrue(value)
+					default:
+						conns, err := strconv.Atoi(value)
+						if err != nil || conns < 0 {
+							return nil, fmt.Errorf("invalid %s: %q", key, value)
+						}
+						if suffix == "_MAX_CONNS_PER_HOST" {
+							outbound.MaxConnsPerHost = conns
+						} else {
+							outbound.MaxIdleConnsPerHost = conns
+						}
+					}
+					bc.Outbound = &outbound
+				}
+				cfg.Bidders[code] = bc
+				break
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// EnableByDefault sets the enabled list to codes when neither the file nor the environment set one
+// Compiled-in adapters outside the list then stay disabled until the enabled list names them.
+func (c *StartupConfig) EnableByDefault(codes []string) {
+	if len(c.Enabled) == 0 {
+		c.Enabled = append([]string(nil), codes...)
+	}
+}
+
+// isTrue parses a boolean environment value
+func isTrue(value string) bool {
+	return value == "true" || value == "1" || value == "yes"
+}
+
+// splitBidderList parses a comma-separated list of bidder codes
+func splitBidderList(value string) []string {
+	var codes []string
+	for _, code := range strings.Split(value, ",") {
+		if code = strings.ToLower(strings.TrimSpace(code)); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// ApplyStartupConfig enables, disables and reconfigures registered bidders
+// Endpoint overrides rebuild the adapter with the bidder's builder. Unknown bidders, invalid
+// demand types, negative rate limits, invalid outbound options (see OutboundOptions.Validate)
+// and endpoint overrides without a builder are errors.
+func (r *Registry) ApplyStartupConfig(cfg *StartupConfig, builders map[string]Builder) error {
+	if cfg == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(cfg.Enabled) > 0 {
+		enabled := make(map[string]struct{}, len(cfg.Enabled))
+		for _, code := range cfg.Enabled {
+			if _, ok := r.adapters[code]; !ok {
+				return fmt.Errorf("enabled bidder not registered: %s", code)
+			}
+			enabled[code] = struct{}{}
+		}
+		for code, awi := range r.adapters {
+			_, keep := enabled[code]
+			awi.Info.Enabled = keep
+			r.adapters[code] = awi
+		}
+	}
+
+	codes := make([]string, 0, len(cfg.Bidders))
+	for code := range cfg.Bidders {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		bc := cfg.Bidders[code]
+		awi, ok := r.adapters[code]
+		if !ok {
+			return fmt.Errorf("configured bidder not registered: %s", code)
+		}
+
+		if bc.Endpoint != "" {
+			build, ok := builders[code]
+			if !ok {
+				return fmt.Errorf("bidder %s: endpoint override not supported", code)
+			}
+			awi.Adapter = build(bc.Endpoint)
+			awi.Info.Endpoint = bc.Endpoint
+		}
+		if bc.DemandType != "" {
+			if bc.DemandType != DemandTypePlatform && bc.DemandType != DemandTypePublisher {
+				return fmt.Errorf("bidder %s: invalid demand type %q", code, bc.DemandType)
+			}
+			awi.Info.DemandType = bc.DemandType
+		}
+		if bc.ExtraInfo != "" {
+			awi.Info.ExtraInfo = bc.ExtraInfo
+		}
+		if bc.RateLimits != nil {
+			if bc.RateLimits.QPS < 0 || bc.RateLimits.Daily < 0 || bc.RateLimits.Concurrent < 0 {
+				return fmt.Errorf("bidder %s: rate limits must not be negative", code)
+			}
+			awi.Info.RateLimits = *bc.RateLimits
+		}
+		if bc.Outbound != nil {
+			if err := bc.Outbound.Validate(); err != nil {
+				return fmt.Errorf("bidder %s: %w", code, err)
+			}
+			awi.Info.Outbound = *bc.Outbound
+		}
+		if bc.ModifyVAST != nil {
+			awi.Info.ModifyingVastXmlAllowed = *bc.ModifyVAST
+		}
+		if bc.Disabled {
+			awi.Info.Enabled = false
+		}
+		r.adapters[code] = awi
+	}
+	return nil
+}
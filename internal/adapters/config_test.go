@@ -0,0 +1,176 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadStartupConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adapters.json")
+	if err := os.WriteFile(path, []byte(`{
+		"enabled": ["appnexus", "criteo"],
+		"bidders": {"criteo": {"endpoint": "https://file.example.com", "demandType": "publisher"}}
+	}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadStartupConfig(path, []string{
+		"ADAPTER_CRITEO_ENDPOINT=https://env.example.com",
+		"ADAPTER_33ACROSS_DISABLED=true",
+		"ADAPTERS_DISABLED=ix, sovrn",
+		"ADAPTER_RUBICON_QPS_LIMIT=200",
+		"ADAPTER_RUBICON_CONCURRENT_LIMIT=50",
+		"ADAPTER_PUBMATIC_GZIP_REQUESTS=true",
+		"ADAPTER_PUBMATIC_HTTP2=1",
+		"ADAPTER_PUBMATIC_MAX_IDLE_CONNS_PER_HOST=20",
+		"ADAPTER_PUBMATIC_MODIFY_VAST=true",
+		"ADAPTER_=ignored",
+		"UNRELATED=1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(cfg.Enabled) != 2 {
+		t.Errorf("expected enabled list from file, got %v", cfg.Enabled)
+	}
+	criteo := cfg.Bidders["criteo"]
+	if criteo.Endpoint != "https://env.example.com" || criteo.DemandType != DemandTypePublisher {
+		t.Errorf("expected env endpoint to override file, got %+v", criteo)
+	}
+	for _, code := range []string{"33across", "ix", "sovrn"} {
+		if !cfg.Bidders[code].Disabled {
+			t.Errorf("expected %s disabled, got %+v", code, cfg.Bidders[code])
+		}
+	}
+
+	if limits := cfg.Bidders["rubicon"].RateLimits; limits == nil || *limits != (RateLimits{QPS: 200, Concurrent: 50}) {
+		t.Errorf("expected rubicon rate limits from env, got %+v", limits)
+	}
+	if _, err := LoadStartupConfig("", []string{"ADAPTER_RUBICON_DAILY_LIMIT=lots"}); err == nil {
+		t.Error("expected error for invalid rate limit")
+	}
+	if outbound := cfg.Bidders["pubmatic"].Outbound; outbound == nil || *outbound != (OutboundOptions{GzipRequests: true, HTTP2: true, MaxIdleConnsPerHost: 20}) {
+		t.Errorf("expected pubmatic outbound options from env, got %+v", outbound)
+	}
+	if modify := cfg.Bidders["pubmatic"].ModifyVAST; modify == nil || !*modify {
+		t.Errorf("expected pubmatic VAST modification allowed from env, got %v", modify)
+	}
+	if _, err := LoadStartupConfig("", []string{"ADAPTER_PUBMATIC_MAX_CONNS_PER_HOST=-5"}); err == nil {
+		t.Error("expected error for invalid pool size")
+	}
+
+	cfg, err = LoadStartupConfig("", []string{
+		"ADAPTER_PMPDSP_SIGNING_SCHEME=HMAC-SHA256",
+		"ADAPTER_PMPDSP_SIGNING_KEY=shared-key",
+		"ADAPTER_PMPDSP_SIGNING_HEADER=X-DSP-Signature",
+		"ADAPTER_PMPDSP_SIGNING_TIMESTAMP_HEADER=X-DSP-Time",
+		"ADAPTER_PMPDSP_CLIENT_CERT_FILE=/etc/certs/client.pem",
+		"ADAPTER_PMPDSP_CLIENT_KEY_FILE=/etc/certs/client-key.pem",
+		"ADAPTER_PMPDSP_CA_FILE=/etc/certs/ca.pem",
+	})
+	want := OutboundOptions{
+		SigningScheme:          SigningHMACSHA256,
+		SigningKey:             "shared-key",
+		SigningHeader:          "X-DSP-Signature",
+		SigningTimestampHeader: "X-DSP-Time",
+		ClientCertFile:         "/etc/certs/client.pem",
+		ClientKeyFile:          "/etc/certs/client-key.pem",
+		CAFile:                 "/etc/certs/ca.pem",
+	}
+	if outbound := cfg.Bidders["pmpdsp"].Outbound; err != nil || outbound == nil || *outbound != want {
+		t.Errorf("expected signing and mTLS options from env, got %+v (%v)", outbound, err)
+	}
+	if _, err := LoadStartupConfig("", []string{"ADAPTER_PMPDSP_SIGNING_SCHEME=md5"}); err == nil {
+		t.Error("expected error for unsupported signing scheme")
+	}
+
+	if cfg, err := LoadStartupConfig("", []string{"ADAPTERS_ENABLED=AppNexus,rubicon"}); err != nil || len(cfg.Enabled) != 2 || cfg.Enabled[0] != "appnexus" {
+		t.Errorf("expected enabled list from env, got %+v (%v)", cfg, err)
+	}
+
+	cfg, _ = LoadStartupConfig("", []string{"ADAPTERS_ENABLED=criteo"})
+	cfg.EnableByDefault([]string{"appnexus", "rubicon"})
+	if len(cfg.Enabled) != 1 || cfg.Enabled[0] != "criteo" {
+		t.Errorf("expected the configured enabled list to be kept, got %v", cfg.Enabled)
+	}
+	cfg, _ = LoadStartupConfig("", nil)
+	cfg.EnableByDefault([]string{"appnexus", "rubicon"})
+	if len(cfg.Enabled) != 2 || cfg.Enabled[1] != "rubicon" {
+		t.Errorf("expected the default enabled list, got %v", cfg.Enabled)
+	}
+
+	if _, err := LoadStartupConfig(filepath.Join(t.TempDir(), "missing.json"), nil); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestRegistry_ApplyStartupConfig(t *testing.T) {
+	newRegistry := func() *Registry {
+		r := NewRegistry()
+		r.Register("appnexus", &mockAdapter{name: "default"}, BidderInfo{Enabled: true, Endpoint: "https://default.example.com"})
+		r.Register("rubicon", &mockAdapter{}, BidderInfo{Enabled: true})
+		r.Register("pubmatic", &mockAdapter{}, BidderInfo{Enabled: true})
+		return r
+	}
+	builders := map[string]Builder{
+		"appnexus": func(endpoint string) Adapter { return &mockAdapter{name: endpoint} },
+	}
+
+	modifyVAST := true
+	r := newRegistry()
+	err := r.ApplyStartupConfig(&StartupConfig{
+		Enabled: []string{"appnexus", "rubicon"},
+		Bidders: map[string]AdapterConfig{
+			"appnexus": {Endpoint: "https://override.example.com", DemandType: DemandTypePublisher},
+			"rubicon":  {Disabled: true, RateLimits: &RateLimits{QPS: 100, Daily: 1000000}},
+			"pubmatic": {Outbound: &OutboundOptions{GzipRequests: true, MaxConnsPerHost: 100}, ModifyVAST: &modifyVAST},
+		},
+	}, builders)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	anx, _ := r.Get("appnexus")
+	if anx.Info.Endpoint != "https://override.example.com" || anx.Info.DemandType != DemandTypePublisher || !anx.Info.Enabled {
+		t.Errorf("unexpected appnexus info: %+v", anx.Info)
+	}
+	if m, ok := anx.Adapter.(*mockAdapter); !ok || m.name != "https://override.example.com" {
+		t.Errorf("expected adapter rebuilt with override endpoint, got %+v", anx.Adapter)
+	}
+	if rubicon, _ := r.Get("rubicon"); rubicon.Info.RateLimits != (RateLimits{QPS: 100, Daily: 1000000}) {
+		t.Errorf("expected rubicon rate limits, got %+v", rubicon.Info.RateLimits)
+	}
+	if pubmatic, _ := r.Get("pubmatic"); pubmatic.Info.Outbound != (OutboundOptions{GzipRequests: true, MaxConnsPerHost: 100}) {
+		t.Errorf("expected pubmatic outbound options, got %+v", pubmatic.Info.Outbound)
+	}
+	if pubmatic, _ := r.Get("pubmatic"); !pubmatic.Info.ModifyingVastXmlAllowed {
+		t.Error("expected pubmatic VAST modification allowed")
+	}
+	if enabled := r.ListEnabledBidders(); len(enabled) != 1 || enabled[0] != "appnexus" {
+		t.Errorf("expected only appnexus enabled, got %v", enabled)
+	}
+
+	errorCases := map[string]*StartupConfig{
+		"unknown enabled bidder":   {Enabled: []string{"missing"}},
+		"unknown bidder override":  {Bidders: map[string]AdapterConfig{"missing": {Disabled: true}}},
+		"endpoint without builder": {Bidders: map[string]AdapterConfig{"rubicon": {Endpoint: "https://x.example.com"}}},
+		"invalid demand type":      {Bidders: map[string]AdapterConfig{"pubmatic": {DemandType: "other"}}},
+		"negative rate limit":      {Bidders: map[string]AdapterConfig{"pubmatic": {RateLimits: &RateLimits{QPS: -1}}}},
+		"negative pool size":       {Bidders: map[string]AdapterConfig{"pubmatic": {Outbound: &OutboundOptions{MaxConnsPerHost: -1}}}},
+		"signing without key":      {Bidders: map[string]AdapterConfig{"pubmatic": {Outbound: &OutboundOptions{SigningScheme: SigningHMACSHA256}}}},
+		"missing client cert":      {Bidders: map[string]AdapterConfig{"pubmatic": {Outbound: &OutboundOptions{ClientCertFile: "/missing.pem", ClientKeyFile: "/missing-key.pem"}}}},
+	}
+	for name, cfg := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			if err := newRegistry().ApplyStartupConfig(cfg, builders); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	if err := newRegistry().ApplyStartupConfig(nil, nil); err != nil {
+		t.Errorf("expected nil config to be a no-op, got %v", err)
+	}
+}
@@ -2,15 +2,20 @@
 package criteo
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/thenexusengine/tne_springwire/internal/adapters"
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
 )
 
-const defaultEndpoint = "https://bidder.criteo.com/cdb"
+const (
+	bidderCode      = "criteo"
+	defaultEndpoint = "https://bidder.criteo.com/cdb"
+)
 
 // Adapter implements the Criteo bidder
 type Adapter struct {
@@ -25,11 +30,64 @@ func New(endpoint string) *Adapter {
 	return &Adapter{endpoint: endpoint}
 }
 
+// impParams are the documented imp.ext.criteo params
+type impParams struct {
+	ZoneID         int    `json:"zoneId"`
+	NetworkID      int    `json:"networkId"`
+	PublisherSubID string `json:"publisherSubId"`
+}
+
+// slotExt is the imp.ext.bidder slot shape the Criteo endpoint expects
+type slotExt struct {
+	ZoneID         int    `json:"zoneid,omitempty"`
+	NetworkID      int    `json:"networkid,omitempty"`
+	PublisherSubID string `json:"publishersubid,omitempty"`
+}
+
 // MakeRequests builds HTTP requests for Criteo
+// Each imp becomes a Criteo slot: zoneId is sent as imp.tagid and the slot params in
+// imp.ext.bidder. Imps without criteo params are sent unchanged.
 func (a *Adapter) MakeRequests(request *openrtb.BidRequest, extraInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
-	requestBody, err := json.Marshal(request)
+	var errors []error
+
+	reqCopy := *request
+	reqCopy.Imp = make([]openrtb.Imp, 0, len(request.Imp))
+	for _, imp := range request.Imp {
+		var params impParams
+		ok, err := adapters.UnmarshalBidderParams(&imp, bidderCode, &params)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		if ok {
+			if params.ZoneID == 0 && params.NetworkID == 0 {
+				errors = append(errors, fmt.Errorf("imp %s: zoneId or networkId required", imp.ID))
+				continue
+			}
+			if params.ZoneID != 0 {
+				imp.TagID = strconv.Itoa(params.ZoneID)
+			}
+			ext, err := json.Marshal(map[string]slotExt{"bidder": {
+				ZoneID:         params.ZoneID,
+				NetworkID:      params.NetworkID,
+				PublisherSubID: params.PublisherSubID,
+			}})
+			if err != nil {
+				errors = append(errors, fmt.Errorf("imp %s: %w", imp.ID, err))
+				continue
+			}
+			imp.Ext = ext
+		}
+		reqCopy.Imp = append(reqCopy.Imp, imp)
+	}
+
+	if len(reqCopy.Imp) == 0 {
+		return nil, errors
+	}
+
+	requestBody, err := json.Marshal(reqCopy)
 	if err != nil {
-		return nil, []error{fmt.Errorf("failed to marshal request: %w", err)}
+		return nil, append(errors, fmt.Errorf("failed to marshal request: %w", err))
 	}
 
 	headers := http.Header{}
@@ -38,10 +96,11 @@ func (a *Adapter) MakeRequests(request *openrtb.BidRequest, extraInfo *adapters.
 
 	return []*adapters.RequestData{
 		{Method: "POST", URI: a.endpoint, Body: requestBody, Headers: headers},
-	}, nil
+	}, errors
 }
 
 // MakeBids parses Criteo responses into bids
+// The bid type comes from bid.ext.prebid.type, falling back to the imp's media type.
 func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.ResponseData) (*adapters.BidderResponse, []error) {
 	if responseData.StatusCode == http.StatusNoContent {
 		return nil, nil
@@ -56,17 +115,30 @@ func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.R
 	}
 
 	response := &adapters.BidderResponse{Currency: bidResp.Cur, ResponseID: bidResp.ID, Bids: make([]*adapters.TypedBid, 0)}
+	impMap := adapters.BuildImpMap(request.Imp)
+	var errors []error
 	for _, seatBid := range bidResp.SeatBid {
 		for i := range seatBid.Bid {
+			bid := &seatBid.Bid[i]
+			bidType, err := adapters.BidTypeFromPrebidExt(bid, impMap)
+			if err != nil {
+				errors = append(errors, err)
+				continue
+			}
 			response.Bids = append(response.Bids, &adapters.TypedBid{
-				Bid:     &seatBid.Bid[i],
-				BidType: adapters.BidTypeBanner,
+				Bid:     bid,
+				BidType: bidType,
 			})
 		}
 	}
-	return response, nil
+	return response, errors
 }
 
+// paramsSchema is the JSON Schema for imp.ext.criteo params
+//
+//go:embed params.json
+var paramsSchema []byte
+
 // Info returns bidder information
 func Info() adapters.BidderInfo {
 	return adapters.BidderInfo{
@@ -78,11 +150,12 @@ func Info() adapters.BidderInfo {
 			Site: &adapters.PlatformInfo{MediaTypes: []adapters.BidType{adapters.BidTypeBanner, adapters.BidTypeVideo, adapters.BidTypeNative}},
 			App:  &adapters.PlatformInfo{MediaTypes: []adapters.BidType{adapters.BidTypeBanner, adapters.BidTypeVideo, adapters.BidTypeNative}},
 		},
+		ParamsSchema: paramsSchema,
 	}
 }
 
 func init() {
-	if err := adapters.RegisterAdapter("criteo", New(""), Info()); err != nil {
+	if err := adapters.RegisterAdapter(bidderCode, New(""), Info()); err != nil {
 		panic(fmt.Sprintf("failed to register criteo adapter: %v", err))
 	}
 }
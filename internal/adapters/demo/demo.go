@@ -3,6 +3,7 @@
 package demo
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"math/rand"
@@ -170,6 +171,11 @@ func (a *Adapter) Info() adapters.BidderInfo {
 	return Info()
 }
 
+// paramsSchema is the JSON Schema for imp.ext.demo params
+//
+//go:embed params.json
+var paramsSchema []byte
+
 // Info returns bidder information (package function for registration)
 func Info() adapters.BidderInfo {
 	return adapters.BidderInfo{
@@ -180,7 +186,8 @@ func Info() adapters.BidderInfo {
 			App:  &adapters.PlatformInfo{MediaTypes: []adapters.BidType{adapters.BidTypeBanner, adapters.BidTypeVideo}},
 			Site: &adapters.PlatformInfo{MediaTypes: []adapters.BidType{adapters.BidTypeBanner, adapters.BidTypeVideo}},
 		},
-		DemandType: adapters.DemandTypePlatform, // Platform demand (obfuscated as "thenexusengine")
+		DemandType:   adapters.DemandTypePlatform, // Platform demand (obfuscated as "thenexusengine")
+		ParamsSchema: paramsSchema,
 	}
 }
 
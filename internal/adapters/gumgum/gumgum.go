@@ -2,6 +2,7 @@
 package gumgum
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -48,6 +49,11 @@ func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.R
 	return response, nil
 }
 
+// paramsSchema is the JSON Schema for imp.ext.gumgum params
+//
+//go:embed params.json
+var paramsSchema []byte
+
 func Info() adapters.BidderInfo {
 	return adapters.BidderInfo{
 		Enabled: true, GVLVendorID: 61, Endpoint: defaultEndpoint,
@@ -55,6 +61,7 @@ func Info() adapters.BidderInfo {
 		Capabilities: &adapters.CapabilitiesInfo{
 			Site: &adapters.PlatformInfo{MediaTypes: []adapters.BidType{adapters.BidTypeBanner, adapters.BidTypeVideo}},
 		},
+		ParamsSchema: paramsSchema,
 	}
 }
 
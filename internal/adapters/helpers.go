@@ -214,3 +214,125 @@ func (a *SimpleAdapter) MakeBidsWithType(request *openrtb.BidRequest, responseDa
 	a.DefaultBidType = orig
 	return result, errs
 }
+
+// ImpBidderParams returns the bidder's params from imp.ext.<bidder>, or imp.ext.prebid.bidder.<bidder>
+// Returns nil when the imp carries no params for the bidder.
+func ImpBidderParams(imp *openrtb.Imp, bidderCode string) json.RawMessage {
+	if len(imp.Ext) == 0 {
+		return nil
+	}
+	var ext map[string]json.RawMessage
+	if err := json.Unmarshal(imp.Ext, &ext); err != nil {
+		return nil
+	}
+	if params, ok := ext[bidderCode]; ok {
+		return params
+	}
+
+	var prebid struct {
+		Bidder map[string]json.RawMessage `json:"bidder"`
+	}
+	if raw, ok := ext["prebid"]; ok && json.Unmarshal(raw, &prebid) == nil {
+		return prebid.Bidder[bidderCode]
+	}
+	return nil
+}
+
+// UnmarshalBidderParams decodes the imp's params for the bidder into v
+// Returns false when the imp carries no params for the bidder.
+func UnmarshalBidderParams(imp *openrtb.Imp, bidderCode string, v interface{}) (bool, error) {
+	params := ImpBidderParams(imp, bidderCode)
+	if params == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return true, fmt.Errorf("imp %s: invalid %s params: %w", imp.ID, bidderCode, err)
+	}
+	return true, nil
+}
+
+// StringOrNumber is a bidder param that may be sent as a JSON string or number
+type StringOrNumber string
+
+// UnmarshalJSON accepts a JSON string or number
+func (s *StringOrNumber) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*s = StringOrNumber(str)
+		return nil
+	}
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return fmt.Errorf("expected string or number, got %s", data)
+	}
+	*s = StringOrNumber(num.String())
+	return nil
+}
+
+// ParseBidType converts a media type name to a BidType
+func ParseBidType(mediaType string) (BidType, error) {
+	switch BidType(mediaType) {
+	case BidTypeBanner, BidTypeVideo, BidTypeAudio, BidTypeNative:
+		return BidType(mediaType), nil
+	}
+	return "", fmt.Errorf("unknown media type %q", mediaType)
+}
+
+// PrebidBidType returns the bid type an SSP set in bid.ext.prebid.type
+// Returns false when the bid carries no type.
+func PrebidBidType(bid *openrtb.Bid) (BidType, bool, error) {
+	if len(bid.Ext) == 0 {
+		return "", false, nil
+	}
+	var ext struct {
+		Prebid struct {
+			Type string `json:"type"`
+		} `json:"prebid"`
+	}
+	if err := json.Unmarshal(bid.Ext, &ext); err != nil {
+		return "", false, fmt.Errorf("bid %s: invalid ext: %w", bid.ID, err)
+	}
+	if ext.Prebid.Type == "" {
+		return "", false, nil
+	}
+	bidType, err := ParseBidType(ext.Prebid.Type)
+	if err != nil {
+		return "", false, fmt.Errorf("bid %s: %w", bid.ID, err)
+	}
+	return bidType, true, nil
+}
+
+// BidTypeFromPrebidExt returns the bid type from bid.ext.prebid.type, falling back to the imp's media type
+func BidTypeFromPrebidExt(bid *openrtb.Bid, impMap map[string]*openrtb.Imp) (BidType, error) {
+	bidType, ok, err := PrebidBidType(bid)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return GetBidTypeFromMap(bid, impMap), nil
+	}
+	return bidType, nil
+}
+
+// SetPublisherID sets site.publisher.id, or app.publisher.id, on copies so the caller's request is unchanged
+func SetPublisherID(req *openrtb.BidRequest, publisherID string) {
+	var publisher openrtb.Publisher
+	switch {
+	case req.Site != nil:
+		site := *req.Site
+		if site.Publisher != nil {
+			publisher = *site.Publisher
+		}
+		publisher.ID = publisherID
+		site.Publisher = &publisher
+		req.Site = &site
+	case req.App != nil:
+		app := *req.App
+		if app.Publisher != nil {
+			publisher = *app.Publisher
+		}
+		publisher.ID = publisherID
+		app.Publisher = &publisher
+		req.App = &app
+	}
+}
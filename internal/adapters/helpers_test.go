@@ -1,6 +1,7 @@
 package adapters
 
 import (
+	"encoding/json"
 	"errors"
 	"strings"
 	"testing"
@@ -446,3 +447,69 @@ func BenchmarkGetBidType(b *testing.B) {
 		GetBidType(bid, request)
 	}
 }
+func TestImpBidderParams(t *testing.T) {
+	tests := []struct {
+		name string
+		ext  string
+		want string
+	}{
+		{"bidder ext", `{"rubicon":{"zoneId":1}}`, `{"zoneId":1}`},
+		{"prebid bidder ext", `{"prebid":{"bidder":{"rubicon":{"zoneId":2}}}}`, `{"zoneId":2}`},
+		{"other bidder", `{"appnexus":{"placementId":1}}`, ""},
+		{"no ext", ``, ""},
+		{"invalid ext", `[1]`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imp := openrtb.Imp{ID: "1", Ext: json.RawMessage(tt.ext)}
+			if got := string(ImpBidderParams(&imp, "rubicon")); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalBidderParams(t *testing.T) {
+	var params struct {
+		SiteID StringOrNumber `json:"siteId"`
+	}
+
+	imp := openrtb.Imp{ID: "1", Ext: json.RawMessage(`{"ix":{"siteId":12345}}`)}
+	if ok, err := UnmarshalBidderParams(&imp, "ix", &params); !ok || err != nil || params.SiteID != "12345" {
+		t.Errorf("expected numeric siteId as string, got %q (%v, %v)", params.SiteID, ok, err)
+	}
+	imp.Ext = json.RawMessage(`{"ix":{"siteId":"abc"}}`)
+	if ok, err := UnmarshalBidderParams(&imp, "ix", &params); !ok || err != nil || params.SiteID != "abc" {
+		t.Errorf("expected string siteId, got %q (%v, %v)", params.SiteID, ok, err)
+	}
+	imp.Ext = json.RawMessage(`{"ix":{"siteId":true}}`)
+	if ok, err := UnmarshalBidderParams(&imp, "ix", &params); !ok || err == nil {
+		t.Errorf("expected error for boolean siteId, got %v, %v", ok, err)
+	}
+	imp.Ext = json.RawMessage(`{"appnexus":{}}`)
+	if ok, err := UnmarshalBidderParams(&imp, "ix", &params); ok || err != nil {
+		t.Errorf("expected no params, got %v, %v", ok, err)
+	}
+}
+
+func TestPrebidBidType(t *testing.T) {
+	tests := []struct {
+		ext     string
+		want    BidType
+		found   bool
+		wantErr bool
+	}{
+		{`{"prebid":{"type":"video"}}`, BidTypeVideo, true, false},
+		{`{"prebid":{"type":"native"}}`, BidTypeNative, true, false},
+		{`{"prebid":{"type":"popup"}}`, "", false, true},
+		{`{"other":1}`, "", false, false},
+		{``, "", false, false},
+		{`[1]`, "", false, true},
+	}
+	for _, tt := range tests {
+		bidType, found, err := PrebidBidType(&openrtb.Bid{ID: "b1", Ext: json.RawMessage(tt.ext)})
+		if bidType != tt.want || found != tt.found || (err != nil) != tt.wantErr {
+			t.Errorf("ext %s: got %q, %v, %v", tt.ext, bidType, found, err)
+		}
+	}
+}
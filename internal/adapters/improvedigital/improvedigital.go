@@ -2,6 +2,7 @@
 package improvedigital
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -48,6 +49,11 @@ func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.R
 	return response, nil
 }
 
+// paramsSchema is the JSON Schema for imp.ext.improvedigital params
+//
+//go:embed params.json
+var paramsSchema []byte
+
 func Info() adapters.BidderInfo {
 	return adapters.BidderInfo{
 		Enabled: true, GVLVendorID: 253, Endpoint: defaultEndpoint,
@@ -56,6 +62,7 @@ func Info() adapters.BidderInfo {
 			Site: &adapters.PlatformInfo{MediaTypes: []adapters.BidType{adapters.BidTypeBanner, adapters.BidTypeVideo, adapters.BidTypeNative}},
 			App:  &adapters.PlatformInfo{MediaTypes: []adapters.BidType{adapters.BidTypeBanner, adapters.BidTypeVideo, adapters.BidTypeNative}},
 		},
+		ParamsSchema: paramsSchema,
 	}
 }
 
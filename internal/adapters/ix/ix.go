@@ -2,15 +2,20 @@
 package ix
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/thenexusengine/tne_springwire/internal/adapters"
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
 )
 
-const defaultEndpoint = "https://htlb.casalemedia.com/openrtb/pbjs"
+const (
+	bidderCode      = "ix"
+	defaultEndpoint = "https://htlb.casalemedia.com/openrtb/pbjs"
+)
 
 // Adapter implements the Index Exchange bidder
 type Adapter struct {
@@ -25,23 +30,129 @@ func New(endpoint string) *Adapter {
 	return &Adapter{endpoint: endpoint}
 }
 
+// impParams are the documented imp.ext.ix params
+type impParams struct {
+	SiteID adapters.StringOrNumber `json:"siteId"`
+	Size   []int                   `json:"size,omitempty"`
+}
+
 // MakeRequests builds HTTP requests for Index Exchange
+// Imps are grouped into one request per siteId, sent as site.publisher.id. A size param limits
+// the banner to that size so each siteId bids on the size it was set up for. Imps without ix
+// params are sent unchanged in a request of their own.
 func (a *Adapter) MakeRequests(request *openrtb.BidRequest, extraInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	var errors []error
+	var siteIDs []string
+	impsBySite := make(map[string][]openrtb.Imp)
+	var passthrough []openrtb.Imp
+
+	for _, imp := range request.Imp {
+		var params impParams
+		ok, err := adapters.UnmarshalBidderParams(&imp, bidderCode, &params)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		if !ok {
+			passthrough = append(passthrough, imp)
+			continue
+		}
+
+		shaped, err := shapeImp(imp, params)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		siteID := string(params.SiteID)
+		if _, seen := impsBySite[siteID]; !seen {
+			siteIDs = append(siteIDs, siteID)
+		}
+		impsBySite[siteID] = append(impsBySite[siteID], shaped)
+	}
+
+	var requests []*adapters.RequestData
+	for _, siteID := range siteIDs {
+		reqCopy := *request
+		reqCopy.Imp = impsBySite[siteID]
+		adapters.SetPublisherID(&reqCopy, siteID)
+		reqData, err := a.requestData(&reqCopy)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		requests = append(requests, reqData)
+	}
+	if len(passthrough) > 0 {
+		reqCopy := *request
+		reqCopy.Imp = passthrough
+		reqData, err := a.requestData(&reqCopy)
+		if err != nil {
+			errors = append(errors, err)
+		} else {
+			requests = append(requests, reqData)
+		}
+	}
+	return requests, errors
+}
+
+func (a *Adapter) requestData(request *openrtb.BidRequest) (*adapters.RequestData, error) {
 	requestBody, err := json.Marshal(request)
 	if err != nil {
-		return nil, []error{fmt.Errorf("failed to marshal request: %w", err)}
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	headers := http.Header{}
 	headers.Set("Content-Type", "application/json;charset=utf-8")
 	headers.Set("Accept", "application/json")
 
-	return []*adapters.RequestData{
-		{Method: "POST", URI: a.endpoint, Body: requestBody, Headers: headers},
-	}, nil
+	return &adapters.RequestData{Method: "POST", URI: a.endpoint, Body: requestBody, Headers: headers}, nil
+}
+
+// shapeImp normalizes the params and applies the size param to the banner
+func shapeImp(imp openrtb.Imp, params impParams) (openrtb.Imp, error) {
+	params.SiteID = adapters.StringOrNumber(strings.TrimSpace(string(params.SiteID)))
+	if params.SiteID == "" {
+		return imp, fmt.Errorf("imp %s: siteId required", imp.ID)
+	}
+
+	if len(params.Size) > 0 {
+		if len(params.Size) != 2 {
+			return imp, fmt.Errorf("imp %s: size must be [w, h]", imp.ID)
+		}
+		if imp.Banner != nil {
+			w, h := params.Size[0], params.Size[1]
+			if !bannerHasSize(imp.Banner, w, h) {
+				return imp, fmt.Errorf("imp %s: size %dx%d not in banner sizes", imp.ID, w, h)
+			}
+			banner := *imp.Banner
+			banner.W, banner.H = w, h
+			banner.Format = []openrtb.Format{{W: w, H: h}}
+			imp.Banner = &banner
+		}
+	}
+
+	ext, err := json.Marshal(map[string]impParams{bidderCode: params})
+	if err != nil {
+		return imp, fmt.Errorf("imp %s: %w", imp.ID, err)
+	}
+	imp.Ext = ext
+	return imp, nil
+}
+
+func bannerHasSize(banner *openrtb.Banner, w, h int) bool {
+	if banner.W == w && banner.H == h {
+		return true
+	}
+	for _, format := range banner.Format {
+		if format.W == w && format.H == h {
+			return true
+		}
+	}
+	return false
 }
 
 // MakeBids parses Index Exchange responses into bids
+// The bid type comes from bid.ext.prebid.type, falling back to the imp's media type.
 func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.ResponseData) (*adapters.BidderResponse, []error) {
 	if responseData.StatusCode == http.StatusNoContent {
 		return nil, nil
@@ -59,18 +170,29 @@ func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.R
 
 	// P3-2: Use shared helper for O(1) bid type lookup
 	impMap := adapters.BuildImpMap(request.Imp)
+	var errors []error
 	for _, seatBid := range bidResp.SeatBid {
 		for i := range seatBid.Bid {
 			bid := &seatBid.Bid[i]
+			bidType, err := adapters.BidTypeFromPrebidExt(bid, impMap)
+			if err != nil {
+				errors = append(errors, err)
+				continue
+			}
 			response.Bids = append(response.Bids, &adapters.TypedBid{
 				Bid:     bid,
-				BidType: adapters.GetBidTypeFromMap(bid, impMap),
+				BidType: bidType,
 			})
 		}
 	}
-	return response, nil
+	return response, errors
 }
 
+// paramsSchema is the JSON Schema for imp.ext.ix params
+//
+//go:embed params.json
+var paramsSchema []byte
+
 // Info returns bidder information
 func Info() adapters.BidderInfo {
 	return adapters.BidderInfo{
@@ -82,11 +204,12 @@ func Info() adapters.BidderInfo {
 			Site: &adapters.PlatformInfo{MediaTypes: []adapters.BidType{adapters.BidTypeBanner, adapters.BidTypeVideo, adapters.BidTypeNative}},
 			App:  &adapters.PlatformInfo{MediaTypes: []adapters.BidType{adapters.BidTypeBanner, adapters.BidTypeVideo, adapters.BidTypeNative}},
 		},
+		ParamsSchema: paramsSchema,
 	}
 }
 
 func init() {
-	if err := adapters.RegisterAdapter("ix", New(""), Info()); err != nil {
+	if err := adapters.RegisterAdapter(bidderCode, New(""), Info()); err != nil {
 		panic(fmt.Sprintf("failed to register ix adapter: %v", err))
 	}
 }
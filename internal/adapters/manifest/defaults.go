@@ -0,0 +1,6 @@
+package manifest
+
+// DefaultEnabled lists the bidders enabled when the adapter config sets no enabled list
+// They are the adapters the server registered before the manifest; every other compiled-in
+// adapter stays disabled until ADAPTERS_ENABLED or the config file's "enabled" list names it.
+var DefaultEnabled = []string{"appnexus", "demo", "pubmatic", "rubicon"}
@@ -0,0 +1,5 @@
+// Package manifest imports every bidder adapter package so the server binary registers them all
+// manifest.go is generated; add an adapter package and re-run go generate to include it.
+package manifest
+
+//go:generate go run gen.go
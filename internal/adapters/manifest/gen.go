@@ -0,0 +1,168 @@
+//go:build ignore
+
+// gen.go writes manifest.go, which imports every adapter package under internal/adapters
+// Run with: go generate ./internal/adapters/manifest
+//
+// An adapter package is included when it has New(endpoint string), Info() and an init that
+// calls adapters.RegisterAdapter with a literal or constant bidder code.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const modulePath = "github.com/thenexusengine/tne_springwire/internal/adapters"
+
+type adapterPackage struct {
+	Dir        string
+	Name       string
+	BidderCode string
+}
+
+var manifestTemplate = template.Must(template.New("manifest").Parse(`// Code generated by gen.go; DO NOT EDIT.
+
+package manifest
+
+import (
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+{{- range .}}
+	{{if ne .Name .Dir}}{{.Name}} {{end}}"` + modulePath + `/{{.Dir}}"
+{{- end}}
+)
+
+// Builders maps each compiled-in bidder code to its adapter constructor
+// Importing this package registers every adapter with adapters.DefaultRegistry.
+var Builders = map[string]adapters.Builder{
+{{- range .}}
+	"{{.BidderCode}}": func(endpoint string) adapters.Adapter { return {{.Name}}.New(endpoint) },
+{{- end}}
+}
+`))
+
+func main() {
+	entries, err := os.ReadDir("..")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var pkgs []adapterPackage
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == "manifest" {
+			continue
+		}
+		pkg, ok, err := inspect(filepath.Join("..", entry.Name()))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if ok {
+			pkg.Dir = entry.Name()
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].BidderCode < pkgs[j].BidderCode })
+
+	var buf bytes.Buffer
+	if err := manifestTemplate.Execute(&buf, pkgs); err != nil {
+		log.Fatal(err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("manifest.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("manifest.go: %d adapters\n", len(pkgs))
+}
+
+// inspect reports whether the directory holds a registrable adapter package
+func inspect(dir string) (adapterPackage, bool, error) {
+	fset := token.NewFileSet()
+	pkgMap, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return adapterPackage{}, false, err
+	}
+
+	for name, pkg := range pkgMap {
+		consts := make(map[string]string)
+		var hasNew, hasInfo bool
+		var codeExpr ast.Expr
+
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				switch d := decl.(type) {
+				case *ast.GenDecl:
+					if d.Tok != token.CONST {
+						continue
+					}
+					for _, spec := range d.Specs {
+						vs := spec.(*ast.ValueSpec)
+						for i, ident := range vs.Names {
+							if i < len(vs.Values) {
+								if lit, ok := vs.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+									consts[ident.Name], _ = strconv.Unquote(lit.Value)
+								}
+							}
+						}
+					}
+				case *ast.FuncDecl:
+					if d.Recv != nil {
+						continue
+					}
+					switch d.Name.Name {
+					case "New":
+						params := d.Type.Params.List
+						if len(params) == 1 && len(params[0].Names) <= 1 {
+							if ident, ok := params[0].Type.(*ast.Ident); ok && ident.Name == "string" {
+								hasNew = true
+							}
+						}
+					case "Info":
+						hasInfo = d.Type.Params.NumFields() == 0
+					case "init":
+						ast.Inspect(d.Body, func(n ast.Node) bool {
+							call, ok := n.(*ast.CallExpr)
+							if !ok || len(call.Args) == 0 {
+								return true
+							}
+							if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "RegisterAdapter" {
+								codeExpr = call.Args[0]
+							}
+							return true
+						})
+					}
+				}
+			}
+		}
+
+		if !hasNew || !hasInfo || codeExpr == nil {
+			continue
+		}
+		var code string
+		switch e := codeExpr.(type) {
+		case *ast.BasicLit:
+			code, _ = strconv.Unquote(e.Value)
+		case *ast.Ident:
+			code = consts[e.Name]
+		}
+		if code == "" {
+			return adapterPackage{}, false, fmt.Errorf("%s: cannot resolve bidder code", dir)
+		}
+		return adapterPackage{Name: name, BidderCode: code}, true, nil
+	}
+	return adapterPackage{}, false, nil
+}
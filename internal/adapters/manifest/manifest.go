@@ -0,0 +1,58 @@
+// Code generated by gen.go; DO NOT EDIT.
+
+package manifest
+
+import (
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	thirtythreeacross "github.com/thenexusengine/tne_springwire/internal/adapters/33across"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/adform"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/appnexus"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/beachfront"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/conversant"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/criteo"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/demo"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/gumgum"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/improvedigital"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/ix"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/medianet"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/openx"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/outbrain"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/pubmatic"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/rubicon"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/sharethrough"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/smartadserver"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/sovrn"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/spotx"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/taboola"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/teads"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/triplelift"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/unruly"
+)
+
+// Builders maps each compiled-in bidder code to its adapter constructor
+// Importing this package registers every adapter with adapters.DefaultRegistry.
+var Builders = map[string]adapters.Builder{
+	"33across":       func(endpoint string) adapters.Adapter { return thirtythreeacross.New(endpoint) },
+	"adform":         func(endpoint string) adapters.Adapter { return adform.New(endpoint) },
+	"appnexus":       func(endpoint string) adapters.Adapter { return appnexus.New(endpoint) },
+	"beachfront":     func(endpoint string) adapters.Adapter { return beachfront.New(endpoint) },
+	"conversant":     func(endpoint string) adapters.Adapter { return conversant.New(endpoint) },
+	"criteo":         func(endpoint string) adapters.Adapter { return criteo.New(endpoint) },
+	"demo":           func(endpoint string) adapters.Adapter { return demo.New(endpoint) },
+	"gumgum":         func(endpoint string) adapters.Adapter { return gumgum.New(endpoint) },
+	"improvedigital": func(endpoint string) adapters.Adapter { return improvedigital.New(endpoint) },
+	"ix":             func(endpoint string) adapters.Adapter { return ix.New(endpoint) },
+	"medianet":       func(endpoint string) adapters.Adapter { return medianet.New(endpoint) },
+	"openx":          func(endpoint string) adapters.Adapter { return openx.New(endpoint) },
+	"outbrain":       func(endpoint string) adapters.Adapter { return outbrain.New(endpoint) },
+	"pubmatic":       func(endpoint string) adapters.Adapter { return pubmatic.New(endpoint) },
+	"rubicon":        func(endpoint string) adapters.Adapter { return rubicon.New(endpoint) },
+	"sharethrough":   func(endpoint string) adapters.Adapter { return sharethrough.New(endpoint) },
+	"smartadserver":  func(endpoint string) adapters.Adapter { return smartadserver.New(endpoint) },
+	"sovrn":          func(endpoint string) adapters.Adapter { return sovrn.New(endpoint) },
+	"spotx":          func(endpoint string) adapters.Adapter { return spotx.New(endpoint) },
+	"taboola":        func(endpoint string) adapters.Adapter { return taboola.New(endpoint) },
+	"teads":          func(endpoint string) adapters.Adapter { return teads.New(endpoint) },
+	"triplelift":     func(endpoint string) adapters.Adapter { return triplelift.New(endpoint) },
+	"unruly":         func(endpoint string) adapters.Adapter { return unruly.New(endpoint) },
+}
@@ -0,0 +1,72 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+)
+
+func TestBuildersRegistered(t *testing.T) {
+	for code, build := range Builders {
+		if _, ok := adapters.DefaultRegistry.Get(code); !ok {
+			t.Errorf("%s is in the manifest but not registered", code)
+		}
+		if build("https://override.example.com") == nil {
+			t.Errorf("%s builder returned nil", code)
+		}
+	}
+}
+
+func TestDefaultEnabledCompiled(t *testing.T) {
+	for _, code := range DefaultEnabled {
+		if _, ok := Builders[code]; !ok {
+			t.Errorf("default-enabled bidder %s is not in the manifest", code)
+		}
+	}
+}
+
+// TestParamsSchemas checks every compiled-in adapter ships a params schema; registration compiles it
+func TestParamsSchemas(t *testing.T) {
+	schemas := adapters.DefaultRegistry.ParamsSchemas()
+	for code := range Builders {
+		if _, ok := schemas[code]; !ok {
+			t.Errorf("%s has no params schema", code)
+		}
+	}
+}
+
+// TestManifestUpToDate fails when an adapter package is missing from the generated manifest
+func TestManifestUpToDate(t *testing.T) {
+	entries, err := os.ReadDir("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var packages int
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == "manifest" {
+			continue
+		}
+		files, _ := filepath.Glob(filepath.Join("..", entry.Name(), "*.go"))
+		for _, f := range files {
+			if strings.HasSuffix(f, "_test.go") {
+				continue
+			}
+			src, err := os.ReadFile(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(src), "adapters.RegisterAdapter(") {
+				packages++
+				break
+			}
+		}
+	}
+
+	if packages != len(Builders) {
+		t.Errorf("found %d adapter packages but the manifest has %d; run go generate ./internal/adapters/manifest", packages, len(Builders))
+	}
+}
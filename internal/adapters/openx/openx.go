@@ -2,15 +2,21 @@
 package openx
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/thenexusengine/tne_springwire/internal/adapters"
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
 )
 
-const defaultEndpoint = "https://rtb.openx.net/openrtb/prebid"
+const (
+	bidderCode      = "openx"
+	defaultEndpoint = "https://rtb.openx.net/openrtb/prebid"
+	bidderConfig    = "hb_pbs_1.0.0" // identifies server-side header bidding traffic to OpenX
+)
 
 // Adapter implements the OpenX bidder
 type Adapter struct {
@@ -25,23 +31,138 @@ func New(endpoint string) *Adapter {
 	return &Adapter{endpoint: endpoint}
 }
 
+// impParams are the documented imp.ext.openx params
+type impParams struct {
+	Unit         adapters.StringOrNumber `json:"unit"`
+	DelDomain    string                  `json:"delDomain"`
+	Platform     string                  `json:"platform"`
+	CustomFloor  float64                 `json:"customFloor"`
+	CustomParams json.RawMessage         `json:"customParams"`
+}
+
+// impExt is the imp.ext shape the OpenX endpoint expects
+type impExt struct {
+	CustomParams json.RawMessage `json:"customParams,omitempty"`
+}
+
 // MakeRequests builds HTTP requests for OpenX
+// The ad unit is sent as imp.tagid, customFloor as the imp floor and delDomain/platform in
+// request.ext. Banner imps share one request; each video imp is sent on its own.
+// Imps without openx params are sent unchanged with the banner imps.
 func (a *Adapter) MakeRequests(request *openrtb.BidRequest, extraInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
-	requestBody, err := json.Marshal(request)
+	var errors []error
+	var bannerImps, videoImps []openrtb.Imp
+	var delDomain, platform string
+
+	for _, imp := range request.Imp {
+		var params impParams
+		ok, err := adapters.UnmarshalBidderParams(&imp, bidderCode, &params)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		if ok {
+			if imp, err = shapeImp(imp, params); err != nil {
+				errors = append(errors, err)
+				continue
+			}
+			if delDomain == "" && platform == "" {
+				delDomain, platform = params.DelDomain, params.Platform
+			}
+		}
+
+		if imp.Video != nil {
+			videoImps = append(videoImps, imp)
+		} else {
+			bannerImps = append(bannerImps, imp)
+		}
+	}
+
+	reqExt, err := requestExt(request.Ext, delDomain, platform)
 	if err != nil {
-		return nil, []error{fmt.Errorf("failed to marshal request: %w", err)}
+		return nil, append(errors, err)
+	}
+
+	var requests []*adapters.RequestData
+	groups := make([][]openrtb.Imp, 0, 1+len(videoImps))
+	if len(bannerImps) > 0 {
+		groups = append(groups, bannerImps)
+	}
+	for _, imp := range videoImps {
+		groups = append(groups, []openrtb.Imp{imp})
+	}
+	for _, imps := range groups {
+		reqCopy := *request
+		reqCopy.Imp = imps
+		reqCopy.Ext = reqExt
+
+		requestBody, err := json.Marshal(reqCopy)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("failed to marshal request: %w", err))
+			continue
+		}
+
+		headers := http.Header{}
+		headers.Set("Content-Type", "application/json;charset=utf-8")
+		headers.Set("Accept", "application/json")
+
+		requests = append(requests, &adapters.RequestData{Method: "POST", URI: a.endpoint, Body: requestBody, Headers: headers})
+	}
+	return requests, errors
+}
+
+// shapeImp maps documented params onto the imp fields OpenX reads
+func shapeImp(imp openrtb.Imp, params impParams) (openrtb.Imp, error) {
+	unit := strings.TrimSpace(string(params.Unit))
+	if unit == "" {
+		return imp, fmt.Errorf("imp %s: unit required", imp.ID)
+	}
+	if params.DelDomain == "" && params.Platform == "" {
+		return imp, fmt.Errorf("imp %s: delDomain or platform required", imp.ID)
+	}
+	imp.TagID = unit
+
+	if imp.BidFloor <= 0 && params.CustomFloor > 0 {
+		imp.BidFloor = params.CustomFloor
+		imp.BidFloorCur = "USD"
+	}
+
+	imp.Ext = nil
+	if len(params.CustomParams) > 0 {
+		ext, err := json.Marshal(impExt{CustomParams: params.CustomParams})
+		if err != nil {
+			return imp, fmt.Errorf("imp %s: %w", imp.ID, err)
+		}
+		imp.Ext = ext
 	}
+	return imp, nil
+}
 
-	headers := http.Header{}
-	headers.Set("Content-Type", "application/json;charset=utf-8")
-	headers.Set("Accept", "application/json")
+// requestExt adds the OpenX delivery domain or platform and bidder code to request.ext
+func requestExt(ext json.RawMessage, delDomain, platform string) (json.RawMessage, error) {
+	if delDomain == "" && platform == "" {
+		return ext, nil
+	}
 
-	return []*adapters.RequestData{
-		{Method: "POST", URI: a.endpoint, Body: requestBody, Headers: headers},
-	}, nil
+	fields := make(map[string]json.RawMessage)
+	if len(ext) > 0 {
+		if err := json.Unmarshal(ext, &fields); err != nil {
+			return nil, fmt.Errorf("invalid request ext: %w", err)
+		}
+	}
+	set := func(key, value string) {
+		if value != "" {
+			fields[key], _ = json.Marshal(value)
+		}
+	}
+	set("delDomain", delDomain)
+	set("platform", platform)
+	set("bc", bidderConfig)
+	return json.Marshal(fields)
 }
 
 // MakeBids parses OpenX responses into bids
+// The bid type comes from bid.ext.prebid.type, falling back to the imp's media type.
 func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.ResponseData) (*adapters.BidderResponse, []error) {
 	if responseData.StatusCode == http.StatusNoContent {
 		return nil, nil
@@ -59,18 +180,29 @@ func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.R
 
 	// P3-2: Use shared helper for O(1) bid type lookup
 	impMap := adapters.BuildImpMap(request.Imp)
+	var errors []error
 	for _, seatBid := range bidResp.SeatBid {
 		for i := range seatBid.Bid {
 			bid := &seatBid.Bid[i]
+			bidType, err := adapters.BidTypeFromPrebidExt(bid, impMap)
+			if err != nil {
+				errors = append(errors, err)
+				continue
+			}
 			response.Bids = append(response.Bids, &adapters.TypedBid{
 				Bid:     bid,
-				BidType: adapters.GetBidTypeFromMap(bid, impMap),
+				BidType: bidType,
 			})
 		}
 	}
-	return response, nil
+	return response, errors
 }
 
+// paramsSchema is the JSON Schema for imp.ext.openx params
+//
+//go:embed params.json
+var paramsSchema []byte
+
 // Info returns bidder information
 func Info() adapters.BidderInfo {
 	return adapters.BidderInfo{
@@ -82,11 +214,12 @@ func Info() adapters.BidderInfo {
 			Site: &adapters.PlatformInfo{MediaTypes: []adapters.BidType{adapters.BidTypeBanner, adapters.BidTypeVideo}},
 			App:  &adapters.PlatformInfo{MediaTypes: []adapters.BidType{adapters.BidTypeBanner, adapters.BidTypeVideo}},
 		},
+		ParamsSchema: paramsSchema,
 	}
 }
 
 func init() {
-	if err := adapters.RegisterAdapter("openx", New(""), Info()); err != nil {
+	if err := adapters.RegisterAdapter(bidderCode, New(""), Info()); err != nil {
 		panic(fmt.Sprintf("failed to register openx adapter: %v", err))
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"strings"
 	"sync"
@@ -14,6 +15,8 @@ import (
 
 	"github.com/thenexusengine/tne_springwire/internal/adapters"
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/secrets"
+	"github.com/thenexusengine/tne_springwire/pkg/vast"
 )
 
 // BidderConfig represents a configurable bidder definition.
@@ -45,11 +48,29 @@ type EndpointConfig struct {
 	ProtocolVersion string            `json:"protocol_version"`
 	AuthType        string            `json:"auth_type"`
 	AuthUsername    string            `json:"auth_username"`
-	AuthPassword    string            `json:"auth_password"`
-	AuthToken       string            `json:"auth_token"`
+	AuthPassword    string            `json:"auth_password"` // Plaintext or secret://name
+	AuthToken       string            `json:"auth_token"`    // Plaintext or secret://name
 	AuthHeaderName  string            `json:"auth_header_name"`
-	AuthHeaderValue string            `json:"auth_header_value"`
-	CustomHeaders   map[string]string `json:"custom_headers"`
+	AuthHeaderValue string            `json:"auth_header_value"` // Plaintext or secret://name
+	CustomHeaders   map[string]string `json:"custom_headers"`    // Values may be secret://name
+
+	// Outbound transport options
+	GzipRequests        bool `json:"gzip_requests"`           // Gzip request bodies
+	AcceptGzip          bool `json:"accept_gzip"`             // Ask for gzip responses
+	HTTP2               bool `json:"http2"`                   // Negotiate HTTP/2 where supported
+	MaxConnsPerHost     int  `json:"max_conns_per_host"`      // 0 = shared pool default
+	MaxIdleConnsPerHost int  `json:"max_idle_conns_per_host"` // 0 = shared pool default
+
+	// Mutual TLS (PEM files)
+	ClientCertFile string `json:"client_cert_file"` // Client certificate presented to the bidder
+	ClientKeyFile  string `json:"client_key_file"`  // Private key of the client certificate
+	CAFile         string `json:"ca_file"`          // CAs the bidder's certificate is verified against (empty = system roots)
+
+	// Request signing
+	SigningScheme          string `json:"signing_scheme"`           // "hmac-sha256", or empty for unsigned requests
+	SigningKey             string `json:"signing_key"`              // Plaintext or secret://name
+	SigningHeader          string `json:"signing_header"`           // Default X-Signature
+	SigningTimestampHeader string `json:"signing_timestamp_header"` // Default X-Signature-Timestamp
 }
 
 // CapabilitiesConfig holds capability information
@@ -98,9 +119,15 @@ type SChainAugmentConfig struct {
 }
 
 // RequestTransformConfig holds request transformation rules
+// The field rules run on the request JSON after the ext templates and schain augmentation:
+// mappings, then additions, then removals. See transform.go for the field path syntax.
 type RequestTransformConfig struct {
-	FieldMappings      map[string]string      `json:"field_mappings"`
-	FieldAdditions     map[string]interface{} `json:"field_additions"`
+	// FieldMappings moves values from the source path (key) to the destination path (value)
+	FieldMappings map[string]string `json:"field_mappings"`
+	// FieldAdditions sets paths to values; strings may copy values with {{path}} macros,
+	// including {{publisher.bidder_params.<key>}} from the publisher's stored params
+	FieldAdditions map[string]interface{} `json:"field_additions"`
+	// FieldRemovals deletes paths
 	FieldRemovals      []string               `json:"field_removals"`
 	ImpExtTemplate     map[string]interface{} `json:"imp_ext_template"`
 	RequestExtTemplate map[string]interface{} `json:"request_ext_template"`
@@ -112,17 +139,21 @@ type RequestTransformConfig struct {
 
 // ResponseTransformConfig holds response transformation rules
 type ResponseTransformConfig struct {
-	BidFieldMappings        map[string]string `json:"bid_field_mappings"`
-	PriceAdjustment         float64           `json:"price_adjustment"`
-	CurrencyConversion      bool              `json:"currency_conversion"`
+	// BidFieldMappings moves values within each bid, from the source path (key) to the destination path (value)
+	BidFieldMappings   map[string]string `json:"bid_field_mappings"`
+	PriceAdjustment    float64           `json:"price_adjustment"`
+	CurrencyConversion bool              `json:"currency_conversion"`
+	// CreativeTypeMappings maps the bidder's creative type in bid.ext.prebid.type to
+	// banner, video, native or audio (bids without a known type use the imp's media type)
 	CreativeTypeMappings    map[string]string `json:"creative_type_mappings"`
 	ExtractDurationFromVAST bool              `json:"extract_duration_from_vast"`
 }
 
 // GenericAdapter implements the Adapter interface for dynamic bidders
 type GenericAdapter struct {
-	config *BidderConfig
-	mu     sync.RWMutex
+	config  *BidderConfig
+	secrets *secrets.Resolver // resolves secret:// credentials (nil = plaintext only)
+	mu      sync.RWMutex
 }
 
 // New creates a new generic adapter with the given configuration
@@ -132,6 +163,16 @@ func New(config *BidderConfig) *GenericAdapter {
 	}
 }
 
+// NewWithSecrets creates a generic adapter whose credentials may be secret:// references
+// References are resolved through the resolver when requests are built, so a rotated secret
+// is used once the resolver's cache expires.
+func NewWithSecrets(config *BidderConfig, resolver *secrets.Resolver) *GenericAdapter {
+	return &GenericAdapter{
+		config:  config,
+		secrets: resolver,
+	}
+}
+
 // UpdateConfig updates the adapter configuration (thread-safe)
 func (a *GenericAdapter) UpdateConfig(config *BidderConfig) {
 	a.mu.Lock()
@@ -139,11 +180,11 @@ func (a *GenericAdapter) UpdateConfig(config *BidderConfig) {
 	a.config = config
 }
 
-// GetConfig returns the current configuration (thread-safe)
+// GetConfig returns a copy of the current configuration with credentials redacted (thread-safe)
 func (a *GenericAdapter) GetConfig() *BidderConfig {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
-	return a.config
+	return a.config.Redacted()
 }
 
 // GetDemandType returns the demand type for this adapter (platform or publisher)
@@ -179,8 +220,23 @@ func (a *GenericAdapter) MakeRequests(request *openrtb.BidRequest, extraInfo *ad
 		return nil, []error{fmt.Errorf("failed to marshal request: %w", err)}
 	}
 
-	// Build headers
-	headers := a.buildHeaders(config)
+	// Apply field mappings, additions and removals
+	if config.RequestTransform.hasFieldRules() {
+		var publisherParams map[string]interface{}
+		if extraInfo != nil {
+			publisherParams = extraInfo.PublisherBidderParams
+		}
+		var errs []error
+		requestBody, errs = applyFieldRules(requestBody, &config.RequestTransform, publisherParams)
+		errors = append(errors, errs...)
+	}
+
+	// Build headers with secret:// credentials resolved
+	resolved, err := a.resolveCredentials(config)
+	if err != nil {
+		return nil, append(errors, err)
+	}
+	headers := a.buildHeaders(resolved)
 
 	return []*adapters.RequestData{
 		{
@@ -213,9 +269,16 @@ func (a *GenericAdapter) MakeBids(request *openrtb.BidRequest, responseData *ada
 		return nil, []error{fmt.Errorf("unexpected status from %s: %d", config.BidderCode, responseData.StatusCode)}
 	}
 
+	// Move non-standard bid fields into place before parsing
+	body := responseData.Body
+	var errs []error
+	if len(config.ResponseTransform.BidFieldMappings) > 0 {
+		body, errs = applyBidFieldMappings(body, config.ResponseTransform.BidFieldMappings)
+	}
+
 	// Parse response
 	var bidResp openrtb.BidResponse
-	if err := json.Unmarshal(responseData.Body, &bidResp); err != nil {
+	if err := json.Unmarshal(body, &bidResp); err != nil {
 		return nil, []error{fmt.Errorf("failed to parse response from %s: %w", config.BidderCode, err)}
 	}
 
@@ -237,14 +300,70 @@ func (a *GenericAdapter) MakeBids(request *openrtb.BidRequest, responseData *ada
 			// Apply response transformations
 			a.transformBid(bid, config)
 
-			response.Bids = append(response.Bids, &adapters.TypedBid{
+			typedBid := &adapters.TypedBid{
 				Bid:     bid,
 				BidType: adapters.GetBidTypeFromMap(bid, impMap),
-			})
+			}
+			if len(config.ResponseTransform.CreativeTypeMappings) > 0 {
+				if bidType, ok := creativeBidType(bid, config.ResponseTransform.CreativeTypeMappings); ok {
+					typedBid.BidType = bidType
+				}
+			}
+			if config.ResponseTransform.ExtractDurationFromVAST && typedBid.BidType == adapters.BidTypeVideo {
+				typedBid.BidVideo = bidVideoFromVAST(bid)
+			}
+			response.Bids = append(response.Bids, typedBid)
 		}
 	}
 
-	return response, nil
+	return response, errs
+}
+
+// creativeBidType maps the creative type the bidder set in bid.ext.prebid.type to a bid type
+// The type may be a string or a number (e.g. an OpenRTB mtype moved there). Types without a
+// mapping are used as is when they name a media type.
+func creativeBidType(bid *openrtb.Bid, mappings map[string]string) (adapters.BidType, bool) {
+	if len(bid.Ext) == 0 {
+		return "", false
+	}
+	var ext struct {
+		Prebid struct {
+			Type json.RawMessage `json:"type"`
+		} `json:"prebid"`
+	}
+	if err := json.Unmarshal(bid.Ext, &ext); err != nil || len(ext.Prebid.Type) == 0 {
+		return "", false
+	}
+	creativeType := strings.Trim(string(ext.Prebid.Type), `"`)
+	if mapped, ok := mappings[creativeType]; ok {
+		creativeType = mapped
+	}
+	bidType, err := adapters.ParseBidType(strings.ToLower(creativeType))
+	if err != nil {
+		return "", false
+	}
+	return bidType, true
+}
+
+// bidVideoFromVAST reads the duration of a video bid from its VAST markup
+// The primary category is the bid's first IAB category. Returns nil when the markup has
+// no duration (e.g. a wrapper or a nurl-only bid) and the bid has no category.
+func bidVideoFromVAST(bid *openrtb.Bid) *adapters.BidVideo {
+	video := &adapters.BidVideo{}
+	if vast.IsVAST(bid.AdM) {
+		if doc, err := vast.Parse(bid.AdM); err == nil {
+			if d, ok := doc.Duration(); ok {
+				video.Duration = int(math.Round(d.Seconds()))
+			}
+		}
+	}
+	if len(bid.Cat) > 0 {
+		video.PrimaryCategory = bid.Cat[0]
+	}
+	if video.Duration == 0 && video.PrimaryCategory == "" {
+		return nil
+	}
+	return video
 }
 
 // transformRequest applies request transformations
@@ -442,7 +561,10 @@ func (a *GenericAdapter) Info() adapters.BidderInfo {
 	}
 
 	// Build capabilities
-	info.Capabilities = &adapters.CapabilitiesInfo{}
+	info.Capabilities = &adapters.CapabilitiesInfo{
+		VideoMimes:     config.Capabilities.VideoMimes,
+		VideoProtocols: config.Capabilities.VideoProtocols,
+	}
 
 	mediaTypes := make([]adapters.BidType, 0)
 	for _, mt := range config.Capabilities.MediaTypes {
@@ -487,6 +609,40 @@ func (a *GenericAdapter) GetTimeout() time.Duration {
 	return time.Duration(a.config.Endpoint.TimeoutMS) * time.Millisecond
 }
 
+// GetRateLimits returns the bidder's configured rate limits for the exchange to enforce
+func (a *GenericAdapter) GetRateLimits() adapters.RateLimits {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return adapters.RateLimits{
+		QPS:        a.config.RateLimits.QPSLimit,
+		Daily:      a.config.RateLimits.DailyLimit,
+		Concurrent: a.config.RateLimits.ConcurrentLimit,
+	}
+}
+
+// GetOutboundOptions returns the bidder's compression, HTTP/2, connection pool, mTLS and signing settings
+// A secret:// signing key is resolved here; when it cannot be resolved the key is left empty,
+// so requests fail instead of being sent unsigned.
+func (a *GenericAdapter) GetOutboundOptions() adapters.OutboundOptions {
+	a.mu.RLock()
+	endpoint := a.config.Endpoint
+	a.mu.RUnlock()
+	return adapters.OutboundOptions{
+		GzipRequests:           endpoint.GzipRequests,
+		AcceptGzip:             endpoint.AcceptGzip,
+		HTTP2:                  endpoint.HTTP2,
+		MaxConnsPerHost:        endpoint.MaxConnsPerHost,
+		MaxIdleConnsPerHost:    endpoint.MaxIdleConnsPerHost,
+		ClientCertFile:         endpoint.ClientCertFile,
+		ClientKeyFile:          endpoint.ClientKeyFile,
+		CAFile:                 endpoint.CAFile,
+		SigningScheme:          endpoint.SigningScheme,
+		SigningKey:             a.resolveSigningKey(endpoint.SigningKey),
+		SigningHeader:          endpoint.SigningHeader,
+		SigningTimestampHeader: endpoint.SigningTimestampHeader,
+	}
+}
+
 // GetGVLVendorID returns the Global Vendor List ID for TCF consent checking
 func (a *GenericAdapter) GetGVLVendorID() int {
 	a.mu.RLock()
@@ -361,6 +361,48 @@ func TestGenericAdapter_MakeBids_BidType(t *testing.T) {
 	}
 }
 
+func TestGenericAdapter_MakeBids_ExtractDurationFromVAST(t *testing.T) {
+	inline := `<VAST version="3.0"><Ad><InLine><AdSystem>dsp</AdSystem><Impression>https://dsp.example.com/imp</Impression>` +
+		`<Creatives><Creative><Linear><Duration>00:00:29.600</Duration><MediaFiles><MediaFile>https://cdn.example.com/a.mp4</MediaFile></MediaFiles></Linear></Creative></Creatives></InLine></Ad></VAST>`
+
+	request := &openrtb.BidRequest{
+		ID:  "test-1",
+		Imp: []openrtb.Imp{{ID: "imp-video", Video: &openrtb.Video{}}, {ID: "imp-banner", Banner: &openrtb.Banner{}}},
+	}
+	respBody, _ := json.Marshal(openrtb.BidResponse{
+		SeatBid: []openrtb.SeatBid{{Bid: []openrtb.Bid{
+			{ID: "inline", ImpID: "imp-video", Price: 2.0, AdM: inline, Cat: []string{"IAB1-5", "IAB2"}},
+			{ID: "nurl", ImpID: "imp-video", Price: 2.0, NURL: "https://dsp.example.com/vast"},
+			{ID: "banner", ImpID: "imp-banner", Price: 1.0, AdM: "<div></div>", Cat: []string{"IAB3"}},
+		}}},
+	})
+	responseData := &adapters.ResponseData{StatusCode: http.StatusOK, Body: respBody}
+
+	config := basicConfig()
+	response, _ := New(config).MakeBids(request, responseData)
+	for _, bid := range response.Bids {
+		if bid.BidVideo != nil {
+			t.Errorf("bid %s: expected no video info when extraction is disabled, got %+v", bid.Bid.ID, bid.BidVideo)
+		}
+	}
+
+	config.ResponseTransform.ExtractDurationFromVAST = true
+	response, _ = New(config).MakeBids(request, responseData)
+	videos := make(map[string]*adapters.BidVideo)
+	for _, bid := range response.Bids {
+		videos[bid.Bid.ID] = bid.BidVideo
+	}
+	if v := videos["inline"]; v == nil || v.Duration != 30 || v.PrimaryCategory != "IAB1-5" {
+		t.Errorf("expected 30s duration and IAB1-5 category, got %+v", v)
+	}
+	if v := videos["nurl"]; v != nil {
+		t.Errorf("expected no video info without markup or category, got %+v", v)
+	}
+	if v := videos["banner"]; v != nil {
+		t.Errorf("expected no video info for banner bid, got %+v", v)
+	}
+}
+
 func TestGenericAdapter_TransformBid_PriceAdjustment(t *testing.T) {
 	config := basicConfig()
 	config.ResponseTransform.PriceAdjustment = 0.9 // 10% discount
@@ -557,6 +599,8 @@ func TestGenericAdapter_Info_Capabilities(t *testing.T) {
 	config.Capabilities.MediaTypes = []string{"banner", "video", "native", "audio"}
 	config.Capabilities.SiteEnabled = true
 	config.Capabilities.AppEnabled = true
+	config.Capabilities.VideoMimes = []string{"video/mp4"}
+	config.Capabilities.VideoProtocols = []int{2, 3}
 	adapter := New(config)
 
 	info := adapter.Info()
@@ -564,6 +608,9 @@ func TestGenericAdapter_Info_Capabilities(t *testing.T) {
 	if info.Capabilities == nil {
 		t.Fatal("expected capabilities")
 	}
+	if len(info.Capabilities.VideoMimes) != 1 || len(info.Capabilities.VideoProtocols) != 2 {
+		t.Errorf("expected video mimes and protocols, got %+v", info.Capabilities)
+	}
 	if info.Capabilities.Site == nil {
 		t.Fatal("expected site capabilities")
 	}
@@ -612,6 +659,56 @@ func TestGenericAdapter_GetTimeout(t *testing.T) {
 	}
 }
 
+func TestGenericAdapter_GetRateLimits(t *testing.T) {
+	config := basicConfig()
+	config.RateLimits = RateLimitsConfig{QPSLimit: 100, DailyLimit: 500000, ConcurrentLimit: 20}
+	adapter := New(config)
+
+	limits := adapter.GetRateLimits()
+
+	if limits != (adapters.RateLimits{QPS: 100, Daily: 500000, Concurrent: 20}) {
+		t.Errorf("unexpected rate limits: %+v", limits)
+	}
+}
+
+func TestGenericAdapter_GetOutboundOptions(t *testing.T) {
+	config := basicConfig()
+	config.Endpoint.GzipRequests = true
+	config.Endpoint.HTTP2 = true
+	config.Endpoint.MaxConnsPerHost = 200
+	adapter := New(config)
+
+	opts := adapter.GetOutboundOptions()
+
+	if opts != (adapters.OutboundOptions{GzipRequests: true, HTTP2: true, MaxConnsPerHost: 200}) {
+		t.Errorf("unexpected outbound options: %+v", opts)
+	}
+}
+
+func TestGenericAdapter_GetOutboundOptions_SigningAndMTLS(t *testing.T) {
+	config := basicConfig()
+	config.Endpoint.ClientCertFile = "/etc/certs/client.pem"
+	config.Endpoint.ClientKeyFile = "/etc/certs/client-key.pem"
+	config.Endpoint.CAFile = "/etc/certs/ca.pem"
+	config.Endpoint.SigningScheme = adapters.SigningHMACSHA256
+	config.Endpoint.SigningKey = "shared-key"
+	config.Endpoint.SigningHeader = "X-DSP-Signature"
+
+	opts := New(config).GetOutboundOptions()
+
+	want := adapters.OutboundOptions{
+		ClientCertFile: "/etc/certs/client.pem",
+		ClientKeyFile:  "/etc/certs/client-key.pem",
+		CAFile:         "/etc/certs/ca.pem",
+		SigningScheme:  adapters.SigningHMACSHA256,
+		SigningKey:     "shared-key",
+		SigningHeader:  "X-DSP-Signature",
+	}
+	if opts != want {
+		t.Errorf("unexpected outbound options: %+v", opts)
+	}
+}
+
 func TestGenericAdapter_CanBidForPublisher(t *testing.T) {
 	tests := []struct {
 		name      string
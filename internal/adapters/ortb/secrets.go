@@ -0,0 +1,99 @@
+package ortb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+	"github.com/thenexusengine/tne_springwire/pkg/secrets"
+)
+
+// resolveCredentials returns the config with secret:// credentials replaced by their values
+// The config is returned as is when it holds no references. References without a resolver
+// are an error, so a request is never sent with a reference as its credential.
+func (a *GenericAdapter) resolveCredentials(config *BidderConfig) (*BidderConfig, error) {
+	if !config.hasSecretReferences() {
+		return config, nil
+	}
+	if a.secrets == nil {
+		return nil, fmt.Errorf("bidder %s: secret references configured without a secret provider", config.BidderCode)
+	}
+
+	ctx := context.Background()
+	resolved := *config
+	endpoint := &resolved.Endpoint
+	for _, field := range []*string{&endpoint.AuthPassword, &endpoint.AuthToken, &endpoint.AuthHeaderValue} {
+		value, err := a.secrets.Resolve(ctx, *field)
+		if err != nil {
+			return nil, fmt.Errorf("bidder %s: %w", config.BidderCode, err)
+		}
+		*field = value
+	}
+	if len(config.Endpoint.CustomHeaders) > 0 {
+		endpoint.CustomHeaders = make(map[string]string, len(config.Endpoint.CustomHeaders))
+		for name, value := range config.Endpoint.CustomHeaders {
+			value, err := a.secrets.Resolve(ctx, value)
+			if err != nil {
+				return nil, fmt.Errorf("bidder %s: header %s: %w", config.BidderCode, name, err)
+			}
+			endpoint.CustomHeaders[name] = value
+		}
+	}
+	return &resolved, nil
+}
+
+// resolveSigningKey returns the request signing key, or "" when a reference cannot be resolved
+func (a *GenericAdapter) resolveSigningKey(key string) string {
+	if !secrets.IsReference(key) {
+		return key
+	}
+	if a.secrets == nil {
+		logger.Log.Warn().Str("secret", key).Msg("Signing key is a secret reference but no secret provider is configured")
+		return ""
+	}
+	resolved, err := a.secrets.Resolve(context.Background(), key)
+	if err != nil {
+		logger.Log.Warn().Err(err).Msg("Failed to resolve signing key")
+		return ""
+	}
+	return resolved
+}
+
+// hasSecretReferences reports whether any credential is a secret:// reference
+func (c *BidderConfig) hasSecretReferences() bool {
+	e := &c.Endpoint
+	if secrets.IsReference(e.AuthPassword) || secrets.IsReference(e.AuthToken) || secrets.IsReference(e.AuthHeaderValue) {
+		return true
+	}
+	for _, value := range e.CustomHeaders {
+		if secrets.IsReference(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted returns a copy of the config safe for admin responses and logs
+// Plaintext passwords, tokens, auth header values and signing keys are replaced, as are custom
+// headers with credential-like names; secret:// references are kept since they hold no secret.
+func (c *BidderConfig) Redacted() *BidderConfig {
+	if c == nil {
+		return nil
+	}
+	redacted := *c
+	endpoint := &redacted.Endpoint
+	endpoint.AuthPassword = secrets.Redact(endpoint.AuthPassword)
+	endpoint.AuthToken = secrets.Redact(endpoint.AuthToken)
+	endpoint.AuthHeaderValue = secrets.Redact(endpoint.AuthHeaderValue)
+	endpoint.SigningKey = secrets.Redact(endpoint.SigningKey)
+	if c.Endpoint.CustomHeaders != nil {
+		endpoint.CustomHeaders = make(map[string]string, len(c.Endpoint.CustomHeaders))
+		for name, value := range c.Endpoint.CustomHeaders {
+			if secrets.IsSensitiveName(name) || (c.Endpoint.AuthType == "header" && name == c.Endpoint.AuthHeaderName) {
+				value = secrets.Redact(value)
+			}
+			endpoint.CustomHeaders[name] = value
+		}
+	}
+	return &redacted
+}
@@ -0,0 +1,112 @@
+package ortb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/pkg/secrets"
+)
+
+func TestGenericAdapter_MakeRequests_ResolvesSecrets(t *testing.T) {
+	t.Setenv("SECRET_TESTBIDDER_TOKEN", "token-v1")
+	t.Setenv("SECRET_TESTBIDDER_SEAT", "seat-42")
+	resolver := secrets.NewResolver(&secrets.EnvProvider{Prefix: "SECRET_"}, time.Hour)
+
+	config := basicConfig()
+	config.Endpoint.AuthType = "bearer"
+	config.Endpoint.AuthToken = "secret://testbidder.token"
+	config.Endpoint.CustomHeaders = map[string]string{"X-Seat-Key": "secret://testbidder.seat", "X-Region": "eu"}
+	adapter := NewWithSecrets(config, resolver)
+
+	requests, errs := adapter.MakeRequests(testBidRequest(), nil)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	headers := requests[0].Headers
+	if headers.Get("Authorization") != "Bearer token-v1" {
+		t.Errorf("expected resolved bearer token, got %q", headers.Get("Authorization"))
+	}
+	if headers.Get("X-Seat-Key") != "seat-42" || headers.Get("X-Region") != "eu" {
+		t.Errorf("unexpected custom headers: %v", headers)
+	}
+	if config.Endpoint.AuthToken != "secret://testbidder.token" {
+		t.Error("expected the stored config to keep the reference")
+	}
+
+	// Rotated credentials are used once the resolver refreshes, without rebuilding the adapter
+	t.Setenv("SECRET_TESTBIDDER_TOKEN", "token-v2")
+	resolver.Refresh()
+	requests, _ = adapter.MakeRequests(testBidRequest(), nil)
+	if requests[0].Headers.Get("Authorization") != "Bearer token-v2" {
+		t.Errorf("expected rotated token, got %q", requests[0].Headers.Get("Authorization"))
+	}
+}
+
+func TestGenericAdapter_MakeRequests_UnresolvedSecret(t *testing.T) {
+	config := basicConfig()
+	config.Endpoint.AuthType = "bearer"
+	config.Endpoint.AuthToken = "secret://testbidder.missing"
+
+	// Without a resolver
+	if requests, errs := New(config).MakeRequests(testBidRequest(), nil); len(requests) != 0 || len(errs) != 1 {
+		t.Errorf("expected no requests and one error, got %d requests and %v", len(requests), errs)
+	}
+
+	// With a resolver that does not know the secret
+	resolver := secrets.NewResolver(&secrets.EnvProvider{Prefix: "SECRET_"}, time.Hour)
+	if requests, errs := NewWithSecrets(config, resolver).MakeRequests(testBidRequest(), nil); len(requests) != 0 || len(errs) != 1 {
+		t.Errorf("expected no requests and one error, got %d requests and %v", len(requests), errs)
+	}
+}
+
+func TestGenericAdapter_GetConfig_Redacted(t *testing.T) {
+	config := basicConfig()
+	config.Endpoint.AuthType = "header"
+	config.Endpoint.AuthUsername = "user"
+	config.Endpoint.AuthPassword = "pass"
+	config.Endpoint.AuthToken = "secret://testbidder.token"
+	config.Endpoint.AuthHeaderName = "X-Partner"
+	config.Endpoint.AuthHeaderValue = "plain-value"
+	config.Endpoint.CustomHeaders = map[string]string{"X-Api-Key": "k", "X-Partner": "plain-value", "X-Region": "eu"}
+	adapter := New(config)
+
+	got := adapter.GetConfig()
+	if got.Endpoint.AuthPassword != secrets.Redacted || got.Endpoint.AuthHeaderValue != secrets.Redacted {
+		t.Errorf("expected plaintext credentials redacted, got %+v", got.Endpoint)
+	}
+	if got.Endpoint.AuthToken != "secret://testbidder.token" || got.Endpoint.AuthUsername != "user" {
+		t.Errorf("expected references and usernames kept, got %+v", got.Endpoint)
+	}
+	headers := got.Endpoint.CustomHeaders
+	if headers["X-Api-Key"] != secrets.Redacted || headers["X-Partner"] != secrets.Redacted || headers["X-Region"] != "eu" {
+		t.Errorf("unexpected custom headers: %v", headers)
+	}
+	if config.Endpoint.AuthPassword != "pass" || config.Endpoint.CustomHeaders["X-Api-Key"] != "k" {
+		t.Error("expected the adapter's config unchanged")
+	}
+}
+
+func TestGenericAdapter_GetOutboundOptions_SigningKeySecret(t *testing.T) {
+	t.Setenv("SECRET_TESTBIDDER_HMAC", "shared-key")
+	resolver := secrets.NewResolver(&secrets.EnvProvider{Prefix: "SECRET_"}, time.Hour)
+
+	config := basicConfig()
+	config.Endpoint.SigningScheme = "hmac-sha256"
+	config.Endpoint.SigningKey = "secret://testbidder.hmac"
+
+	if key := NewWithSecrets(config, resolver).GetOutboundOptions().SigningKey; key != "shared-key" {
+		t.Errorf("expected resolved signing key, got %q", key)
+	}
+	// An unresolvable key is left empty so requests fail rather than go out unsigned
+	if key := New(config).GetOutboundOptions().SigningKey; key != "" {
+		t.Errorf("expected empty signing key without a resolver, got %q", key)
+	}
+	if got := New(config).GetConfig().Endpoint.SigningKey; got != "secret://testbidder.hmac" {
+		t.Errorf("expected signing key reference kept, got %q", got)
+	}
+
+	config.Endpoint.SigningKey = "plain-key"
+	if got := New(config).GetConfig().Endpoint.SigningKey; got != secrets.Redacted {
+		t.Errorf("expected plaintext signing key redacted, got %q", got)
+	}
+}
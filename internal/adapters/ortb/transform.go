@@ -0,0 +1,369 @@
+package ortb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Field paths name a value in the JSON of a request or bid with dot-separated keys,
+// e.g. "site.publisher.id". A numeric segment selects an array element ("imp.0.tagid"),
+// and a "[]" suffix selects every element ("imp[].tagid").
+//
+// When the destination of a mapping or addition has "[]", the rule is applied to each
+// element, and sources and macros with the same prefix read from that element:
+// "imp[].tagid" <- "imp[].ext.bidder.placementId" moves each imp's own placement ID.
+// A "[]" source with a plain destination reads the first element that has the value.
+
+// wildcard is the path segment that selects every array element
+const wildcard = "[]"
+
+// publisherParamsMacro prefixes macros reading the publisher's stored params for the bidder
+const publisherParamsMacro = "publisher.bidder_params."
+
+// macroPattern matches {{path}} macros in field addition values
+var macroPattern = regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*\}\}`)
+
+// fieldPath is a parsed field path; "[]" segments stand for every element of the array before them
+type fieldPath []string
+
+// parseFieldPath parses a dot-separated field path
+func parseFieldPath(s string) (fieldPath, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty field path")
+	}
+	var p fieldPath
+	for _, part := range strings.Split(s, ".") {
+		name := strings.TrimSuffix(part, wildcard)
+		if name == "" || strings.Contains(name, "[") || strings.Contains(name, "]") {
+			return nil, fmt.Errorf("invalid field path %q", s)
+		}
+		p = append(p, name)
+		if len(name) != len(part) {
+			p = append(p, wildcard)
+		}
+	}
+	return p, nil
+}
+
+func (p fieldPath) String() string {
+	return strings.ReplaceAll(strings.Join(p, "."), "."+wildcard, wildcard)
+}
+
+// bind replaces the wildcards p shares with pattern by the indexes of concrete,
+// a path matched by pattern
+func (p fieldPath) bind(pattern, concrete fieldPath) fieldPath {
+	bound := make(fieldPath, len(p))
+	copy(bound, p)
+	for i := 0; i < len(p) && i < len(pattern) && p[i] == pattern[i]; i++ {
+		if p[i] == wildcard {
+			bound[i] = concrete[i]
+		}
+	}
+	return bound
+}
+
+// expandPath returns the concrete paths p matches in doc, replacing each wildcard by the
+// indexes of the array it selects. Paths without wildcards are returned as is.
+func expandPath(doc interface{}, p fieldPath) []fieldPath {
+	for i, seg := range p {
+		if seg != wildcard {
+			continue
+		}
+		arr, ok := getField(doc, p[:i])
+		if !ok {
+			return nil
+		}
+		elems, ok := arr.([]interface{})
+		if !ok {
+			return nil
+		}
+		var paths []fieldPath
+		for j := range elems {
+			concrete := make(fieldPath, len(p))
+			copy(concrete, p)
+			concrete[i] = strconv.Itoa(j)
+			paths = append(paths, expandPath(doc, concrete)...)
+		}
+		return paths
+	}
+	return []fieldPath{p}
+}
+
+// getField returns the value at a concrete path
+func getField(doc interface{}, p fieldPath) (interface{}, bool) {
+	cur := doc
+	for _, seg := range p {
+		switch node := cur.(type) {
+		case map[string]interface{}:
+			v, ok := node[seg]
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case []interface{}:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			cur = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// firstField returns the first value found at the paths p matches
+func firstField(doc interface{}, p fieldPath) (fieldPath, interface{}, bool) {
+	for _, concrete := range expandPath(doc, p) {
+		if v, ok := getField(doc, concrete); ok && v != nil {
+			return concrete, v, true
+		}
+	}
+	return nil, nil, false
+}
+
+// setField stores v at a concrete path, creating missing objects on the way
+func setField(doc interface{}, p fieldPath, v interface{}) error {
+	cur := doc
+	for i, seg := range p {
+		last := i == len(p)-1
+		switch node := cur.(type) {
+		case map[string]interface{}:
+			if last {
+				node[seg] = v
+				return nil
+			}
+			switch child := node[seg].(type) {
+			case map[string]interface{}, []interface{}:
+				cur = child
+			case nil:
+				next := make(map[string]interface{})
+				node[seg] = next
+				cur = next
+			default:
+				return fmt.Errorf("cannot set %s: %s is not an object", p, p[:i+1])
+			}
+		case []interface{}:
+			j, err := strconv.Atoi(seg)
+			if err != nil || j < 0 || j >= len(node) {
+				return fmt.Errorf("cannot set %s: no element %s", p, p[:i+1])
+			}
+			if last {
+				node[j] = v
+				return nil
+			}
+			cur = node[j]
+		default:
+			return fmt.Errorf("cannot set %s: %s is not an object", p, p[:i])
+		}
+	}
+	return nil
+}
+
+// removeField deletes the object key at a concrete path
+func removeField(doc interface{}, p fieldPath) {
+	parent, ok := getField(doc, p[:len(p)-1])
+	if !ok {
+		return
+	}
+	if obj, ok := parent.(map[string]interface{}); ok {
+		delete(obj, p[len(p)-1])
+	}
+}
+
+// transformer applies the field rules of a bidder to a decoded JSON document
+type transformer struct {
+	publisherParams map[string]interface{} // Publisher's stored params for the bidder
+	errs            []error
+}
+
+// move moves the value at each path matched by src to dst
+func (t *transformer) move(doc interface{}, srcPath, dstPath string) {
+	src, err := parseFieldPath(srcPath)
+	if err != nil {
+		t.errs = append(t.errs, err)
+		return
+	}
+	dst, err := parseFieldPath(dstPath)
+	if err != nil {
+		t.errs = append(t.errs, err)
+		return
+	}
+	for _, target := range expandPath(doc, dst) {
+		from, v, ok := firstField(doc, src.bind(dst, target))
+		if !ok {
+			continue
+		}
+		removeField(doc, from)
+		if err := setField(doc, target, v); err != nil {
+			t.errs = append(t.errs, err)
+		}
+	}
+}
+
+// add sets dst to value, expanding the macros of string values
+func (t *transformer) add(doc interface{}, dstPath string, value interface{}) {
+	dst, err := parseFieldPath(dstPath)
+	if err != nil {
+		t.errs = append(t.errs, err)
+		return
+	}
+	for _, target := range expandPath(doc, dst) {
+		v, ok := value, true
+		if s, isString := value.(string); isString {
+			v, ok = t.expandMacros(doc, s, dst, target)
+		}
+		if !ok {
+			continue
+		}
+		if err := setField(doc, target, v); err != nil {
+			t.errs = append(t.errs, err)
+		}
+	}
+}
+
+// drop removes the values at each path matched by p
+func (t *transformer) drop(doc interface{}, path string) {
+	p, err := parseFieldPath(path)
+	if err != nil {
+		t.errs = append(t.errs, err)
+		return
+	}
+	if p[len(p)-1] == wildcard {
+		p = p[:len(p)-1]
+	}
+	for _, concrete := range expandPath(doc, p) {
+		removeField(doc, concrete)
+	}
+}
+
+// expandMacros resolves the {{path}} macros of s
+// A value that is a single macro takes the referenced value with its JSON type, and is
+// skipped when the value is missing; macros embedded in text are replaced by the value's
+// text, or by nothing when it is missing.
+func (t *transformer) expandMacros(doc interface{}, s string, pattern, concrete fieldPath) (interface{}, bool) {
+	if m := macroPattern.FindStringSubmatch(s); m != nil && m[0] == strings.TrimSpace(s) {
+		return t.resolveMacro(doc, m[1], pattern, concrete)
+	}
+	return macroPattern.ReplaceAllStringFunc(s, func(match string) string {
+		v, ok := t.resolveMacro(doc, macroPattern.FindStringSubmatch(match)[1], pattern, concrete)
+		if !ok {
+			return ""
+		}
+		return macroText(v)
+	}), true
+}
+
+// resolveMacro returns the value a macro references
+func (t *transformer) resolveMacro(doc interface{}, macro string, pattern, concrete fieldPath) (interface{}, bool) {
+	root := doc
+	if strings.HasPrefix(macro, publisherParamsMacro) {
+		macro = strings.TrimPrefix(macro, publisherParamsMacro)
+		root = t.publisherParams
+	}
+	p, err := parseFieldPath(macro)
+	if err != nil {
+		t.errs = append(t.errs, fmt.Errorf("invalid macro {{%s}}: %w", macro, err))
+		return nil, false
+	}
+	_, v, ok := firstField(root, p.bind(pattern, concrete))
+	return v, ok
+}
+
+// macroText formats a value for text
+func macroText(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+}
+
+// hasFieldRules reports whether a request transform has mappings, additions or removals
+func (rt *RequestTransformConfig) hasFieldRules() bool {
+	return len(rt.FieldMappings) > 0 || len(rt.FieldAdditions) > 0 || len(rt.FieldRemovals) > 0
+}
+
+// applyFieldRules applies the field mappings, additions and removals to a request body
+// Mappings run first, then additions, then removals; each in path order. Rules that
+// cannot be applied are reported and skipped.
+func applyFieldRules(body []byte, rt *RequestTransformConfig, publisherParams map[string]interface{}) ([]byte, []error) {
+	doc, err := decodeJSON(body)
+	if err != nil {
+		return body, []error{fmt.Errorf("field rules not applied: %w", err)}
+	}
+	t := &transformer{publisherParams: publisherParams}
+
+	for _, src := range sortedKeys(rt.FieldMappings) {
+		t.move(doc, src, rt.FieldMappings[src])
+	}
+	for _, dst := range sortedKeys(rt.FieldAdditions) {
+		t.add(doc, dst, rt.FieldAdditions[dst])
+	}
+	for _, path := range rt.FieldRemovals {
+		t.drop(doc, path)
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return body, append(t.errs, fmt.Errorf("field rules not applied: %w", err))
+	}
+	return out, t.errs
+}
+
+// applyBidFieldMappings moves fields within each bid of a response body
+// Paths are relative to the bid, e.g. "ext.creative_id" -> "crid".
+func applyBidFieldMappings(body []byte, mappings map[string]string) ([]byte, []error) {
+	doc, err := decodeJSON(body)
+	if err != nil {
+		// Reported when the response is parsed
+		return body, nil
+	}
+	t := &transformer{}
+	keys := sortedKeys(mappings)
+	for _, bidPath := range expandPath(doc, fieldPath{"seatbid", wildcard, "bid", wildcard}) {
+		bid, _ := getField(doc, bidPath)
+		for _, src := range keys {
+			t.move(bid, src, mappings[src])
+		}
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return body, append(t.errs, err)
+	}
+	return out, t.errs
+}
+
+// decodeJSON decodes JSON keeping numbers as json.Number so IDs and prices round-trip
+func decodeJSON(body []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,296 @@
+package ortb
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// lookup reads a dot-separated path from decoded JSON for assertions
+func lookup(t *testing.T, body []byte, path string) interface{} {
+	t.Helper()
+	doc, err := decodeJSON(body)
+	if err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	p, err := parseFieldPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, _ := getField(doc, p)
+	return v
+}
+
+func transformRequest() *openrtb.BidRequest {
+	return &openrtb.BidRequest{
+		ID: "req-1",
+		Imp: []openrtb.Imp{
+			{ID: "imp-1", Banner: &openrtb.Banner{W: 300, H: 250}, Ext: json.RawMessage(`{"bidder":{"placementId":"plc-1","zone":11},"prebid":{"storedrequest":{"id":"x"}}}`)},
+			{ID: "imp-2", Banner: &openrtb.Banner{W: 728, H: 90}, Ext: json.RawMessage(`{"bidder":{"placementId":"plc-2","zone":12}}`)},
+		},
+		Site: &openrtb.Site{ID: "site-1", Domain: "example.com", Publisher: &openrtb.Publisher{ID: "pub-1"}},
+		User: &openrtb.User{ID: "user-1", BuyerUID: "buyer-1", Consent: "CONSENT"},
+		Regs: &openrtb.Regs{GDPR: intPtr(1)},
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestMakeRequests_FieldRules(t *testing.T) {
+	publisherParams := map[string]interface{}{"siteId": "ssp-site-9", "networkId": float64(42)}
+
+	tests := []struct {
+		name      string
+		transform RequestTransformConfig
+		want      map[string]interface{} // path -> expected value (nil = absent)
+	}{
+		{
+			name:      "placement moved from imp.ext.bidder to tagid per imp",
+			transform: RequestTransformConfig{FieldMappings: map[string]string{"imp[].ext.bidder.placementId": "imp[].tagid"}},
+			want: map[string]interface{}{
+				"imp.0.tagid":                  "plc-1",
+				"imp.1.tagid":                  "plc-2",
+				"imp.0.ext.bidder.placementId": nil,
+				"imp.1.ext.bidder.zone":        json.Number("12"),
+			},
+		},
+		{
+			name: "OpenRTB 2.6 privacy fields moved to 2.5 extensions",
+			transform: RequestTransformConfig{FieldMappings: map[string]string{
+				"regs.gdpr":    "regs.ext.gdpr",
+				"user.consent": "user.ext.consent",
+			}},
+			want: map[string]interface{}{
+				"regs.ext.gdpr":    json.Number("1"),
+				"regs.gdpr":        nil,
+				"user.ext.consent": "CONSENT",
+				"user.consent":     nil,
+			},
+		},
+		{
+			name:      "first imp's zone becomes the site-level zone",
+			transform: RequestTransformConfig{FieldMappings: map[string]string{"imp[].ext.bidder.zone": "site.ext.zone"}},
+			want: map[string]interface{}{
+				"site.ext.zone":         json.Number("11"),
+				"imp.0.ext.bidder.zone": nil,
+				"imp.1.ext.bidder.zone": json.Number("12"),
+			},
+		},
+		{
+			name: "publisher params copied with macros",
+			transform: RequestTransformConfig{FieldAdditions: map[string]interface{}{
+				"site.ext.siteId":  "{{publisher.bidder_params.siteId}}",
+				"site.ext.network": "{{publisher.bidder_params.networkId}}",
+				"ext.missing":      "{{publisher.bidder_params.unknown}}",
+			}},
+			want: map[string]interface{}{
+				"site.ext.siteId":  "ssp-site-9",
+				"site.ext.network": json.Number("42"),
+				"ext.missing":      nil,
+			},
+		},
+		{
+			name: "request values copied and interpolated per imp",
+			transform: RequestTransformConfig{FieldAdditions: map[string]interface{}{
+				"imp[].ext.ref":      "{{site.publisher.id}}/{{imp[].id}}/{{imp[].ext.bidder.zone}}",
+				"imp[].secure":       1,
+				"user.ext.buyerid":   "{{user.buyeruid}}",
+				"source.ext.partner": map[string]interface{}{"name": "springwire"},
+			}},
+			want: map[string]interface{}{
+				"imp.0.ext.ref":           "pub-1/imp-1/11",
+				"imp.1.ext.ref":           "pub-1/imp-2/12",
+				"imp.1.secure":            json.Number("1"),
+				"user.ext.buyerid":        "buyer-1",
+				"user.buyeruid":           "buyer-1",
+				"source.ext.partner.name": "springwire",
+			},
+		},
+		{
+			name: "fields the SSP rejects are removed",
+			transform: RequestTransformConfig{FieldRemovals: []string{
+				"imp[].ext.prebid",
+				"user.id",
+				"site.content.language", // absent paths are ignored
+			}},
+			want: map[string]interface{}{
+				"imp.0.ext.prebid":             nil,
+				"imp.0.ext.bidder.placementId": "plc-1",
+				"user.id":                      nil,
+				"user.buyeruid":                "buyer-1",
+			},
+		},
+		{
+			name: "mappings run before additions and removals",
+			transform: RequestTransformConfig{
+				FieldMappings:  map[string]string{"imp[].ext.bidder": "imp[].ext.ssp"},
+				FieldAdditions: map[string]interface{}{"imp[].tagid": "{{imp[].ext.ssp.placementId}}"},
+				FieldRemovals:  []string{"imp[].ext.ssp.placementId"},
+			},
+			want: map[string]interface{}{
+				"imp.0.tagid":               "plc-1",
+				"imp.0.ext.ssp.zone":        json.Number("11"),
+				"imp.0.ext.ssp.placementId": nil,
+				"imp.0.ext.bidder":          nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := basicConfig()
+			config.RequestTransform = tt.transform
+			request := transformRequest()
+
+			requests, errs := New(config).MakeRequests(request, &adapters.ExtraRequestInfo{PublisherBidderParams: publisherParams})
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			for path, want := range tt.want {
+				if got := lookup(t, requests[0].Body, path); got != want {
+					t.Errorf("%s: expected %v (%T), got %v (%T)", path, want, want, got, got)
+				}
+			}
+			if request.Regs.GDPR == nil || string(request.Imp[0].Ext) == "" || request.User.ID != "user-1" {
+				t.Error("expected the caller's request unchanged")
+			}
+		})
+	}
+}
+
+func TestMakeRequests_FieldRules_Errors(t *testing.T) {
+	config := basicConfig()
+	config.RequestTransform = RequestTransformConfig{
+		FieldMappings:  map[string]string{"site.domain": "bad[path"},
+		FieldAdditions: map[string]interface{}{"site.domain.sub": "x", "site.ext.kept": "yes"},
+	}
+
+	requests, errs := New(config).MakeRequests(transformRequest(), nil)
+	if len(errs) != 2 {
+		t.Fatalf("expected an error per broken rule, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "invalid field path") || !strings.Contains(errs[1].Error(), "site.domain is not an object") {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	// Other rules still apply and the request is still sent
+	if got := lookup(t, requests[0].Body, "site.ext.kept"); got != "yes" {
+		t.Errorf("expected working rules applied, got %v", got)
+	}
+	if got := lookup(t, requests[0].Body, "site.domain"); got != "example.com" {
+		t.Errorf("expected site.domain kept, got %v", got)
+	}
+}
+
+func TestMakeBids_BidFieldMappings(t *testing.T) {
+	request := &openrtb.BidRequest{
+		ID: "req-1",
+		Imp: []openrtb.Imp{
+			{ID: "imp-1", Banner: &openrtb.Banner{W: 300, H: 250}, Video: &openrtb.Video{W: 640, H: 360}},
+			{ID: "imp-2", Banner: &openrtb.Banner{W: 728, H: 90}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		transform ResponseTransformConfig
+		bid       string
+		check     func(t *testing.T, tb *adapters.TypedBid)
+	}{
+		{
+			name:      "creative ID and advertiser domains from ext",
+			transform: ResponseTransformConfig{BidFieldMappings: map[string]string{"ext.creative_id": "crid", "ext.adomains": "adomain", "dealId": "dealid"}},
+			bid:       `{"id":"b1","impid":"imp-2","price":1.5,"adm":"<div></div>","dealId":"deal-7","ext":{"creative_id":"cr-9","adomains":["adv.example.com"]}}`,
+			check: func(t *testing.T, tb *adapters.TypedBid) {
+				if tb.Bid.CRID != "cr-9" || len(tb.Bid.ADomain) != 1 || tb.Bid.ADomain[0] != "adv.example.com" || tb.Bid.DealID != "deal-7" {
+					t.Errorf("expected mapped crid, adomain and dealid, got %+v", tb.Bid)
+				}
+				if strings.Contains(string(tb.Bid.Ext), "creative_id") {
+					t.Errorf("expected moved fields removed from ext, got %s", tb.Bid.Ext)
+				}
+			},
+		},
+		{
+			name:      "markup under a non-standard key",
+			transform: ResponseTransformConfig{BidFieldMappings: map[string]string{"ext.markup": "adm", "ext.win_url": "nurl"}},
+			bid:       `{"id":"b1","impid":"imp-2","price":1.5,"ext":{"markup":"<div>ad</div>","win_url":"https://ssp.example.com/win"}}`,
+			check: func(t *testing.T, tb *adapters.TypedBid) {
+				if tb.Bid.AdM != "<div>ad</div>" || tb.Bid.NURL != "https://ssp.example.com/win" {
+					t.Errorf("expected adm and nurl mapped, got %+v", tb.Bid)
+				}
+			},
+		},
+		{
+			name: "creative type names mapped to media types",
+			transform: ResponseTransformConfig{
+				BidFieldMappings:     map[string]string{"ext.format": "ext.prebid.type"},
+				CreativeTypeMappings: map[string]string{"vid": "video", "display": "banner"},
+			},
+			bid: `{"id":"b1","impid":"imp-1","price":1.5,"adm":"<VAST/>","ext":{"format":"vid"}}`,
+			check: func(t *testing.T, tb *adapters.TypedBid) {
+				if tb.BidType != adapters.BidTypeVideo {
+					t.Errorf("expected video from the creative type, got %s", tb.BidType)
+				}
+			},
+		},
+		{
+			name: "numeric creative types",
+			transform: ResponseTransformConfig{
+				BidFieldMappings:     map[string]string{"mtype": "ext.prebid.type"},
+				CreativeTypeMappings: map[string]string{"1": "banner", "2": "video"},
+			},
+			bid: `{"id":"b1","impid":"imp-1","price":1.5,"adm":"<div></div>","mtype":1}`,
+			check: func(t *testing.T, tb *adapters.TypedBid) {
+				if tb.BidType != adapters.BidTypeBanner {
+					t.Errorf("expected banner from mtype 1, got %s", tb.BidType)
+				}
+			},
+		},
+		{
+			name:      "unknown creative types fall back to the imp",
+			transform: ResponseTransformConfig{CreativeTypeMappings: map[string]string{"vid": "video"}},
+			bid:       `{"id":"b1","impid":"imp-1","price":1.5,"adm":"<div></div>","ext":{"prebid":{"type":"rich"}}}`,
+			check: func(t *testing.T, tb *adapters.TypedBid) {
+				if tb.BidType != adapters.BidTypeVideo {
+					t.Errorf("expected imp media type, got %s", tb.BidType)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := basicConfig()
+			config.ResponseTransform = tt.transform
+			body := `{"id":"req-1","cur":"USD","seatbid":[{"seat":"ssp","bid":[` + tt.bid + `]}]}`
+
+			response, errs := New(config).MakeBids(request, &adapters.ResponseData{StatusCode: http.StatusOK, Body: []byte(body)})
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if response == nil || len(response.Bids) != 1 {
+				t.Fatalf("expected one bid, got %+v", response)
+			}
+			tt.check(t, response.Bids[0])
+		})
+	}
+}
+
+func TestParseFieldPath(t *testing.T) {
+	p, err := parseFieldPath("imp[].banner.format[].w")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(p) != 6 || p[1] != wildcard || p[4] != wildcard || p.String() != "imp[].banner.format[].w" {
+		t.Errorf("unexpected path %q", p)
+	}
+
+	for _, bad := range []string{"", "site..id", "imp[0].tagid", "[].id", "imp[]]"} {
+		if _, err := parseFieldPath(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
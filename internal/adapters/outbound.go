@@ -0,0 +1,206 @@
+package adapters
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"os"
+)
+
+// OutboundOptions control how requests to a bidder are sent
+// The zero value sends uncompressed, unsigned JSON over HTTP/1.1 on the shared connection pool.
+type OutboundOptions struct {
+	GzipRequests        bool `json:"gzipRequests,omitempty"`        // Gzip request bodies (Content-Encoding: gzip)
+	AcceptGzip          bool `json:"acceptGzip,omitempty"`          // Ask for gzip responses (Accept-Encoding: gzip)
+	HTTP2               bool `json:"http2,omitempty"`               // Negotiate HTTP/2 where the endpoint supports it
+	MaxConnsPerHost     int  `json:"maxConnsPerHost,omitempty"`     // Connections per bidder host (0 = shared pool default)
+	MaxIdleConnsPerHost int  `json:"maxIdleConnsPerHost,omitempty"` // Idle connections kept per bidder host (0 = shared pool default)
+
+	// Mutual TLS (PEM files)
+	ClientCertFile string `json:"clientCertFile,omitempty"` // Client certificate presented to the bidder
+	ClientKeyFile  string `json:"clientKeyFile,omitempty"`  // Private key of the client certificate
+	CAFile         string `json:"caFile,omitempty"`         // CAs the bidder's certificate is verified against (empty = system roots)
+
+	// Request signing (see signRequest)
+	SigningScheme          string `json:"signingScheme,omitempty"`          // SigningHMACSHA256, or empty for unsigned requests
+	SigningKey             string `json:"signingKey,omitempty"`             // Shared HMAC key
+	SigningHeader          string `json:"signingHeader,omitempty"`          // Signature header (default X-Signature)
+	SigningTimestampHeader string `json:"signingTimestampHeader,omitempty"` // Timestamp header (default X-Signature-Timestamp)
+}
+
+// IsZero reports whether no option is set
+func (o OutboundOptions) IsZero() bool {
+	return o == OutboundOptions{}
+}
+
+// Merge returns the options with those set in override replacing them, field by field
+// A field is set when it is non-zero, so an override can switch a boolean option on but not off.
+func (o OutboundOptions) Merge(override OutboundOptions) OutboundOptions {
+	if override.GzipRequests {
+		o.GzipRequests = true
+	}
+	if override.AcceptGzip {
+		o.AcceptGzip = true
+	}
+	if override.HTTP2 {
+		o.HTTP2 = true
+	}
+	if override.MaxConnsPerHost != 0 {
+		o.MaxConnsPerHost = override.MaxConnsPerHost
+	}
+	if override.MaxIdleConnsPerHost != 0 {
+		o.MaxIdleConnsPerHost = override.MaxIdleConnsPerHost
+	}
+	if override.ClientCertFile != "" {
+		o.ClientCertFile = override.ClientCertFile
+	}
+	if override.ClientKeyFile != "" {
+		o.ClientKeyFile = override.ClientKeyFile
+	}
+	if override.CAFile != "" {
+		o.CAFile = override.CAFile
+	}
+	if override.SigningScheme != "" {
+		o.SigningScheme = override.SigningScheme
+	}
+	if override.SigningKey != "" {
+		o.SigningKey = override.SigningKey
+	}
+	if override.SigningHeader != "" {
+		o.SigningHeader = override.SigningHeader
+	}
+	if override.SigningTimestampHeader != "" {
+		o.SigningTimestampHeader = override.SigningTimestampHeader
+	}
+	return o
+}
+
+// Validate checks pool sizes and signing settings, and loads the mTLS certificate and CA files
+func (o OutboundOptions) Validate() error {
+	if o.MaxConnsPerHost < 0 || o.MaxIdleConnsPerHost < 0 {
+		return fmt.Errorf("connection pool sizes must not be negative")
+	}
+	if !ValidSigningScheme(o.SigningScheme) {
+		return fmt.Errorf("unsupported signing scheme %q", o.SigningScheme)
+	}
+	if o.SigningScheme != "" && o.SigningKey == "" {
+		return fmt.Errorf("signing scheme %s requires a signing key", o.SigningScheme)
+	}
+	_, err := o.transport().tlsConfig()
+	return err
+}
+
+// transportOptions are the options that need a dedicated transport
+type transportOptions struct {
+	http2           bool
+	maxConnsPerHost int
+	maxIdlePerHost  int
+	clientCertFile  string
+	clientKeyFile   string
+	caFile          string
+}
+
+func (o OutboundOptions) transport() transportOptions {
+	return transportOptions{
+		http2:           o.HTTP2,
+		maxConnsPerHost: o.MaxConnsPerHost,
+		maxIdlePerHost:  o.MaxIdleConnsPerHost,
+		clientCertFile:  o.ClientCertFile,
+		clientKeyFile:   o.ClientKeyFile,
+		caFile:          o.CAFile,
+	}
+}
+
+// tlsConfig returns the TLS settings of a transport
+// The client certificate is read again on every handshake, so renewed certificates are used
+// without a restart; it is also loaded once here so a bad certificate fails the first request
+// with a clear error instead of a handshake failure.
+func (o transportOptions) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		ClientSessionCache: tls.NewLRUClientSessionCache(100),
+		MinVersion:         tls.VersionTLS12, // Require TLS 1.2+
+	}
+
+	if o.caFile != "" {
+		pem, err := os.ReadFile(o.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in CA file %s", o.caFile)
+		}
+		config.RootCAs = roots
+	}
+
+	if o.clientCertFile != "" || o.clientKeyFile != "" {
+		if o.clientCertFile == "" || o.clientKeyFile == "" {
+			return nil, fmt.Errorf("client certificate and key files must both be set")
+		}
+		if _, err := tls.LoadX509KeyPair(o.clientCertFile, o.clientKeyFile); err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		certFile, keyFile := o.clientCertFile, o.clientKeyFile
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client certificate: %w", err)
+			}
+			return &cert, nil
+		}
+	}
+	return config, nil
+}
+
+type outboundOptionsKey struct{}
+
+// WithOutboundOptions returns a context whose requests are sent with the bidder's outbound options
+func WithOutboundOptions(ctx context.Context, opts OutboundOptions) context.Context {
+	if opts.IsZero() {
+		return ctx
+	}
+	return context.WithValue(ctx, outboundOptionsKey{}, opts)
+}
+
+// outboundOptionsFromContext returns the outbound options set on the context
+func outboundOptionsFromContext(ctx context.Context) OutboundOptions {
+	opts, _ := ctx.Value(outboundOptionsKey{}).(OutboundOptions)
+	return opts
+}
+
+// gzipBody compresses a request body
+func gzipBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gunzipBody decompresses a response body, capped at maxResponseSize
+func gunzipBody(body []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip response: %w", err)
+	}
+	defer zr.Close()
+	data, err := io.ReadAll(io.LimitReader(zr, maxResponseSize+1)) // +1 to detect overflow
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip response: %w", err)
+	}
+	if len(data) > maxResponseSize {
+		return nil, fmt.Errorf("response too large: exceeded %d bytes decompressed", maxResponseSize)
+	}
+	return data, nil
+}
@@ -0,0 +1,161 @@
+package adapters
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPClientDo_GzipRequest(t *testing.T) {
+	body := []byte(`{"id":"req-1","imp":[{"id":"imp-1","banner":{"w":300,"h":250}}]}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("expected gzip content encoding, got %q", r.Header.Get("Content-Encoding"))
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatalf("expected gzip body: %v", err)
+		}
+		got, _ := io.ReadAll(zr)
+		if !bytes.Equal(got, body) {
+			t.Errorf("expected original body, got %s", got)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(5 * time.Second)
+	ctx := WithOutboundOptions(context.Background(), OutboundOptions{GzipRequests: true})
+	headers := http.Header{"Content-Type": []string{"application/json"}}
+	resp, err := client.Do(ctx, &RequestData{Method: "POST", URI: server.URL, Body: body, Headers: headers}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.BytesSent == 0 || resp.BytesSent == len(body) {
+		t.Errorf("expected compressed bytes sent, got %d", resp.BytesSent)
+	}
+	if headers.Get("Content-Encoding") != "" {
+		t.Error("expected adapter headers to be left unchanged")
+	}
+}
+
+func TestHTTPClientDo_GzipResponse(t *testing.T) {
+	payload := `{"id":"req-1","seatbid":[]}` + strings.Repeat(" ", 1000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("expected gzip accept encoding, got %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		zw.Write([]byte(payload))
+		zw.Close()
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(5 * time.Second)
+	ctx := WithOutboundOptions(context.Background(), OutboundOptions{AcceptGzip: true})
+	resp, err := client.Do(ctx, &RequestData{Method: "POST", URI: server.URL, Body: []byte(`{}`)}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != payload {
+		t.Errorf("expected decompressed body, got %q", resp.Body)
+	}
+	if resp.Headers.Get("Content-Encoding") != "" {
+		t.Error("expected content encoding removed after decompression")
+	}
+	if resp.BytesReceived == 0 || resp.BytesReceived >= len(payload) {
+		t.Errorf("expected compressed bytes received, got %d", resp.BytesReceived)
+	}
+	if resp.BytesSent != 2 {
+		t.Errorf("expected 2 bytes sent, got %d", resp.BytesSent)
+	}
+}
+
+func TestHTTPClientDo_GzipResponseTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		zw.Write(bytes.Repeat([]byte("x"), maxResponseSize+100))
+		zw.Close()
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPClient(5*time.Second).Do(context.Background(), &RequestData{Method: "GET", URI: server.URL}, 0)
+	if err == nil || !strings.Contains(err.Error(), "response too large") {
+		t.Errorf("expected decompressed size limit error, got %v", err)
+	}
+}
+
+func TestHTTPClientDo_HTTP2(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	u, _ := url.Parse(server.URL)
+
+	client := NewHTTPClient(5 * time.Second)
+	for _, opts := range []OutboundOptions{{}, {HTTP2: true}} {
+		c, _ := client.clientFor(u.Host, opts)
+		c.Transport.(*http.Transport).TLSClientConfig.RootCAs = roots
+	}
+
+	resp, err := client.Do(context.Background(), &RequestData{Method: "GET", URI: server.URL}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "HTTP/1.1" {
+		t.Errorf("expected HTTP/1.1 by default, got %s", resp.Body)
+	}
+
+	ctx := WithOutboundOptions(context.Background(), OutboundOptions{HTTP2: true})
+	resp, err = client.Do(ctx, &RequestData{Method: "GET", URI: server.URL}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "HTTP/2.0" {
+		t.Errorf("expected HTTP/2.0 when enabled, got %s", resp.Body)
+	}
+}
+
+func TestHTTPClient_PerHostPools(t *testing.T) {
+	client := NewHTTPClient(time.Second)
+
+	if c, _ := client.clientFor("bidder.example.com", OutboundOptions{GzipRequests: true}); c != client.client {
+		t.Error("expected options without transport settings to use the shared pool")
+	}
+
+	opts := OutboundOptions{MaxConnsPerHost: 200, MaxIdleConnsPerHost: 40}
+	c, err := client.clientFor("bidder.example.com", opts)
+	if err != nil || c == client.client {
+		t.Fatal("expected a dedicated client for pool settings")
+	}
+	transport := c.Transport.(*http.Transport)
+	if transport.MaxConnsPerHost != 200 || transport.MaxIdleConnsPerHost != 40 || transport.ForceAttemptHTTP2 {
+		t.Errorf("unexpected transport settings: conns=%d idle=%d http2=%v",
+			transport.MaxConnsPerHost, transport.MaxIdleConnsPerHost, transport.ForceAttemptHTTP2)
+	}
+	if c.Timeout != time.Second {
+		t.Errorf("expected client timeout, got %v", c.Timeout)
+	}
+
+	if same, _ := client.clientFor("bidder.example.com", opts); same != c {
+		t.Error("expected the dedicated client to be reused")
+	}
+	if other, _ := client.clientFor("other.example.com", opts); other == c {
+		t.Error("expected a separate pool per host")
+	}
+}
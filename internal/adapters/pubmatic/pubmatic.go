@@ -2,15 +2,19 @@
 package pubmatic
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/thenexusengine/tne_springwire/internal/adapters"
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
 )
 
 const (
+	bidderCode      = "pubmatic"
 	defaultEndpoint = "https://hbopenbid.pubmatic.com/translator"
 )
 
@@ -27,13 +31,67 @@ func New(endpoint string) *Adapter {
 	return &Adapter{endpoint: endpoint}
 }
 
+// impParams are the documented imp.ext.pubmatic params
+type impParams struct {
+	PublisherID string `json:"publisherId"`
+	AdSlot      string `json:"adSlot"`
+	PmZoneID    string `json:"pmzoneid"`
+	KadFloor    string `json:"kadfloor"`
+}
+
+// impExt is the imp.ext shape the PubMatic endpoint expects
+type impExt struct {
+	PmZoneID string `json:"pmZoneId,omitempty"`
+}
+
+// bidExt carries the media type PubMatic reports for each bid
+type bidExt struct {
+	BidType *int `json:"BidType"`
+}
+
 // MakeRequests builds HTTP requests for PubMatic
+// The publisher ID is sent as site.publisher.id (or app.publisher.id) and adSlot "name@WxH"
+// becomes imp.tagid with the banner size. Imps without pubmatic params are sent unchanged.
 func (a *Adapter) MakeRequests(request *openrtb.BidRequest, extraInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
 	var errors []error
 
-	requestBody, err := json.Marshal(request)
+	reqCopy := *request
+	reqCopy.Imp = make([]openrtb.Imp, 0, len(request.Imp))
+
+	var publisherID string
+	for _, imp := range request.Imp {
+		var params impParams
+		ok, err := adapters.UnmarshalBidderParams(&imp, bidderCode, &params)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		if !ok {
+			reqCopy.Imp = append(reqCopy.Imp, imp)
+			continue
+		}
+
+		shaped, err := shapeImp(imp, params)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		if publisherID == "" {
+			publisherID = strings.TrimSpace(params.PublisherID)
+		}
+		reqCopy.Imp = append(reqCopy.Imp, shaped)
+	}
+
+	if len(reqCopy.Imp) == 0 {
+		return nil, errors
+	}
+	if publisherID != "" {
+		adapters.SetPublisherID(&reqCopy, publisherID)
+	}
+
+	requestBody, err := json.Marshal(reqCopy)
 	if err != nil {
-		return nil, []error{fmt.Errorf("failed to marshal request: %w", err)}
+		return nil, append(errors, fmt.Errorf("failed to marshal request: %w", err))
 	}
 
 	headers := http.Header{}
@@ -50,7 +108,75 @@ func (a *Adapter) MakeRequests(request *openrtb.BidRequest, extraInfo *adapters.
 	}, errors
 }
 
+// shapeImp maps documented params onto the imp fields PubMatic reads
+func shapeImp(imp openrtb.Imp, params impParams) (openrtb.Imp, error) {
+	slot, size, hasSize := strings.Cut(strings.TrimSpace(params.AdSlot), "@")
+	slot = strings.TrimSpace(slot)
+	if slot == "" {
+		return imp, fmt.Errorf("imp %s: invalid adSlot %q", imp.ID, params.AdSlot)
+	}
+	imp.TagID = slot
+
+	if imp.Banner != nil {
+		banner := *imp.Banner
+		if hasSize {
+			// "300x250:1" carries a slot index after the size
+			size, _, _ = strings.Cut(size, ":")
+			w, h, err := parseSize(size)
+			if err != nil {
+				return imp, fmt.Errorf("imp %s: invalid adSlot %q: %w", imp.ID, params.AdSlot, err)
+			}
+			banner.W, banner.H = w, h
+		} else if banner.W == 0 || banner.H == 0 {
+			if len(banner.Format) == 0 {
+				return imp, fmt.Errorf("imp %s: adSlot %q has no size and the banner has none", imp.ID, params.AdSlot)
+			}
+			banner.W, banner.H = banner.Format[0].W, banner.Format[0].H
+		}
+		imp.Banner = &banner
+	}
+
+	if kadfloor := strings.TrimSpace(params.KadFloor); kadfloor != "" {
+		floor, err := strconv.ParseFloat(kadfloor, 64)
+		if err != nil {
+			return imp, fmt.Errorf("imp %s: invalid kadfloor %q", imp.ID, params.KadFloor)
+		}
+		if floor > imp.BidFloor {
+			imp.BidFloor = floor
+			imp.BidFloorCur = "USD"
+		}
+	}
+
+	imp.Ext = nil
+	if params.PmZoneID != "" {
+		ext, err := json.Marshal(impExt{PmZoneID: params.PmZoneID})
+		if err != nil {
+			return imp, fmt.Errorf("imp %s: %w", imp.ID, err)
+		}
+		imp.Ext = ext
+	}
+	return imp, nil
+}
+
+// parseSize parses "WxH"
+func parseSize(size string) (int, int, error) {
+	ws, hs, ok := strings.Cut(strings.ToLower(strings.TrimSpace(size)), "x")
+	if !ok {
+		return 0, 0, fmt.Errorf("size must be WxH")
+	}
+	w, err := strconv.Atoi(strings.TrimSpace(ws))
+	if err != nil || w <= 0 {
+		return 0, 0, fmt.Errorf("invalid width %q", ws)
+	}
+	h, err := strconv.Atoi(strings.TrimSpace(hs))
+	if err != nil || h <= 0 {
+		return 0, 0, fmt.Errorf("invalid height %q", hs)
+	}
+	return w, h, nil
+}
+
 // MakeBids parses PubMatic responses into bids
+// The bid type comes from bid.ext.BidType, falling back to the imp's media type.
 func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.ResponseData) (*adapters.BidderResponse, []error) {
 	if responseData.StatusCode == http.StatusNoContent {
 		return nil, nil
@@ -78,10 +204,15 @@ func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.R
 	// P2-3: Build impression map once for O(1) lookups instead of O(n) per bid
 	impMap := adapters.BuildImpMap(request.Imp)
 
+	var errors []error
 	for _, seatBid := range bidResp.SeatBid {
 		for i := range seatBid.Bid {
 			bid := &seatBid.Bid[i]
-			bidType := adapters.GetBidTypeFromMap(bid, impMap)
+			bidType, err := getBidType(bid, impMap)
+			if err != nil {
+				errors = append(errors, err)
+				continue
+			}
 
 			response.Bids = append(response.Bids, &adapters.TypedBid{
 				Bid:     bid,
@@ -90,9 +221,37 @@ func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.R
 		}
 	}
 
-	return response, nil
+	return response, errors
 }
 
+// getBidType maps bid.ext.BidType (0 banner, 1 video, 2 native) to a bid type
+func getBidType(bid *openrtb.Bid, impMap map[string]*openrtb.Imp) (adapters.BidType, error) {
+	var ext bidExt
+	if len(bid.Ext) > 0 {
+		if err := json.Unmarshal(bid.Ext, &ext); err != nil {
+			return "", fmt.Errorf("bid %s: invalid ext: %w", bid.ID, err)
+		}
+	}
+	if ext.BidType == nil {
+		return adapters.GetBidTypeFromMap(bid, impMap), nil
+	}
+
+	switch *ext.BidType {
+	case 0:
+		return adapters.BidTypeBanner, nil
+	case 1:
+		return adapters.BidTypeVideo, nil
+	case 2:
+		return adapters.BidTypeNative, nil
+	}
+	return "", fmt.Errorf("bid %s: unknown BidType %d", bid.ID, *ext.BidType)
+}
+
+// paramsSchema is the JSON Schema for imp.ext.pubmatic params
+//
+//go:embed params.json
+var paramsSchema []byte
+
 // Info returns bidder information
 func Info() adapters.BidderInfo {
 	return adapters.BidderInfo{
@@ -116,9 +275,10 @@ func Info() adapters.BidderInfo {
 				},
 			},
 		},
-		GVLVendorID: 76,
-		Endpoint:    defaultEndpoint,
-		DemandType:  adapters.DemandTypePlatform, // Platform demand (obfuscated as "thenexusengine")
+		GVLVendorID:  76,
+		Endpoint:     defaultEndpoint,
+		DemandType:   adapters.DemandTypePlatform, // Platform demand (obfuscated as "thenexusengine")
+		ParamsSchema: paramsSchema,
 	}
 }
 
@@ -1,8 +1,11 @@
 package adapters
 
 import (
+	"encoding/json"
 	"fmt"
 	"sync"
+
+	"github.com/thenexusengine/tne_springwire/pkg/jsonschema"
 )
 
 // Registry holds all registered bidder adapters
@@ -27,13 +30,35 @@ func (r *Registry) Register(bidderCode string, adapter Adapter, info BidderInfo)
 		return fmt.Errorf("adapter already registered: %s", bidderCode)
 	}
 
-	r.adapters[bidderCode] = AdapterWithInfo{
+	awi := AdapterWithInfo{
 		Adapter: adapter,
 		Info:    info,
 	}
+	if len(info.ParamsSchema) > 0 {
+		schema, err := jsonschema.Compile(info.ParamsSchema)
+		if err != nil {
+			return fmt.Errorf("adapter %s: invalid params schema: %w", bidderCode, err)
+		}
+		awi.params = schema
+	}
+	r.adapters[bidderCode] = awi
 	return nil
 }
 
+// ParamsSchemas returns the raw params schema of every registered bidder that has one
+func (r *Registry) ParamsSchemas() map[string]json.RawMessage {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schemas := make(map[string]json.RawMessage, len(r.adapters))
+	for code, awi := range r.adapters {
+		if len(awi.Info.ParamsSchema) > 0 {
+			schemas[code] = awi.Info.ParamsSchema
+		}
+	}
+	return schemas
+}
+
 // Get retrieves an adapter by bidder code
 func (r *Registry) Get(bidderCode string) (AdapterWithInfo, bool) {
 	r.mu.RLock()
@@ -1,6 +1,7 @@
 package adapters
 
 import (
+	"encoding/json"
 	"sync"
 	"testing"
 
@@ -409,3 +410,45 @@ func BenchmarkRegistry_ListEnabledBidders(b *testing.B) {
 		r.ListEnabledBidders()
 	}
 }
+
+func TestRegistry_ParamsSchema(t *testing.T) {
+	r := NewRegistry()
+	schema := json.RawMessage(`{"type":"object","properties":{"placementId":{"type":"integer"}},"required":["placementId"]}`)
+	if err := r.Register("appnexus", &mockAdapter{}, BidderInfo{Enabled: true, ParamsSchema: schema}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r.Register("noschema", &mockAdapter{}, BidderInfo{Enabled: true})
+
+	if err := r.Register("broken", &mockAdapter{}, BidderInfo{ParamsSchema: json.RawMessage(`{"type":"float"}`)}); err == nil {
+		t.Error("expected error for invalid params schema")
+	}
+	if _, ok := r.Get("broken"); ok {
+		t.Error("expected bidder with invalid schema not to be registered")
+	}
+
+	awi, _ := r.Get("appnexus")
+	if err := awi.ValidateParams(json.RawMessage(`{"placementId":123}`)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := awi.ValidateParams(json.RawMessage(`{"placementId":"123"}`)); err == nil {
+		t.Error("expected error for invalid params")
+	}
+	other, _ := r.Get("noschema")
+	if err := other.ValidateParams(json.RawMessage(`{"anything":true}`)); err != nil {
+		t.Errorf("expected bidder without schema to accept any params, got %v", err)
+	}
+
+	// Aliases validate against their core bidder's schema
+	if err := r.RegisterAlias(AliasConfig{Alias: "anx_alias", Core: "appnexus"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	alias, _ := r.Get("anx_alias")
+	if err := alias.ValidateParams(json.RawMessage(`{}`)); err == nil {
+		t.Error("expected alias to use core params schema")
+	}
+
+	schemas := r.ParamsSchemas()
+	if len(schemas) != 2 || string(schemas["appnexus"]) != string(schema) {
+		t.Errorf("expected appnexus and alias schemas, got %v", schemas)
+	}
+}
@@ -2,6 +2,7 @@
 package rubicon
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -101,6 +102,11 @@ func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.R
 	return response, nil
 }
 
+// paramsSchema is the JSON Schema for imp.ext.rubicon params
+//
+//go:embed params.json
+var paramsSchema []byte
+
 // Info returns bidder information
 func Info() adapters.BidderInfo {
 	return adapters.BidderInfo{
@@ -122,9 +128,10 @@ func Info() adapters.BidderInfo {
 				},
 			},
 		},
-		GVLVendorID: 52,
-		Endpoint:    defaultEndpoint,
-		DemandType:  adapters.DemandTypePlatform, // Platform demand (obfuscated as "thenexusengine")
+		GVLVendorID:  52,
+		Endpoint:     defaultEndpoint,
+		DemandType:   adapters.DemandTypePlatform, // Platform demand (obfuscated as "thenexusengine")
+		ParamsSchema: paramsSchema,
 	}
 }
 
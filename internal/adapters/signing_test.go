@@ -0,0 +1,258 @@
+package adapters
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignRequest(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"id":"req-1"}`)
+
+	headers := http.Header{}
+	opts := OutboundOptions{SigningScheme: SigningHMACSHA256, SigningKey: "shared-key"}
+	if err := signRequest(headers, body, opts, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if headers.Get(DefaultSignatureTimestampHeader) != "1700000000" {
+		t.Errorf("unexpected timestamp header: %q", headers.Get(DefaultSignatureTimestampHeader))
+	}
+	want := Sign([]byte("shared-key"), "1700000000", body)
+	if got := headers.Get(DefaultSignatureHeader); got != want || len(got) != 64 {
+		t.Errorf("expected signature %s, got %s", want, got)
+	}
+	if Sign([]byte("shared-key"), "1700000001", body) == want {
+		t.Error("expected the timestamp to be signed")
+	}
+
+	headers = http.Header{}
+	opts.SigningHeader = "X-DSP-Signature"
+	opts.SigningTimestampHeader = "X-DSP-Time"
+	if err := signRequest(headers, body, opts, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if headers.Get("X-DSP-Signature") != want || headers.Get("X-DSP-Time") != "1700000000" {
+		t.Errorf("expected custom headers, got %v", headers)
+	}
+
+	if err := signRequest(http.Header{}, body, OutboundOptions{SigningScheme: "rsa"}, now); err == nil {
+		t.Error("expected unsupported scheme to be rejected")
+	}
+	if err := signRequest(http.Header{}, body, OutboundOptions{SigningScheme: SigningHMACSHA256}, now); err == nil {
+		t.Error("expected missing key to be rejected")
+	}
+}
+
+func TestHTTPClientDo_SignsWireBody(t *testing.T) {
+	var gotSignature, gotTimestamp string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(DefaultSignatureHeader)
+		gotTimestamp = r.Header.Get(DefaultSignatureTimestampHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(5 * time.Second)
+	ctx := WithOutboundOptions(context.Background(), OutboundOptions{
+		GzipRequests:  true,
+		SigningScheme: SigningHMACSHA256,
+		SigningKey:    "shared-key",
+	})
+	if _, err := client.Do(ctx, &RequestData{Method: "POST", URI: server.URL, Body: []byte(`{"id":"req-1"}`)}, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ts, err := strconv.ParseInt(gotTimestamp, 10, 64); err != nil || time.Since(time.Unix(ts, 0)) > time.Minute {
+		t.Errorf("expected a current timestamp, got %q", gotTimestamp)
+	}
+	if want := Sign([]byte("shared-key"), gotTimestamp, gotBody); gotSignature != want {
+		t.Errorf("expected the signature to cover the gzipped body, got %s want %s", gotSignature, want)
+	}
+
+	ctx = WithOutboundOptions(context.Background(), OutboundOptions{SigningScheme: SigningHMACSHA256})
+	if _, err := client.Do(ctx, &RequestData{Method: "POST", URI: server.URL, Body: []byte(`{}`)}, 0); err == nil {
+		t.Error("expected a request without a signing key to fail instead of being sent unsigned")
+	}
+}
+
+// testPKI is a private CA with a server certificate for 127.0.0.1 and a client certificate
+type testPKI struct {
+	caFile, clientCertFile, clientKeyFile string
+	caPool                                *x509.CertPool
+	serverCert                            tls.Certificate
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create CA: %v", err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+
+	issue := func(serial int64, usage x509.ExtKeyUsage, ips []net.IP) ([]byte, []byte) {
+		key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "test"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  ips,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatalf("failed to issue certificate: %v", err)
+		}
+		keyDER, _ := x509.MarshalECPrivateKey(key)
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	}
+
+	pki := &testPKI{
+		caFile:         filepath.Join(dir, "ca.pem"),
+		clientCertFile: filepath.Join(dir, "client.pem"),
+		clientKeyFile:  filepath.Join(dir, "client-key.pem"),
+		caPool:         x509.NewCertPool(),
+	}
+	pki.caPool.AddCert(caCert)
+
+	serverCertPEM, serverKeyPEM := issue(2, x509.ExtKeyUsageServerAuth, []net.IP{net.ParseIP("127.0.0.1")})
+	if pki.serverCert, err = tls.X509KeyPair(serverCertPEM, serverKeyPEM); err != nil {
+		t.Fatalf("failed to load server certificate: %v", err)
+	}
+	clientCertPEM, clientKeyPEM := issue(3, x509.ExtKeyUsageClientAuth, nil)
+
+	for path, data := range map[string][]byte{
+		pki.caFile:         pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		pki.clientCertFile: clientCertPEM,
+		pki.clientKeyFile:  clientKeyPEM,
+	} {
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return pki
+}
+
+func TestHTTPClientDo_MutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{pki.serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pki.caPool,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	client := NewHTTPClient(5 * time.Second)
+	req := &RequestData{Method: "GET", URI: server.URL}
+
+	// The shared pool neither trusts the private CA nor presents a certificate
+	if _, err := client.Do(context.Background(), req, 0); err == nil {
+		t.Error("expected the shared pool to fail against the private CA")
+	}
+
+	// CA only: the server's certificate verifies but the server requires a client certificate
+	ctx := WithOutboundOptions(context.Background(), OutboundOptions{CAFile: pki.caFile})
+	if _, err := client.Do(ctx, req, 0); err == nil {
+		t.Error("expected the handshake to fail without a client certificate")
+	}
+
+	ctx = WithOutboundOptions(context.Background(), OutboundOptions{
+		CAFile:         pki.caFile,
+		ClientCertFile: pki.clientCertFile,
+		ClientKeyFile:  pki.clientKeyFile,
+	})
+	resp, err := client.Do(ctx, req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "test" {
+		t.Errorf("expected the client certificate to be presented, got %q", resp.Body)
+	}
+}
+
+func TestOutboundOptions_Validate(t *testing.T) {
+	pki := newTestPKI(t)
+	valid := []OutboundOptions{
+		{},
+		{SigningScheme: SigningHMACSHA256, SigningKey: "k"},
+		{CAFile: pki.caFile, ClientCertFile: pki.clientCertFile, ClientKeyFile: pki.clientKeyFile},
+	}
+	for _, opts := range valid {
+		if err := opts.Validate(); err != nil {
+			t.Errorf("%+v: unexpected error: %v", opts, err)
+		}
+	}
+
+	invalid := map[string]OutboundOptions{
+		"negative pool size":    {MaxConnsPerHost: -1},
+		"unknown scheme":        {SigningScheme: "rsa-sha256", SigningKey: "k"},
+		"scheme without key":    {SigningScheme: SigningHMACSHA256},
+		"missing CA file":       {CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"CA file without certs": {CAFile: pki.clientKeyFile},
+		"cert without key":      {ClientCertFile: pki.clientCertFile},
+		"mismatched key":        {ClientCertFile: pki.caFile, ClientKeyFile: pki.clientKeyFile},
+	}
+	for name, opts := range invalid {
+		if err := opts.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestOutboundOptions_Merge(t *testing.T) {
+	own := OutboundOptions{HTTP2: true, MaxConnsPerHost: 50, ClientCertFile: "own.pem", ClientKeyFile: "own-key.pem", SigningScheme: SigningHMACSHA256, SigningKey: "own"}
+	override := OutboundOptions{GzipRequests: true, MaxConnsPerHost: 100, ClientCertFile: "renewed.pem"}
+
+	want := OutboundOptions{
+		GzipRequests:    true,
+		HTTP2:           true,
+		MaxConnsPerHost: 100,
+		ClientCertFile:  "renewed.pem",
+		ClientKeyFile:   "own-key.pem",
+		SigningScheme:   SigningHMACSHA256,
+		SigningKey:      "own",
+	}
+	if got := own.Merge(override); got != want {
+		t.Errorf("expected override set field by field, got %+v", got)
+	}
+	if got := own.Merge(OutboundOptions{}); got != own {
+		t.Errorf("expected an empty override to keep the adapter's options, got %+v", got)
+	}
+}
@@ -3,6 +3,7 @@
 package unruly
 
 import (
+	_ "embed"
 	"fmt"
 
 	"github.com/thenexusengine/tne_springwire/internal/adapters"
@@ -29,6 +30,11 @@ func New(endpoint string) *Adapter {
 	}
 }
 
+// paramsSchema is the JSON Schema for imp.ext.unruly params
+//
+//go:embed params.json
+var paramsSchema []byte
+
 // Info returns Unruly bidder information
 func Info() adapters.BidderInfo {
 	return adapters.BidderInfo{
@@ -39,6 +45,7 @@ func Info() adapters.BidderInfo {
 		Capabilities: &adapters.CapabilitiesInfo{
 			Site: &adapters.PlatformInfo{MediaTypes: []adapters.BidType{adapters.BidTypeBanner, adapters.BidTypeVideo}},
 		},
+		ParamsSchema: paramsSchema,
 	}
 }
 
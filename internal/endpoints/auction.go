@@ -7,14 +7,19 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	log "github.com/rs/zerolog/log"
 
 	"github.com/thenexusengine/tne_springwire/internal/exchange"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/internal/usersync"
 	"github.com/thenexusengine/tne_springwire/pkg/logger"
 )
 
@@ -45,6 +50,7 @@ func GetPublisherID(ctx context.Context) (string, bool) {
 // AuctionHandler handles /openrtb2/auction requests
 type AuctionHandler struct {
 	exchange *exchange.Exchange
+	sharedID *usersync.SharedIDManager
 }
 
 // NewAuctionHandler creates a new auction handler
@@ -52,6 +58,11 @@ func NewAuctionHandler(ex *exchange.Exchange) *AuctionHandler {
 	return &AuctionHandler{exchange: ex}
 }
 
+// SetSharedIDManager enables first-party ID minting for requests without a user ID
+func (h *AuctionHandler) SetSharedIDManager(m *usersync.SharedIDManager) {
+	h.sharedID = m
+}
+
 // ServeHTTP handles the auction request
 func (h *AuctionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -83,6 +94,9 @@ func (h *AuctionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Attach the first-party ID (and persist it) before the exchange filters EIDs
+	h.applySharedID(w, r, &bidRequest)
+
 	// Build auction request
 	// P2-1: Debug mode requires authentication to prevent information disclosure
 	debugRequested := r.URL.Query().Get("debug") == "1"
@@ -170,6 +184,12 @@ func (h *AuctionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		if extBytes, err := json.Marshal(ext); err == nil {
 			response.Ext = extBytes
 		}
+	} else if len(result.SeatNonBid) > 0 {
+		// ext.prebid.returnallbidstatus: report non-bids without the rest of the debug info
+		ext := &openrtb.BidResponseExt{SeatNonBid: result.SeatNonBid}
+		if extBytes, err := json.Marshal(ext); err == nil {
+			response.Ext = extBytes
+		}
 	}
 
 	// Write response
@@ -180,6 +200,65 @@ func (h *AuctionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// applySharedID resolves the first-party ID cookie, adds it to the request as
+// user.id and a pubcid.org EID, and refreshes the cookie on the response.
+// Storage is skipped when the user opted out or consent does not permit it.
+// Only site requests sent by a browser get an ID: app and server-to-server
+// callers have no cookie jar, so a minted ID would change on every request.
+func (h *AuctionHandler) applySharedID(w http.ResponseWriter, r *http.Request, req *openrtb.BidRequest) {
+	if h.sharedID == nil || !h.sharedID.IsEnabled() {
+		return
+	}
+	if req.Site == nil || !isBrowserRequest(r) {
+		return
+	}
+
+	domain := cookieDomain(r)
+
+	if usersync.ParseCookie(r).IsOptOut() || !middleware.AllowsIdentifierStorage(req) {
+		// Remove any ID set before consent was withdrawn
+		if h.sharedID.Read(r) != nil {
+			http.SetCookie(w, h.sharedID.ExpireCookie(domain))
+		}
+		return
+	}
+
+	sid, minted, err := h.sharedID.Resolve(r)
+	if err != nil {
+		logger.Log.Warn().Err(err).Msg("Failed to resolve shared ID")
+		return
+	}
+
+	h.sharedID.Apply(req, sid)
+
+	if httpCookie, err := h.sharedID.ToHTTPCookie(sid, domain); err == nil {
+		http.SetCookie(w, httpCookie)
+	} else {
+		logger.Log.Error().Err(err).Msg("Failed to create shared ID cookie")
+	}
+
+	if minted {
+		logger.Log.Debug().Str("request_id", req.ID).Msg("Minted new shared ID")
+	}
+}
+
+// isBrowserRequest reports whether the auction call came from a browser.
+// Browsers send Origin on cross-origin and POST requests, and Sec-Fetch-Site on all
+// requests; server-to-server callers set neither.
+func isBrowserRequest(r *http.Request) bool {
+	return r.Header.Get("Origin") != "" || r.Header.Get("Sec-Fetch-Site") != ""
+}
+
+// cookieDomain extracts the host (without port) for setting cookies
+func cookieDomain(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		// No port, e.g. "example.com" or "[::1]"
+		return strings.Trim(r.Host, "[]")
+	}
+	return host
+}
+
 // validateBidRequest validates the bid request
 func validateBidRequest(req *openrtb.BidRequest) error {
 	if req.ID == "" {
@@ -234,12 +313,32 @@ func buildResponseExt(result *exchange.AuctionResponse) *openrtb.BidResponseExt
 			ext.Errors[bidder] = messages
 		}
 
+		ext.SeatNonBid = buildSeatNonBid(result.DebugInfo.NonBids)
+
 		ext.TMMaxRequest = int(result.DebugInfo.TotalLatency.Milliseconds())
 	}
 
 	return ext
 }
 
+// buildSeatNonBid converts rejected bids per seat into ext.seatnonbid, ordered by seat
+func buildSeatNonBid(nonBids map[string][]openrtb.NonBid) []openrtb.SeatNonBid {
+	if len(nonBids) == 0 {
+		return nil
+	}
+	seats := make([]string, 0, len(nonBids))
+	for seat := range nonBids {
+		seats = append(seats, seat)
+	}
+	sort.Strings(seats)
+
+	seatNonBid := make([]openrtb.SeatNonBid, 0, len(seats))
+	for _, seat := range seats {
+		seatNonBid = append(seatNonBid, openrtb.SeatNonBid{Seat: seat, NonBid: nonBids[seat]})
+	}
+	return seatNonBid
+}
+
 // writeError writes an error response
 func writeError(w http.ResponseWriter, message string, status int) {
 	w.Header().Set("Content-Type", "application/json")
@@ -355,3 +454,71 @@ func (h *InfoBiddersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		log.Error().Err(err).Msg("failed to encode bidders response")
 	}
 }
+
+// ParamsSchemaLister is an interface for listing bidder params schemas
+type ParamsSchemaLister interface {
+	ParamsSchemas() map[string]json.RawMessage
+}
+
+// BidderParamsHandler handles /bidders/params requests
+// It returns the JSON Schema for each bidder's imp.ext.<bidder> params, keyed by bidder code.
+type BidderParamsHandler struct {
+	registry ParamsSchemaLister
+}
+
+// NewBidderParamsHandler creates a handler that serves params schemas from the registry
+func NewBidderParamsHandler(registry ParamsSchemaLister) *BidderParamsHandler {
+	return &BidderParamsHandler{registry: registry}
+}
+
+// ServeHTTP handles bidders/params requests
+func (h *BidderParamsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	schemas := make(map[string]json.RawMessage)
+	if h.registry != nil {
+		schemas = h.registry.ParamsSchemas()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(schemas); err != nil {
+		log.Error().Err(err).Msg("failed to encode bidder params response")
+	}
+}
+
+// BidderHealthReporter is an interface for reporting per-bidder circuit breaker state
+type BidderHealthReporter interface {
+	BidderHealth() []exchange.BidderHealthStatus
+}
+
+// BidderHealthHandler handles /admin/bidders/health requests
+// It returns each bidder's circuit breaker state and recent error rate.
+type BidderHealthHandler struct {
+	reporter BidderHealthReporter
+}
+
+// NewBidderHealthHandler creates a handler that serves bidder health from the reporter
+func NewBidderHealthHandler(reporter BidderHealthReporter) *BidderHealthHandler {
+	return &BidderHealthHandler{reporter: reporter}
+}
+
+// ServeHTTP handles bidders/health requests
+func (h *BidderHealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	statuses := []exchange.BidderHealthStatus{}
+	if h.reporter != nil {
+		statuses = h.reporter.BidderHealth()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"bidders": statuses}); err != nil {
+		log.Error().Err(err).Msg("failed to encode bidder health response")
+	}
+}
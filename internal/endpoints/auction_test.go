@@ -15,6 +15,7 @@ import (
 	"github.com/thenexusengine/tne_springwire/internal/adapters"
 	"github.com/thenexusengine/tne_springwire/internal/exchange"
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/internal/usersync"
 )
 
 // Mock adapter for testing
@@ -694,6 +695,29 @@ func TestBuildResponseExt_WithErrors(t *testing.T) {
 	}
 }
 
+func TestBuildResponseExt_WithSeatNonBid(t *testing.T) {
+	result := &exchange.AuctionResponse{
+		DebugInfo: &exchange.DebugInfo{
+			BidderLatencies: map[string]time.Duration{},
+			NonBids: map[string][]openrtb.NonBid{
+				"rubicon":  {{ImpID: "imp1", StatusCode: openrtb.NonBidNoBid}},
+				"appnexus": {{ImpID: "imp1", StatusCode: openrtb.NonBidCreativeFiltered}},
+			},
+		},
+	}
+	ext := buildResponseExt(result)
+
+	if len(ext.SeatNonBid) != 2 {
+		t.Fatalf("expected 2 seats, got %d", len(ext.SeatNonBid))
+	}
+	if ext.SeatNonBid[0].Seat != "appnexus" || ext.SeatNonBid[1].Seat != "rubicon" {
+		t.Errorf("expected seats ordered by name, got %s, %s", ext.SeatNonBid[0].Seat, ext.SeatNonBid[1].Seat)
+	}
+	if ext.SeatNonBid[0].NonBid[0].StatusCode != openrtb.NonBidCreativeFiltered {
+		t.Errorf("expected creative filtered code, got %d", ext.SeatNonBid[0].NonBid[0].StatusCode)
+	}
+}
+
 // Test writeError
 func TestWriteError(t *testing.T) {
 	w := httptest.NewRecorder()
@@ -983,3 +1007,243 @@ func BenchmarkAuctionHandler_ValidRequest(b *testing.B) {
 		handler.ServeHTTP(w, req)
 	}
 }
+
+func findCookie(w *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, c := range w.Result().Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestAuctionHandler_SharedID_Minted(t *testing.T) {
+	registry := adapters.NewRegistry()
+	ex := exchange.New(registry, &exchange.Config{
+		DefaultTimeout: 100 * time.Millisecond,
+	})
+	handler := NewAuctionHandler(ex)
+	handler.SetSharedIDManager(usersync.NewSharedIDManager(usersync.DefaultSharedIDConfig()))
+
+	body, _ := json.Marshal(validBidRequest())
+	req := httptest.NewRequest("POST", "/openrtb2/auction", bytes.NewReader(body))
+	req.Header.Set("Origin", "https://example.com")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	cookie := findCookie(w, usersync.SharedIDCookieName)
+	if cookie == nil {
+		t.Fatal("expected shared ID cookie to be set")
+	}
+	if cookie.Domain != "example.com" {
+		t.Errorf("expected cookie domain example.com, got %s", cookie.Domain)
+	}
+}
+
+func TestApplySharedID(t *testing.T) {
+	gdpr := 1
+	manager := usersync.NewSharedIDManager(usersync.DefaultSharedIDConfig())
+	handler := &AuctionHandler{sharedID: manager}
+
+	existing, _ := manager.ToHTTPCookie(&usersync.SharedID{ID: "existing-id", Created: time.Now()}, "")
+
+	optOut := usersync.NewCookie()
+	optOut.SetOptOut(true)
+	optOutCookie, _ := optOut.ToHTTPCookie("")
+
+	tests := []struct {
+		name        string
+		regs        *openrtb.Regs
+		cookies     []*http.Cookie
+		wantID      string
+		wantApplied bool
+		wantExpire  bool
+	}{
+		{name: "mints new id", wantApplied: true},
+		{name: "reuses existing id", cookies: []*http.Cookie{existing}, wantID: "existing-id", wantApplied: true},
+		{name: "coppa", regs: &openrtb.Regs{COPPA: 1}},
+		{name: "coppa expires existing", regs: &openrtb.Regs{COPPA: 1}, cookies: []*http.Cookie{existing}, wantExpire: true},
+		{name: "gdpr without consent", regs: &openrtb.Regs{GDPR: &gdpr}, cookies: []*http.Cookie{existing}, wantExpire: true},
+		{name: "ccpa opt-out", regs: &openrtb.Regs{USPrivacy: "1YYN"}},
+		{name: "user opted out", cookies: []*http.Cookie{optOutCookie, existing}, wantExpire: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bidReq := validBidRequest()
+			bidReq.Regs = tt.regs
+
+			req := httptest.NewRequest("POST", "/openrtb2/auction", nil)
+			req.Header.Set("Origin", "https://example.com")
+			for _, c := range tt.cookies {
+				req.AddCookie(c)
+			}
+			w := httptest.NewRecorder()
+
+			handler.applySharedID(w, req, bidReq)
+
+			cookie := findCookie(w, usersync.SharedIDCookieName)
+			if !tt.wantApplied {
+				if bidReq.User != nil && len(bidReq.User.EIDs) > 0 {
+					t.Error("expected no EIDs to be added")
+				}
+				if tt.wantExpire && (cookie == nil || cookie.MaxAge >= 0) {
+					t.Error("expected shared ID cookie to be expired")
+				}
+				if !tt.wantExpire && cookie != nil {
+					t.Error("expected no shared ID cookie")
+				}
+				return
+			}
+
+			if bidReq.User == nil || bidReq.User.ID == "" {
+				t.Fatal("expected user.id to be set")
+			}
+			if tt.wantID != "" && bidReq.User.ID != tt.wantID {
+				t.Errorf("expected user.id %s, got %s", tt.wantID, bidReq.User.ID)
+			}
+			if len(bidReq.User.EIDs) != 1 || bidReq.User.EIDs[0].Source != usersync.SharedIDSource {
+				t.Errorf("expected pubcid.org EID, got %+v", bidReq.User.EIDs)
+			}
+			if cookie == nil || cookie.MaxAge <= 0 {
+				t.Error("expected shared ID cookie to be refreshed")
+			}
+		})
+	}
+}
+
+func TestApplySharedID_NoCookieJar(t *testing.T) {
+	handler := &AuctionHandler{sharedID: usersync.NewSharedIDManager(usersync.DefaultSharedIDConfig())}
+
+	browser := httptest.NewRequest("POST", "/openrtb2/auction", nil)
+	browser.Header.Set("Origin", "https://example.com")
+
+	appReq := validBidRequest()
+	appReq.Site = nil
+	appReq.App = &openrtb.App{ID: "app-1", Bundle: "com.example.app"}
+
+	tests := []struct {
+		name   string
+		r      *http.Request
+		bidReq *openrtb.BidRequest
+	}{
+		{"app request", browser, appReq},
+		{"server-to-server site request", httptest.NewRequest("POST", "/openrtb2/auction", nil), validBidRequest()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.applySharedID(w, tt.r, tt.bidReq)
+
+			if tt.bidReq.User != nil {
+				t.Errorf("expected no user ID to be minted, got %+v", tt.bidReq.User)
+			}
+			if findCookie(w, usersync.SharedIDCookieName) != nil {
+				t.Error("expected no shared ID cookie")
+			}
+		})
+	}
+}
+
+func TestApplySharedID_Disabled(t *testing.T) {
+	handler := &AuctionHandler{sharedID: usersync.NewSharedIDManager(&usersync.SharedIDConfig{Enabled: false})}
+
+	bidReq := validBidRequest()
+	w := httptest.NewRecorder()
+	handler.applySharedID(w, httptest.NewRequest("POST", "/openrtb2/auction", nil), bidReq)
+
+	if bidReq.User != nil {
+		t.Error("expected request to be unmodified when disabled")
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("expected no cookies when disabled")
+	}
+}
+
+func TestBidderParamsHandler(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("rubicon", &mockAdapter{}, adapters.BidderInfo{
+		Enabled:      true,
+		ParamsSchema: json.RawMessage(`{"type":"object","required":["zoneId"]}`),
+	})
+	registry.Register("noschema", &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+	handler := NewBidderParamsHandler(registry)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/bidders/params", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Error("expected application/json content type")
+	}
+	var schemas map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &schemas); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(schemas) != 1 || string(schemas["rubicon"]) != `{"type":"object","required":["zoneId"]}` {
+		t.Errorf("unexpected schemas: %v", schemas)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/bidders/params", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+// staticHealthReporter reports fixed bidder health
+type staticHealthReporter []exchange.BidderHealthStatus
+
+func (s staticHealthReporter) BidderHealth() []exchange.BidderHealthStatus {
+	return s
+}
+
+func TestBidderHealthHandler(t *testing.T) {
+	handler := NewBidderHealthHandler(staticHealthReporter{
+		{Bidder: "appnexus", State: "closed", ErrorRate: 0.1, WindowCalls: 40},
+		{Bidder: "rubicon", State: "open", ErrorRate: 0.8, WindowCalls: 50},
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/admin/bidders/health", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var body struct {
+		Bidders []exchange.BidderHealthStatus `json:"bidders"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(body.Bidders) != 2 || body.Bidders[1].Bidder != "rubicon" || body.Bidders[1].State != "open" {
+		t.Errorf("unexpected bidder health: %+v", body.Bidders)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/admin/bidders/health", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestCookieDomain(t *testing.T) {
+	for host, want := range map[string]string{
+		"example.com":      "example.com",
+		"example.com:8000": "example.com",
+		"[::1]:8000":       "::1",
+		"[::1]":            "::1",
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+		r.Host = host
+		if got := cookieDomain(r); got != want {
+			t.Errorf("%s: expected %q, got %q", host, want, got)
+		}
+	}
+}
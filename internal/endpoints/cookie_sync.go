@@ -55,9 +55,10 @@ type BidderSyncStatus struct {
 
 // CookieSyncHandler handles cookie sync requests
 type CookieSyncHandler struct {
-	syncers  map[string]*usersync.Syncer
-	hostURL  string
-	maxSyncs int
+	syncers    map[string]*usersync.Syncer
+	syncerKeys map[string]string // lowercase bidder alias -> lowercase syncer key
+	hostURL    string
+	maxSyncs   int
 }
 
 // CookieSyncConfig holds configuration for the cookie sync handler
@@ -65,6 +66,8 @@ type CookieSyncConfig struct {
 	HostURL     string
 	MaxSyncs    int
 	SyncConfigs map[string]usersync.SyncerConfig
+	// SyncerKeys maps bidder aliases to the syncer key whose UID they share
+	SyncerKeys map[string]string
 }
 
 // DefaultCookieSyncConfig returns default configuration
@@ -84,10 +87,16 @@ func NewCookieSyncHandler(config *CookieSyncConfig) *CookieSyncHandler {
 		syncers[code] = usersync.NewSyncer(syncConfig, config.HostURL)
 	}
 
+	syncerKeys := make(map[string]string, len(config.SyncerKeys))
+	for bidder, key := range config.SyncerKeys {
+		syncerKeys[strings.ToLower(bidder)] = strings.ToLower(key)
+	}
+
 	return &CookieSyncHandler{
-		syncers:  syncers,
-		hostURL:  config.HostURL,
-		maxSyncs: config.MaxSyncs,
+		syncers:    syncers,
+		syncerKeys: syncerKeys,
+		hostURL:    config.HostURL,
+		maxSyncs:   config.MaxSyncs,
 	}
 }
 
@@ -136,12 +145,18 @@ func (h *CookieSyncHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	syncCount := 0
+	synced := make(map[string]struct{}, len(biddersToSync))
 	for _, bidderCode := range biddersToSync {
 		if syncCount >= req.Limit {
 			break
 		}
 
-		syncer, ok := h.syncers[strings.ToLower(bidderCode)]
+		// An alias and its core share a syncer key; sync it once
+		syncerKey := h.syncerKey(bidderCode)
+		if _, done := synced[strings.ToLower(syncerKey)]; done {
+			continue
+		}
+		syncer, ok := h.syncers[strings.ToLower(syncerKey)]
 		if !ok {
 			response.BidderStatus = append(response.BidderStatus, BidderSyncStatus{
 				Bidder: bidderCode,
@@ -155,7 +170,7 @@ func (h *CookieSyncHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Check if already synced
-		if cookie.HasUID(bidderCode) {
+		if cookie.HasUID(syncerKey) {
 			continue
 		}
 
@@ -182,6 +197,7 @@ func (h *CookieSyncHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			NoCookie: true,
 			UserSync: syncInfo,
 		})
+		synced[strings.ToLower(syncerKey)] = struct{}{}
 		syncCount++
 	}
 
@@ -270,7 +286,7 @@ func (h *CookieSyncHandler) getBiddersToSync(req CookieSyncRequest, cookie *user
 	if cookie != nil {
 		needsSync := make([]string, 0, len(bidders))
 		for _, bidder := range bidders {
-			if !cookie.HasUID(bidder) {
+			if !cookie.HasUID(h.syncerKey(bidder)) {
 				needsSync = append(needsSync, bidder)
 			}
 		}
@@ -297,6 +313,14 @@ func (h *CookieSyncHandler) respondJSON(w http.ResponseWriter, data interface{})
 	}
 }
 
+// syncerKey returns the key a bidder's UID is synced under; aliases map to the key they share
+func (h *CookieSyncHandler) syncerKey(bidderCode string) string {
+	if key, ok := h.syncerKeys[strings.ToLower(bidderCode)]; ok {
+		return key
+	}
+	return bidderCode
+}
+
 // AddSyncer adds a syncer for a bidder
 func (h *CookieSyncHandler) AddSyncer(config usersync.SyncerConfig) {
 	h.syncers[strings.ToLower(config.BidderCode)] = usersync.NewSyncer(config, h.hostURL)
@@ -304,6 +304,46 @@ func TestCookieSyncHandler_UnsupportedBidder(t *testing.T) {
 	}
 }
 
+func TestCookieSyncHandler_AliasSyncerKey(t *testing.T) {
+	config := DefaultCookieSyncConfig("https://test.example.com")
+	config.SyncerKeys = map[string]string{"AppNexus_Video": "appnexus"}
+	handler := NewCookieSyncHandler(config)
+
+	// The alias and its core share one syncer, so only one sync is returned
+	reqBody := CookieSyncRequest{Bidders: []string{"appnexus_video", "appnexus"}}
+	body, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest("POST", "/cookie_sync", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var resp CookieSyncResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.BidderStatus) != 1 || resp.BidderStatus[0].UserSync == nil {
+		t.Fatalf("expected one sync for the shared key, got %+v", resp.BidderStatus)
+	}
+	if !strings.Contains(resp.BidderStatus[0].UserSync.URL, "adnxs.com") {
+		t.Errorf("expected the core syncer URL, got %s", resp.BidderStatus[0].UserSync.URL)
+	}
+
+	// A UID under the shared key counts as synced for the alias
+	cookie := usersync.NewCookie()
+	cookie.SetUID("appnexus", "existing-uid")
+	httpCookie, _ := cookie.ToHTTPCookie("example.com")
+
+	body, _ = json.Marshal(CookieSyncRequest{Bidders: []string{"appnexus_video"}})
+	req = httptest.NewRequest("POST", "/cookie_sync", bytes.NewReader(body))
+	req.AddCookie(httpCookie)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	resp = CookieSyncResponse{}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.BidderStatus) != 0 {
+		t.Errorf("expected alias to be treated as synced, got %+v", resp.BidderStatus)
+	}
+}
+
 func TestCookieSyncHandler_GDPR(t *testing.T) {
 	handler := createTestHandler()
 
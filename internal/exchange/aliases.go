@@ -0,0 +1,170 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// maxRequestAliases caps the aliases a request may declare, since each adds an outbound call
+const maxRequestAliases = 5
+
+// requestAliasesKey is the context key for aliases declared in ext.prebid.aliases
+type requestAliasesKey struct{}
+
+// requestAliases resolves the aliases declared in ext.prebid.aliases against the registry
+// Request aliases keep the core bidder's demand type and metrics label so callers cannot
+// change how demand is reported; only the GVL vendor ID may be set, via ext.prebid.aliasgvlids.
+// Aliases that shadow a registered bidder or name an unknown core are ignored with a warning,
+// as are aliases past maxRequestAliases.
+func (e *Exchange) requestAliases(req *openrtb.BidRequest) (map[string]adapters.AdapterWithInfo, []string) {
+	if req == nil || len(req.Ext) == 0 {
+		return nil, nil
+	}
+	var ext openrtb.BidRequestExt
+	if err := json.Unmarshal(req.Ext, &ext); err != nil || ext.Prebid == nil || len(ext.Prebid.Aliases) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(ext.Prebid.Aliases))
+	for alias := range ext.Prebid.Aliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+
+	var warnings []string
+	aliases := make(map[string]adapters.AdapterWithInfo, len(names))
+	for _, alias := range names {
+		coreCode := ext.Prebid.Aliases[alias]
+		if _, exists := e.registry.Get(alias); exists {
+			warnings = append(warnings, fmt.Sprintf("alias %s ignored: bidder already registered", alias))
+			continue
+		}
+		core, ok := e.registry.Get(coreCode)
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("alias %s ignored: unknown core bidder %s", alias, coreCode))
+			continue
+		}
+		if len(aliases) >= maxRequestAliases {
+			warnings = append(warnings, fmt.Sprintf("alias %s ignored: at most %d aliases per request", alias, maxRequestAliases))
+			continue
+		}
+
+		info := adapters.AliasInfo(coreCode, core.Info, adapters.AliasConfig{
+			Alias:       alias,
+			Core:        coreCode,
+			GVLVendorID: ext.Prebid.AliasGVLIDs[alias],
+		})
+		info.MetricsLabel = bidderMetricsLabel(coreCode, core.Info)
+		awi := core
+		awi.Info = info
+		aliases[alias] = awi
+	}
+
+	if len(aliases) == 0 {
+		return nil, warnings
+	}
+	return aliases, warnings
+}
+
+// useAliasParams puts an alias's imp params under its core bidder's code on the alias's request
+// Adapters read params under their own code, so imp.ext.<alias> and imp.ext.prebid.bidder.<alias>
+// replace the core bidder's entries; an imp without params for the alias keeps none for the core,
+// so the alias never sends the core seat's params. req must be the alias's cloned request.
+func useAliasParams(req *openrtb.BidRequest, alias, coreCode string) {
+	for i := range req.Imp {
+		imp := &req.Imp[i]
+		if len(imp.Ext) == 0 {
+			continue
+		}
+		var ext map[string]json.RawMessage
+		if err := json.Unmarshal(imp.Ext, &ext); err != nil {
+			continue
+		}
+
+		params, hasParams := ext[alias]
+		delete(ext, alias)
+		delete(ext, coreCode)
+		if hasParams {
+			ext[coreCode] = params
+		}
+
+		if raw, ok := ext["prebid"]; ok {
+			var prebid map[string]json.RawMessage
+			var bidders map[string]json.RawMessage
+			if json.Unmarshal(raw, &prebid) == nil && json.Unmarshal(prebid["bidder"], &bidders) == nil && bidders != nil {
+				params, hasParams := bidders[alias]
+				delete(bidders, alias)
+				delete(bidders, coreCode)
+				if hasParams {
+					bidders[coreCode] = params
+				}
+				if b, err := json.Marshal(bidders); err == nil {
+					prebid["bidder"] = b
+				}
+				if p, err := json.Marshal(prebid); err == nil {
+					ext["prebid"] = p
+				}
+			}
+		}
+
+		if b, err := json.Marshal(ext); err == nil {
+			imp.Ext = b
+		}
+	}
+}
+
+// withRequestAliases stores the request's resolved aliases on the auction context
+func withRequestAliases(ctx context.Context, aliases map[string]adapters.AdapterWithInfo) context.Context {
+	if len(aliases) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, requestAliasesKey{}, aliases)
+}
+
+// lookupBidder returns a registered bidder or an alias declared on the request
+func (e *Exchange) lookupBidder(ctx context.Context, bidderCode string) (adapters.AdapterWithInfo, bool) {
+	if awi, ok := e.registry.Get(bidderCode); ok {
+		return awi, true
+	}
+	aliases, _ := ctx.Value(requestAliasesKey{}).(map[string]adapters.AdapterWithInfo)
+	awi, ok := aliases[bidderCode]
+	return awi, ok
+}
+
+// bidderDemandType returns the demand type for a bidder, including request aliases
+// Unknown bidders default to platform (obfuscated) demand
+func (e *Exchange) bidderDemandType(ctx context.Context, bidderCode string) adapters.DemandType {
+	if awi, ok := e.lookupBidder(ctx, bidderCode); ok {
+		return awi.Info.DemandType
+	}
+	return adapters.DemandTypePlatform
+}
+
+// coreBidder returns the code of the adapter that builds requests for the bidder
+func (e *Exchange) coreBidder(ctx context.Context, bidderCode string) string {
+	if awi, ok := e.lookupBidder(ctx, bidderCode); ok && awi.Info.AliasOf != "" {
+		return awi.Info.AliasOf
+	}
+	return bidderCode
+}
+
+// metricsLabel returns the bidder label used in metrics
+func (e *Exchange) metricsLabel(ctx context.Context, bidderCode string) string {
+	if awi, ok := e.lookupBidder(ctx, bidderCode); ok {
+		return bidderMetricsLabel(bidderCode, awi.Info)
+	}
+	return bidderCode
+}
+
+// bidderMetricsLabel returns the configured metrics label, or the bidder code
+func bidderMetricsLabel(bidderCode string, info adapters.BidderInfo) string {
+	if info.MetricsLabel != "" {
+		return info.MetricsLabel
+	}
+	return bidderCode
+}
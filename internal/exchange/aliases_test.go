@@ -0,0 +1,236 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// coreNameAdapter records the core bidder name it is called with and bids once per call
+type coreNameAdapter struct {
+	mu        sync.Mutex
+	coreNames []string
+	calls     int
+}
+
+func (a *coreNameAdapter) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.coreNames = append(a.coreNames, reqInfo.BidderCoreName)
+	a.calls++
+	return []*adapters.RequestData{{Method: "MOCK", Body: []byte(fmt.Sprintf("bid-%d", a.calls))}}, nil
+}
+
+func (a *coreNameAdapter) MakeBids(request *openrtb.BidRequest, response *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	return &adapters.BidderResponse{Bids: []*adapters.TypedBid{{
+		Bid:     &openrtb.Bid{ID: string(response.Body), ImpID: "imp1", Price: 2.00, AdM: "ad", W: 300, H: 250},
+		BidType: adapters.BidTypeBanner,
+	}}}, nil
+}
+
+func TestRequestAliases(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("appnexus", &mockAdapter{}, adapters.BidderInfo{
+		Enabled: true, GVLVendorID: 32, DemandType: adapters.DemandTypePublisher, MetricsLabel: "anx",
+	})
+	registry.Register("rubicon", &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+	ex := New(registry, nil)
+
+	aliases, warnings := ex.requestAliases(&openrtb.BidRequest{Ext: json.RawMessage(
+		`{"prebid":{"aliases":{"myanx":"appnexus","rubicon":"appnexus","ghost":"missing"},"aliasgvlids":{"myanx":77}}}`,
+	)})
+
+	if len(aliases) != 1 {
+		t.Fatalf("expected only myanx to resolve, got %+v", aliases)
+	}
+	info := aliases["myanx"].Info
+	if info.AliasOf != "appnexus" || info.GVLVendorID != 77 {
+		t.Errorf("unexpected alias info: %+v", info)
+	}
+	if info.DemandType != adapters.DemandTypePublisher || info.MetricsLabel != "anx" {
+		t.Errorf("expected request alias to keep core demand type and label: %+v", info)
+	}
+	if len(warnings) != 2 {
+		t.Errorf("expected warnings for shadowing and unknown core, got %v", warnings)
+	}
+
+	if aliases, _ := ex.requestAliases(&openrtb.BidRequest{Ext: json.RawMessage(`{"prebid":{}}`)}); aliases != nil {
+		t.Errorf("expected no aliases, got %+v", aliases)
+	}
+
+	// An alias may run the same core bidder under another seat in one request
+	aliases, warnings = ex.requestAliases(&openrtb.BidRequest{
+		Imp: []openrtb.Imp{{ID: "imp1", Ext: json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":1}}}}`)}},
+		Ext: json.RawMessage(`{"prebid":{"aliases":{"myanx":"appnexus"}}}`),
+	})
+	if _, ok := aliases["myanx"]; !ok || len(warnings) != 0 {
+		t.Errorf("expected myanx to resolve alongside appnexus, got %+v (%v)", aliases, warnings)
+	}
+}
+
+func TestRequestAliases_Capped(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("rubicon", &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+	ex := New(registry, nil)
+
+	declared := make(map[string]string)
+	for i := 0; i < maxRequestAliases+3; i++ {
+		declared[fmt.Sprintf("alias%02d", i)] = "rubicon"
+	}
+	ext, _ := json.Marshal(map[string]interface{}{"prebid": map[string]interface{}{"aliases": declared}})
+
+	aliases, warnings := ex.requestAliases(&openrtb.BidRequest{Ext: ext})
+	if len(aliases) != maxRequestAliases || len(warnings) != 3 {
+		t.Errorf("expected %d aliases and 3 warnings, got %d and %v", maxRequestAliases, len(aliases), warnings)
+	}
+	if _, ok := aliases["alias00"]; !ok {
+		t.Error("expected aliases to be taken in name order")
+	}
+}
+
+func TestRunAuction_BidderAliases(t *testing.T) {
+	core := &coreNameAdapter{}
+	registry := adapters.NewRegistry()
+	registry.Register("appnexus", core, adapters.BidderInfo{Enabled: true, DemandType: adapters.DemandTypePlatform})
+	if err := registry.RegisterAlias(adapters.AliasConfig{
+		Alias:        "anx_pub",
+		Core:         "appnexus",
+		DemandType:   adapters.DemandTypePublisher,
+		TimeoutMS:    300,
+		MetricsLabel: "anx_pub_label",
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ex := New(registry, &Config{
+		DefaultTimeout: 500 * time.Millisecond,
+		AuctionType:    FirstPriceAuction,
+	})
+	metrics := &nonBidMetrics{nonBids: make(map[string]int)}
+	ex.SetMetrics(metrics)
+
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "alias-auction",
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+			Ext:  json.RawMessage(`{"prebid":{"aliases":{"myanx":"appnexus"}}}`),
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, code := range []string{"appnexus", "anx_pub", "myanx"} {
+		if _, ok := resp.BidderResults[code]; !ok {
+			t.Errorf("expected %s to be called, got %v", code, resp.BidderResults)
+		}
+	}
+	for _, name := range core.coreNames {
+		if name != "appnexus" {
+			t.Errorf("expected core name appnexus, got %s", name)
+		}
+	}
+
+	// Losing bids are counted under each bidder's metrics label; request aliases use the core's
+	if len(metrics.nonBids) == 0 {
+		t.Fatal("expected losing bids to be recorded")
+	}
+	for key := range metrics.nonBids {
+		if label := strings.SplitN(key, "|", 2)[0]; label != "appnexus" && label != "anx_pub_label" {
+			t.Errorf("unexpected metrics label %q", label)
+		}
+	}
+
+	ctx := withRequestAliases(context.Background(), map[string]adapters.AdapterWithInfo{
+		"myanx": {Info: adapters.BidderInfo{AliasOf: "appnexus", DemandType: adapters.DemandTypePlatform}},
+	})
+	if ex.bidderDemandType(ctx, "anx_pub") != adapters.DemandTypePublisher {
+		t.Error("expected server alias demand type")
+	}
+	if ex.coreBidder(ctx, "myanx") != "appnexus" || ex.coreBidder(ctx, "unknown") != "unknown" {
+		t.Error("unexpected core bidder resolution")
+	}
+}
+
+// paramsAdapter records the outbound body and the appnexus placement of each request it builds
+type paramsAdapter struct {
+	mu         sync.Mutex
+	bodies     []string
+	placements []int
+}
+
+func (a *paramsAdapter) MakeRequests(request *openrtb.BidRequest, _ *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	var params struct {
+		PlacementID int `json:"placementId"`
+	}
+	if _, err := adapters.UnmarshalBidderParams(&request.Imp[0], "appnexus", &params); err != nil {
+		return nil, []error{err}
+	}
+	body, _ := json.Marshal(request)
+	a.mu.Lock()
+	a.bodies = append(a.bodies, string(body))
+	a.placements = append(a.placements, params.PlacementID)
+	a.mu.Unlock()
+	return nil, nil
+}
+
+func (a *paramsAdapter) MakeBids(*openrtb.BidRequest, *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	return nil, nil
+}
+
+func TestRunAuction_AliasParams(t *testing.T) {
+	for name, impExt := range map[string]string{
+		"imp.ext":               `{"appnexus":{"placementId":1},"myanx":{"placementId":2}}`,
+		"imp.ext.prebid.bidder": `{"prebid":{"bidder":{"appnexus":{"placementId":1},"myanx":{"placementId":2}}}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			adapter := &paramsAdapter{}
+			registry := adapters.NewRegistry()
+			registry.Register("appnexus", adapter, adapters.BidderInfo{Enabled: true})
+			ex := New(registry, &Config{DefaultTimeout: 500 * time.Millisecond})
+
+			_, err := ex.RunAuction(context.Background(), &AuctionRequest{
+				BidRequest: &openrtb.BidRequest{
+					ID:   "alias-params-auction",
+					Site: testSite(),
+					Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}, Ext: json.RawMessage(impExt)}},
+					Ext:  json.RawMessage(`{"prebid":{"aliases":{"myanx":"appnexus"}}}`),
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			adapter.mu.Lock()
+			defer adapter.mu.Unlock()
+			if len(adapter.bodies) != 2 {
+				t.Fatalf("expected requests for appnexus and myanx, got %d", len(adapter.bodies))
+			}
+			for i, body := range adapter.bodies {
+				switch adapter.placements[i] {
+				case 1:
+					if !strings.Contains(body, `"myanx":{"placementId":2}`) {
+						t.Errorf("expected the appnexus request unchanged, got %s", body)
+					}
+				case 2:
+					if strings.Contains(body, `"myanx":{"placementId"`) || strings.Contains(body, `"placementId":1`) {
+						t.Errorf("expected the alias request to carry only its own params under appnexus, got %s", body)
+					}
+				default:
+					t.Errorf("unexpected placement %d in %s", adapter.placements[i], body)
+				}
+			}
+			if adapter.placements[0] == adapter.placements[1] {
+				t.Errorf("expected each seat to send its own params, got %v", adapter.placements)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package exchange
+
+import (
+	"context"
+
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// resolveAuctionType picks the auction type for a request
+// Priority: the request's OpenRTB "at" field, then the publisher's configured
+// auction type, then the exchange default. Values other than 1 and 2 are ignored.
+func (e *Exchange) resolveAuctionType(ctx context.Context, req *openrtb.BidRequest) AuctionType {
+	if req != nil {
+		if at, ok := validAuctionType(req.AT); ok {
+			return at
+		}
+	}
+	if pub := middleware.PublisherFromContext(ctx); pub != nil {
+		if at, ok := validAuctionType(extractAuctionType(pub)); ok {
+			return at
+		}
+	}
+	return e.config.AuctionType
+}
+
+// validAuctionType converts an OpenRTB "at" value to an AuctionType
+func validAuctionType(at int) (AuctionType, bool) {
+	switch AuctionType(at) {
+	case FirstPriceAuction, SecondPriceAuction:
+		return AuctionType(at), true
+	default:
+		return 0, false
+	}
+}
+
+// extractAuctionType safely extracts the publisher's auction type (0 = exchange default)
+func extractAuctionType(v interface{}) int {
+	type auctionTypeGetter interface {
+		GetAuctionType() int
+	}
+	if getter, ok := v.(auctionTypeGetter); ok {
+		return getter.GetAuctionType()
+	}
+	return 0
+}
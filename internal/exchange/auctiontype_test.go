@@ -0,0 +1,156 @@
+package exchange
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// mockPublisherWithAuctionType is a publisher with a configured auction type
+type mockPublisherWithAuctionType struct {
+	mockPublisherWithMultiplier
+	AuctionType int
+}
+
+func (m *mockPublisherWithAuctionType) GetAuctionType() int {
+	return m.AuctionType
+}
+
+func TestResolveAuctionType(t *testing.T) {
+	ex := New(adapters.NewRegistry(), &Config{AuctionType: FirstPriceAuction})
+	pub := &mockPublisherWithAuctionType{
+		mockPublisherWithMultiplier: mockPublisherWithMultiplier{PublisherID: "pub1", BidMultiplier: 1.0},
+		AuctionType:                 2,
+	}
+	pubCtx := middleware.NewContextWithPublisher(context.Background(), pub)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		at   int
+		want AuctionType
+	}{
+		{"exchange default", context.Background(), 0, FirstPriceAuction},
+		{"request at", context.Background(), 2, SecondPriceAuction},
+		{"publisher auction type", pubCtx, 0, SecondPriceAuction},
+		{"request at overrides publisher", pubCtx, 1, FirstPriceAuction},
+		{"exchange-specific at ignored", context.Background(), 501, FirstPriceAuction},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ex.resolveAuctionType(tt.ctx, &openrtb.BidRequest{AT: tt.at})
+			if got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAuctionLogic_SecondPrice_Clearing(t *testing.T) {
+	ex := New(adapters.NewRegistry(), &Config{
+		AuctionType:    FirstPriceAuction,
+		PriceIncrement: 0.01,
+	})
+	impFloors := map[string]float64{"imp1": 2.50, "imp2": 0.50, "imp3": 1.00}
+
+	adapterBid := &openrtb.Bid{ID: "w1", ImpID: "imp1", Price: 5.00}
+	validBids := []ValidatedBid{
+		// imp1: floor (2.50) is above the runner-up (2.00)
+		{Bid: &adapters.TypedBid{Bid: adapterBid}, BidderCode: "b1", Floor: 2.50, OriginalPrice: 5.00},
+		{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: "r1", ImpID: "imp1", Price: 2.00}}, BidderCode: "b2", Floor: 2.50},
+		// imp2: runner-up above the floor
+		{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: "w2", ImpID: "imp2", Price: 4.00}}, BidderCode: "b1", Floor: 0.50, OriginalPrice: 4.00},
+		{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: "r2", ImpID: "imp2", Price: 3.00}}, BidderCode: "b2", Floor: 0.50},
+		// imp3: tied bids clear at the bid price, not above it
+		{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: "w3", ImpID: "imp3", Price: 3.00}}, BidderCode: "b1", Floor: 1.00, OriginalPrice: 3.00},
+		{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: "r3", ImpID: "imp3", Price: 3.00}}, BidderCode: "b2", Floor: 1.00},
+	}
+
+	result := ex.runAuctionLogic(validBids, impFloors, SecondPriceAuction)
+
+	tests := []struct {
+		impID    string
+		winner   string
+		clearing float64
+		original float64
+	}{
+		{"imp1", "w1", 2.51, 5.00},
+		{"imp2", "w2", 3.01, 4.00},
+		{"imp3", "w3", 3.00, 3.00},
+	}
+	for _, tt := range tests {
+		bids := result[tt.impID]
+		if len(bids) != 2 {
+			t.Fatalf("%s: expected 2 bids, got %d", tt.impID, len(bids))
+		}
+		winner := bids[0]
+		if winner.Bid.Bid.ID != tt.winner || winner.Bid.Bid.Price != tt.clearing || winner.OriginalPrice != tt.original {
+			t.Errorf("%s: expected %s clearing %.2f (original %.2f), got %s clearing %.2f (original %.2f)",
+				tt.impID, tt.winner, tt.clearing, tt.original, winner.Bid.Bid.ID, winner.Bid.Bid.Price, winner.OriginalPrice)
+		}
+	}
+
+	// The adapter's bid is not repriced in place
+	if adapterBid.Price != 5.00 {
+		t.Errorf("expected adapter bid to keep price 5.00, got %.2f", adapterBid.Price)
+	}
+}
+
+func TestAuctionLogic_FirstPrice_KeepsOriginal(t *testing.T) {
+	ex := New(adapters.NewRegistry(), &Config{AuctionType: SecondPriceAuction, PriceIncrement: 0.01})
+	validBids := []ValidatedBid{
+		{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: "w", ImpID: "imp1", Price: 5.00}}, BidderCode: "b1", OriginalPrice: 5.00},
+		{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: "r", ImpID: "imp1", Price: 2.00}}, BidderCode: "b2"},
+	}
+
+	result := ex.runAuctionLogic(validBids, map[string]float64{"imp1": 0}, FirstPriceAuction)
+
+	winner := result["imp1"][0]
+	if winner.Bid.Bid.Price != 5.00 || winner.OriginalPrice != 5.00 {
+		t.Errorf("expected first-price winner at 5.00, got %.2f (original %.2f)", winner.Bid.Bid.Price, winner.OriginalPrice)
+	}
+}
+
+func TestRunAuction_RequestAuctionType(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("bidder1", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "b1", ImpID: "imp1", Price: 5.00, AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true})
+	registry.Register("bidder2", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "b2", ImpID: "imp1", Price: 3.00, AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true, DemandType: adapters.DemandTypePublisher})
+
+	ex := New(registry, &Config{
+		DefaultTimeout: 500 * time.Millisecond,
+		AuctionType:    FirstPriceAuction,
+		PriceIncrement: 0.01,
+	})
+
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "at-req",
+			AT:   2,
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}, BidFloor: 1.00}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var winningPrice float64
+	for _, sb := range resp.BidResponse.SeatBid {
+		for _, bid := range sb.Bid {
+			if bid.ID == "b1" {
+				winningPrice = bid.Price
+			}
+		}
+	}
+	if winningPrice != 3.01 {
+		t.Errorf("expected request at=2 to clear at 3.01, got %.2f", winningPrice)
+	}
+}
@@ -0,0 +1,261 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// Bid adjustment types (ext.prebid.bidadjustments adjtype)
+const (
+	AdjustmentTypeMultiplier = "multiplier" // price * value
+	AdjustmentTypeCPM        = "cpm"        // price - value (fixed fee)
+	AdjustmentTypeStatic     = "static"     // price = value
+)
+
+// adjustmentWildcard matches any media type, bidder or deal ID in adjustment rules
+const adjustmentWildcard = "*"
+
+// maxAdjustmentMultiplier is the largest accepted multiplier or adjustment factor
+const maxAdjustmentMultiplier = 100.0
+
+// bidAdjustments holds the resolved bid adjustments for one auction
+// Factors (ext.prebid.bidadjustmentfactors) are applied first, then the matching rule list
+type bidAdjustments struct {
+	publisherID  string
+	factors      map[string]float64                                          // bidder -> multiplier
+	mediaFactors map[string]map[string]float64                               // media type -> bidder -> multiplier
+	rules        map[string]map[string]map[string][]openrtb.ExtBidAdjustment // media type -> bidder -> deal ID
+}
+
+// buildBidAdjustments merges the publisher's stored adjustment rules with the request's
+// ext.prebid.bidadjustments (request rules replace stored rules for the same media type,
+// bidder and deal) and reads ext.prebid.bidadjustmentfactors
+// Request adjustments can reprice any bid, so they apply only for publishers that allow them
+// Returns nil when no adjustments apply
+func (e *Exchange) buildBidAdjustments(ctx context.Context, req *openrtb.BidRequest) *bidAdjustments {
+	adj := &bidAdjustments{}
+	currency := e.config.DefaultCurrency
+	allowRequest := false
+
+	if pub := middleware.PublisherFromContext(ctx); pub != nil {
+		adj.publisherID, _ = extractPublisherID(pub)
+		allowRequest = extractAllowRequestBidAdjustments(pub)
+		if raw := extractBidAdjustments(pub); len(raw) > 0 {
+			var stored openrtb.ExtBidAdjustments
+			if err := json.Unmarshal(raw, &stored); err != nil {
+				logger.Log.Warn().
+					Err(err).
+					Str("publisher_id", adj.publisherID).
+					Msg("Invalid stored bid adjustments, ignoring")
+			} else {
+				adj.addRules(&stored, currency)
+			}
+		}
+	}
+
+	if allowRequest && req != nil && len(req.Ext) > 0 {
+		var ext openrtb.BidRequestExt
+		if err := json.Unmarshal(req.Ext, &ext); err == nil && ext.Prebid != nil {
+			adj.addFactors(ext.Prebid.BidAdjustmentFactors)
+			adj.addRules(ext.Prebid.BidAdjustments, currency)
+		}
+	}
+
+	if len(adj.factors) == 0 && len(adj.mediaFactors) == 0 && len(adj.rules) == 0 {
+		return nil
+	}
+	return adj
+}
+
+// addFactors records valid bidadjustmentfactors, keyed by lowercase bidder code
+func (a *bidAdjustments) addFactors(f *openrtb.ExtBidAdjustmentFactors) {
+	if f == nil {
+		return
+	}
+	for bidder, factor := range f.Bidders {
+		if validAdjustmentFactor(factor) {
+			if a.factors == nil {
+				a.factors = make(map[string]float64)
+			}
+			a.factors[strings.ToLower(bidder)] = factor
+		}
+	}
+	for mediaType, bidders := range f.MediaTypes {
+		for bidder, factor := range bidders {
+			if !validAdjustmentFactor(factor) {
+				continue
+			}
+			if a.mediaFactors == nil {
+				a.mediaFactors = make(map[string]map[string]float64)
+			}
+			mt := strings.ToLower(mediaType)
+			if a.mediaFactors[mt] == nil {
+				a.mediaFactors[mt] = make(map[string]float64)
+			}
+			a.mediaFactors[mt][strings.ToLower(bidder)] = factor
+		}
+	}
+}
+
+// addRules merges adjustment rules, replacing any existing list for the same key
+// Invalid adjustments, and fixed amounts in another currency, are dropped
+func (a *bidAdjustments) addRules(r *openrtb.ExtBidAdjustments, currency string) {
+	if r == nil {
+		return
+	}
+	for mediaType, bidders := range r.MediaType {
+		for bidder, deals := range bidders {
+			for dealID, list := range deals {
+				valid := make([]openrtb.ExtBidAdjustment, 0, len(list))
+				for _, adjustment := range list {
+					if reason := validateAdjustment(adjustment, currency); reason != "" {
+						logger.Log.Debug().
+							Str("mediatype", mediaType).
+							Str("bidder", bidder).
+							Str("dealid", dealID).
+							Str("reason", reason).
+							Msg("bid adjustment ignored")
+						continue
+					}
+					valid = append(valid, adjustment)
+				}
+				if len(valid) == 0 {
+					continue
+				}
+
+				mt, b := strings.ToLower(mediaType), strings.ToLower(bidder)
+				if a.rules == nil {
+					a.rules = make(map[string]map[string]map[string][]openrtb.ExtBidAdjustment)
+				}
+				if a.rules[mt] == nil {
+					a.rules[mt] = make(map[string]map[string][]openrtb.ExtBidAdjustment)
+				}
+				if a.rules[mt][b] == nil {
+					a.rules[mt][b] = make(map[string][]openrtb.ExtBidAdjustment)
+				}
+				a.rules[mt][b][dealID] = valid
+			}
+		}
+	}
+}
+
+// validateAdjustment returns an empty string when the adjustment can be applied
+func validateAdjustment(adjustment openrtb.ExtBidAdjustment, currency string) string {
+	switch strings.ToLower(adjustment.AdjType) {
+	case AdjustmentTypeMultiplier:
+		if !validAdjustmentFactor(adjustment.Value) {
+			return "multiplier out of range"
+		}
+		return ""
+	case AdjustmentTypeCPM, AdjustmentTypeStatic:
+		if adjustment.Value < 0 || math.IsNaN(adjustment.Value) || math.IsInf(adjustment.Value, 0) {
+			return "negative value"
+		}
+		if adjustment.Currency != "" && currency != "" && !strings.EqualFold(adjustment.Currency, currency) {
+			return "unsupported currency " + adjustment.Currency
+		}
+		return ""
+	default:
+		return "unknown adjtype " + adjustment.AdjType
+	}
+}
+
+// validAdjustmentFactor reports whether a multiplier is within [0, maxAdjustmentMultiplier)
+func validAdjustmentFactor(v float64) bool {
+	return v >= 0 && v < maxAdjustmentMultiplier && !math.IsNaN(v)
+}
+
+// ruleList returns the most specific rule list for the bid
+// Media type is matched first, then bidder, then deal ID, each falling back to "*"
+func (a *bidAdjustments) ruleList(mediaType, bidder, dealID string) []openrtb.ExtBidAdjustment {
+	dealKeys := []string{adjustmentWildcard}
+	if dealID != "" {
+		dealKeys = []string{dealID, adjustmentWildcard}
+	}
+	for _, mt := range []string{mediaType, adjustmentWildcard} {
+		bidders := a.rules[mt]
+		if bidders == nil {
+			continue
+		}
+		for _, b := range []string{bidder, adjustmentWildcard} {
+			deals := bidders[b]
+			if deals == nil {
+				continue
+			}
+			for _, d := range dealKeys {
+				if list, ok := deals[d]; ok {
+					return list
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// adjustedPrice returns the price the bid is validated and ranked at
+// The bid itself is not changed. Returns true when the price differs from the bid price.
+func (a *bidAdjustments) adjustedPrice(tb *adapters.TypedBid, bidderCode string) (float64, bool) {
+	if a == nil || tb == nil || tb.Bid == nil {
+		return 0, false
+	}
+
+	bidder := strings.ToLower(bidderCode)
+	mediaType := string(tb.BidType)
+	original := tb.Bid.Price
+	price := original
+
+	if factor, ok := a.mediaFactors[mediaType][bidder]; ok {
+		price *= factor
+	} else if factor, ok := a.factors[bidder]; ok {
+		price *= factor
+	}
+
+	for _, adjustment := range a.ruleList(mediaType, bidder, tb.Bid.DealID) {
+		switch strings.ToLower(adjustment.AdjType) {
+		case AdjustmentTypeMultiplier:
+			price *= adjustment.Value
+		case AdjustmentTypeCPM:
+			price -= adjustment.Value
+		case AdjustmentTypeStatic:
+			price = adjustment.Value
+		}
+	}
+
+	if price < 0 {
+		price = 0
+	}
+	price = math.Round(price*10000) / 10000
+	if price == original {
+		return original, false
+	}
+	return price, true
+}
+
+// extractAllowRequestBidAdjustments reports whether the publisher accepts bid adjustments sent on the request
+func extractAllowRequestBidAdjustments(v interface{}) bool {
+	type allowRequestBidAdjustmentsGetter interface {
+		GetAllowRequestBidAdjustments() bool
+	}
+	if getter, ok := v.(allowRequestBidAdjustmentsGetter); ok {
+		return getter.GetAllowRequestBidAdjustments()
+	}
+	return false
+}
+
+// extractBidAdjustments safely extracts stored bid adjustment rules from the publisher
+func extractBidAdjustments(v interface{}) json.RawMessage {
+	type bidAdjustmentsGetter interface {
+		GetBidAdjustments() json.RawMessage
+	}
+	if getter, ok := v.(bidAdjustmentsGetter); ok {
+		return getter.GetBidAdjustments()
+	}
+	return nil
+}
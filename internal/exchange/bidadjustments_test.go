@@ -0,0 +1,243 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// mockPublisherWithBidAdjustments is a publisher with stored bid adjustment rules
+type mockPublisherWithBidAdjustments struct {
+	mockPublisherWithMultiplier
+	BidAdjustments             json.RawMessage
+	AllowRequestBidAdjustments bool
+}
+
+func (m *mockPublisherWithBidAdjustments) GetBidAdjustments() json.RawMessage {
+	return m.BidAdjustments
+}
+
+func (m *mockPublisherWithBidAdjustments) GetAllowRequestBidAdjustments() bool {
+	return m.AllowRequestBidAdjustments
+}
+
+// requestAdjustmentsContext returns a context whose publisher accepts request bid adjustments
+func requestAdjustmentsContext() context.Context {
+	return middleware.NewContextWithPublisher(context.Background(), &mockPublisherWithBidAdjustments{
+		mockPublisherWithMultiplier: mockPublisherWithMultiplier{PublisherID: "pub1", BidMultiplier: 1.0},
+		AllowRequestBidAdjustments:  true,
+	})
+}
+
+// recordingMetrics captures bid adjustment metrics
+type recordingMetrics struct {
+	mockMetricsRecorder
+	adjustments []string
+	original    float64
+	adjusted    float64
+}
+
+func (m *recordingMetrics) RecordBidAdjustment(publisher, bidder, mediaType string, originalPrice, adjustedPrice float64) {
+	m.adjustments = append(m.adjustments, publisher+"|"+bidder+"|"+mediaType)
+	m.original += originalPrice
+	m.adjusted += adjustedPrice
+}
+
+func adjustmentRequest(ext string) *openrtb.BidRequest {
+	return &openrtb.BidRequest{ID: "adj-req", Ext: json.RawMessage(ext)}
+}
+
+func TestBidAdjustments_Apply(t *testing.T) {
+	ex := New(adapters.NewRegistry(), nil)
+	adj := ex.buildBidAdjustments(requestAdjustmentsContext(), adjustmentRequest(`{"prebid":{"bidadjustments":{"mediatype":{
+		"banner":{
+			"rubicon":{"deal-1":[{"adjtype":"static","value":5.0}],"*":[{"adjtype":"multiplier","value":0.9}]},
+			"*":{"*":[{"adjtype":"cpm","value":0.25}]}
+		},
+		"*":{
+			"AppNexus":{"*":[{"adjtype":"multiplier","value":0.5},{"adjtype":"cpm","value":0.1}]}
+		}
+	}}}}`))
+	if adj == nil {
+		t.Fatal("expected bid adjustments")
+	}
+
+	tests := []struct {
+		name    string
+		bidder  string
+		bidType adapters.BidType
+		dealID  string
+		price   float64
+		want    float64
+	}{
+		{"deal-specific static", "rubicon", adapters.BidTypeBanner, "deal-1", 2.00, 5.00},
+		{"bidder multiplier", "rubicon", adapters.BidTypeBanner, "", 2.00, 1.80},
+		{"unknown deal falls back to bidder wildcard", "rubicon", adapters.BidTypeBanner, "deal-x", 2.00, 1.80},
+		{"media type wildcard bidder fixed fee", "pubmatic", adapters.BidTypeBanner, "", 2.00, 1.75},
+		{"wildcard media type, case-insensitive bidder", "appnexus", adapters.BidTypeVideo, "", 2.00, 0.90},
+		{"no matching rule", "pubmatic", adapters.BidTypeVideo, "", 2.00, 2.00},
+		{"fixed fee floors at zero", "pubmatic", adapters.BidTypeBanner, "", 0.10, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := &adapters.TypedBid{Bid: &openrtb.Bid{ID: "b", ImpID: "imp1", Price: tt.price, DealID: tt.dealID}, BidType: tt.bidType}
+			price, changed := adj.adjustedPrice(tb, tt.bidder)
+			if price != tt.want {
+				t.Errorf("expected price %.4f, got %.4f", tt.want, price)
+			}
+			if changed != (tt.want != tt.price) {
+				t.Errorf("expected changed=%v", tt.want != tt.price)
+			}
+			if tb.Bid.Price != tt.price {
+				t.Errorf("expected the bid to keep price %.4f, got %.4f", tt.price, tb.Bid.Price)
+			}
+		})
+	}
+}
+
+func TestBidAdjustments_Factors(t *testing.T) {
+	ex := New(adapters.NewRegistry(), nil)
+	adj := ex.buildBidAdjustments(requestAdjustmentsContext(), adjustmentRequest(`{"prebid":{
+		"bidadjustmentfactors":{"rubicon":0.8,"mediatypes":{"video":{"rubicon":0.5}}},
+		"bidadjustments":{"mediatype":{"*":{"rubicon":{"*":[{"adjtype":"cpm","value":0.1}]}}}}
+	}}`))
+
+	banner := &adapters.TypedBid{Bid: &openrtb.Bid{Price: 2.00}, BidType: adapters.BidTypeBanner}
+	if price, _ := adj.adjustedPrice(banner, "rubicon"); price != 1.50 { // 2.00 x 0.8 - 0.10
+		t.Errorf("expected 1.50, got %.4f", price)
+	}
+
+	video := &adapters.TypedBid{Bid: &openrtb.Bid{Price: 2.00}, BidType: adapters.BidTypeVideo}
+	if price, _ := adj.adjustedPrice(video, "rubicon"); price != 0.90 { // media type factor overrides bidder factor
+		t.Errorf("expected 0.90, got %.4f", price)
+	}
+}
+
+func TestBidAdjustments_Invalid(t *testing.T) {
+	ex := New(adapters.NewRegistry(), nil)
+	adj := ex.buildBidAdjustments(requestAdjustmentsContext(), adjustmentRequest(`{"prebid":{
+		"bidadjustmentfactors":{"rubicon":-1,"appnexus":250},
+		"bidadjustments":{"mediatype":{"*":{"*":{"*":[
+			{"adjtype":"multiplier","value":150},
+			{"adjtype":"cpm","value":-1},
+			{"adjtype":"cpm","value":0.5,"currency":"EUR"},
+			{"adjtype":"bogus","value":1}
+		]}}}}
+	}}`))
+	if adj != nil {
+		t.Errorf("expected all invalid adjustments to be dropped, got %+v", adj)
+	}
+
+	var nilAdj *bidAdjustments
+	if _, changed := nilAdj.adjustedPrice(&adapters.TypedBid{Bid: &openrtb.Bid{Price: 1}}, "rubicon"); changed {
+		t.Error("nil adjustments must not change bids")
+	}
+}
+
+func TestBidAdjustments_AccountRules(t *testing.T) {
+	ex := New(adapters.NewRegistry(), nil)
+	pub := &mockPublisherWithBidAdjustments{
+		mockPublisherWithMultiplier: mockPublisherWithMultiplier{PublisherID: "pub1", BidMultiplier: 1.0},
+		BidAdjustments: json.RawMessage(`{"mediatype":{"*":{
+			"rubicon":{"*":[{"adjtype":"multiplier","value":0.9}]},
+			"pubmatic":{"*":[{"adjtype":"multiplier","value":0.7}]}
+		}}}`),
+		AllowRequestBidAdjustments: true,
+	}
+	ctx := middleware.NewContextWithPublisher(context.Background(), pub)
+
+	// Request rules replace the account rule for the same key only
+	adj := ex.buildBidAdjustments(ctx, adjustmentRequest(
+		`{"prebid":{"bidadjustments":{"mediatype":{"*":{"rubicon":{"*":[{"adjtype":"multiplier","value":0.5}]}}}}}}`))
+	if adj.publisherID != "pub1" {
+		t.Errorf("expected publisher pub1, got %q", adj.publisherID)
+	}
+
+	rubicon := &adapters.TypedBid{Bid: &openrtb.Bid{Price: 2.00}, BidType: adapters.BidTypeBanner}
+	if price, _ := adj.adjustedPrice(rubicon, "rubicon"); price != 1.00 {
+		t.Errorf("expected request rule 1.00, got %.4f", price)
+	}
+	pubmatic := &adapters.TypedBid{Bid: &openrtb.Bid{Price: 2.00}, BidType: adapters.BidTypeBanner}
+	if price, _ := adj.adjustedPrice(pubmatic, "pubmatic"); price != 1.40 {
+		t.Errorf("expected account rule 1.40, got %.4f", price)
+	}
+}
+
+func TestBidAdjustments_RequestRequiresOptIn(t *testing.T) {
+	ex := New(adapters.NewRegistry(), nil)
+	req := adjustmentRequest(`{"prebid":{
+		"bidadjustmentfactors":{"rubicon":50},
+		"bidadjustments":{"mediatype":{"*":{"*":{"*":[{"adjtype":"static","value":99}]}}}}
+	}}`)
+
+	// Without an authenticated publisher
+	if adj := ex.buildBidAdjustments(context.Background(), req); adj != nil {
+		t.Errorf("expected request adjustments ignored without a publisher, got %+v", adj)
+	}
+
+	// The publisher has not opted in: only its stored rules apply
+	pub := &mockPublisherWithBidAdjustments{
+		mockPublisherWithMultiplier: mockPublisherWithMultiplier{PublisherID: "pub1", BidMultiplier: 1.0},
+		BidAdjustments:              json.RawMessage(`{"mediatype":{"*":{"*":{"*":[{"adjtype":"multiplier","value":0.9}]}}}}`),
+	}
+	adj := ex.buildBidAdjustments(middleware.NewContextWithPublisher(context.Background(), pub), req)
+	tb := &adapters.TypedBid{Bid: &openrtb.Bid{Price: 2.00}, BidType: adapters.BidTypeBanner}
+	if price, _ := adj.adjustedPrice(tb, "rubicon"); price != 1.80 {
+		t.Errorf("expected only the stored rule (1.80), got %.4f", price)
+	}
+}
+
+func TestRunAuction_BidAdjustments(t *testing.T) {
+	registry := adapters.NewRegistry()
+	rubiconBid := &openrtb.Bid{ID: "r1", ImpID: "imp1", Price: 2.00, AdM: "ad", W: 300, H: 250}
+	registry.Register("rubicon", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: rubiconBid, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true})
+	registry.Register("appnexus", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "a1", ImpID: "imp1", Price: 1.50, AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{
+		DefaultTimeout: 500 * time.Millisecond,
+		AuctionType:    FirstPriceAuction,
+	})
+	metrics := &recordingMetrics{}
+	ex.SetMetrics(metrics)
+
+	// rubicon's 2.00 is cut to 1.00, so appnexus wins; the adjusted price is also held to the 1.20 floor
+	resp, err := ex.RunAuction(requestAdjustmentsContext(), &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "adj-auction",
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}, BidFloor: 1.20}},
+			Ext:  json.RawMessage(`{"prebid":{"bidadjustments":{"mediatype":{"banner":{"rubicon":{"*":[{"adjtype":"multiplier","value":0.5}]}}}}}}`),
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var winners []openrtb.Bid
+	for _, sb := range resp.BidResponse.SeatBid {
+		winners = append(winners, sb.Bid...)
+	}
+	if len(winners) != 1 || winners[0].ID != "a1" {
+		t.Fatalf("expected only appnexus bid a1, got %+v", winners)
+	}
+
+	if rubiconBid.Price != 2.00 {
+		t.Errorf("expected the adapter's bid to keep price 2.00, got %.2f", rubiconBid.Price)
+	}
+
+	if len(metrics.adjustments) != 1 || metrics.adjustments[0] != "pub1|rubicon|banner" {
+		t.Errorf("unexpected adjustment metrics: %v", metrics.adjustments)
+	}
+	if metrics.original != 2.00 || metrics.adjusted != 1.00 {
+		t.Errorf("expected original 2.00 / adjusted 1.00, got %.2f / %.2f", metrics.original, metrics.adjusted)
+	}
+}
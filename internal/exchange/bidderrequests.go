@@ -0,0 +1,268 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// outboundAdapter is implemented by adapters with their own outbound options (e.g. ortb.GenericAdapter)
+type outboundAdapter interface {
+	GetOutboundOptions() adapters.OutboundOptions
+}
+
+// bidderOutbound returns the compression, HTTP/2, pooling, mTLS and signing options for requests to a bidder
+// Options set in the adapter config override the adapter's own, field by field.
+func bidderOutbound(awi adapters.AdapterWithInfo) adapters.OutboundOptions {
+	var opts adapters.OutboundOptions
+	if oa, ok := awi.Adapter.(outboundAdapter); ok {
+		opts = oa.GetOutboundOptions()
+	}
+	return opts.Merge(awi.Info.Outbound)
+}
+
+// ValidateBidderOutbound checks the outbound options of every enabled bidder
+// Invalid pool sizes, signing settings or mTLS files, from the adapter config or the adapter
+// itself, fail at startup instead of failing every request to the bidder.
+func (e *Exchange) ValidateBidderOutbound() error {
+	all := e.registry.GetAll()
+	codes := make([]string, 0, len(all))
+	for code := range all {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		awi := all[code]
+		if !awi.Info.Enabled {
+			continue
+		}
+		if opts := bidderOutbound(awi); !opts.IsZero() {
+			if err := opts.Validate(); err != nil {
+				return fmt.Errorf("bidder %s: %w", code, err)
+			}
+		}
+	}
+	return nil
+}
+
+// BidderHeaderSource supplies extra HTTP headers for a bidder's requests (e.g. *storage.BidderHeaders)
+type BidderHeaderSource interface {
+	BidderHeaders(ctx context.Context, bidderCode string) (map[string]string, error)
+}
+
+// SetBidderHeaders sets the source of extra bidder request headers (nil sends the adapters' headers only)
+func (e *Exchange) SetBidderHeaders(src BidderHeaderSource) {
+	e.configMu.Lock()
+	defer e.configMu.Unlock()
+	e.bidderHeaders = src
+}
+
+// withBidderHeaders returns a copy of the request with the bidder's extra headers set
+// Headers are looked up per request so rotated credentials are used without a restart.
+// Aliases send their core bidder's headers, since they call the same endpoint.
+func (e *Exchange) withBidderHeaders(ctx context.Context, bidderCode string, reqData *adapters.RequestData) (*adapters.RequestData, error) {
+	e.configMu.RLock()
+	src := e.bidderHeaders
+	e.configMu.RUnlock()
+	if src == nil {
+		return reqData, nil
+	}
+
+	headers, err := src.BidderHeaders(ctx, e.coreBidder(ctx, bidderCode))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve bidder headers: %w", err)
+	}
+	if len(headers) == 0 {
+		return reqData, nil
+	}
+
+	withHeaders := *reqData
+	withHeaders.Headers = reqData.Headers.Clone()
+	if withHeaders.Headers == nil {
+		withHeaders.Headers = make(http.Header, len(headers))
+	}
+	for name, value := range headers {
+		withHeaders.Headers.Set(name, value)
+	}
+	return &withHeaders, nil
+}
+
+// requestOutcome is the result of one of a bidder's HTTP requests
+type requestOutcome struct {
+	bids     []*adapters.TypedBid
+	errs     []error
+	timedOut bool
+}
+
+// executeBidderRequests runs a bidder's requests with at most MaxConcurrentBidderRequests in flight
+// All requests share the bidder's deadline. Outcomes are collected as they arrive and returned
+// in request order so results are deterministic; requests still outstanding at the deadline
+// are reported as timed out. With more than one request, errors name the request they came from.
+func (e *Exchange) executeBidderRequests(ctx context.Context, req *openrtb.BidRequest, bidderCode string, adapter adapters.Adapter, requests []*adapters.RequestData, timeout time.Duration, start time.Time) []requestOutcome {
+	outcomes := make([]requestOutcome, len(requests))
+	if len(requests) == 1 {
+		outcomes[0] = e.executeBidderRequest(ctx, req, bidderCode, adapter, requests[0], timeout, start)
+		return outcomes
+	}
+
+	limit := e.config.MaxConcurrentBidderRequests
+	if limit <= 0 || limit > len(requests) {
+		limit = len(requests)
+	}
+	sem := make(chan struct{}, limit)
+
+	type indexedOutcome struct {
+		index   int
+		outcome requestOutcome
+	}
+	// Buffered so requests finishing after the deadline never block
+	arrived := make(chan indexedOutcome, len(requests))
+	for i, reqData := range requests {
+		go func(i int, reqData *adapters.RequestData) {
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				arrived <- indexedOutcome{i, requestOutcome{errs: []error{ctx.Err()}, timedOut: true}}
+				return
+			}
+			arrived <- indexedOutcome{i, e.executeBidderRequest(ctx, req, bidderCode, adapter, reqData, timeout, start)}
+		}(i, reqData)
+	}
+
+	done := make([]bool, len(requests))
+	for remaining := len(requests); remaining > 0; remaining-- {
+		select {
+		case r := <-arrived:
+			outcomes[r.index] = r.outcome
+			done[r.index] = true
+		case <-ctx.Done():
+			for i := range outcomes {
+				if !done[i] {
+					outcomes[i] = requestOutcome{errs: []error{ctx.Err()}, timedOut: true}
+				}
+			}
+			remaining = 0
+		}
+	}
+
+	for i := range outcomes {
+		for j, err := range outcomes[i].errs {
+			outcomes[i].errs[j] = fmt.Errorf("request %d/%d (%s): %w", i+1, len(requests), requests[i].URI, err)
+		}
+	}
+	return outcomes
+}
+
+// executeBidderRequest sends one request and validates the bids parsed from its response
+func (e *Exchange) executeBidderRequest(ctx context.Context, req *openrtb.BidRequest, bidderCode string, adapter adapters.Adapter, reqData *adapters.RequestData, timeout time.Duration, start time.Time) requestOutcome {
+	var outcome requestOutcome
+
+	// Check if context has expired before the request to avoid wasted work
+	select {
+	case <-ctx.Done():
+		outcome.errs = append(outcome.errs, ctx.Err())
+		outcome.timedOut = true // P2-2: mark as timed out
+		return outcome
+	default:
+		// Context still valid, proceed with request
+	}
+
+	// Handle mock requests (e.g., demo adapter) - use request body as response
+	var resp *adapters.ResponseData
+	if reqData.Method == "MOCK" {
+		resp = &adapters.ResponseData{
+			StatusCode: 200,
+			Body:       reqData.Body,
+			Headers:    reqData.Headers,
+		}
+	} else {
+		var err error
+		reqData, err = e.withBidderHeaders(ctx, bidderCode, reqData)
+		if err != nil {
+			outcome.errs = append(outcome.errs, err)
+			return outcome
+		}
+		resp, err = e.httpClient.Do(e.rtt.withRTTTrace(ctx, bidderCode), reqData, timeout)
+		if err != nil {
+			// P3-1: Log HTTP request failures with context
+			isTimeout := errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+			logger.Log.Debug().
+				Str("bidder", bidderCode).
+				Str("uri", reqData.URI).
+				Dur("elapsed", time.Since(start)).
+				Bool("timeout", isTimeout).
+				Err(err).
+				Msg("bidder HTTP request failed")
+			outcome.errs = append(outcome.errs, err)
+			// P2-2: Check if this was a timeout error
+			outcome.timedOut = isTimeout
+			return outcome
+		}
+		e.recordBidderBytes(ctx, bidderCode, resp)
+	}
+
+	bidderResp, errs := adapter.MakeBids(req, resp)
+	if len(errs) > 0 {
+		outcome.errs = append(outcome.errs, errs...)
+	}
+	if bidderResp == nil {
+		return outcome
+	}
+
+	// P2-5: Validate BidResponse.ID matches BidRequest.ID (OpenRTB 2.x requirement)
+	// Per spec, response ID must echo request ID - reject on mismatch
+	if bidderResp.ResponseID != "" && bidderResp.ResponseID != req.ID {
+		outcome.errs = append(outcome.errs, fmt.Errorf(
+			"response ID mismatch from %s: expected %q, got %q (bids rejected)",
+			bidderCode, req.ID, bidderResp.ResponseID,
+		))
+		return outcome // Reject all bids from this response
+	}
+
+	// P1-NEW-3: Normalize and validate response currency
+	// Per OpenRTB 2.5 spec section 7.2, empty currency means USD
+	responseCurrency := bidderResp.Currency
+	if responseCurrency == "" {
+		responseCurrency = "USD" // OpenRTB 2.5 default
+	}
+
+	// P1-NEW-4: Defensive check for exchange currency misconfiguration
+	// Normalize exchange currency to USD if empty to prevent silent validation bypass
+	exchangeCurrency := e.config.DefaultCurrency
+	if exchangeCurrency == "" {
+		exchangeCurrency = "USD" // Fallback if misconfigured
+	}
+
+	if responseCurrency != exchangeCurrency {
+		outcome.errs = append(outcome.errs, fmt.Errorf(
+			"currency mismatch from %s: expected %s, got %s (bids rejected)",
+			bidderCode, exchangeCurrency, responseCurrency,
+		))
+		// Skip bids with wrong currency - can't safely compare prices
+		return outcome
+	}
+
+	outcome.bids = bidderResp.Bids
+	return outcome
+}
+
+// recordBidderBytes records the bytes a bidder request sent and received on the wire
+func (e *Exchange) recordBidderBytes(ctx context.Context, bidderCode string, resp *adapters.ResponseData) {
+	if resp.BytesSent == 0 && resp.BytesReceived == 0 {
+		return
+	}
+	e.configMu.RLock()
+	defer e.configMu.RUnlock()
+	if e.metrics != nil {
+		e.metrics.RecordBidderBytes(e.metricsLabel(ctx, bidderCode), resp.BytesSent, resp.BytesReceived)
+	}
+}
@@ -0,0 +1,335 @@
+package exchange
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// delayHTTPClient answers each request after the delay set for its URI
+// The response body is the URI, so bids can be traced back to their request.
+type delayHTTPClient struct {
+	delays map[string]time.Duration
+	errs   map[string]error
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (c *delayHTTPClient) Do(ctx context.Context, req *adapters.RequestData, _ time.Duration) (*adapters.ResponseData, error) {
+	c.mu.Lock()
+	c.inFlight++
+	if c.inFlight > c.maxInFlight {
+		c.maxInFlight = c.inFlight
+	}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.inFlight--
+		c.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(c.delays[req.URI]):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if err := c.errs[req.URI]; err != nil {
+		return nil, err
+	}
+	return &adapters.ResponseData{StatusCode: 200, Body: []byte(req.URI)}, nil
+}
+
+// uriBidAdapter returns one bid per response, with the request URI as the bid ID
+type uriBidAdapter struct{}
+
+func (uriBidAdapter) MakeRequests(*openrtb.BidRequest, *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	return nil, nil
+}
+
+func (uriBidAdapter) MakeBids(_ *openrtb.BidRequest, resp *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	return &adapters.BidderResponse{
+		Bids: []*adapters.TypedBid{{Bid: &openrtb.Bid{ID: string(resp.Body), Price: 1}, BidType: adapters.BidTypeBanner}},
+	}, nil
+}
+
+func bidderRequests(uris ...string) []*adapters.RequestData {
+	requests := make([]*adapters.RequestData, len(uris))
+	for i, uri := range uris {
+		requests[i] = &adapters.RequestData{Method: "POST", URI: uri}
+	}
+	return requests
+}
+
+func outcomeBidIDs(outcomes []requestOutcome) []string {
+	var ids []string
+	for _, outcome := range outcomes {
+		for _, bid := range outcome.bids {
+			ids = append(ids, bid.Bid.ID)
+		}
+	}
+	return ids
+}
+
+func TestExecuteBidderRequests_BoundedParallel(t *testing.T) {
+	client := &delayHTTPClient{delays: map[string]time.Duration{
+		"r1": 90 * time.Millisecond, "r2": 75 * time.Millisecond, "r3": 60 * time.Millisecond,
+		"r4": 45 * time.Millisecond, "r5": 30 * time.Millisecond, "r6": 15 * time.Millisecond,
+	}}
+	ex := New(adapters.NewRegistry(), &Config{MaxConcurrentBidderRequests: 3})
+	ex.httpClient = client
+
+	start := time.Now()
+	outcomes := ex.executeBidderRequests(context.Background(), &openrtb.BidRequest{ID: "req"}, "rubicon",
+		uriBidAdapter{}, bidderRequests("r1", "r2", "r3", "r4", "r5", "r6"), time.Second, start)
+	elapsed := time.Since(start)
+
+	// Serially the requests take 315ms; three at a time they finish in about 135ms
+	if elapsed >= 300*time.Millisecond {
+		t.Errorf("expected requests to run in parallel, took %v", elapsed)
+	}
+	if client.maxInFlight != 3 {
+		t.Errorf("expected at most 3 requests in flight, got %d", client.maxInFlight)
+	}
+	// Bids are returned in request order, not arrival order
+	if got := strings.Join(outcomeBidIDs(outcomes), ","); got != "r1,r2,r3,r4,r5,r6" {
+		t.Errorf("expected bids in request order, got %s", got)
+	}
+}
+
+func TestExecuteBidderRequests_ErrorAttribution(t *testing.T) {
+	boom := errors.New("connection refused")
+	ex := New(adapters.NewRegistry(), nil)
+	ex.httpClient = &delayHTTPClient{errs: map[string]error{"https://bidder.test/imp-2": boom}}
+
+	outcomes := ex.executeBidderRequests(context.Background(), &openrtb.BidRequest{ID: "req"}, "rubicon", uriBidAdapter{},
+		bidderRequests("https://bidder.test/imp-1", "https://bidder.test/imp-2", "https://bidder.test/imp-3"), time.Second, time.Now())
+
+	if got := len(outcomeBidIDs(outcomes)); got != 2 {
+		t.Errorf("expected bids from the two successful requests, got %d", got)
+	}
+	if len(outcomes[1].errs) != 1 {
+		t.Fatalf("expected one error on the failed request, got %v", outcomes[1].errs)
+	}
+	err := outcomes[1].errs[0]
+	if err.Error() != "request 2/3 (https://bidder.test/imp-2): connection refused" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !errors.Is(err, boom) {
+		t.Error("expected error to wrap the HTTP error")
+	}
+}
+
+func TestExecuteBidderRequests_SharedDeadline(t *testing.T) {
+	ex := New(adapters.NewRegistry(), nil)
+	ex.httpClient = &delayHTTPClient{delays: map[string]time.Duration{
+		"fast": 5 * time.Millisecond, "slow": time.Second,
+	}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	outcomes := ex.executeBidderRequests(ctx, &openrtb.BidRequest{ID: "req"}, "rubicon", uriBidAdapter{},
+		bidderRequests("slow", "fast"), time.Second, start)
+
+	if elapsed := time.Since(start); elapsed >= 500*time.Millisecond {
+		t.Errorf("expected requests to stop at the deadline, took %v", elapsed)
+	}
+	if got := strings.Join(outcomeBidIDs(outcomes), ","); got != "fast" {
+		t.Errorf("expected the fast request's bid, got %q", got)
+	}
+	if !outcomes[0].timedOut || outcomes[1].timedOut {
+		t.Errorf("expected only the slow request to time out, got %v and %v", outcomes[0].timedOut, outcomes[1].timedOut)
+	}
+	if len(outcomes[0].errs) != 1 || !errors.Is(outcomes[0].errs[0], context.DeadlineExceeded) {
+		t.Errorf("expected deadline error on the slow request, got %v", outcomes[0].errs)
+	}
+}
+
+func TestCallBidder_MultiRequest(t *testing.T) {
+	client := &delayHTTPClient{delays: map[string]time.Duration{"a": 20 * time.Millisecond, "b": 5 * time.Millisecond}}
+	ex := New(adapters.NewRegistry(), nil)
+	ex.httpClient = client
+
+	adapter := &multiRequestAdapter{requests: bidderRequests("a", "b")}
+	result := ex.callBidder(context.Background(), &openrtb.BidRequest{ID: "req"}, "rubicon", adapter, time.Second)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if len(result.Bids) != 2 || result.Bids[0].Bid.ID != "a" || result.Bids[1].Bid.ID != "b" {
+		t.Errorf("expected bids a and b in request order, got %d bids", len(result.Bids))
+	}
+}
+
+// multiRequestAdapter returns fixed requests and one bid per response
+type multiRequestAdapter struct {
+	uriBidAdapter
+	requests []*adapters.RequestData
+}
+
+func (m *multiRequestAdapter) MakeRequests(*openrtb.BidRequest, *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	return m.requests, nil
+}
+
+// outboundAdapterStub is a mockAdapter with its own outbound options
+type outboundAdapterStub struct {
+	mockAdapter
+	opts adapters.OutboundOptions
+}
+
+func (o *outboundAdapterStub) GetOutboundOptions() adapters.OutboundOptions {
+	return o.opts
+}
+
+func TestBidderOutbound(t *testing.T) {
+	own := &outboundAdapterStub{opts: adapters.OutboundOptions{HTTP2: true, MaxConnsPerHost: 50}}
+	configured := adapters.OutboundOptions{GzipRequests: true, MaxConnsPerHost: 100}
+
+	if got := bidderOutbound(adapters.AdapterWithInfo{Adapter: own}); got != own.opts {
+		t.Errorf("expected adapter options, got %+v", got)
+	}
+	want := adapters.OutboundOptions{GzipRequests: true, HTTP2: true, MaxConnsPerHost: 100}
+	if got := bidderOutbound(adapters.AdapterWithInfo{Adapter: own, Info: adapters.BidderInfo{Outbound: configured}}); got != want {
+		t.Errorf("expected configured options merged over the adapter's, got %+v", got)
+	}
+	if got := bidderOutbound(adapters.AdapterWithInfo{Adapter: &mockAdapter{}}); !got.IsZero() {
+		t.Errorf("expected no options, got %+v", got)
+	}
+}
+
+func TestValidateBidderOutbound(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("rubicon", &mockAdapter{}, adapters.BidderInfo{Enabled: true, Outbound: adapters.OutboundOptions{GzipRequests: true}})
+	registry.Register("pmpdsp", &outboundAdapterStub{opts: adapters.OutboundOptions{ClientCertFile: "/missing.pem", ClientKeyFile: "/missing-key.pem"}},
+		adapters.BidderInfo{Enabled: false})
+	ex := New(registry, nil)
+	if err := ex.ValidateBidderOutbound(); err != nil {
+		t.Errorf("expected valid options, got %v", err)
+	}
+
+	// Options supplied by the adapter itself are checked too
+	registry.Register("pmpdsp2", &outboundAdapterStub{opts: adapters.OutboundOptions{ClientCertFile: "/missing.pem", ClientKeyFile: "/missing-key.pem"}},
+		adapters.BidderInfo{Enabled: true})
+	if err := ex.ValidateBidderOutbound(); err == nil || !strings.Contains(err.Error(), "pmpdsp2") {
+		t.Errorf("expected an error naming pmpdsp2, got %v", err)
+	}
+}
+
+// staticHeaderSource returns fixed headers per bidder code
+type staticHeaderSource struct {
+	headers map[string]map[string]string
+	err     error
+}
+
+func (s *staticHeaderSource) BidderHeaders(_ context.Context, bidderCode string) (map[string]string, error) {
+	return s.headers[bidderCode], s.err
+}
+
+func TestWithBidderHeaders(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("rubicon", &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+	ex := New(registry, nil)
+	reqData := &adapters.RequestData{Method: "POST", URI: "https://bidder.test", Headers: http.Header{"Authorization": {"adapter"}}}
+
+	if got, err := ex.withBidderHeaders(context.Background(), "rubicon", reqData); err != nil || got != reqData {
+		t.Errorf("expected the request unchanged without a header source, got %+v (%v)", got, err)
+	}
+
+	ex.SetBidderHeaders(&staticHeaderSource{headers: map[string]map[string]string{
+		"rubicon": {"Authorization": "Bearer stored", "X-Region": "eu"},
+	}})
+	got, err := ex.withBidderHeaders(context.Background(), "rubicon", reqData)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Headers.Get("Authorization") != "Bearer stored" || got.Headers.Get("X-Region") != "eu" {
+		t.Errorf("expected stored headers, got %v", got.Headers)
+	}
+	if reqData.Headers.Get("Authorization") != "adapter" || reqData.Headers.Get("X-Region") != "" {
+		t.Errorf("expected the adapter's request left untouched, got %v", reqData.Headers)
+	}
+
+	ex.SetBidderHeaders(&staticHeaderSource{err: errors.New("secret not found")})
+	if _, err := ex.withBidderHeaders(context.Background(), "rubicon", reqData); err == nil {
+		t.Error("expected an error when headers can't be resolved")
+	}
+}
+
+// bytesMetrics captures bidder bytes metrics
+type bytesMetrics struct {
+	mockMetricsRecorder
+	mu       sync.Mutex
+	sent     map[string]int
+	received map[string]int
+}
+
+func (m *bytesMetrics) RecordBidderBytes(bidder string, sent, received int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent[bidder] += sent
+	m.received[bidder] += received
+}
+
+func TestRunAuction_GzipBidderRequests(t *testing.T) {
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("expected gzip request, got content encoding %q", r.Header.Get("Content-Encoding"))
+			return
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("expected gzip body: %v", err)
+			return
+		}
+		gotBody, _ = io.ReadAll(zr)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	body := []byte(`{"id":"gzip-auction","imp":[{"id":"imp1"}]}`)
+	registry := adapters.NewRegistry()
+	registry.Register("pubmatic", &mockAdapter{
+		requests: []*adapters.RequestData{{Method: "POST", URI: server.URL, Body: body}},
+	}, adapters.BidderInfo{Enabled: true, Outbound: adapters.OutboundOptions{GzipRequests: true}})
+
+	ex := New(registry, &Config{DefaultTimeout: 500 * time.Millisecond})
+	metrics := &bytesMetrics{sent: make(map[string]int), received: make(map[string]int)}
+	ex.SetMetrics(metrics)
+
+	_, err := ex.RunAuction(context.Background(), &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "gzip-auction",
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(gotBody) != string(body) {
+		t.Errorf("expected bidder to receive the original body, got %s", gotBody)
+	}
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if sent := metrics.sent["pubmatic"]; sent == 0 || sent == len(body) {
+		t.Errorf("expected compressed bytes sent, got %d", sent)
+	}
+	if received := metrics.received["pubmatic"]; received != 2 {
+		t.Errorf("expected 2 bytes received, got %d", received)
+	}
+}
@@ -0,0 +1,294 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// CatTaxIABContent10 is the OpenRTB cattax value for IAB Content Category 1.0,
+// the taxonomy assumed when bcat or bid.cat omit cattax
+const CatTaxIABContent10 = 1
+
+// publisherBlocklists is the stored per-publisher blocklist format
+type publisherBlocklists struct {
+	BCat   []string `json:"bcat,omitempty"`
+	CatTax int      `json:"cattax,omitempty"`
+	BAdv   []string `json:"badv,omitempty"`
+	BAttr  []int    `json:"battr,omitempty"`
+	BApp   []string `json:"bapp,omitempty"`
+}
+
+// responseBlocklist holds the blocklists enforced on returned bids for one auction
+// Request and publisher lists are merged; a bid matching either is rejected
+type responseBlocklist struct {
+	categories  map[int]map[string]struct{} // cattax -> blocked categories
+	advertisers []string                    // normalized advertiser domains
+	apps        map[string]struct{}         // lowercase app bundles
+	attrs       map[int]struct{}            // creative attributes blocked on every imp
+	impAttrs    map[string]map[int]struct{} // imp ID -> creative attributes blocked on that imp
+}
+
+// buildBlocklist merges the request's bcat/badv/battr/bapp with the publisher's stored blocklists
+// Returns nil when nothing is blocked
+func (e *Exchange) buildBlocklist(ctx context.Context, req *openrtb.BidRequest) *responseBlocklist {
+	bl := &responseBlocklist{}
+
+	if req != nil {
+		bl.addCategories(req.BCat, req.CatTax)
+		bl.addAdvertisers(req.BAdv)
+		bl.addApps(req.BApp)
+		for i := range req.Imp {
+			bl.addImpAttrs(&req.Imp[i])
+		}
+	}
+
+	if pub := middleware.PublisherFromContext(ctx); pub != nil {
+		if raw := extractBlocklists(pub); len(raw) > 0 {
+			var stored publisherBlocklists
+			if err := json.Unmarshal(raw, &stored); err != nil {
+				publisherID, _ := extractPublisherID(pub)
+				logger.Log.Warn().
+					Err(err).
+					Str("publisher_id", publisherID).
+					Msg("Invalid stored blocklists, ignoring")
+			} else {
+				bl.addCategories(stored.BCat, stored.CatTax)
+				bl.addAdvertisers(stored.BAdv)
+				bl.addApps(stored.BApp)
+				for _, attr := range stored.BAttr {
+					if bl.attrs == nil {
+						bl.attrs = make(map[int]struct{})
+					}
+					bl.attrs[attr] = struct{}{}
+				}
+			}
+		}
+	}
+
+	if len(bl.categories) == 0 && len(bl.advertisers) == 0 && len(bl.apps) == 0 &&
+		len(bl.attrs) == 0 && len(bl.impAttrs) == 0 {
+		return nil
+	}
+	return bl
+}
+
+// addCategories records blocked categories under their taxonomy
+func (b *responseBlocklist) addCategories(cats []string, catTax int) {
+	tax := normalizeCatTax(catTax)
+	for _, cat := range cats {
+		cat = strings.TrimSpace(cat)
+		if cat == "" {
+			continue
+		}
+		if b.categories == nil {
+			b.categories = make(map[int]map[string]struct{})
+		}
+		if b.categories[tax] == nil {
+			b.categories[tax] = make(map[string]struct{})
+		}
+		b.categories[tax][strings.ToUpper(cat)] = struct{}{}
+	}
+}
+
+// addAdvertisers records blocked advertiser domains
+func (b *responseBlocklist) addAdvertisers(domains []string) {
+	for _, d := range domains {
+		if d = normalizeDomain(d); d != "" {
+			b.advertisers = append(b.advertisers, d)
+		}
+	}
+}
+
+// addApps records blocked app bundles
+func (b *responseBlocklist) addApps(bundles []string) {
+	for _, bundle := range bundles {
+		bundle = strings.ToLower(strings.TrimSpace(bundle))
+		if bundle == "" {
+			continue
+		}
+		if b.apps == nil {
+			b.apps = make(map[string]struct{})
+		}
+		b.apps[bundle] = struct{}{}
+	}
+}
+
+// addImpAttrs records the battr of every media object on the impression
+func (b *responseBlocklist) addImpAttrs(imp *openrtb.Imp) {
+	var lists [][]int
+	if imp.Banner != nil {
+		lists = append(lists, imp.Banner.BAttr)
+	}
+	if imp.Video != nil {
+		lists = append(lists, imp.Video.BAttr)
+	}
+	if imp.Audio != nil {
+		lists = append(lists, imp.Audio.BAttr)
+	}
+	if imp.Native != nil {
+		lists = append(lists, imp.Native.BAttr)
+	}
+	for _, list := range lists {
+		for _, attr := range list {
+			if b.impAttrs == nil {
+				b.impAttrs = make(map[string]map[int]struct{})
+			}
+			if b.impAttrs[imp.ID] == nil {
+				b.impAttrs[imp.ID] = make(map[int]struct{})
+			}
+			b.impAttrs[imp.ID][attr] = struct{}{}
+		}
+	}
+}
+
+// check returns the seat-non-bid status code and reason when the bid is blocked,
+// or 0 and an empty reason when it may take part in the auction
+func (b *responseBlocklist) check(bid *openrtb.Bid) (openrtb.NonBidStatusCode, string) {
+	if b == nil || bid == nil {
+		return 0, ""
+	}
+
+	for _, adomain := range bid.ADomain {
+		if blocked := b.blockedAdvertiser(adomain); blocked != "" {
+			return openrtb.NonBidCreativeFiltered,
+				fmt.Sprintf("advertiser domain %q blocked by badv %q", adomain, blocked)
+		}
+	}
+
+	if cat := b.blockedCategory(bid); cat != "" {
+		return openrtb.NonBidCreativeFiltered,
+			fmt.Sprintf("category %q blocked by bcat", cat)
+	}
+
+	for _, attr := range bid.Attr {
+		_, global := b.attrs[attr]
+		_, imp := b.impAttrs[bid.ImpID][attr]
+		if global || imp {
+			return openrtb.NonBidCreativeFiltered,
+				fmt.Sprintf("creative attribute %d blocked by battr", attr)
+		}
+	}
+
+	if bid.Bundle != "" {
+		if _, blocked := b.apps[strings.ToLower(bid.Bundle)]; blocked {
+			return openrtb.NonBidCreativeFiltered,
+				fmt.Sprintf("app bundle %q blocked by bapp", bid.Bundle)
+		}
+	}
+
+	return 0, ""
+}
+
+// blockedAdvertiser returns the blocked domain matching adomain, including its subdomains
+func (b *responseBlocklist) blockedAdvertiser(adomain string) string {
+	d := normalizeDomain(adomain)
+	if d == "" {
+		return ""
+	}
+	for _, blocked := range b.advertisers {
+		if d == blocked || strings.HasSuffix(d, "."+blocked) {
+			return blocked
+		}
+	}
+	return ""
+}
+
+// blockedCategory returns the first blocked category on the bid
+// Categories are only compared when the bid uses the same taxonomy as the blocklist;
+// in IAB Content Category 1.0 a blocked tier-1 category (IAB1) also blocks its tier-2 children (IAB1-5)
+func (b *responseBlocklist) blockedCategory(bid *openrtb.Bid) string {
+	if len(b.categories) == 0 || len(bid.Cat) == 0 {
+		return ""
+	}
+
+	tax := normalizeCatTax(bid.CatTax)
+	blocked := b.categories[tax]
+	if len(blocked) == 0 {
+		logger.Log.Debug().
+			Str("bidID", bid.ID).
+			Int("cattax", tax).
+			Msg("bid categories use a taxonomy with no blocklist, not checked")
+		return ""
+	}
+
+	for _, cat := range bid.Cat {
+		c := strings.ToUpper(strings.TrimSpace(cat))
+		if _, ok := blocked[c]; ok {
+			return cat
+		}
+		if tax == CatTaxIABContent10 {
+			if i := strings.IndexByte(c, '-'); i > 0 {
+				if _, ok := blocked[c[:i]]; ok {
+					return cat
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// normalizeCatTax maps an omitted cattax to the OpenRTB default
+func normalizeCatTax(catTax int) int {
+	if catTax <= 0 {
+		return CatTaxIABContent10
+	}
+	return catTax
+}
+
+// normalizeDomain reduces an advertiser domain or URL to a lowercase host without "www."
+func normalizeDomain(d string) string {
+	d = strings.ToLower(strings.TrimSpace(d))
+	if i := strings.Index(d, "://"); i >= 0 {
+		d = d[i+3:]
+	}
+	if i := strings.IndexAny(d, "/?#"); i >= 0 {
+		d = d[:i]
+	}
+	if i := strings.LastIndexByte(d, ':'); i >= 0 {
+		d = d[:i]
+	}
+	d = strings.TrimSuffix(d, ".")
+	return strings.TrimPrefix(d, "www.")
+}
+
+// newNonBid builds the seat-non-bid entry for a rejected bid
+func newNonBid(bid *openrtb.Bid, code openrtb.NonBidStatusCode, reason string) openrtb.NonBid {
+	return openrtb.NonBid{
+		ImpID:      bid.ImpID,
+		StatusCode: code,
+		Ext: &openrtb.NonBidExt{
+			Prebid: openrtb.NonBidExtPrebid{
+				Bid: openrtb.NonBidBid{
+					ID:      bid.ID,
+					Price:   bid.Price,
+					ADomain: bid.ADomain,
+					Cat:     bid.Cat,
+					Attr:    bid.Attr,
+					Bundle:  bid.Bundle,
+					CRID:    bid.CRID,
+					DealID:  bid.DealID,
+					W:       bid.W,
+					H:       bid.H,
+				},
+				Reason: reason,
+			},
+		},
+	}
+}
+
+// extractBlocklists safely extracts the stored blocklists from the publisher
+func extractBlocklists(v interface{}) json.RawMessage {
+	type blocklistsGetter interface {
+		GetBlocklists() json.RawMessage
+	}
+	if getter, ok := v.(blocklistsGetter); ok {
+		return getter.GetBlocklists()
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// mockPublisherWithBlocklists is a publisher with stored blocklists
+type mockPublisherWithBlocklists struct {
+	mockPublisherWithMultiplier
+	Blocklists json.RawMessage
+}
+
+func (m *mockPublisherWithBlocklists) GetBlocklists() json.RawMessage {
+	return m.Blocklists
+}
+
+func TestBlocklist_Check(t *testing.T) {
+	ex := New(adapters.NewRegistry(), nil)
+	bl := ex.buildBlocklist(context.Background(), &openrtb.BidRequest{
+		BCat: []string{"IAB25", "IAB7-39"},
+		BAdv: []string{"https://www.Blocked.com/landing", "other.org"},
+		BApp: []string{"com.blocked.app"},
+		Imp: []openrtb.Imp{
+			{ID: "imp1", Banner: &openrtb.Banner{BAttr: []int{1, 3}}},
+			{ID: "imp2", Video: &openrtb.Video{BAttr: []int{16}}},
+		},
+	})
+	if bl == nil {
+		t.Fatal("expected blocklist")
+	}
+
+	tests := []struct {
+		name string
+		bid  openrtb.Bid
+		want openrtb.NonBidStatusCode
+	}{
+		{"clean bid", openrtb.Bid{ImpID: "imp1", ADomain: []string{"fine.com"}, Cat: []string{"IAB1"}}, 0},
+		{"exact advertiser", openrtb.Bid{ImpID: "imp1", ADomain: []string{"blocked.com"}}, openrtb.NonBidCreativeFiltered},
+		{"advertiser subdomain", openrtb.Bid{ImpID: "imp1", ADomain: []string{"ads.blocked.com"}}, openrtb.NonBidCreativeFiltered},
+		{"advertiser suffix is not subdomain", openrtb.Bid{ImpID: "imp1", ADomain: []string{"notblocked.com"}}, 0},
+		{"tier-1 category blocks children", openrtb.Bid{ImpID: "imp1", Cat: []string{"IAB25-3"}}, openrtb.NonBidCreativeFiltered},
+		{"tier-2 category blocks only itself", openrtb.Bid{ImpID: "imp1", Cat: []string{"IAB7-38"}}, 0},
+		{"other taxonomy not compared", openrtb.Bid{ImpID: "imp1", Cat: []string{"IAB25"}, CatTax: 7}, 0},
+		{"imp attribute", openrtb.Bid{ImpID: "imp1", Attr: []int{3}}, openrtb.NonBidCreativeFiltered},
+		{"attribute blocked on other imp only", openrtb.Bid{ImpID: "imp1", Attr: []int{16}}, 0},
+		{"video attribute", openrtb.Bid{ImpID: "imp2", Attr: []int{16}}, openrtb.NonBidCreativeFiltered},
+		{"app bundle", openrtb.Bid{ImpID: "imp1", Bundle: "COM.BLOCKED.APP"}, openrtb.NonBidCreativeFiltered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, reason := bl.check(&tt.bid)
+			if code != tt.want {
+				t.Errorf("expected code %d, got %d (%s)", tt.want, code, reason)
+			}
+			if (code != 0) != (reason != "") {
+				t.Errorf("reason %q does not match code %d", reason, code)
+			}
+		})
+	}
+}
+
+func TestBlocklist_NoneConfigured(t *testing.T) {
+	ex := New(adapters.NewRegistry(), nil)
+	bl := ex.buildBlocklist(context.Background(), &openrtb.BidRequest{
+		Imp: []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+	})
+	if bl != nil {
+		t.Errorf("expected nil blocklist, got %+v", bl)
+	}
+	if code, _ := bl.check(&openrtb.Bid{ADomain: []string{"any.com"}}); code != 0 {
+		t.Errorf("expected nil blocklist to allow bids, got %d", code)
+	}
+}
+
+func TestBlocklist_PublisherLists(t *testing.T) {
+	ex := New(adapters.NewRegistry(), nil)
+	pub := &mockPublisherWithBlocklists{
+		mockPublisherWithMultiplier: mockPublisherWithMultiplier{PublisherID: "pub1", BidMultiplier: 1.0},
+		Blocklists:                  json.RawMessage(`{"bcat":["481"],"cattax":7,"badv":["competitor.com"],"battr":[8]}`),
+	}
+	ctx := middleware.NewContextWithPublisher(context.Background(), pub)
+
+	bl := ex.buildBlocklist(ctx, &openrtb.BidRequest{BCat: []string{"IAB26"}})
+	if bl == nil {
+		t.Fatal("expected blocklist")
+	}
+
+	if code, _ := bl.check(&openrtb.Bid{ADomain: []string{"www.competitor.com"}}); code != openrtb.NonBidCreativeFiltered {
+		t.Errorf("expected publisher badv to apply, got %d", code)
+	}
+	if code, _ := bl.check(&openrtb.Bid{ImpID: "any", Attr: []int{8}}); code != openrtb.NonBidCreativeFiltered {
+		t.Errorf("expected publisher battr to apply on every imp, got %d", code)
+	}
+	if code, _ := bl.check(&openrtb.Bid{Cat: []string{"481"}, CatTax: 7}); code != openrtb.NonBidCreativeFiltered {
+		t.Errorf("expected publisher bcat in taxonomy 7, got %d", code)
+	}
+	if code, _ := bl.check(&openrtb.Bid{Cat: []string{"IAB26"}}); code != openrtb.NonBidCreativeFiltered {
+		t.Errorf("expected request bcat in default taxonomy, got %d", code)
+	}
+
+	// Invalid stored JSON is ignored; the request lists still apply
+	pub.Blocklists = json.RawMessage(`{"badv":`)
+	if bl := ex.buildBlocklist(ctx, &openrtb.BidRequest{}); bl != nil {
+		t.Errorf("expected invalid stored blocklists to be ignored, got %+v", bl)
+	}
+}
+
+func TestNormalizeDomain(t *testing.T) {
+	tests := map[string]string{
+		"Example.com":                  "example.com",
+		" www.example.com ":            "example.com",
+		"https://www.example.com/path": "example.com",
+		"http://ads.example.com:8080":  "ads.example.com",
+		"example.com.":                 "example.com",
+		"":                             "",
+	}
+	for in, want := range tests {
+		if got := normalizeDomain(in); got != want {
+			t.Errorf("normalizeDomain(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRunAuction_Blocklists(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("rubicon", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "r1", ImpID: "imp1", Price: 3.00, AdM: "ad", ADomain: []string{"shop.blocked.com"}, W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true})
+	registry.Register("appnexus", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "a1", ImpID: "imp1", Price: 1.50, AdM: "ad", ADomain: []string{"fine.com"}, W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{
+		DefaultTimeout: 500 * time.Millisecond,
+		AuctionType:    FirstPriceAuction,
+	})
+
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "block-auction",
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+			BAdv: []string{"blocked.com"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var winners []openrtb.Bid
+	for _, sb := range resp.BidResponse.SeatBid {
+		winners = append(winners, sb.Bid...)
+	}
+	if len(winners) != 1 || winners[0].ID != "a1" {
+		t.Fatalf("expected only appnexus bid a1, got %+v", winners)
+	}
+
+	nonBids := resp.DebugInfo.NonBids["rubicon"]
+	if len(nonBids) != 1 {
+		t.Fatalf("expected 1 rubicon non-bid, got %+v", resp.DebugInfo.NonBids)
+	}
+	if nonBids[0].StatusCode != openrtb.NonBidCreativeFiltered || nonBids[0].ImpID != "imp1" {
+		t.Errorf("unexpected non-bid: %+v", nonBids[0])
+	}
+	if nonBids[0].Ext == nil || nonBids[0].Ext.Prebid.Bid.Price != 3.00 || nonBids[0].Ext.Prebid.Reason == "" {
+		t.Errorf("expected rejected bid details, got %+v", nonBids[0].Ext)
+	}
+	if len(resp.DebugInfo.Errors["rubicon"]) != 1 {
+		t.Errorf("expected blocking reason in debug errors, got %v", resp.DebugInfo.Errors["rubicon"])
+	}
+}
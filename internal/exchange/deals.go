@@ -0,0 +1,150 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// impPMP holds the private marketplace rules for a single impression
+type impPMP struct {
+	privateAuction bool
+	deals          map[string]openrtb.Deal
+	floors         map[string]float64 // Effective floor per deal ID (0 = inherit imp floor)
+}
+
+// buildImpDealMap creates a map of impression IDs to their PMP rules
+// Deal floors are the higher of imp.pmp.deals[].bidfloor and the publisher's configured
+// deal floor, with the publisher bid multiplier applied the same way as imp floors
+func (e *Exchange) buildImpDealMap(ctx context.Context, req *openrtb.BidRequest) map[string]*impPMP {
+	multiplier, _ := publisherFloorMultiplier(ctx)
+	var publisherFloors map[string]float64
+	if pub := middleware.PublisherFromContext(ctx); pub != nil {
+		publisherFloors = extractDealFloors(pub)
+	}
+
+	impDeals := make(map[string]*impPMP)
+	for _, imp := range req.Imp {
+		if imp.PMP == nil || (len(imp.PMP.Deals) == 0 && imp.PMP.PrivateAuction != 1) {
+			continue
+		}
+
+		pmp := &impPMP{
+			privateAuction: imp.PMP.PrivateAuction == 1,
+			deals:          make(map[string]openrtb.Deal, len(imp.PMP.Deals)),
+			floors:         make(map[string]float64, len(imp.PMP.Deals)),
+		}
+		for _, deal := range imp.PMP.Deals {
+			if deal.ID == "" {
+				continue
+			}
+			pmp.deals[deal.ID] = deal
+
+			floor := deal.BidFloor
+			if pf := publisherFloors[deal.ID]; pf > floor {
+				floor = pf
+			}
+			if floor > 0 {
+				pmp.floors[deal.ID] = roundToCents(floor * multiplier)
+			}
+		}
+		impDeals[imp.ID] = pmp
+	}
+
+	return impDeals
+}
+
+// validateDeal checks a bid against the impression's PMP rules
+// Returns an empty string when the bid is allowed. A nil impPMP is an impression without
+// deals, on which any dealid is rejected.
+func (p *impPMP) validateDeal(bid *openrtb.Bid, bidderCode string) string {
+	if p == nil {
+		if bid.DealID != "" {
+			return fmt.Sprintf("dealid %q not offered on impression", bid.DealID)
+		}
+		return ""
+	}
+	if bid.DealID == "" {
+		if p.privateAuction {
+			return "open market bid not allowed in private auction"
+		}
+		return ""
+	}
+
+	deal, ok := p.deals[bid.DealID]
+	if !ok {
+		return fmt.Sprintf("dealid %q not offered on impression", bid.DealID)
+	}
+
+	if len(deal.WSeat) > 0 && !containsFold(deal.WSeat, bidderCode) {
+		return fmt.Sprintf("seat %q not allowed on deal %q", bidderCode, bid.DealID)
+	}
+
+	return ""
+}
+
+// dealFloor returns the effective floor for a deal, or false if the deal has none
+func (p *impPMP) dealFloor(dealID string) (float64, bool) {
+	floor, ok := p.floors[dealID]
+	return floor, ok
+}
+
+// hasDeal returns true if the deal is offered on the impression
+func (p *impPMP) hasDeal(dealID string) bool {
+	_, ok := p.deals[dealID]
+	return ok
+}
+
+// bidFloor returns the floor a bid must meet
+// Bids on a deal offered in imp.pmp use the deal floor when one is set; all others use the imp floor
+func bidFloor(bid *openrtb.Bid, impFloors map[string]float64, impDeals map[string]*impPMP) float64 {
+	if bid.DealID != "" {
+		if pmp := impDeals[bid.ImpID]; pmp != nil {
+			if floor, ok := pmp.dealFloor(bid.DealID); ok {
+				return floor
+			}
+		}
+	}
+	return impFloors[bid.ImpID]
+}
+
+// dealTier returns the ranking tier of a bid
+// Open market bids (and deals not offered in imp.pmp) are tier 0. Bids on an offered
+// deal rank above them, ordered by the adapter-supplied deal priority.
+func dealTier(bid *openrtb.Bid, dealPriority int, impDeals map[string]*impPMP) int {
+	if bid.DealID == "" {
+		return 0
+	}
+	pmp := impDeals[bid.ImpID]
+	if pmp == nil || !pmp.hasDeal(bid.DealID) {
+		return 0
+	}
+	if dealPriority < 0 {
+		dealPriority = 0
+	}
+	return 1 + dealPriority
+}
+
+// extractDealFloors safely extracts per-deal floors from the publisher
+func extractDealFloors(v interface{}) map[string]float64 {
+	type dealFloorsGetter interface {
+		GetDealFloors() map[string]float64
+	}
+	if getter, ok := v.(dealFloorsGetter); ok {
+		return getter.GetDealFloors()
+	}
+	return nil
+}
+
+// containsFold reports whether list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
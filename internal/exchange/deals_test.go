@@ -0,0 +1,286 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// mockPublisherWithDealFloors is a publisher with per-deal floors configured
+type mockPublisherWithDealFloors struct {
+	mockPublisherWithMultiplier
+	DealFloors map[string]float64
+}
+
+func (m *mockPublisherWithDealFloors) GetDealFloors() map[string]float64 {
+	return m.DealFloors
+}
+
+func pmpRequest(privateAuction int, deals ...openrtb.Deal) *openrtb.BidRequest {
+	return &openrtb.BidRequest{
+		ID:   "deal-req",
+		Site: testSite(),
+		Imp: []openrtb.Imp{
+			{
+				ID:       "imp1",
+				Banner:   &openrtb.Banner{W: 300, H: 250},
+				BidFloor: 1.00,
+				PMP:      &openrtb.PMP{PrivateAuction: privateAuction, Deals: deals},
+			},
+			{ID: "imp2", Banner: &openrtb.Banner{W: 728, H: 90}},
+		},
+	}
+}
+
+func TestBuildImpDealMap(t *testing.T) {
+	ex := New(adapters.NewRegistry(), nil)
+	req := pmpRequest(1,
+		openrtb.Deal{ID: "deal-a", BidFloor: 3.00},
+		openrtb.Deal{ID: "deal-b"},
+		openrtb.Deal{ID: "deal-c", BidFloor: 2.00},
+		openrtb.Deal{ID: ""},
+	)
+
+	pub := &mockPublisherWithDealFloors{
+		mockPublisherWithMultiplier: mockPublisherWithMultiplier{PublisherID: "pub1", BidMultiplier: 1.10},
+		DealFloors:                  map[string]float64{"deal-b": 4.00, "deal-c": 1.00},
+	}
+	ctx := middleware.NewContextWithPublisher(context.Background(), pub)
+
+	impDeals := ex.buildImpDealMap(ctx, req)
+
+	if _, ok := impDeals["imp2"]; ok {
+		t.Error("expected no PMP rules for imp without pmp")
+	}
+	pmp := impDeals["imp1"]
+	if pmp == nil {
+		t.Fatal("expected PMP rules for imp1")
+	}
+	if !pmp.privateAuction {
+		t.Error("expected private auction")
+	}
+	if len(pmp.deals) != 3 {
+		t.Errorf("expected 3 deals (empty ID skipped), got %d", len(pmp.deals))
+	}
+
+	tests := []struct {
+		dealID string
+		want   float64
+	}{
+		{"deal-a", 3.30}, // request floor x multiplier
+		{"deal-b", 4.40}, // publisher floor x multiplier
+		{"deal-c", 2.20}, // request floor beats lower publisher floor
+	}
+	for _, tt := range tests {
+		got, ok := pmp.dealFloor(tt.dealID)
+		if !ok || got != tt.want {
+			t.Errorf("deal %s: expected floor %.2f, got %.2f (ok=%v)", tt.dealID, tt.want, got, ok)
+		}
+	}
+}
+
+func TestValidateBid_Deals(t *testing.T) {
+	ex := New(adapters.NewRegistry(), &Config{MinBidPrice: 0})
+	req := pmpRequest(0,
+		openrtb.Deal{ID: "deal-a", BidFloor: 3.00},
+		openrtb.Deal{ID: "deal-seat", WSeat: []string{"AppNexus"}},
+		openrtb.Deal{ID: "deal-nofloor"},
+	)
+	impFloors := ex.buildImpFloorMap(context.Background(), req)
+	impDeals := ex.buildImpDealMap(context.Background(), req)
+
+	tests := []struct {
+		name       string
+		bid        *openrtb.Bid
+		bidderCode string
+		wantErr    string
+	}{
+		{"deal bid meets deal floor", &openrtb.Bid{DealID: "deal-a", Price: 3.00}, "rubicon", ""},
+		{"deal bid below deal floor", &openrtb.Bid{DealID: "deal-a", Price: 2.50}, "rubicon", "below floor 3.0000"},
+		{"unknown deal", &openrtb.Bid{DealID: "deal-x", Price: 5.00}, "rubicon", "not offered"},
+		{"wseat allowed (case-insensitive)", &openrtb.Bid{DealID: "deal-seat", Price: 1.50}, "appnexus", ""},
+		{"wseat rejected", &openrtb.Bid{DealID: "deal-seat", Price: 1.50}, "rubicon", "not allowed on deal"},
+		{"deal without floor inherits imp floor", &openrtb.Bid{DealID: "deal-nofloor", Price: 0.50}, "rubicon", "below floor 1.0000"},
+		{"open market allowed", &openrtb.Bid{Price: 1.50}, "rubicon", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.bid.ID = "bid1"
+			tt.bid.ImpID = "imp1"
+			tt.bid.AdM = "<div>ad</div>"
+
+			err := ex.validateBid(tt.bid, tt.bidderCode, impFloors, impDeals)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Reason, tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateBid_DealOnImpWithoutPMP(t *testing.T) {
+	ex := New(adapters.NewRegistry(), &Config{MinBidPrice: 0})
+	req := pmpRequest(0, openrtb.Deal{ID: "deal-a"})
+	impFloors := ex.buildImpFloorMap(context.Background(), req)
+	impDeals := ex.buildImpDealMap(context.Background(), req)
+
+	// imp2 has no imp.pmp, so no deal was offered on it
+	bid := &openrtb.Bid{ID: "bid1", ImpID: "imp2", DealID: "deal-a", Price: 5.00, AdM: "<div>ad</div>"}
+	if err := ex.validateBid(bid, "rubicon", impFloors, impDeals); err == nil || !strings.Contains(err.Reason, "not offered") {
+		t.Errorf("expected deal on imp without pmp to be rejected, got %v", err)
+	}
+
+	bid.DealID = ""
+	if err := ex.validateBid(bid, "rubicon", impFloors, impDeals); err != nil {
+		t.Errorf("expected open market bid on imp without pmp to be allowed, got %v", err)
+	}
+}
+
+func TestValidateBid_PrivateAuction(t *testing.T) {
+	ex := New(adapters.NewRegistry(), &Config{MinBidPrice: 0})
+	req := pmpRequest(1, openrtb.Deal{ID: "deal-a"})
+	impFloors := ex.buildImpFloorMap(context.Background(), req)
+	impDeals := ex.buildImpDealMap(context.Background(), req)
+
+	openBid := &openrtb.Bid{ID: "b1", ImpID: "imp1", Price: 10.00, AdM: "ad"}
+	if err := ex.validateBid(openBid, "rubicon", impFloors, impDeals); err == nil {
+		t.Error("expected open market bid to be rejected in private auction")
+	}
+
+	dealBid := &openrtb.Bid{ID: "b2", ImpID: "imp1", Price: 2.00, DealID: "deal-a", AdM: "ad"}
+	if err := ex.validateBid(dealBid, "rubicon", impFloors, impDeals); err != nil {
+		t.Errorf("expected deal bid to be accepted, got %v", err)
+	}
+
+	// Other impressions are unaffected
+	otherBid := &openrtb.Bid{ID: "b3", ImpID: "imp2", Price: 2.00, AdM: "ad"}
+	if err := ex.validateBid(otherBid, "rubicon", impFloors, impDeals); err != nil {
+		t.Errorf("expected open market bid on imp2 to be accepted, got %v", err)
+	}
+}
+
+func TestDealTier(t *testing.T) {
+	impDeals := map[string]*impPMP{
+		"imp1": {deals: map[string]openrtb.Deal{"deal-a": {ID: "deal-a"}}},
+	}
+
+	tests := []struct {
+		name     string
+		bid      *openrtb.Bid
+		priority int
+		want     int
+	}{
+		{"open market", &openrtb.Bid{ImpID: "imp1"}, 5, 0},
+		{"offered deal", &openrtb.Bid{ImpID: "imp1", DealID: "deal-a"}, 0, 1},
+		{"offered deal with priority", &openrtb.Bid{ImpID: "imp1", DealID: "deal-a"}, 3, 4},
+		{"deal not in pmp", &openrtb.Bid{ImpID: "imp2", DealID: "deal-a"}, 3, 0},
+	}
+	for _, tt := range tests {
+		if got := dealTier(tt.bid, tt.priority, impDeals); got != tt.want {
+			t.Errorf("%s: expected tier %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestSortBidsByPrice_DealTiers(t *testing.T) {
+	bids := []ValidatedBid{
+		{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: "open-high", Price: 20.00}}},
+		{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: "deal-low", Price: 3.00}}, DealTier: 1},
+		{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: "deal-priority", Price: 2.00}}, DealTier: 3},
+		{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: "deal-high", Price: 5.00}}, DealTier: 1},
+	}
+
+	sortBidsByPrice(bids)
+
+	want := []string{"deal-priority", "deal-high", "deal-low", "open-high"}
+	for i, id := range want {
+		if bids[i].Bid.Bid.ID != id {
+			t.Errorf("position %d: expected %s, got %s", i, id, bids[i].Bid.Bid.ID)
+		}
+	}
+}
+
+func TestAuctionLogic_SecondPrice_DealTier(t *testing.T) {
+	ex := New(adapters.NewRegistry(), &Config{
+		AuctionType:    SecondPriceAuction,
+		PriceIncrement: 0.01,
+	})
+	impFloors := map[string]float64{"imp1": 1.00}
+
+	validBids := []ValidatedBid{
+		{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: "open", ImpID: "imp1", Price: 10.00}}, BidderCode: "b1"},
+		{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: "deal", ImpID: "imp1", Price: 4.00, DealID: "d1"}}, BidderCode: "b2", DealTier: 1, Floor: 3.00},
+	}
+
+	result := ex.runAuctionLogic(validBids, impFloors, SecondPriceAuction)
+
+	winner := result["imp1"][0]
+	if winner.Bid.Bid.ID != "deal" {
+		t.Fatalf("expected deal bid to win, got %s", winner.Bid.Bid.ID)
+	}
+	// Open market bid is in a lower tier, so the deal clears at its floor + increment
+	if winner.Bid.Bid.Price != 3.01 {
+		t.Errorf("expected clearing price 3.01, got %.2f", winner.Bid.Bid.Price)
+	}
+}
+
+func TestRunAuction_DealPriority(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("openbidder", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "open1", ImpID: "imp1", Price: 8.00, AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true, DemandType: adapters.DemandTypePublisher})
+	registry.Register("dealbidder", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "deal1", ImpID: "imp1", Price: 4.00, DealID: "deal-a", AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+		{Bid: &openrtb.Bid{ID: "deal2", ImpID: "imp1", Price: 9.00, DealID: "deal-unknown", AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true, DemandType: adapters.DemandTypePlatform})
+
+	ex := New(registry, &Config{
+		DefaultTimeout: 500 * time.Millisecond,
+		AuctionType:    FirstPriceAuction,
+	})
+
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+		BidRequest: pmpRequest(0, openrtb.Deal{ID: "deal-a", BidFloor: 2.00}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var platformBid *openrtb.Bid
+	for _, sb := range resp.BidResponse.SeatBid {
+		if sb.Seat == adapters.PlatformSeatName {
+			platformBid = &sb.Bid[0]
+		}
+	}
+	if platformBid == nil {
+		t.Fatal("expected platform seat bid")
+	}
+	if platformBid.ID != "deal1" {
+		t.Errorf("expected offered deal bid, got %s", platformBid.ID)
+	}
+
+	var ext openrtb.BidExt
+	if err := json.Unmarshal(platformBid.Ext, &ext); err != nil {
+		t.Fatalf("failed to parse bid ext: %v", err)
+	}
+	if ext.Prebid.Targeting["hb_deal"] != "deal-a" {
+		t.Errorf("expected hb_deal=deal-a, got %q", ext.Prebid.Targeting["hb_deal"])
+	}
+
+	// Bid on a deal not offered in imp.pmp is rejected
+	if errs := resp.DebugInfo.Errors["dealbidder"]; len(errs) == 0 || !strings.Contains(errs[0], "not offered") {
+		t.Errorf("expected deal validation error, got %v", errs)
+	}
+}
@@ -0,0 +1,199 @@
+package exchange
+
+import (
+	"context"
+	"strings"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// targetedAdapter is implemented by adapters with publisher and country targeting (e.g. ortb.GenericAdapter)
+type targetedAdapter interface {
+	CanBidForPublisher(publisherID string) bool
+	CanBidForCountry(country string) bool
+}
+
+// filterEligibleBidders drops the bidders that cannot bid on a request before selection
+// A bidder is dropped when its targeting excludes the publisher or country, when it does not
+// support the request's site or app channel, or when it supports none of the imps (see
+// supportedImp). Unknown publishers and countries are not filtered on. Returns the eligible
+// bidders and, for each dropped bidder, the reason.
+func (e *Exchange) filterEligibleBidders(ctx context.Context, req *openrtb.BidRequest, bidders []string) ([]string, map[string]string) {
+	publisherID := requestPublisherID(ctx, req)
+	var country string
+	if req.Device != nil && req.Device.Geo != nil {
+		country = req.Device.Geo.Country
+	}
+
+	eligible := make([]string, 0, len(bidders))
+	var dropped map[string]string
+	for _, code := range bidders {
+		awi, ok := e.lookupBidder(ctx, code)
+		if !ok {
+			eligible = append(eligible, code)
+			continue
+		}
+		reason := bidderIneligibility(awi, req, publisherID, country)
+		if reason == "" {
+			eligible = append(eligible, code)
+			continue
+		}
+		logger.Log.Debug().
+			Str("bidder", code).
+			Str("request_id", req.ID).
+			Str("reason", reason).
+			Msg("Skipping bidder - not eligible for request")
+		if dropped == nil {
+			dropped = make(map[string]string)
+		}
+		dropped[code] = reason
+	}
+	return eligible, dropped
+}
+
+// bidderIneligibility returns why a bidder cannot bid on a request, or "" when it can
+func bidderIneligibility(awi adapters.AdapterWithInfo, req *openrtb.BidRequest, publisherID, country string) string {
+	if ta, ok := awi.Adapter.(targetedAdapter); ok {
+		if publisherID != "" && !ta.CanBidForPublisher(publisherID) {
+			return "publisher not targeted"
+		}
+		if country != "" && !ta.CanBidForCountry(country) {
+			return "country not targeted"
+		}
+	}
+
+	caps := awi.Info.Capabilities
+	if caps == nil {
+		return ""
+	}
+	platform, ok := requestPlatform(caps, req)
+	if !ok {
+		if req.App != nil {
+			return "app not supported"
+		}
+		return "site not supported"
+	}
+	for _, imp := range req.Imp {
+		if _, ok := supportedImp(imp, platform, caps); ok {
+			return ""
+		}
+	}
+	return "no supported imps"
+}
+
+// pruneUnsupportedImps removes the imps, and the media types of multi-format imps, a bidder
+// does not support from its copy of the request
+func pruneUnsupportedImps(req *openrtb.BidRequest, awi adapters.AdapterWithInfo) {
+	caps := awi.Info.Capabilities
+	if caps == nil {
+		return
+	}
+	platform, _ := requestPlatform(caps, req)
+	kept := req.Imp[:0]
+	for _, imp := range req.Imp {
+		if supported, ok := supportedImp(imp, platform, caps); ok {
+			kept = append(kept, supported)
+		}
+	}
+	req.Imp = kept
+}
+
+// requestPlatform returns the bidder's capabilities for the request's site or app channel
+// Returns false when the bidder does not support the channel. Bidders that declare neither
+// site nor app support, and requests with neither (e.g. DOOH), are not restricted.
+func requestPlatform(caps *adapters.CapabilitiesInfo, req *openrtb.BidRequest) (*adapters.PlatformInfo, bool) {
+	if caps.Site == nil && caps.App == nil {
+		return nil, true
+	}
+	switch {
+	case req.Site != nil:
+		return caps.Site, caps.Site != nil
+	case req.App != nil:
+		return caps.App, caps.App != nil
+	}
+	return nil, true
+}
+
+// supportedImp returns the imp without the media types the bidder does not support
+// Video also needs a mime type and protocol in common with the bidder's, when both list them.
+// Returns false when no media type is left.
+func supportedImp(imp openrtb.Imp, platform *adapters.PlatformInfo, caps *adapters.CapabilitiesInfo) (openrtb.Imp, bool) {
+	if imp.Banner != nil && !supportsMediaType(platform, adapters.BidTypeBanner) {
+		imp.Banner = nil
+	}
+	if imp.Video != nil && (!supportsMediaType(platform, adapters.BidTypeVideo) || !supportsVideo(imp.Video, caps)) {
+		imp.Video = nil
+	}
+	if imp.Audio != nil && !supportsMediaType(platform, adapters.BidTypeAudio) {
+		imp.Audio = nil
+	}
+	if imp.Native != nil && !supportsMediaType(platform, adapters.BidTypeNative) {
+		imp.Native = nil
+	}
+	return imp, imp.Banner != nil || imp.Video != nil || imp.Audio != nil || imp.Native != nil
+}
+
+// supportsMediaType reports whether a platform accepts a media type (no media types = any)
+func supportsMediaType(platform *adapters.PlatformInfo, bidType adapters.BidType) bool {
+	if platform == nil || len(platform.MediaTypes) == 0 {
+		return true
+	}
+	for _, mt := range platform.MediaTypes {
+		if mt == bidType {
+			return true
+		}
+	}
+	return false
+}
+
+// supportsVideo reports whether a video imp shares a mime type and protocol with the bidder
+func supportsVideo(video *openrtb.Video, caps *adapters.CapabilitiesInfo) bool {
+	if len(caps.VideoMimes) > 0 && len(video.Mimes) > 0 {
+		shared := false
+		for _, mime := range video.Mimes {
+			for _, supported := range caps.VideoMimes {
+				if strings.EqualFold(mime, supported) {
+					shared = true
+				}
+			}
+		}
+		if !shared {
+			return false
+		}
+	}
+
+	protocols := video.Protocols
+	if len(protocols) == 0 && video.Protocol != 0 {
+		protocols = []int{video.Protocol}
+	}
+	if len(caps.VideoProtocols) > 0 && len(protocols) > 0 {
+		for _, protocol := range protocols {
+			for _, supported := range caps.VideoProtocols {
+				if protocol == supported {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// requestPublisherID returns the authenticated publisher's ID, or the request's site/app publisher ID
+func requestPublisherID(ctx context.Context, req *openrtb.BidRequest) string {
+	if pub := middleware.PublisherFromContext(ctx); pub != nil {
+		if id, ok := extractPublisherID(pub); ok {
+			return id
+		}
+	}
+	switch {
+	case req.Site != nil && req.Site.Publisher != nil:
+		return req.Site.Publisher.ID
+	case req.App != nil && req.App.Publisher != nil:
+		return req.App.Publisher.ID
+	}
+	return ""
+}
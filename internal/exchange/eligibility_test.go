@@ -0,0 +1,138 @@
+package exchange
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// targetingAdapter records the requests it receives and targets publishers and countries
+type targetingAdapter struct {
+	mockAdapter
+	blockedPublisher string
+	allowedCountry   string
+	mu               sync.Mutex
+	received         []*openrtb.BidRequest
+}
+
+func (a *targetingAdapter) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	a.mu.Lock()
+	a.received = append(a.received, request)
+	a.mu.Unlock()
+	return a.mockAdapter.MakeRequests(request, reqInfo)
+}
+
+func (a *targetingAdapter) CanBidForPublisher(publisherID string) bool {
+	return publisherID != a.blockedPublisher
+}
+
+func (a *targetingAdapter) CanBidForCountry(country string) bool {
+	return a.allowedCountry == "" || strings.EqualFold(country, a.allowedCountry)
+}
+
+func platform(types ...adapters.BidType) *adapters.PlatformInfo {
+	return &adapters.PlatformInfo{MediaTypes: types}
+}
+
+func TestBidderIneligibility(t *testing.T) {
+	siteReq := &openrtb.BidRequest{
+		Site: testSite(),
+		Imp: []openrtb.Imp{
+			{ID: "banner", Banner: &openrtb.Banner{W: 300, H: 250}},
+			{ID: "video", Video: &openrtb.Video{Mimes: []string{"video/mp4"}, Protocols: []int{2, 3}}},
+		},
+	}
+	appReq := &openrtb.BidRequest{App: &openrtb.App{ID: "app"}, Imp: siteReq.Imp}
+	videoOnlyReq := &openrtb.BidRequest{Site: testSite(), Imp: siteReq.Imp[1:]}
+
+	tests := []struct {
+		name      string
+		adapter   adapters.Adapter
+		caps      *adapters.CapabilitiesInfo
+		req       *openrtb.BidRequest
+		publisher string
+		country   string
+		want      string
+	}{
+		{"no capabilities declared", &mockAdapter{}, nil, appReq, "pub1", "US", ""},
+		{"blocked publisher", &targetingAdapter{blockedPublisher: "pub1"}, nil, siteReq, "pub1", "US", "publisher not targeted"},
+		{"other publisher", &targetingAdapter{blockedPublisher: "pub1"}, nil, siteReq, "pub2", "US", ""},
+		{"country outside allow list", &targetingAdapter{allowedCountry: "US"}, nil, siteReq, "pub1", "DE", "country not targeted"},
+		{"unknown country", &targetingAdapter{allowedCountry: "US"}, nil, siteReq, "pub1", "", ""},
+		{"site-only bidder on app", &mockAdapter{}, &adapters.CapabilitiesInfo{Site: platform()}, appReq, "", "", "app not supported"},
+		{"app-only bidder on site", &mockAdapter{}, &adapters.CapabilitiesInfo{App: platform()}, siteReq, "", "", "site not supported"},
+		{"banner bidder on video-only request", &mockAdapter{}, &adapters.CapabilitiesInfo{Site: platform(adapters.BidTypeBanner)}, videoOnlyReq, "", "", "no supported imps"},
+		{"banner bidder on mixed request", &mockAdapter{}, &adapters.CapabilitiesInfo{Site: platform(adapters.BidTypeBanner)}, siteReq, "", "", ""},
+		{"video mimes in common", &mockAdapter{}, &adapters.CapabilitiesInfo{Site: platform(adapters.BidTypeVideo), VideoMimes: []string{"VIDEO/MP4"}}, videoOnlyReq, "", "", ""},
+		{"no video mime in common", &mockAdapter{}, &adapters.CapabilitiesInfo{Site: platform(adapters.BidTypeVideo), VideoMimes: []string{"video/webm"}}, videoOnlyReq, "", "", "no supported imps"},
+		{"no video protocol in common", &mockAdapter{}, &adapters.CapabilitiesInfo{Site: platform(adapters.BidTypeVideo), VideoProtocols: []int{7, 8}}, videoOnlyReq, "", "", "no supported imps"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			awi := adapters.AdapterWithInfo{Adapter: tt.adapter, Info: adapters.BidderInfo{Enabled: true, Capabilities: tt.caps}}
+			if got := bidderIneligibility(awi, tt.req, tt.publisher, tt.country); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRunAuction_BidderEligibility(t *testing.T) {
+	videoSSP := &targetingAdapter{}
+	bannerSSP := &targetingAdapter{}
+	geoSSP := &targetingAdapter{allowedCountry: "DE"}
+	registry := adapters.NewRegistry()
+	registry.Register("videossp", videoSSP, adapters.BidderInfo{Enabled: true, Capabilities: &adapters.CapabilitiesInfo{
+		Site: platform(adapters.BidTypeVideo),
+	}})
+	registry.Register("bannerssp", bannerSSP, adapters.BidderInfo{Enabled: true, Capabilities: &adapters.CapabilitiesInfo{
+		Site: platform(adapters.BidTypeBanner),
+		App:  platform(adapters.BidTypeBanner),
+	}})
+	registry.Register("geossp", geoSSP, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{DefaultTimeout: 500 * time.Millisecond})
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:     "eligibility",
+			Site:   testSite(),
+			Device: &openrtb.Device{Geo: &openrtb.Geo{Country: "US"}},
+			Imp: []openrtb.Imp{
+				{ID: "banner", Banner: &openrtb.Banner{W: 300, H: 250}},
+				{ID: "video", Video: &openrtb.Video{W: 640, H: 360, Mimes: []string{"video/mp4"}}},
+				{ID: "multi", Banner: &openrtb.Banner{W: 300, H: 250}, Video: &openrtb.Video{W: 640, H: 360, Mimes: []string{"video/mp4"}}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(geoSSP.received) != 0 {
+		t.Error("expected the DE-only bidder not to be called for US traffic")
+	}
+	if strings.Join(resp.DebugInfo.ExcludedBidders, ",") != "geossp" {
+		t.Errorf("expected geossp excluded, got %v", resp.DebugInfo.ExcludedBidders)
+	}
+
+	if len(videoSSP.received) != 1 {
+		t.Fatalf("expected one request to the video bidder, got %d", len(videoSSP.received))
+	}
+	videoImps := videoSSP.received[0].Imp
+	if len(videoImps) != 2 || videoImps[0].ID != "video" || videoImps[1].ID != "multi" || videoImps[1].Banner != nil || videoImps[1].Video == nil {
+		t.Errorf("expected only video imps, with the banner of the multi-format imp removed, got %+v", videoImps)
+	}
+
+	if len(bannerSSP.received) != 1 {
+		t.Fatalf("expected one request to the banner bidder, got %d", len(bannerSSP.received))
+	}
+	bannerImps := bannerSSP.received[0].Imp
+	if len(bannerImps) != 2 || bannerImps[0].ID != "banner" || bannerImps[1].ID != "multi" || bannerImps[1].Video != nil {
+		t.Errorf("expected only banner imps, got %+v", bannerImps)
+	}
+}
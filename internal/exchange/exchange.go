@@ -6,17 +6,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html/template"
 	"math"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/floors"
 	"github.com/thenexusengine/tne_springwire/internal/fpd"
 	"github.com/thenexusengine/tne_springwire/internal/middleware"
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
 	"github.com/thenexusengine/tne_springwire/pkg/idr"
 	"github.com/thenexusengine/tne_springwire/pkg/logger"
+	"github.com/thenexusengine/tne_springwire/pkg/native"
 )
 
 // ValidationError represents a client validation error (results in 4xx response)
@@ -37,6 +40,11 @@ func NewValidationError(format string, args ...interface{}) *ValidationError {
 type MetricsRecorder interface {
 	RecordMargin(publisher, bidder, mediaType string, originalPrice, adjustedPrice, platformCut float64)
 	RecordFloorAdjustment(publisher string)
+	RecordBidAdjustment(publisher, bidder, mediaType string, originalPrice, adjustedPrice float64)
+	RecordSeatNonBid(bidder, reason string)
+	RecordBidderThrottled(bidder, limit string)
+	SetBidderCircuitState(bidder, state string)
+	RecordBidderBytes(bidder string, sent, received int)
 }
 
 // Exchange orchestrates the auction process
@@ -49,6 +57,14 @@ type Exchange struct {
 	fpdProcessor    *fpd.Processor
 	eidFilter       *fpd.EIDFilter
 	metrics         MetricsRecorder
+	floorsProcessor *floors.Processor
+	priceEncrypter  PriceEncrypter     // Encrypts ${AUCTION_PRICE} (nil = clear price)
+	bidderHeaders   BidderHeaderSource // Extra headers for bidder requests, e.g. stored credentials (nil = none)
+	lossNotifier    *lossNotifier
+	rtt             *rttTracker        // Observed connection RTT per bidder for tmax
+	limiter         *rateLimiter       // Per-bidder QPS, daily and concurrency caps
+	health          *healthTracker     // Per-bidder circuit breakers (nil = disabled)
+	nativeTemplate  *template.Template // Renders native bids for publishers without a native renderer
 
 	// configMu protects fpdProcessor, eidFilter, and config.FPD
 	// for safe concurrent access during runtime config updates
@@ -114,7 +130,21 @@ type Config struct {
 	CurrencyConv         bool
 	DefaultCurrency      string
 	FPD                  *fpd.Config
-	CloneLimits          *CloneLimits // P3-1: Configurable clone limits
+	Floors               *floors.Config    // Dynamic price floors (nil = defaults)
+	CloneLimits          *CloneLimits      // P3-1: Configurable clone limits
+	LossNotify           *LossNotifyConfig // Server-fired lurl loss notifications (nil = defaults)
+	TMax                 *TMaxConfig       // Outgoing tmax buffers (nil = defaults)
+	RateLimit            *RateLimitConfig  // Traffic shaping for bidder rate limits (nil = defaults)
+	// BidderHealth trips per-bidder circuit breakers on error and timeout rates (nil = defaults)
+	BidderHealth *BidderHealthConfig
+	// VAST validates video bid markup and injects impression and tracking URLs (nil = defaults)
+	VAST *VASTConfig
+	// Native upgrades legacy native requests, validates native bids and renders them (nil = defaults)
+	Native *NativeConfig
+	// BidderTimeouts caps the timeout of individual bidders (e.g. bidders.timeout_ms)
+	BidderTimeouts map[string]time.Duration
+	// MaxConcurrentBidderRequests limits the requests of one bidder call in flight at once (0 = unlimited)
+	MaxConcurrentBidderRequests int
 	// Auction configuration
 	AuctionType    AuctionType
 	PriceIncrement float64 // For second-price auctions (typically 0.01)
@@ -134,10 +164,18 @@ func DefaultConfig() *Config {
 		CurrencyConv:          false,
 		DefaultCurrency:       "USD",
 		FPD:                   fpd.DefaultConfig(),
+		Floors:                floors.DefaultConfig(),
 		CloneLimits:           DefaultCloneLimits(), // P3-1: Configurable clone limits
+		LossNotify:            DefaultLossNotifyConfig(),
+		TMax:                  DefaultTMaxConfig(),
+		VAST:                  DefaultVASTConfig(),
+		Native:                DefaultNativeConfig(),
 		AuctionType:           FirstPriceAuction,
 		PriceIncrement:        0.01,
 		MinBidPrice:           0.0,
+
+		// Multi-request adapters run up to 4 requests at once
+		MaxConcurrentBidderRequests: 4,
 	}
 }
 
@@ -161,6 +199,11 @@ func validateConfig(config *Config) *Config {
 		config.MaxConcurrentBidders = defaults.MaxConcurrentBidders
 	}
 
+	// MaxConcurrentBidderRequests must be non-negative (0 means unlimited)
+	if config.MaxConcurrentBidderRequests < 0 {
+		config.MaxConcurrentBidderRequests = defaults.MaxConcurrentBidderRequests
+	}
+
 	// AuctionType must be valid
 	if config.AuctionType != FirstPriceAuction && config.AuctionType != SecondPriceAuction {
 		config.AuctionType = FirstPriceAuction
@@ -203,6 +246,74 @@ func validateConfig(config *Config) *Config {
 		}
 	}
 
+	// TMax buffers must not be negative; the RTT percentile must be in (0, 1]
+	if config.TMax == nil {
+		config.TMax = DefaultTMaxConfig()
+	} else {
+		defaultTMax := DefaultTMaxConfig()
+		if config.TMax.UpstreamBuffer < 0 {
+			config.TMax.UpstreamBuffer = defaultTMax.UpstreamBuffer
+		}
+		if config.TMax.NetworkBuffer < 0 {
+			config.TMax.NetworkBuffer = defaultTMax.NetworkBuffer
+		}
+		if config.TMax.RTTPercentile <= 0 || config.TMax.RTTPercentile > 1 {
+			config.TMax.RTTPercentile = defaultTMax.RTTPercentile
+		}
+		if config.TMax.RTTSamples <= 0 {
+			config.TMax.RTTSamples = defaultTMax.RTTSamples
+		}
+		if config.TMax.MinTMax <= 0 {
+			config.TMax.MinTMax = defaultTMax.MinTMax
+		}
+	}
+
+	if config.RateLimit == nil {
+		config.RateLimit = DefaultRateLimitConfig()
+	} else {
+		if config.RateLimit.ReserveFraction < 0 || config.RateLimit.ReserveFraction >= 1 {
+			config.RateLimit.ReserveFraction = DefaultRateLimitConfig().ReserveFraction
+		}
+		if config.RateLimit.HighValueFloor < 0 {
+			config.RateLimit.HighValueFloor = 0
+		}
+	}
+
+	if config.BidderHealth == nil {
+		config.BidderHealth = DefaultBidderHealthConfig()
+	} else {
+		defaultHealth := DefaultBidderHealthConfig()
+		if config.BidderHealth.Window <= 0 {
+			config.BidderHealth.Window = defaultHealth.Window
+		}
+		if config.BidderHealth.MinRequests <= 0 || config.BidderHealth.MinRequests > config.BidderHealth.Window {
+			config.BidderHealth.MinRequests = config.BidderHealth.Window
+		}
+		if config.BidderHealth.ErrorRateThreshold <= 0 || config.BidderHealth.ErrorRateThreshold > 1 {
+			config.BidderHealth.ErrorRateThreshold = defaultHealth.ErrorRateThreshold
+		}
+		if config.BidderHealth.FailureThreshold <= 0 {
+			config.BidderHealth.FailureThreshold = defaultHealth.FailureThreshold
+		}
+		if config.BidderHealth.SuccessThreshold <= 0 {
+			config.BidderHealth.SuccessThreshold = defaultHealth.SuccessThreshold
+		}
+		if config.BidderHealth.OpenTimeout <= 0 {
+			config.BidderHealth.OpenTimeout = defaultHealth.OpenTimeout
+		}
+		if config.BidderHealth.ProbeRate < 0 || config.BidderHealth.ProbeRate > 1 {
+			config.BidderHealth.ProbeRate = defaultHealth.ProbeRate
+		}
+	}
+
+	if config.VAST == nil {
+		config.VAST = DefaultVASTConfig()
+	}
+
+	if config.Native == nil {
+		config.Native = DefaultNativeConfig()
+	}
+
 	return config
 }
 
@@ -227,8 +338,21 @@ func New(registry *adapters.Registry, config *Config) *Exchange {
 		config:       config,
 		fpdProcessor: fpd.NewProcessor(fpdConfig),
 		eidFilter:    fpd.NewEIDFilter(fpdConfig),
+		rtt:          newRTTTracker(config.TMax.RTTSamples),
+		limiter:      newRateLimiter(config.RateLimit),
+	}
+
+	if config.BidderHealth.Enabled {
+		ex.health = newHealthTracker(config.BidderHealth, ex.recordBidderCircuitState)
 	}
 
+	tmpl, err := native.ParseTemplate(config.Native.RenderTemplate)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Invalid native render template, using the default")
+		tmpl, _ = native.ParseTemplate("")
+	}
+	ex.nativeTemplate = tmpl
+
 	if config.IDREnabled && config.IDRServiceURL != "" {
 		ex.idrClient = idr.NewClient(config.IDRServiceURL, 50*time.Millisecond, config.IDRAPIKey)
 	}
@@ -237,6 +361,28 @@ func New(registry *adapters.Registry, config *Config) *Exchange {
 		ex.eventRecorder = idr.NewEventRecorder(config.IDRServiceURL, config.EventBufferSize)
 	}
 
+	// Initialize dynamic floors, fetching rule files when a fetch URL is configured
+	floorsConfig := config.Floors
+	if floorsConfig == nil {
+		floorsConfig = floors.DefaultConfig()
+	}
+	if floorsConfig.Currency == "" {
+		floorsConfig.Currency = config.DefaultCurrency
+	}
+	ex.floorsProcessor = floors.NewProcessor(floorsConfig)
+	if floorsConfig.Enabled && floorsConfig.FetchURL != "" {
+		ex.floorsProcessor.SetFetcher(floors.NewFetcher(floorsConfig))
+	}
+
+	// Loss notifications for bids that lose the auction
+	lossConfig := config.LossNotify
+	if lossConfig == nil {
+		lossConfig = DefaultLossNotifyConfig()
+	}
+	if lossConfig.Enabled {
+		ex.lossNotifier = newLossNotifier(lossConfig)
+	}
+
 	return ex
 }
 
@@ -247,8 +393,46 @@ func (e *Exchange) SetMetrics(m MetricsRecorder) {
 	e.metrics = m
 }
 
+// SetDailyCounter shares bidder daily request counts across instances (nil counts per instance)
+func (e *Exchange) SetDailyCounter(c DailyCounter) {
+	e.limiter.setCounter(c)
+}
+
+// BidderHealth returns the circuit breaker state of every bidder called so far
+func (e *Exchange) BidderHealth() []BidderHealthStatus {
+	if e.health == nil {
+		return []BidderHealthStatus{}
+	}
+	return e.health.statuses()
+}
+
+// recordBidderCircuitState logs a bidder's breaker transition and updates its gauge
+// Breakers are kept per core bidder, which is always registered, so its label needs no request context.
+func (e *Exchange) recordBidderCircuitState(bidderCode, from, to string) {
+	logger.Log.Warn().
+		Str("bidder", bidderCode).
+		Str("from", from).
+		Str("to", to).
+		Msg("Bidder circuit breaker state changed")
+	e.configMu.RLock()
+	defer e.configMu.RUnlock()
+	if e.metrics != nil {
+		e.metrics.SetBidderCircuitState(e.metricsLabel(context.Background(), bidderCode), to)
+	}
+}
+
+// SetPriceEncrypter sets the encrypter for the ${AUCTION_PRICE} macro (nil sends the clear price)
+func (e *Exchange) SetPriceEncrypter(pe PriceEncrypter) {
+	e.configMu.Lock()
+	defer e.configMu.Unlock()
+	e.priceEncrypter = pe
+}
+
 // Close shuts down the exchange and flushes pending events
 func (e *Exchange) Close() error {
+	if e.lossNotifier != nil {
+		e.lossNotifier.close()
+	}
 	if e.eventRecorder != nil {
 		return e.eventRecorder.Close()
 	}
@@ -269,6 +453,7 @@ type AuctionResponse struct {
 	BidderResults map[string]*BidderResult
 	IDRResult     *idr.SelectPartnersResponse
 	DebugInfo     *DebugInfo
+	SeatNonBid    []openrtb.SeatNonBid // Set when ext.prebid.returnallbidstatus is true
 }
 
 // BidderResult contains results from a single bidder
@@ -280,6 +465,12 @@ type BidderResult struct {
 	Selected   bool
 	Score      float64
 	TimedOut   bool // P2-2: indicates if the bidder request timed out
+	// PrivacyFiltered indicates the bidder was not called for lack of consent
+	PrivacyFiltered bool
+	// Throttled indicates the bidder was not called because it reached a rate limit
+	Throttled bool
+	// Unhealthy indicates the bidder was not called because its circuit breaker is open
+	Unhealthy bool
 }
 
 // DebugInfo contains debug information
@@ -291,7 +482,8 @@ type DebugInfo struct {
 	SelectedBidders []string
 	ExcludedBidders []string
 	Errors          map[string][]string
-	errorsMu        sync.Mutex // Protects concurrent access to Errors map
+	NonBids         map[string][]openrtb.NonBid // Seat -> bids rejected after the bidder responded
+	errorsMu        sync.Mutex                  // Protects concurrent access to Errors and NonBids maps
 }
 
 // AddError safely adds errors to the Errors map with mutex protection
@@ -308,6 +500,16 @@ func (d *DebugInfo) AppendError(key string, errMsg string) {
 	d.Errors[key] = append(d.Errors[key], errMsg)
 }
 
+// AddNonBid safely records a rejected bid for the seat with mutex protection
+func (d *DebugInfo) AddNonBid(seat string, nonBid openrtb.NonBid) {
+	d.errorsMu.Lock()
+	defer d.errorsMu.Unlock()
+	if d.NonBids == nil {
+		d.NonBids = make(map[string][]openrtb.NonBid)
+	}
+	d.NonBids[seat] = append(d.NonBids[seat], nonBid)
+}
+
 // RequestValidationError represents a bid request validation failure
 type RequestValidationError struct {
 	Field  string
@@ -398,14 +600,24 @@ type BidValidationError struct {
 	ImpID      string
 	Reason     string
 	BidderCode string
+	StatusCode openrtb.NonBidStatusCode // Seat-non-bid code; zero means invalid bid
 }
 
 func (e *BidValidationError) Error() string {
 	return fmt.Sprintf("invalid bid from %s (bid=%s, imp=%s): %s", e.BidderCode, e.BidID, e.ImpID, e.Reason)
 }
 
+// NonBidStatusCode returns the seat-non-bid code for the rejection
+func (e *BidValidationError) NonBidStatusCode() openrtb.NonBidStatusCode {
+	if e.StatusCode == 0 {
+		return openrtb.NonBidInvalidBid
+	}
+	return e.StatusCode
+}
+
 // validateBid checks if a bid meets OpenRTB requirements and exchange rules
-func (e *Exchange) validateBid(bid *openrtb.Bid, bidderCode string, impIDs map[string]float64) *BidValidationError {
+// impDeals may be nil when no impression carries a PMP object
+func (e *Exchange) validateBid(bid *openrtb.Bid, bidderCode string, impIDs map[string]float64, impDeals map[string]*impPMP) *BidValidationError {
 	if bid == nil {
 		return &BidValidationError{BidderCode: bidderCode, Reason: "nil bid"}
 	}
@@ -429,8 +641,7 @@ func (e *Exchange) validateBid(bid *openrtb.Bid, bidderCode string, impIDs map[s
 	}
 
 	// Validate ImpID exists in request
-	floor, validImp := impIDs[bid.ImpID]
-	if !validImp {
+	if _, validImp := impIDs[bid.ImpID]; !validImp {
 		return &BidValidationError{
 			BidID:      bid.ID,
 			ImpID:      bid.ImpID,
@@ -439,6 +650,19 @@ func (e *Exchange) validateBid(bid *openrtb.Bid, bidderCode string, impIDs map[s
 		}
 	}
 
+	// Validate deal against imp.pmp (dealid offered, wseat, private_auction)
+	if reason := impDeals[bid.ImpID].validateDeal(bid, bidderCode); reason != "" {
+		return &BidValidationError{
+			BidID:      bid.ID,
+			ImpID:      bid.ImpID,
+			BidderCode: bidderCode,
+			Reason:     reason,
+		}
+	}
+
+	// Deal bids are held to the deal floor, all others to the imp floor
+	floor := bidFloor(bid, impIDs, impDeals)
+
 	// Check price is non-negative
 	if bid.Price < 0 {
 		return &BidValidationError{
@@ -456,6 +680,7 @@ func (e *Exchange) validateBid(bid *openrtb.Bid, bidderCode string, impIDs map[s
 			ImpID:      bid.ImpID,
 			BidderCode: bidderCode,
 			Reason:     fmt.Sprintf("price %.4f below minimum %.4f", bid.Price, e.config.MinBidPrice),
+			StatusCode: openrtb.NonBidBelowFloor,
 		}
 	}
 
@@ -466,6 +691,7 @@ func (e *Exchange) validateBid(bid *openrtb.Bid, bidderCode string, impIDs map[s
 			ImpID:      bid.ImpID,
 			BidderCode: bidderCode,
 			Reason:     fmt.Sprintf("price %.4f below floor %.4f", bid.Price, floor),
+			StatusCode: openrtb.NonBidBelowFloor,
 		}
 	}
 
@@ -490,17 +716,7 @@ func (e *Exchange) buildImpFloorMap(ctx context.Context, req *openrtb.BidRequest
 	impFloors := make(map[string]float64, len(req.Imp))
 
 	// Get publisher's bid multiplier
-	var multiplier float64 = 1.0
-	var publisherID string
-	if pub := middleware.PublisherFromContext(ctx); pub != nil {
-		if v, ok := extractBidMultiplier(pub); ok && v >= 1.0 && v <= 10.0 {
-			multiplier = v
-		}
-		// Extract publisher ID for metrics
-		if pid, ok := extractPublisherID(pub); ok {
-			publisherID = pid
-		}
-	}
+	multiplier, publisherID := publisherFloorMultiplier(ctx)
 
 	// Build floor map with multiplier applied
 	floorsAdjusted := 0
@@ -536,20 +752,46 @@ func (e *Exchange) buildImpFloorMap(ctx context.Context, req *openrtb.BidRequest
 	return impFloors
 }
 
+// publisherFloorMultiplier returns the publisher's bid multiplier for floors (1.0 if unset
+// or out of range) and the publisher ID for metrics
+func publisherFloorMultiplier(ctx context.Context) (float64, string) {
+	var multiplier float64 = 1.0
+	var publisherID string
+	if pub := middleware.PublisherFromContext(ctx); pub != nil {
+		if v, ok := extractBidMultiplier(pub); ok && v >= 1.0 && v <= 10.0 {
+			multiplier = v
+		}
+		if pid, ok := extractPublisherID(pub); ok {
+			publisherID = pid
+		}
+	}
+	return multiplier, publisherID
+}
+
 // ValidatedBid wraps a bid with validation status
 type ValidatedBid struct {
 	Bid        *adapters.TypedBid
 	BidderCode string
 	DemandType adapters.DemandType // platform (obfuscated) or publisher (transparent)
+	DealTier   int                 // 0 = open market; deal bids rank by tier before price
+	Floor      float64             // Floor the bid was validated against (deal floor for PMP bids)
+	// OriginalPrice is the price the bidder bid, before bid adjustments.
+	// Bid.Bid.Price holds the adjusted price the bid ranks at, and the clearing price after runAuctionLogic.
+	OriginalPrice float64
+	// ClearingPrice is the price the buyer pays, before the publisher bid multiplier.
+	// Used for ${AUCTION_PRICE} macros.
+	ClearingPrice float64
 }
 
 // runAuctionLogic applies auction rules (first-price or second-price) to validated bids
-// Returns bids grouped by impression with prices adjusted according to auction type
-func (e *Exchange) runAuctionLogic(validBids []ValidatedBid, impFloors map[string]float64) map[string][]ValidatedBid {
+// Returns bids grouped by impression with prices set to the clearing price for the auction type.
+// Winning bids are copied before repricing so the adapter's bid keeps its original price.
+func (e *Exchange) runAuctionLogic(validBids []ValidatedBid, impFloors map[string]float64, auctionType AuctionType) map[string][]ValidatedBid {
 	// Group bids by impression
 	bidsByImp := make(map[string][]ValidatedBid)
 	for _, vb := range validBids {
 		impID := vb.Bid.Bid.ImpID
+		vb.ClearingPrice = vb.Bid.Bid.Price
 		bidsByImp[impID] = append(bidsByImp[impID], vb)
 	}
 
@@ -562,42 +804,47 @@ func (e *Exchange) runAuctionLogic(validBids []ValidatedBid, impFloors map[strin
 		// Sort by price descending
 		sortBidsByPrice(bids)
 
-		if e.config.AuctionType == SecondPriceAuction {
-			var winningPrice float64
-			originalBidPrice := bids[0].Bid.Bid.Price
+		if auctionType == SecondPriceAuction {
+			bidPrice := bids[0].Bid.Bid.Price
 
-			if len(bids) > 1 {
-				// Multiple bids: winner pays second highest + increment
-				// Use integer arithmetic to avoid floating-point precision errors (P0-2)
-				secondPrice := bids[1].Bid.Bid.Price
-				winningPrice = roundToCents(secondPrice + e.config.PriceIncrement)
-			} else {
-				// P0-6: Single bid - use floor as "second price" for consistent auction semantics
-				floor := impFloors[impID]
-				if floor > 0 {
-					winningPrice = roundToCents(floor + e.config.PriceIncrement)
-				} else {
-					// No floor - winner pays minimum bid price + increment
-					winningPrice = roundToCents(e.config.MinBidPrice + e.config.PriceIncrement)
-				}
+			// Clearing price is the highest of the winner's floor (deal floor for PMP bids)
+			// and the runner-up in the same deal tier; a higher-priced bid in a lower
+			// tier must not set the clearing price
+			floor := bids[0].Floor
+			if floor == 0 {
+				floor = impFloors[impID]
+			}
+			secondPrice := floor
+			if len(bids) > 1 && bids[1].DealTier == bids[0].DealTier && bids[1].Bid.Bid.Price > secondPrice {
+				secondPrice = bids[1].Bid.Bid.Price
+			}
+			if secondPrice == 0 {
+				// No runner-up or floor - winner pays minimum bid price
+				secondPrice = e.config.MinBidPrice
 			}
 
-			// P2-2: If winning price exceeds bid, reject the bid entirely
+			// P2-2: If the floor exceeds the bid, reject the bid entirely
 			// A bid that can't meet the second-price threshold shouldn't win
-			if winningPrice > originalBidPrice {
+			if secondPrice > bidPrice {
 				// P2-3: Log bid rejection for debugging auction behavior
 				logger.Log.Debug().
 					Str("impID", impID).
 					Str("bidder", bids[0].BidderCode).
-					Float64("bidPrice", originalBidPrice).
-					Float64("clearingPrice", winningPrice).
-					Float64("floor", impFloors[impID]).
-					Float64("increment", e.config.PriceIncrement).
+					Float64("bidPrice", bidPrice).
+					Float64("clearingPrice", secondPrice).
+					Float64("floor", floor).
 					Msg("bid rejected: clearing price exceeds bid in second-price auction")
 				bidsByImp[impID] = nil
 				continue
 			}
-			bids[0].Bid.Bid.Price = winningPrice
+
+			// Winner pays the second price + increment, never more than its own bid
+			// Use integer arithmetic to avoid floating-point precision errors (P0-2)
+			winningPrice := roundToCents(secondPrice + e.config.PriceIncrement)
+			if winningPrice > bidPrice {
+				winningPrice = bidPrice
+			}
+			bids[0] = withClearingPrice(bids[0], winningPrice)
 		}
 		// First-price: winner pays their bid (no adjustment needed)
 
@@ -607,7 +854,24 @@ func (e *Exchange) runAuctionLogic(validBids []ValidatedBid, impFloors map[strin
 	return bidsByImp
 }
 
+// withClearingPrice returns a copy of the validated bid priced at the clearing price
+func withClearingPrice(vb ValidatedBid, price float64) ValidatedBid {
+	vb.Bid = withBidPrice(vb.Bid, price)
+	vb.ClearingPrice = price
+	return vb
+}
+
+// withBidPrice returns a copy of the typed bid at the given price, leaving the original untouched
+func withBidPrice(tb *adapters.TypedBid, price float64) *adapters.TypedBid {
+	typed := *tb
+	bid := *typed.Bid
+	bid.Price = price
+	typed.Bid = &bid
+	return &typed
+}
+
 // sortBidsByPrice sorts bids in descending order by price (highest first)
+// Deal tiers rank ahead of price, so a deal bid beats any open market bid
 // Includes defensive nil checks to prevent panics
 func sortBidsByPrice(bids []ValidatedBid) {
 	// Simple insertion sort - typically small number of bids per impression
@@ -619,7 +883,8 @@ func sortBidsByPrice(bids []ValidatedBid) {
 				bids[j-1].Bid == nil || bids[j-1].Bid.Bid == nil {
 				break
 			}
-			if bids[j].Bid.Bid.Price > bids[j-1].Bid.Bid.Price {
+			if bids[j].DealTier > bids[j-1].DealTier ||
+				(bids[j].DealTier == bids[j-1].DealTier && bids[j].Bid.Bid.Price > bids[j-1].Bid.Bid.Price) {
 				bids[j], bids[j-1] = bids[j-1], bids[j]
 				j--
 			} else {
@@ -638,7 +903,7 @@ func roundToCents(price float64) float64 {
 
 // applyBidMultiplier applies the publisher's bid multiplier to all bids
 // This allows the platform to take a revenue share before returning bids to the publisher
-// Bid prices are DIVIDED by the multiplier
+// Bid prices are DIVIDED by the multiplier; bids are copied, never repriced in place
 // For example: multiplier = 1.05 means publisher gets ~95%, platform keeps ~5% of bid price
 func (e *Exchange) applyBidMultiplier(ctx context.Context, bidsByImp map[string][]ValidatedBid) map[string][]ValidatedBid {
 	// Get publisher from context (set by publisher_auth middleware)
@@ -718,12 +983,13 @@ func (e *Exchange) applyBidMultiplier(ctx context.Context, bidsByImp map[string]
 				if publisherID != "" {
 					e.configMu.RLock()
 					if e.metrics != nil {
-						e.metrics.RecordMargin(publisherID, bids[i].BidderCode, mediaType, originalPrice, adjustedPrice, platformCut)
+						e.metrics.RecordMargin(publisherID, e.metricsLabel(ctx, bids[i].BidderCode), mediaType, originalPrice, adjustedPrice, platformCut)
 					}
 					e.configMu.RUnlock()
 				}
 
-				bids[i].Bid.Bid.Price = adjustedPrice
+				// Reprice a copy so the adapter's bid keeps the bidder's price
+				bids[i].Bid = withBidPrice(bids[i].Bid, adjustedPrice)
 			}
 		}
 	}
@@ -880,6 +1146,27 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 	// Get available bidders from static registry
 	availableBidders := e.registry.ListEnabledBidders()
 
+	// Add aliases declared in ext.prebid.aliases; they reuse a registered core adapter
+	reqAliases, aliasWarnings := e.requestAliases(req.BidRequest)
+	if len(aliasWarnings) > 0 {
+		response.DebugInfo.AddError("aliases", aliasWarnings)
+	}
+	for alias, awi := range reqAliases {
+		if awi.Info.Enabled {
+			availableBidders = append(availableBidders, alias)
+		}
+	}
+	ctx = withRequestAliases(ctx, reqAliases)
+
+	// Drop bidders whose targeting or capabilities rule out the request
+	eligibleBidders, ineligible := e.filterEligibleBidders(ctx, req.BidRequest, availableBidders)
+	for _, code := range availableBidders {
+		if _, ok := ineligible[code]; ok {
+			response.DebugInfo.ExcludedBidders = append(response.DebugInfo.ExcludedBidders, code)
+		}
+	}
+	availableBidders = eligibleBidders
+
 	// Snapshot config-protected fields under lock for consistent view during auction
 	e.configMu.RLock()
 	fpdProcessor := e.fpdProcessor
@@ -935,8 +1222,32 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 		}
 	}
 
+	// Apply dynamic price floors before calling bidders so floors are signalled in the request
+	floorResult := e.applyFloors(ctx, req.BidRequest)
+
+	// Build impression floor map for bid validation (with multiplier applied to floors)
+	impFloors := e.buildImpFloorMap(ctx, req.BidRequest)
+	if floorResult != nil && floorResult.Applied && !floorResult.Enforced {
+		// Floors are signalled only - validate bids against the publisher's own imp.bidfloor
+		multiplier, _ := publisherFloorMultiplier(ctx)
+		for impID := range floorResult.Imps {
+			impFloors[impID] = roundToCents(floorResult.RequestFloors[impID] * multiplier)
+		}
+	}
+
+	// Parse native requests, upgrading legacy ones before bidders see them
+	nativeRequests, nativeUpgrades := e.prepareNativeRequests(req.BidRequest)
+	renderNative := publisherNativeRender(ctx)
+
+	// Requests with publisher deals or high server-side floors may use the bidders' reserved rate limits
+	var dealFloors map[string]float64
+	if pub := middleware.PublisherFromContext(ctx); pub != nil {
+		dealFloors = extractDealFloors(pub)
+	}
+	highValue := e.limiter.isHighValue(req.BidRequest, dealFloors, floorResult)
+
 	// Call bidders in parallel
-	results := e.callBiddersWithFPD(ctx, req.BidRequest, selectedBidders, timeout, bidderFPD)
+	results := e.callBiddersWithFPD(ctx, req.BidRequest, selectedBidders, timeout, bidderFPD, highValue, nativeUpgrades)
 
 	// Extract request context for event recording
 	var country, deviceType, mediaType, adSize, publisherID string
@@ -971,6 +1282,8 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 	if req.BidRequest.Site != nil && req.BidRequest.Site.Publisher != nil {
 		publisherID = req.BidRequest.Site.Publisher.ID
 	}
+	// Floors signalled to bidders, reported per imp with bid responses and non-bids
+	signalledFloors := buildImpFloors(req.BidRequest.Imp)
 
 	// P1-2: Check context deadline before expensive validation work
 	// If we've already timed out, return early with whatever we have
@@ -983,8 +1296,14 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 		// Context still valid, proceed with validation
 	}
 
-	// Build impression floor map for bid validation (with multiplier applied to floors)
-	impFloors := e.buildImpFloorMap(ctx, req.BidRequest)
+	// Build PMP deal rules for deal validation and priority ranking
+	impDeals := e.buildImpDealMap(ctx, req.BidRequest)
+
+	// Resolve bid adjustments (account rules, ext.prebid.bidadjustments, bidadjustmentfactors)
+	bidAdj := e.buildBidAdjustments(ctx, req.BidRequest)
+
+	// Merge request and publisher blocklists (bcat, badv, battr, bapp)
+	blocklist := e.buildBlocklist(ctx, req.BidRequest)
 
 	// Track seen bid IDs for deduplication
 	seenBidIDs := make(map[string]struct{})
@@ -1010,9 +1329,14 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 		if e.eventRecorder != nil {
 			hadBid := len(result.Bids) > 0
 			var bidCPM *float64
+			// The floor is that of the reported bid's imp, or of the first imp (as media type/size) without a bid
+			var floorPrice *float64
 			if hadBid && len(result.Bids) > 0 {
 				cpm := result.Bids[0].Bid.Price
 				bidCPM = &cpm
+				floorPrice = signalledFloors.floor(result.Bids[0].Bid.ImpID)
+			} else if len(req.BidRequest.Imp) > 0 {
+				floorPrice = signalledFloors.floor(req.BidRequest.Imp[0].ID)
 			}
 			hadError := len(result.Errors) > 0
 			var errorMsg string
@@ -1035,7 +1359,7 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 				float64(result.Latency.Milliseconds()),
 				hadBid,
 				bidCPM,
-				nil, // floor price
+				floorPrice,
 				country,
 				deviceType,
 				mediaType,
@@ -1047,6 +1371,11 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 			)
 		}
 
+		// Imps the bidder did not bid on (no bid, timeout, privacy, error)
+		for _, nonBid := range bidderNonBids(result, req.BidRequest.Imp) {
+			e.recordNonBid(ctx, response, req.BidRequest.ID, publisherID, signalledFloors, bidderCode, nonBid)
+		}
+
 		// Validate and deduplicate bids
 		for _, tb := range result.Bids {
 			// Skip nil bids
@@ -1054,8 +1383,28 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 				continue
 			}
 
-			// Validate bid
-			if validErr := e.validateBid(tb.Bid, bidderCode, impFloors); validErr != nil {
+			// Adjust the price before validation so floors apply to the adjusted bid
+			// The adapter's bid keeps the bidder's price; validation and ranking use a repriced copy
+			originalPrice := tb.Bid.Price
+			if price, adjusted := bidAdj.adjustedPrice(tb, bidderCode); adjusted {
+				e.configMu.RLock()
+				if e.metrics != nil {
+					e.metrics.RecordBidAdjustment(bidAdj.publisherID, e.metricsLabel(ctx, bidderCode), string(tb.BidType), originalPrice, price)
+				}
+				e.configMu.RUnlock()
+				tb = withBidPrice(tb, price)
+			}
+
+			// Validate bid, then check the VAST of video bids and the adm of native bids
+			// The checks return a copy of the bid when they change its markup
+			validErr := e.validateBid(tb.Bid, bidderCode, impFloors, impDeals)
+			if validErr == nil {
+				tb, validErr = e.prepareVideoBid(ctx, tb, bidderCode)
+			}
+			if validErr == nil {
+				tb, validErr = e.prepareNativeBid(tb, bidderCode, nativeRequests, renderNative)
+			}
+			if validErr != nil {
 				// P3-1: Log bid validation failures for debugging
 				logger.Log.Debug().
 					Str("bidder", bidderCode).
@@ -1066,6 +1415,26 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 					Msg("bid validation failed")
 				validationErrors = append(validationErrors, validErr) //nolint:staticcheck
 				response.DebugInfo.AppendError(bidderCode, validErr.Error())
+				e.recordNonBid(ctx, response, req.BidRequest.ID, publisherID, signalledFloors, bidderCode,
+					newNonBid(tb.Bid, validErr.NonBidStatusCode(), validErr.Reason))
+				continue
+			}
+
+			// Enforce blocklists on the bid's advertiser, categories, attributes and app
+			if code, reason := blocklist.check(tb.Bid); code != 0 {
+				logger.Log.Debug().
+					Str("bidder", bidderCode).
+					Str("bidID", tb.Bid.ID).
+					Str("impID", tb.Bid.ImpID).
+					Int("statusCode", int(code)).
+					Msg(reason)
+				response.DebugInfo.AppendError(bidderCode, (&BidValidationError{
+					BidID:      tb.Bid.ID,
+					ImpID:      tb.Bid.ImpID,
+					BidderCode: bidderCode,
+					Reason:     reason,
+				}).Error())
+				e.recordNonBid(ctx, response, req.BidRequest.ID, publisherID, signalledFloors, bidderCode, newNonBid(tb.Bid, code, reason))
 				continue
 			}
 
@@ -1076,24 +1445,44 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 					ImpID:      tb.Bid.ImpID,
 					BidderCode: bidderCode,
 					Reason:     "duplicate bid ID",
+					StatusCode: openrtb.NonBidDuplicateBid,
 				}
 				validationErrors = append(validationErrors, dupErr) //nolint:staticcheck
 				response.DebugInfo.AppendError(bidderCode, dupErr.Error())
+				e.recordNonBid(ctx, response, req.BidRequest.ID, publisherID, signalledFloors, bidderCode,
+					newNonBid(tb.Bid, dupErr.NonBidStatusCode(), dupErr.Reason))
 				continue
 			}
 			seenBidIDs[tb.Bid.ID] = struct{}{}
 
 			// Add to valid bids with demand type
 			validBids = append(validBids, ValidatedBid{
-				Bid:        tb,
-				BidderCode: bidderCode,
-				DemandType: e.getDemandType(bidderCode),
+				Bid:           tb,
+				BidderCode:    bidderCode,
+				DemandType:    e.bidderDemandType(ctx, bidderCode),
+				DealTier:      dealTier(tb.Bid, tb.DealPriority, impDeals),
+				Floor:         bidFloor(tb.Bid, impFloors, impDeals),
+				OriginalPrice: originalPrice,
 			})
 		}
 	}
 
 	// Apply auction logic (first-price or second-price)
-	auctionedBids := e.runAuctionLogic(validBids, impFloors)
+	auctionedBids := e.runAuctionLogic(validBids, impFloors, e.resolveAuctionType(ctx, req.BidRequest))
+
+	// Bids dropped by the auction did not clear the floor
+	cleared := make(map[string]struct{}, len(validBids))
+	for _, impBids := range auctionedBids {
+		for _, vb := range impBids {
+			cleared[vb.Bid.Bid.ID] = struct{}{}
+		}
+	}
+	for _, vb := range validBids {
+		if _, ok := cleared[vb.Bid.Bid.ID]; !ok {
+			e.recordNonBid(ctx, response, req.BidRequest.ID, publisherID, signalledFloors, vb.BidderCode,
+				newNonBid(vb.Bid.Bid, openrtb.NonBidBelowFloor, "price below clearing floor"))
+		}
+	}
 
 	// Apply bid multiplier if publisher is configured with one
 	auctionedBids = e.applyBidMultiplier(ctx, auctionedBids)
@@ -1103,6 +1492,12 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 	// - Publisher demand: shown transparently with original bidder codes
 	seatBidMap := make(map[string]*openrtb.SeatBid)
 
+	// Multibid: bidders may return more than one bid per imp (ext.prebid.multibid)
+	multiBid := parseMultiBid(req.BidRequest)
+
+	// Bid IDs included in the response; all other bids lost the auction
+	returned := make(map[string]struct{})
+
 	for _, impBids := range auctionedBids {
 		// Separate platform and publisher bids for this impression
 		var platformBids []ValidatedBid
@@ -1117,16 +1512,9 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 			}
 		}
 
-		// Add highest platform bid to "thenexusengine" seat (obfuscated)
+		// Add highest platform bid(s) to "thenexusengine" seat (obfuscated)
+		// Multibid for platform demand is keyed by the platform seat name, never the real bidder
 		if len(platformBids) > 0 {
-			// Find highest CPM platform bid for this impression
-			highestPlatformBid := platformBids[0]
-			for _, vb := range platformBids[1:] {
-				if vb.Bid.Bid.Price > highestPlatformBid.Bid.Bid.Price {
-					highestPlatformBid = vb
-				}
-			}
-
 			// Get or create the thenexusengine seat
 			nexusSeat, ok := seatBidMap[adapters.PlatformSeatName]
 			if !ok {
@@ -1137,17 +1525,46 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 				seatBidMap[adapters.PlatformSeatName] = nexusSeat
 			}
 
-			// Create obfuscated bid with "thenexusengine" branding in targeting
-			bid := *highestPlatformBid.Bid.Bid
-			bidExt := e.buildBidExtension(highestPlatformBid)
-			if extBytes, err := json.Marshal(bidExt); err == nil {
-				bid.Ext = extBytes
+			mbConfig, hasMultiBid := multiBid[adapters.PlatformSeatName]
+			limit := MultiBidDefault
+			if hasMultiBid {
+				limit = mbConfig.maxBids
+			}
+
+			// Bids are already ranked by deal tier then price, so the first is the winner
+			for i, vb := range platformBids {
+				if i >= limit {
+					break
+				}
+
+				// Create obfuscated bid with "thenexusengine" branding in targeting
+				bid := *vb.Bid.Bid
+				e.applyWinMacros(&bid, req.BidRequest.ID, vb)
+				returned[bid.ID] = struct{}{}
+				var bidExt *openrtb.BidExt
+				if i == 0 {
+					bidExt = e.buildBidExtension(vb)
+				} else {
+					bidExt = e.buildExtraBidExtension(vb, mbConfig.targetBidderCode(i+1))
+				}
+				if extBytes, err := json.Marshal(bidExt); err == nil {
+					bid.Ext = extBytes
+				}
+				nexusSeat.Bid = append(nexusSeat.Bid, bid)
 			}
-			nexusSeat.Bid = append(nexusSeat.Bid, bid)
 		}
 
-		// Add all publisher bids transparently
+		// Add publisher bids transparently
+		// Without a multibid entry every bid is returned; with one, at most maxbids per bidder
+		bidsPerBidder := make(map[string]int)
 		for _, vb := range publisherBids {
+			mbConfig, hasMultiBid := multiBid[vb.BidderCode]
+			n := bidsPerBidder[vb.BidderCode] + 1
+			if hasMultiBid && n > mbConfig.maxBids {
+				continue
+			}
+			bidsPerBidder[vb.BidderCode] = n
+
 			sb, ok := seatBidMap[vb.BidderCode]
 			if !ok {
 				sb = &openrtb.SeatBid{
@@ -1159,7 +1576,14 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 
 			// Create bid copy with Prebid extension for targeting
 			bid := *vb.Bid.Bid
-			bidExt := e.buildBidExtension(vb)
+			e.applyWinMacros(&bid, req.BidRequest.ID, vb)
+			returned[bid.ID] = struct{}{}
+			var bidExt *openrtb.BidExt
+			if n > 1 && hasMultiBid {
+				bidExt = e.buildExtraBidExtension(vb, mbConfig.targetBidderCode(n))
+			} else {
+				bidExt = e.buildBidExtension(vb)
+			}
 			if extBytes, err := json.Marshal(bidExt); err == nil {
 				bid.Ext = extBytes
 			}
@@ -1167,6 +1591,18 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 		}
 	}
 
+	// Fire loss notifications and record non-bids for bids that were not returned
+	for _, impBids := range auctionedBids {
+		e.notifyLosers(req.BidRequest.ID, impBids, returned)
+		for _, vb := range impBids {
+			if _, ok := returned[vb.Bid.Bid.ID]; ok {
+				continue
+			}
+			e.recordNonBid(ctx, response, req.BidRequest.ID, publisherID, signalledFloors, vb.BidderCode,
+				newNonBid(vb.Bid.Bid, lostBidStatus(impBids[0], vb), ""))
+		}
+	}
+
 	// Convert seat bid map to slice
 	allBids := make([]openrtb.SeatBid, 0, len(seatBidMap))
 	for _, sb := range seatBidMap {
@@ -1180,6 +1616,10 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 		Cur:     e.config.DefaultCurrency,
 	}
 
+	if returnAllBidStatus(req.BidRequest) {
+		response.SeatNonBid = e.seatNonBids(ctx, response.DebugInfo.NonBids)
+	}
+
 	response.DebugInfo.TotalLatency = time.Since(startTime)
 
 	// P3-1: Log auction completion with summary stats
@@ -1201,7 +1641,7 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 // callBiddersWithFPD calls all selected bidders in parallel with FPD support
 // P0-1: Uses sync.Map for thread-safe result collection
 // P0-4: Uses semaphore to limit concurrent bidder goroutines
-func (e *Exchange) callBiddersWithFPD(ctx context.Context, req *openrtb.BidRequest, bidders []string, timeout time.Duration, bidderFPD fpd.BidderFPD) map[string]*BidderResult {
+func (e *Exchange) callBiddersWithFPD(ctx context.Context, req *openrtb.BidRequest, bidders []string, timeout time.Duration, bidderFPD fpd.BidderFPD, highValue bool, nativeUpgrades map[string]*openrtb.Native) map[string]*BidderResult {
 	var results sync.Map // P0-1: Thread-safe map for concurrent writes
 	var wg sync.WaitGroup
 
@@ -1213,8 +1653,8 @@ func (e *Exchange) callBiddersWithFPD(ctx context.Context, req *openrtb.BidReque
 	sem := make(chan struct{}, maxConcurrent)
 
 	for _, bidderCode := range bidders {
-		// Try static registry first
-		adapterWithInfo, ok := e.registry.Get(bidderCode)
+		// Registered bidders and server aliases, then aliases declared on the request
+		adapterWithInfo, ok := e.lookupBidder(ctx, bidderCode)
 		if ok {
 			wg.Add(1)
 			go func(code string, awi adapters.AdapterWithInfo) {
@@ -1263,16 +1703,88 @@ func (e *Exchange) callBiddersWithFPD(ctx context.Context, req *openrtb.BidReque
 						Msg("Skipping bidder - no consent for user's geographic location")
 
 					results.Store(code, &BidderResult{
-						BidderCode: code,
-						Errors:     []error{fmt.Errorf("no %s consent for vendor %d", regulation, gvlID)},
+						BidderCode:      code,
+						Errors:          []error{fmt.Errorf("no %s consent for vendor %d", regulation, gvlID)},
+						PrivacyFiltered: true,
 					})
 					return
 				}
 
 				// Clone request and apply bidder-specific FPD
 				bidderReq := e.cloneRequestWithFPD(req, code, bidderFPD)
+				upgradeNativeImps(bidderReq, nativeUpgrades)
+
+				// Imps and media types the bidder does not support are not sent to it
+				pruneUnsupportedImps(bidderReq, awi)
+
+				// Imps with params that fail the bidder's schema are not sent to it
+				paramErrs := filterInvalidParams(bidderReq, code, awi)
+				if len(paramErrs) > 0 && len(bidderReq.Imp) == 0 {
+					results.Store(code, &BidderResult{
+						BidderCode: code,
+						Errors:     paramErrs,
+					})
+					return
+				}
+
+				// Aliases call the core adapter, which reads imp params under the core bidder's code
+				coreCode := e.coreBidder(ctx, code)
+				if coreCode != code {
+					useAliasParams(bidderReq, code, coreCode)
+				}
 
-				result := e.callBidder(ctx, bidderReq, code, awi.Adapter, timeout)
+				// Bidders with an open circuit breaker only get a probe sample of requests
+				// Aliases share their core bidder's breaker, since they call the same endpoint
+				if e.health != nil && !e.health.admit(coreCode) {
+					results.Store(code, unhealthyResult(code, paramErrs))
+					return
+				}
+
+				// Bidders at a QPS, daily or concurrency cap are skipped; the reserve keeps high-value requests
+				// Aliases share their core bidder's limits, so they can't multiply its traffic
+				release, limit := e.limiter.acquire(ctx, coreCode, bidderRateLimits(awi), highValue)
+				if limit != "" {
+					logger.Log.Debug().
+						Str("bidder", code).
+						Str("request_id", req.ID).
+						Str("limit", limit).
+						Msg("Skipping bidder - rate limit reached")
+					e.configMu.RLock()
+					if e.metrics != nil {
+						e.metrics.RecordBidderThrottled(e.metricsLabel(ctx, code), limit)
+					}
+					e.configMu.RUnlock()
+					results.Store(code, &BidderResult{
+						BidderCode: code,
+						Errors:     append(paramErrs, fmt.Errorf("throttled: %s limit reached", limit)),
+						Throttled:  true,
+					})
+					return
+				}
+				defer release()
+
+				// Bidders may have a shorter timeout than the auction; tmax tells them how long they have
+				bidderCtx, bidderTimeout, cancel := e.bidderContext(ctx, code, awi, timeout)
+				defer cancel()
+				bidderCtx = adapters.WithOutboundOptions(bidderCtx, bidderOutbound(awi))
+				bidderReq.TMax = e.outgoingTMax(bidderCtx, code, bidderTimeout)
+
+				var result *BidderResult
+				if e.health != nil {
+					var called bool
+					result, called = e.health.execute(coreCode, func() *BidderResult {
+						return e.callBidder(bidderCtx, bidderReq, code, awi.Adapter, bidderTimeout)
+					})
+					if !called {
+						results.Store(code, unhealthyResult(code, paramErrs))
+						return
+					}
+				} else {
+					result = e.callBidder(bidderCtx, bidderReq, code, awi.Adapter, bidderTimeout)
+				}
+				if len(paramErrs) > 0 {
+					result.Errors = append(paramErrs, result.Errors...)
+				}
 
 				results.Store(code, result) // P0-1: Thread-safe store
 			}(bidderCode, adapterWithInfo)
@@ -1295,6 +1807,15 @@ func (e *Exchange) callBiddersWithFPD(ctx context.Context, req *openrtb.BidReque
 	return finalResults
 }
 
+// unhealthyResult is the result of a bidder skipped by its circuit breaker
+func unhealthyResult(bidderCode string, paramErrs []error) *BidderResult {
+	return &BidderResult{
+		BidderCode: bidderCode,
+		Errors:     append(paramErrs, errors.New("bidder unhealthy: circuit breaker open")),
+		Unhealthy:  true,
+	}
+}
+
 // cloneRequestWithFPD creates a selective copy of the request with bidder-specific FPD applied
 // and enforces USD currency for all bid requests.
 // PERF: Only clones fields that are modified (Cur, Imp, Site/App/User if FPD applies).
@@ -1591,7 +2112,8 @@ func (e *Exchange) callBidder(ctx context.Context, req *openrtb.BidRequest, bidd
 
 	// Build requests
 	extraInfo := &adapters.ExtraRequestInfo{
-		BidderCoreName: bidderCode,
+		BidderCoreName:        e.coreBidder(ctx, bidderCode),
+		PublisherBidderParams: publisherBidderParams(ctx, bidderCode),
 	}
 
 	requests, errs := adapter.MakeRequests(req, extraInfo)
@@ -1620,91 +2142,14 @@ func (e *Exchange) callBidder(ctx context.Context, req *openrtb.BidRequest, bidd
 		return result
 	}
 
-	// Execute requests (could parallelize for multi-request adapters)
+	// Multi-request adapters (e.g. one request per imp) run their requests in parallel
 	allBids := make([]*adapters.TypedBid, 0)
-	for _, reqData := range requests {
-		// Check if context has expired before each request to avoid wasted work
-		select {
-		case <-ctx.Done():
-			result.Errors = append(result.Errors, ctx.Err())
-			result.Latency = time.Since(start)
+	for _, outcome := range e.executeBidderRequests(ctx, req, bidderCode, adapter, requests, timeout, start) {
+		result.Errors = append(result.Errors, outcome.errs...)
+		if outcome.timedOut {
 			result.TimedOut = true // P2-2: mark as timed out
-			return result
-		default:
-			// Context still valid, proceed with request
-		}
-
-		// Handle mock requests (e.g., demo adapter) - use request body as response
-		var resp *adapters.ResponseData
-		if reqData.Method == "MOCK" {
-			resp = &adapters.ResponseData{
-				StatusCode: 200,
-				Body:       reqData.Body,
-				Headers:    reqData.Headers,
-			}
-		} else {
-			var err error
-			resp, err = e.httpClient.Do(ctx, reqData, timeout)
-			if err != nil {
-				// P3-1: Log HTTP request failures with context
-				isTimeout := errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
-				logger.Log.Debug().
-					Str("bidder", bidderCode).
-					Str("uri", reqData.URI).
-					Dur("elapsed", time.Since(start)).
-					Bool("timeout", isTimeout).
-					Err(err).
-					Msg("bidder HTTP request failed")
-				result.Errors = append(result.Errors, err)
-				// P2-2: Check if this was a timeout error
-				if isTimeout {
-					result.TimedOut = true
-				}
-				continue
-			}
-		}
-
-		bidderResp, errs := adapter.MakeBids(req, resp)
-		if len(errs) > 0 {
-			result.Errors = append(result.Errors, errs...)
-		}
-
-		if bidderResp != nil {
-			// P2-5: Validate BidResponse.ID matches BidRequest.ID (OpenRTB 2.x requirement)
-			// Per spec, response ID must echo request ID - reject on mismatch
-			if bidderResp.ResponseID != "" && bidderResp.ResponseID != req.ID {
-				result.Errors = append(result.Errors, fmt.Errorf(
-					"response ID mismatch from %s: expected %q, got %q (bids rejected)",
-					bidderCode, req.ID, bidderResp.ResponseID,
-				))
-				continue // Reject all bids from this response
-			}
-
-			// P1-NEW-3: Normalize and validate response currency
-			// Per OpenRTB 2.5 spec section 7.2, empty currency means USD
-			responseCurrency := bidderResp.Currency
-			if responseCurrency == "" {
-				responseCurrency = "USD" // OpenRTB 2.5 default
-			}
-
-			// P1-NEW-4: Defensive check for exchange currency misconfiguration
-			// Normalize exchange currency to USD if empty to prevent silent validation bypass
-			exchangeCurrency := e.config.DefaultCurrency
-			if exchangeCurrency == "" {
-				exchangeCurrency = "USD" // Fallback if misconfigured
-			}
-
-			if responseCurrency != exchangeCurrency {
-				result.Errors = append(result.Errors, fmt.Errorf(
-					"currency mismatch from %s: expected %s, got %s (bids rejected)",
-					bidderCode, exchangeCurrency, responseCurrency,
-				))
-				// Skip bids with wrong currency - can't safely compare prices
-				continue
-			}
-
-			allBids = append(allBids, bidderResp.Bids...)
 		}
+		allBids = append(allBids, outcome.bids...)
 	}
 
 	result.Bids = allBids
@@ -1741,36 +2186,70 @@ func (e *Exchange) buildBidExtension(vb ValidatedBid) *openrtb.BidExt {
 	}
 
 	// Build targeting keys that Prebid.js expects
-	targeting := map[string]string{
-		"hb_pb":                          priceBucket,
-		"hb_bidder":                      displayBidderCode,
-		"hb_pb_" + displayBidderCode:     priceBucket,
-		"hb_bidder_" + displayBidderCode: displayBidderCode,
-	}
+	targeting := bidderTargeting(bid, priceBucket, displayBidderCode)
+	targeting["hb_pb"] = priceBucket
+	targeting["hb_bidder"] = displayBidderCode
 
 	// Only add hb_size for bids that have valid dimensions
 	// Video/native/audio bids often don't set W/H, and "0x0" breaks Prebid targeting
 	if bid.W > 0 && bid.H > 0 {
-		sizeStr := fmt.Sprintf("%dx%d", bid.W, bid.H)
-		targeting["hb_size"] = sizeStr
-		targeting["hb_size_"+displayBidderCode] = sizeStr
+		targeting["hb_size"] = fmt.Sprintf("%dx%d", bid.W, bid.H)
 	}
 
 	// Add deal ID if present
 	if bid.DealID != "" {
 		targeting["hb_deal"] = bid.DealID
-		targeting["hb_deal_"+displayBidderCode] = bid.DealID
 	}
 
 	return &openrtb.BidExt{
 		Prebid: &openrtb.ExtBidPrebid{
 			Type:      bidType,
 			Targeting: targeting,
+			Video:     bidVideoExt(vb.Bid),
+			Meta: &openrtb.ExtBidPrebidMeta{
+				MediaType: bidType,
+			},
+		},
+	}
+}
+
+// buildExtraBidExtension creates the Prebid extension for a multibid bid after the first
+// Extra bids only get bidder-suffixed targeting under targetBidderCode (e.g. hb_pb_rubicon2),
+// and none at all when the multibid entry has no targetbiddercodeprefix
+func (e *Exchange) buildExtraBidExtension(vb ValidatedBid, targetBidderCode string) *openrtb.BidExt {
+	bidType := string(vb.Bid.BidType)
+
+	ext := &openrtb.BidExt{
+		Prebid: &openrtb.ExtBidPrebid{
+			Type:  bidType,
+			Video: bidVideoExt(vb.Bid),
 			Meta: &openrtb.ExtBidPrebidMeta{
 				MediaType: bidType,
 			},
 		},
 	}
+
+	if targetBidderCode != "" {
+		ext.Prebid.TargetBidderCode = targetBidderCode
+		ext.Prebid.Targeting = bidderTargeting(vb.Bid.Bid, formatPriceBucket(vb.Bid.Bid.Price), targetBidderCode)
+	}
+
+	return ext
+}
+
+// bidderTargeting builds the bidder-suffixed targeting keys (hb_pb_<code>, hb_bidder_<code>, ...)
+func bidderTargeting(bid *openrtb.Bid, priceBucket, code string) map[string]string {
+	targeting := map[string]string{
+		"hb_pb_" + code:     priceBucket,
+		"hb_bidder_" + code: code,
+	}
+	if bid.W > 0 && bid.H > 0 {
+		targeting["hb_size_"+code] = fmt.Sprintf("%dx%d", bid.W, bid.H)
+	}
+	if bid.DealID != "" {
+		targeting["hb_deal_"+code] = bid.DealID
+	}
+	return targeting
 }
 
 // formatPriceBucket formats price using medium granularity (per Prebid.js spec)
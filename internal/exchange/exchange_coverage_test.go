@@ -830,18 +830,20 @@ func TestApplyBidMultiplier_ValidMultiplier(t *testing.T) {
 		BidMultiplier: 2.0,
 	}
 
+	adapterBid := &openrtb.Bid{
+		ID:    "bid1",
+		ImpID: "imp1",
+		Price: 2.00,
+	}
 	bidsByImp := map[string][]ValidatedBid{
 		"imp1": {
 			{
 				Bid: &adapters.TypedBid{
-					Bid: &openrtb.Bid{
-						ID:    "bid1",
-						ImpID: "imp1",
-						Price: 2.00,
-					},
+					Bid:     adapterBid,
 					BidType: adapters.BidTypeBanner,
 				},
-				BidderCode: "appnexus",
+				BidderCode:    "appnexus",
+				OriginalPrice: 2.00,
 			},
 		},
 	}
@@ -853,6 +855,11 @@ func TestApplyBidMultiplier_ValidMultiplier(t *testing.T) {
 	if result["imp1"][0].Bid.Bid.Price != 1.00 {
 		t.Errorf("Expected price 1.00, got %f", result["imp1"][0].Bid.Bid.Price)
 	}
+
+	// The adapter's bid and the bidder's original price are untouched
+	if adapterBid.Price != 2.00 || result["imp1"][0].OriginalPrice != 2.00 {
+		t.Errorf("Expected adapter bid and original price 2.00, got %f / %f", adapterBid.Price, result["imp1"][0].OriginalPrice)
+	}
 }
 
 // TestApplyBidMultiplier_MultipleMediaTypes tests different media types
@@ -959,3 +966,9 @@ type mockMetricsRecorder struct{}
 func (m *mockMetricsRecorder) RecordMargin(publisher, bidder, mediaType string, originalPrice, adjustedPrice, platformCut float64) {
 }
 func (m *mockMetricsRecorder) RecordFloorAdjustment(publisher string) {}
+func (m *mockMetricsRecorder) RecordBidAdjustment(publisher, bidder, mediaType string, originalPrice, adjustedPrice float64) {
+}
+func (m *mockMetricsRecorder) RecordSeatNonBid(bidder, reason string)              {}
+func (m *mockMetricsRecorder) RecordBidderThrottled(bidder, limit string)          {}
+func (m *mockMetricsRecorder) SetBidderCircuitState(bidder, state string)          {}
+func (m *mockMetricsRecorder) RecordBidderBytes(bidder string, sent, received int) {}
@@ -502,7 +502,7 @@ func TestBidValidation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := ex.validateBid(tt.bid, tt.bidderCode, impFloors)
+			err := ex.validateBid(tt.bid, tt.bidderCode, impFloors, nil)
 			if tt.wantErr {
 				if err == nil {
 					t.Error("expected error, got nil")
@@ -1697,7 +1697,7 @@ func TestAuctionLogic_SecondPrice_SingleBidWithFloor(t *testing.T) {
 		{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: "b1", ImpID: "imp1", Price: 5.00}}, BidderCode: "bidder1"},
 	}
 
-	result := ex.runAuctionLogic(validBids, impFloors)
+	result := ex.runAuctionLogic(validBids, impFloors, SecondPriceAuction)
 
 	// With single bid and floor 2.00, winning price should be floor + increment = 2.01
 	if len(result["imp1"]) != 1 {
@@ -1723,7 +1723,7 @@ func TestAuctionLogic_SecondPrice_ClearingExceedsBid(t *testing.T) {
 		{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: "b1", ImpID: "imp1", Price: 4.00}}, BidderCode: "bidder1"},
 	}
 
-	result := ex.runAuctionLogic(validBids, impFloors)
+	result := ex.runAuctionLogic(validBids, impFloors, SecondPriceAuction)
 
 	// Bid should be rejected since clearing price exceeds bid
 	if len(result["imp1"]) != 0 {
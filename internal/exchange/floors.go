@@ -0,0 +1,62 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/thenexusengine/tne_springwire/internal/floors"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// applyFloors resolves price floor rules for the request and sets imp.bidfloor
+// Stored rules come from the publisher in context, whose ID also keys rule file fetches.
+// Rule files are only fetched for publishers known from storage or authentication: the
+// request's site/app.publisher.id is caller-controlled and would let any caller trigger
+// fetches and grow the fetch cache.
+func (e *Exchange) applyFloors(ctx context.Context, req *openrtb.BidRequest) *floors.Result {
+	if e.floorsProcessor == nil {
+		return nil
+	}
+
+	var publisherID string
+	var stored *floors.Rules
+	if pub := middleware.PublisherFromContext(ctx); pub != nil {
+		publisherID, _ = extractPublisherID(pub)
+		if raw := extractPriceFloors(pub); len(raw) > 0 {
+			rules, err := floors.ParseRules(raw)
+			if err != nil {
+				logger.Log.Warn().
+					Err(err).
+					Str("publisher_id", publisherID).
+					Msg("Invalid stored price floors, ignoring")
+			} else {
+				stored = rules
+			}
+		}
+	}
+
+	result := e.floorsProcessor.Apply(req, publisherID, stored)
+	if result != nil && result.Applied {
+		logger.Log.Debug().
+			Str("requestID", req.ID).
+			Str("location", result.Location).
+			Str("modelVersion", result.ModelVersion).
+			Bool("enforced", result.Enforced).
+			Int("imps", len(result.Imps)).
+			Msg("price floors applied")
+	}
+	return result
+}
+
+// extractPriceFloors safely extracts the stored floor rule set from the publisher
+func extractPriceFloors(v interface{}) json.RawMessage {
+	type priceFloorsGetter interface {
+		GetPriceFloors() json.RawMessage
+	}
+	if getter, ok := v.(priceFloorsGetter); ok {
+		return getter.GetPriceFloors()
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/floors"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// mockPublisherWithPriceFloors is a publisher with stored floor rules
+type mockPublisherWithPriceFloors struct {
+	mockPublisherWithMultiplier
+	PriceFloors json.RawMessage
+}
+
+func (m *mockPublisherWithPriceFloors) GetPriceFloors() json.RawMessage {
+	return m.PriceFloors
+}
+
+func floorsAuction(t *testing.T, ctx context.Context, ext string) *AuctionResponse {
+	t.Helper()
+
+	registry := adapters.NewRegistry()
+	registry.Register("rubicon", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "r1", ImpID: "imp1", Price: 1.50, AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{
+		DefaultTimeout: 500 * time.Millisecond,
+		AuctionType:    FirstPriceAuction,
+	})
+
+	resp, err := ex.RunAuction(ctx, &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "floors-req",
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}, BidFloor: 0.50}},
+			Ext:  json.RawMessage(ext),
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp
+}
+
+func countBids(resp *AuctionResponse) int {
+	n := 0
+	for _, sb := range resp.BidResponse.SeatBid {
+		n += len(sb.Bid)
+	}
+	return n
+}
+
+func TestRunAuction_Floors_Enforced(t *testing.T) {
+	resp := floorsAuction(t, context.Background(),
+		`{"prebid":{"floors":{"data":{"modelGroups":[{"schema":{"fields":["mediaType","size"]},"values":{"banner|300x250":2.00}}]}}}}`)
+	if n := countBids(resp); n != 0 {
+		t.Errorf("expected bid below dynamic floor to be rejected, got %d bids", n)
+	}
+}
+
+func TestRunAuction_Floors_NotEnforced(t *testing.T) {
+	// Floors signalled to bidders only: bids are validated against the publisher's own floor
+	resp := floorsAuction(t, context.Background(),
+		`{"prebid":{"floors":{"enforcement":{"enforcePBS":false},"data":{"modelGroups":[{"schema":{"fields":["mediaType"]},"values":{"*":2.00}}]}}}}`)
+	if n := countBids(resp); n != 1 {
+		t.Errorf("expected bid to pass when floors are not enforced, got %d bids", n)
+	}
+}
+
+func TestRunAuction_Floors_StoredRules(t *testing.T) {
+	pub := &mockPublisherWithPriceFloors{
+		mockPublisherWithMultiplier: mockPublisherWithMultiplier{PublisherID: "pub1", BidMultiplier: 1.0},
+		PriceFloors:                 json.RawMessage(`{"data":{"modelGroups":[{"schema":{"fields":["mediaType"]},"values":{"banner":1.00}}]}}`),
+	}
+	resp := floorsAuction(t, middleware.NewContextWithPublisher(context.Background(), pub), "")
+	if n := countBids(resp); n != 1 {
+		t.Errorf("expected bid above stored floor to win, got %d bids", n)
+	}
+
+	pub.PriceFloors = json.RawMessage(`{"data":{"modelGroups":[{"schema":{"fields":["mediaType"]},"values":{"banner":1.75}}]}}`)
+	resp = floorsAuction(t, middleware.NewContextWithPublisher(context.Background(), pub), "")
+	if n := countBids(resp); n != 0 {
+		t.Errorf("expected bid below stored floor to be rejected, got %d bids", n)
+	}
+}
+
+func TestRunAuction_Floors_FetchOnlyForKnownPublishers(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	floorsConfig := floors.DefaultConfig()
+	floorsConfig.FetchURL = server.URL + "/{publisherId}"
+	ex := New(adapters.NewRegistry(), &Config{
+		DefaultTimeout: 500 * time.Millisecond,
+		AuctionType:    FirstPriceAuction,
+		Floors:         floorsConfig,
+	})
+
+	// The request's site.publisher.id is not trusted to key fetches
+	req := &openrtb.BidRequest{
+		ID:   "floors-fetch",
+		Site: &openrtb.Site{ID: "site1", Publisher: &openrtb.Publisher{ID: "unknown-pub"}},
+		Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+	}
+	if result := ex.applyFloors(context.Background(), req); result != nil && result.FetchStatus != floors.FetchStatusNone {
+		t.Errorf("expected no fetch without a known publisher, got status %s", result.FetchStatus)
+	}
+
+	pub := &mockPublisherWithMultiplier{PublisherID: "pub1", BidMultiplier: 1.0}
+	ctx := middleware.NewContextWithPublisher(context.Background(), pub)
+	if result := ex.applyFloors(ctx, req); result == nil || result.FetchStatus != floors.FetchStatusInProgress {
+		t.Errorf("expected a fetch for the known publisher, got %+v", result)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for fetches.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("expected one fetch, got %d", n)
+	}
+}
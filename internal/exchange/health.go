@@ -0,0 +1,220 @@
+package exchange
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+)
+
+// BidderHealthConfig controls the per-bidder circuit breakers
+// A bidder whose error and timeout rate over the last Window calls reaches ErrorRateThreshold
+// trips its breaker after FailureThreshold such calls in a row. An open bidder is skipped; after
+// OpenTimeout a ProbeRate sample of its requests is sent to test whether it has recovered.
+type BidderHealthConfig struct {
+	Enabled            bool
+	Window             int           // Calls the error rate is computed over
+	MinRequests        int           // Calls needed in the window before the rate counts
+	ErrorRateThreshold float64       // Error and timeout rate at which a call counts as a failure (0-1]
+	FailureThreshold   int           // Failing calls in a row that open the breaker
+	SuccessThreshold   int           // Successful probes that close the breaker
+	OpenTimeout        time.Duration // Time an open breaker waits before probing
+	ProbeRate          float64       // Share of requests sent to a bidder that is not healthy [0-1]
+}
+
+// DefaultBidderHealthConfig returns the default bidder health configuration
+func DefaultBidderHealthConfig() *BidderHealthConfig {
+	return &BidderHealthConfig{
+		Enabled:            true,
+		Window:             50,
+		MinRequests:        20,
+		ErrorRateThreshold: 0.5,
+		FailureThreshold:   3,
+		SuccessThreshold:   2,
+		OpenTimeout:        30 * time.Second,
+		ProbeRate:          0.1,
+	}
+}
+
+// BidderHealthStatus is one bidder's circuit breaker state, served at /admin/bidders/health
+type BidderHealthStatus struct {
+	Bidder      string                  `json:"bidder"`
+	State       string                  `json:"state"`
+	ErrorRate   float64                 `json:"error_rate"`
+	WindowCalls int                     `json:"window_calls"`
+	Breaker     idr.CircuitBreakerStats `json:"breaker"`
+}
+
+var (
+	errBidderFailed    = errors.New("bidder call failed")
+	errBidderUnhealthy = errors.New("bidder error rate above threshold")
+)
+
+// bidderCallFailed reports whether a call counts against the bidder's health
+// Timeouts and calls that returned only errors count; a plain no bid does not.
+func bidderCallFailed(result *BidderResult) bool {
+	return result.TimedOut || (len(result.Bids) == 0 && len(result.Errors) > 0)
+}
+
+// bidderHealth is one bidder's breaker and its window of recent call outcomes
+type bidderHealth struct {
+	breaker *idr.CircuitBreaker
+
+	mu       sync.Mutex
+	window   []bool // true = failed
+	next     int
+	calls    int
+	failures int
+}
+
+// observe adds a call outcome to the window and returns the window's error rate
+func (h *bidderHealth) observe(failed bool) (rate float64, calls int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.calls == len(h.window) {
+		if h.window[h.next] {
+			h.failures--
+		}
+	} else {
+		h.calls++
+	}
+	h.window[h.next] = failed
+	if failed {
+		h.failures++
+	}
+	h.next = (h.next + 1) % len(h.window)
+	return float64(h.failures) / float64(h.calls), h.calls
+}
+
+// errorRate returns the window's error rate and size
+func (h *bidderHealth) errorRate() (rate float64, calls int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.calls == 0 {
+		return 0, 0
+	}
+	return float64(h.failures) / float64(h.calls), h.calls
+}
+
+// reset clears the window so a recovered bidder starts afresh
+func (h *bidderHealth) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := range h.window {
+		h.window[i] = false
+	}
+	h.next, h.calls, h.failures = 0, 0, 0
+}
+
+// healthTracker keeps a circuit breaker per bidder, driven by the bidders' error and timeout rates
+type healthTracker struct {
+	config        *BidderHealthConfig
+	onStateChange func(bidder, from, to string)
+
+	mu      sync.Mutex
+	bidders map[string]*bidderHealth
+	sample  func() float64
+}
+
+func newHealthTracker(config *BidderHealthConfig, onStateChange func(bidder, from, to string)) *healthTracker {
+	return &healthTracker{
+		config:        config,
+		onStateChange: onStateChange,
+		bidders:       make(map[string]*bidderHealth),
+		sample:        rand.Float64, // #nosec G404 -- probe sampling, not security sensitive
+	}
+}
+
+// bidder returns the bidder's health, creating a closed breaker on first use
+func (t *healthTracker) bidder(bidderCode string) *bidderHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if h, ok := t.bidders[bidderCode]; ok {
+		return h
+	}
+	h := &bidderHealth{window: make([]bool, t.config.Window)}
+	h.breaker = idr.NewCircuitBreaker(&idr.CircuitBreakerConfig{
+		FailureThreshold: t.config.FailureThreshold,
+		SuccessThreshold: t.config.SuccessThreshold,
+		Timeout:          t.config.OpenTimeout,
+		OnStateChange: func(from, to string) {
+			if t.onStateChange != nil {
+				t.onStateChange(bidderCode, from, to)
+			}
+		},
+	})
+	t.bidders[bidderCode] = h
+	return h
+}
+
+// admit reports whether a request should be sent to the bidder
+// Healthy bidders get every request; others only a ProbeRate sample.
+func (t *healthTracker) admit(bidderCode string) bool {
+	if t.bidder(bidderCode).breaker.State() == idr.StateClosed {
+		return true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sample() < t.config.ProbeRate
+}
+
+// execute calls the bidder through its breaker, returning false when the breaker rejected the call
+// While closed, a call is a breaker failure only when the window's error rate has reached the
+// threshold; while probing, each call's own outcome counts.
+func (t *healthTracker) execute(bidderCode string, call func() *BidderResult) (*BidderResult, bool) {
+	h := t.bidder(bidderCode)
+	probing := h.breaker.State() != idr.StateClosed
+
+	var result *BidderResult
+	err := h.breaker.Execute(func() error {
+		result = call()
+		failed := bidderCallFailed(result)
+		rate, calls := h.observe(failed)
+		if probing {
+			if failed {
+				return errBidderFailed
+			}
+			return nil
+		}
+		if calls >= t.config.MinRequests && rate >= t.config.ErrorRateThreshold {
+			return errBidderUnhealthy
+		}
+		return nil
+	})
+	if result == nil {
+		return nil, false
+	}
+	if err == nil && probing && h.breaker.State() == idr.StateClosed {
+		h.reset()
+	}
+	return result, true
+}
+
+// statuses returns every tracked bidder's health, sorted by bidder code
+func (t *healthTracker) statuses() []BidderHealthStatus {
+	t.mu.Lock()
+	codes := make([]string, 0, len(t.bidders))
+	for code := range t.bidders {
+		codes = append(codes, code)
+	}
+	t.mu.Unlock()
+	sort.Strings(codes)
+
+	statuses := make([]BidderHealthStatus, 0, len(codes))
+	for _, code := range codes {
+		h := t.bidder(code)
+		rate, calls := h.errorRate()
+		stats := h.breaker.Stats()
+		statuses = append(statuses, BidderHealthStatus{
+			Bidder:      code,
+			State:       stats.State,
+			ErrorRate:   rate,
+			WindowCalls: calls,
+			Breaker:     stats,
+		})
+	}
+	return statuses
+}
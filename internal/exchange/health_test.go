@@ -0,0 +1,321 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+)
+
+func testHealthConfig() *BidderHealthConfig {
+	return &BidderHealthConfig{
+		Enabled:            true,
+		Window:             4,
+		MinRequests:        4,
+		ErrorRateThreshold: 0.5,
+		FailureThreshold:   2,
+		SuccessThreshold:   2,
+		OpenTimeout:        20 * time.Millisecond,
+		ProbeRate:          0.5,
+	}
+}
+
+func failedCall() *BidderResult {
+	return &BidderResult{Errors: []error{errors.New("connection refused")}}
+}
+
+func timedOutCall() *BidderResult {
+	return &BidderResult{TimedOut: true, Errors: []error{context.DeadlineExceeded}}
+}
+
+func noBidCall() *BidderResult {
+	return &BidderResult{}
+}
+
+func TestBidderCallFailed(t *testing.T) {
+	bid := []*adapters.TypedBid{{Bid: &openrtb.Bid{ID: "b1"}}}
+	tests := []struct {
+		name   string
+		result *BidderResult
+		want   bool
+	}{
+		{"no bid", noBidCall(), false},
+		{"bids", &BidderResult{Bids: bid}, false},
+		{"bids with errors", &BidderResult{Bids: bid, Errors: []error{errors.New("request 2/2 failed")}}, false},
+		{"errors only", failedCall(), true},
+		{"timeout", timedOutCall(), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bidderCallFailed(tt.result); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHealthTracker_OpensOnErrorRate(t *testing.T) {
+	tracker := newHealthTracker(testHealthConfig(), nil)
+
+	// Below MinRequests the window does not count, however bad it is
+	for i := 0; i < 3; i++ {
+		tracker.execute("rubicon", timedOutCall)
+	}
+	if state := tracker.bidder("rubicon").breaker.State(); state != idr.StateClosed {
+		t.Fatalf("expected closed below MinRequests, got %s", state)
+	}
+
+	// 3 of 4 failed: each call over the threshold counts towards FailureThreshold
+	tracker.execute("rubicon", noBidCall)
+	tracker.execute("rubicon", failedCall)
+	if state := tracker.bidder("rubicon").breaker.State(); state != idr.StateOpen {
+		t.Fatalf("expected open after two calls over the threshold, got %s", state)
+	}
+
+	// Open bidders are skipped without being called
+	called := false
+	if _, ok := tracker.execute("rubicon", func() *BidderResult { called = true; return noBidCall() }); ok || called {
+		t.Error("expected open breaker to reject the call")
+	}
+
+	// Other bidders are unaffected
+	if !tracker.admit("appnexus") {
+		t.Error("expected healthy bidder to be admitted")
+	}
+}
+
+func TestHealthTracker_StaysClosedBelowThreshold(t *testing.T) {
+	tracker := newHealthTracker(testHealthConfig(), nil)
+
+	// One failure in four stays under the 50% threshold
+	for i := 0; i < 20; i++ {
+		if i%4 == 0 {
+			tracker.execute("rubicon", failedCall)
+		} else {
+			tracker.execute("rubicon", noBidCall)
+		}
+	}
+	if state := tracker.bidder("rubicon").breaker.State(); state != idr.StateClosed {
+		t.Errorf("expected closed, got %s", state)
+	}
+	if rate, calls := tracker.bidder("rubicon").errorRate(); rate != 0.25 || calls != 4 {
+		t.Errorf("expected 25%% error rate over 4 calls, got %v over %d", rate, calls)
+	}
+}
+
+func TestHealthTracker_ProbeSampling(t *testing.T) {
+	tracker := newHealthTracker(testHealthConfig(), nil)
+	tracker.bidder("rubicon").breaker.ForceOpen()
+
+	tracker.sample = func() float64 { return 0.7 }
+	if tracker.admit("rubicon") {
+		t.Error("expected request outside the probe sample to be skipped")
+	}
+	tracker.sample = func() float64 { return 0.2 }
+	if !tracker.admit("rubicon") {
+		t.Error("expected request inside the probe sample to be admitted")
+	}
+}
+
+func TestHealthTracker_RecoversAfterProbes(t *testing.T) {
+	var mu sync.Mutex
+	var transitions []string
+	tracker := newHealthTracker(testHealthConfig(), func(bidder, from, to string) {
+		mu.Lock()
+		defer mu.Unlock()
+		transitions = append(transitions, bidder+":"+to)
+	})
+	for i := 0; i < 5; i++ {
+		tracker.execute("rubicon", failedCall)
+	}
+	if state := tracker.bidder("rubicon").breaker.State(); state != idr.StateOpen {
+		t.Fatalf("expected open, got %s", state)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	// A failed probe reopens the breaker
+	if _, ok := tracker.execute("rubicon", failedCall); !ok {
+		t.Fatal("expected probe after the open timeout")
+	}
+	if state := tracker.bidder("rubicon").breaker.State(); state != idr.StateOpen {
+		t.Fatalf("expected failed probe to reopen, got %s", state)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	// Two successful probes close it and start a fresh window
+	tracker.execute("rubicon", noBidCall)
+	tracker.execute("rubicon", noBidCall)
+	if state := tracker.bidder("rubicon").breaker.State(); state != idr.StateClosed {
+		t.Fatalf("expected closed after successful probes, got %s", state)
+	}
+	if _, calls := tracker.bidder("rubicon").errorRate(); calls != 0 {
+		t.Errorf("expected window reset on recovery, got %d calls", calls)
+	}
+
+	tracker.bidder("rubicon").breaker.Close()
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"rubicon:open", "rubicon:half-open", "rubicon:open", "rubicon:half-open", "rubicon:closed"}
+	if len(transitions) != len(want) {
+		t.Errorf("expected transitions %v, got %v", want, transitions)
+	}
+}
+
+func TestHealthTracker_Statuses(t *testing.T) {
+	tracker := newHealthTracker(testHealthConfig(), nil)
+	tracker.execute("rubicon", failedCall)
+	tracker.execute("appnexus", noBidCall)
+
+	statuses := tracker.statuses()
+	if len(statuses) != 2 || statuses[0].Bidder != "appnexus" || statuses[1].Bidder != "rubicon" {
+		t.Fatalf("expected statuses sorted by bidder, got %+v", statuses)
+	}
+	if statuses[1].State != idr.StateClosed || statuses[1].ErrorRate != 1 || statuses[1].WindowCalls != 1 {
+		t.Errorf("unexpected rubicon status: %+v", statuses[1])
+	}
+	if statuses[1].Breaker.TotalRequests != 1 {
+		t.Errorf("expected breaker stats, got %+v", statuses[1].Breaker)
+	}
+}
+
+// circuitMetrics captures circuit breaker state and seat non-bid metrics
+type circuitMetrics struct {
+	nonBidMetrics
+	mu     sync.Mutex
+	states map[string]string
+}
+
+func (m *circuitMetrics) SetBidderCircuitState(bidder, state string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[bidder] = state
+}
+
+func TestRunAuction_UnhealthyBidder(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("rubicon", &mockAdapter{bidsErr: errors.New("bad response")}, adapters.BidderInfo{Enabled: true})
+
+	healthConfig := testHealthConfig()
+	healthConfig.OpenTimeout = time.Minute
+	ex := New(registry, &Config{
+		DefaultTimeout: 500 * time.Millisecond,
+		BidderHealth:   healthConfig,
+	})
+	metrics := &circuitMetrics{nonBidMetrics: nonBidMetrics{nonBids: make(map[string]int)}, states: make(map[string]string)}
+	ex.SetMetrics(metrics)
+
+	run := func() *AuctionResponse {
+		resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+			BidRequest: &openrtb.BidRequest{
+				ID:   "health-auction",
+				Site: testSite(),
+				Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+				Ext:  json.RawMessage(`{"prebid":{"returnallbidstatus":true}}`),
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp
+	}
+
+	for i := 0; i < 5; i++ {
+		if nonBids := run().DebugInfo.NonBids["rubicon"]; len(nonBids) != 1 || nonBids[0].StatusCode != openrtb.NonBidBidderError {
+			t.Fatalf("expected bidder error non-bid on call %d, got %+v", i+1, nonBids)
+		}
+	}
+
+	ex.health.sample = func() float64 { return 1 }
+	resp := run()
+	if nonBids := resp.DebugInfo.NonBids["rubicon"]; len(nonBids) != 1 || nonBids[0].StatusCode != openrtb.NonBidBidderUnhealthy {
+		t.Errorf("expected bidder unhealthy non-bid, got %+v", nonBids)
+	}
+	if !resp.BidderResults["rubicon"].Unhealthy {
+		t.Error("expected rubicon result to be marked unhealthy")
+	}
+
+	health := ex.BidderHealth()
+	if len(health) != 1 || health[0].State != idr.StateOpen {
+		t.Errorf("expected rubicon open in bidder health, got %+v", health)
+	}
+
+	ex.health.bidder("rubicon").breaker.Close()
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if metrics.states["rubicon"] != idr.StateOpen {
+		t.Errorf("expected open circuit state metric, got %v", metrics.states)
+	}
+}
+
+func TestRunAuction_UnhealthyAliasSharesCoreBreaker(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("rubicon", &mockAdapter{bidsErr: errors.New("bad response")}, adapters.BidderInfo{Enabled: true, MetricsLabel: "rp"})
+
+	healthConfig := testHealthConfig()
+	healthConfig.OpenTimeout = time.Minute
+	ex := New(registry, &Config{
+		DefaultTimeout: 500 * time.Millisecond,
+		BidderHealth:   healthConfig,
+	})
+	metrics := &circuitMetrics{nonBidMetrics: nonBidMetrics{nonBids: make(map[string]int)}, states: make(map[string]string)}
+	ex.SetMetrics(metrics)
+
+	run := func(bidder string) *AuctionResponse {
+		resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+			BidRequest: &openrtb.BidRequest{
+				ID:   "health-alias-auction",
+				Site: testSite(),
+				Imp: []openrtb.Imp{{
+					ID:     "imp1",
+					Banner: &openrtb.Banner{W: 300, H: 250},
+					Ext:    json.RawMessage(`{"prebid":{"bidder":{"` + bidder + `":{}}}}`),
+				}},
+				Ext: json.RawMessage(`{"prebid":{"returnallbidstatus":true,"aliases":{"rubi2":"rubicon"}}}`),
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp
+	}
+
+	// Failures through the alias trip the core bidder's breaker
+	for i := 0; i < 5; i++ {
+		run("rubi2")
+	}
+
+	ex.health.sample = func() float64 { return 1 }
+	if nonBids := run("rubicon").DebugInfo.NonBids["rubicon"]; len(nonBids) != 1 || nonBids[0].StatusCode != openrtb.NonBidBidderUnhealthy {
+		t.Errorf("expected rubicon unhealthy after alias failures, got %+v", nonBids)
+	}
+
+	health := ex.BidderHealth()
+	if len(health) != 1 || health[0].Bidder != "rubicon" {
+		t.Errorf("expected a single breaker for the core bidder, got %+v", health)
+	}
+
+	ex.health.bidder("rubicon").breaker.Close()
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if metrics.states["rp"] != idr.StateOpen {
+		t.Errorf("expected circuit state metric under the bidder's metrics label, got %v", metrics.states)
+	}
+}
+
+func TestBidderHealth_Disabled(t *testing.T) {
+	ex := New(adapters.NewRegistry(), &Config{BidderHealth: &BidderHealthConfig{Enabled: false}})
+	if ex.health != nil {
+		t.Error("expected no health tracking when disabled")
+	}
+	if health := ex.BidderHealth(); health == nil || len(health) != 0 {
+		t.Errorf("expected empty bidder health, got %v", health)
+	}
+}
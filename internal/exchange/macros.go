@@ -0,0 +1,149 @@
+package exchange
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// OpenRTB substitution macros expanded in nurl, burl, lurl and adm
+const (
+	MacroAuctionID       = "${AUCTION_ID}"
+	MacroAuctionBidID    = "${AUCTION_BID_ID}"
+	MacroAuctionImpID    = "${AUCTION_IMP_ID}"
+	MacroAuctionSeatID   = "${AUCTION_SEAT_ID}"
+	MacroAuctionAdID     = "${AUCTION_AD_ID}"
+	MacroAuctionPrice    = "${AUCTION_PRICE}"
+	MacroAuctionCurrency = "${AUCTION_CURRENCY}"
+	MacroAuctionLoss     = "${AUCTION_LOSS}"
+)
+
+// OpenRTB loss reason codes sent in ${AUCTION_LOSS}
+const (
+	LossReasonLostToHigherBid = 102
+	LossReasonLostToDeal      = 103
+)
+
+// macroPrefix is shared by all auction macros, used to skip strings without macros
+const macroPrefix = "${AUCTION_"
+
+// auctionMacros holds the values substituted for one bid
+type auctionMacros struct {
+	auctionID string
+	bidID     string
+	impID     string
+	seatID    string
+	adID      string
+	price     string
+	currency  string
+	loss      string
+}
+
+// expand replaces auction macros in s
+func (m *auctionMacros) expand(s string) string {
+	if !strings.Contains(s, macroPrefix) {
+		return s
+	}
+	return strings.NewReplacer(
+		MacroAuctionID, m.auctionID,
+		MacroAuctionBidID, m.bidID,
+		MacroAuctionImpID, m.impID,
+		MacroAuctionSeatID, m.seatID,
+		MacroAuctionAdID, m.adID,
+		MacroAuctionPrice, m.price,
+		MacroAuctionCurrency, m.currency,
+		MacroAuctionLoss, m.loss,
+	).Replace(s)
+}
+
+// newAuctionMacros builds the macro values for a bid
+// price is the clearing price the buyer pays, encrypted when a price encrypter is configured
+func (e *Exchange) newAuctionMacros(auctionID string, vb ValidatedBid, price float64) *auctionMacros {
+	bid := vb.Bid.Bid
+
+	// Platform demand is shown under the platform seat, so macros must not reveal the bidder
+	seatID := vb.BidderCode
+	if vb.DemandType != adapters.DemandTypePublisher {
+		seatID = adapters.PlatformSeatName
+	}
+
+	currency := e.config.DefaultCurrency
+	if currency == "" {
+		currency = "USD" // OpenRTB default
+	}
+
+	return &auctionMacros{
+		auctionID: auctionID,
+		bidID:     bid.ID,
+		impID:     bid.ImpID,
+		seatID:    seatID,
+		adID:      bid.AdID,
+		price:     e.formatMacroPrice(price),
+		currency:  currency,
+	}
+}
+
+// formatMacroPrice formats the ${AUCTION_PRICE} value, encrypting it when configured
+func (e *Exchange) formatMacroPrice(price float64) string {
+	e.configMu.RLock()
+	encrypter := e.priceEncrypter
+	e.configMu.RUnlock()
+
+	if encrypter != nil {
+		encrypted, err := encrypter.Encrypt(price)
+		if err == nil {
+			return encrypted
+		}
+		logger.Log.Warn().Err(err).Msg("price encryption failed, sending clear price")
+	}
+	return strconv.FormatFloat(price, 'f', -1, 64)
+}
+
+// applyWinMacros expands macros in the nurl, burl and adm of a bid returned in the response
+// The lurl is left for the receiver since the bid may still lose downstream
+func (e *Exchange) applyWinMacros(bid *openrtb.Bid, auctionID string, vb ValidatedBid) {
+	if !strings.Contains(bid.NURL, macroPrefix) && !strings.Contains(bid.BURL, macroPrefix) &&
+		!strings.Contains(bid.AdM, macroPrefix) {
+		return
+	}
+	macros := e.newAuctionMacros(auctionID, vb, vb.billingPrice())
+	bid.NURL = macros.expand(bid.NURL)
+	bid.BURL = macros.expand(bid.BURL)
+	bid.AdM = macros.expand(bid.AdM)
+}
+
+// billingPrice is the ${AUCTION_PRICE} of a returned bid: the clearing price, capped at the
+// bidder's own price so bid adjustments never bill a buyer more than it bid
+func (vb ValidatedBid) billingPrice() float64 {
+	return math.Min(vb.ClearingPrice, vb.OriginalPrice)
+}
+
+// notifyLosers fires the lurl of bids that were not returned in the response
+// winner is the top ranked bid for the impression; its clearing price is sent as ${AUCTION_PRICE}
+func (e *Exchange) notifyLosers(auctionID string, bids []ValidatedBid, returned map[string]struct{}) {
+	if e.lossNotifier == nil || len(bids) == 0 {
+		return
+	}
+	winner := bids[0]
+	for _, vb := range bids {
+		if vb.Bid == nil || vb.Bid.Bid == nil || vb.Bid.Bid.LURL == "" {
+			continue
+		}
+		if _, ok := returned[vb.Bid.Bid.ID]; ok {
+			continue
+		}
+
+		reason := LossReasonLostToHigherBid
+		if winner.DealTier > vb.DealTier {
+			reason = LossReasonLostToDeal
+		}
+
+		macros := e.newAuctionMacros(auctionID, vb, winner.ClearingPrice)
+		macros.loss = strconv.Itoa(reason)
+		e.lossNotifier.notify(macros.expand(vb.Bid.Bid.LURL))
+	}
+}
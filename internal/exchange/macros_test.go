@@ -0,0 +1,274 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+func TestAuctionMacros_Expand(t *testing.T) {
+	m := &auctionMacros{
+		auctionID: "auc-1",
+		bidID:     "bid-1",
+		impID:     "imp-1",
+		seatID:    "seat-1",
+		adID:      "ad-1",
+		price:     "3.01",
+		currency:  "USD",
+		loss:      "102",
+	}
+
+	got := m.expand("https://dsp.example/win?a=${AUCTION_ID}&b=${AUCTION_BID_ID}&i=${AUCTION_IMP_ID}" +
+		"&s=${AUCTION_SEAT_ID}&ad=${AUCTION_AD_ID}&p=${AUCTION_PRICE}&c=${AUCTION_CURRENCY}&l=${AUCTION_LOSS}&x=${OTHER}")
+	want := "https://dsp.example/win?a=auc-1&b=bid-1&i=imp-1&s=seat-1&ad=ad-1&p=3.01&c=USD&l=102&x=${OTHER}"
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	if plain := "https://dsp.example/win"; m.expand(plain) != plain {
+		t.Error("expected string without macros to be unchanged")
+	}
+}
+
+// lurlRecorder is a stand-in DSP endpoint collecting loss notifications
+type lurlRecorder struct {
+	mu      sync.Mutex
+	queries []string
+	server  *httptest.Server
+}
+
+func newLURLRecorder() *lurlRecorder {
+	r := &lurlRecorder{}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		r.queries = append(r.queries, req.URL.RawQuery)
+		r.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	return r
+}
+
+// allowLoopbackLURLs lets a notifier reach httptest servers, which listen on loopback
+func allowLoopbackLURLs(n *lossNotifier) {
+	n.client = &http.Client{Timeout: n.timeout}
+}
+
+func (r *lurlRecorder) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.queries...)
+}
+
+func macroAuction(t *testing.T, ex *Exchange) *AuctionResponse {
+	t.Helper()
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "auc-1",
+			AT:   2,
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}, BidFloor: 1.00}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp
+}
+
+func TestRunAuction_Macros(t *testing.T) {
+	lurls := newLURLRecorder()
+	defer lurls.server.Close()
+
+	registry := adapters.NewRegistry()
+	registry.Register("winner", &mockAdapter{bids: []*adapters.TypedBid{{
+		Bid: &openrtb.Bid{
+			ID: "w1", ImpID: "imp1", Price: 5.00, W: 300, H: 250, AdID: "ad-9",
+			AdM:  `<img src="https://dsp.example/imp?p=${AUCTION_PRICE}&s=${AUCTION_SEAT_ID}">`,
+			NURL: "https://dsp.example/win?id=${AUCTION_ID}&bid=${AUCTION_BID_ID}&imp=${AUCTION_IMP_ID}&p=${AUCTION_PRICE}&c=${AUCTION_CURRENCY}",
+			BURL: "https://dsp.example/bill?p=${AUCTION_PRICE}&ad=${AUCTION_AD_ID}",
+			LURL: lurls.server.URL + "/loss?l=${AUCTION_LOSS}",
+		},
+		BidType: adapters.BidTypeBanner,
+	}}}, adapters.BidderInfo{Enabled: true})
+	registry.Register("loser", &mockAdapter{bids: []*adapters.TypedBid{{
+		Bid: &openrtb.Bid{
+			ID: "l1", ImpID: "imp1", Price: 3.00, AdM: "ad", W: 300, H: 250,
+			LURL: lurls.server.URL + "/loss?id=${AUCTION_ID}&bid=${AUCTION_BID_ID}&l=${AUCTION_LOSS}&p=${AUCTION_PRICE}",
+		},
+		BidType: adapters.BidTypeBanner,
+	}}}, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{
+		DefaultTimeout: 500 * time.Millisecond,
+		AuctionType:    FirstPriceAuction,
+		PriceIncrement: 0.01,
+	})
+	allowLoopbackLURLs(ex.lossNotifier)
+
+	resp := macroAuction(t, ex)
+	if len(resp.BidResponse.SeatBid) != 1 || len(resp.BidResponse.SeatBid[0].Bid) != 1 {
+		t.Fatalf("expected a single platform bid, got %+v", resp.BidResponse.SeatBid)
+	}
+	bid := resp.BidResponse.SeatBid[0].Bid[0]
+
+	if want := "https://dsp.example/win?id=auc-1&bid=w1&imp=imp1&p=3.01&c=USD"; bid.NURL != want {
+		t.Errorf("expected nurl %s, got %s", want, bid.NURL)
+	}
+	if want := "https://dsp.example/bill?p=3.01&ad=ad-9"; bid.BURL != want {
+		t.Errorf("expected burl %s, got %s", want, bid.BURL)
+	}
+	// Platform demand is shown under the platform seat
+	if want := `<img src="https://dsp.example/imp?p=3.01&s=thenexusengine">`; bid.AdM != want {
+		t.Errorf("expected adm %s, got %s", want, bid.AdM)
+	}
+	if !strings.Contains(bid.LURL, "${AUCTION_LOSS}") {
+		t.Errorf("expected returned bid lurl to be left unexpanded, got %s", bid.LURL)
+	}
+
+	// Close flushes the queued loss notification for the losing bid
+	ex.Close()
+	queries := lurls.received()
+	if len(queries) != 1 || queries[0] != "id=auc-1&bid=l1&l=102&p=3.01" {
+		t.Errorf("expected one loss notification for l1, got %v", queries)
+	}
+}
+
+func TestRunAuction_Macros_EncryptedPrice(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("winner", &mockAdapter{bids: []*adapters.TypedBid{{
+		Bid:     &openrtb.Bid{ID: "w1", ImpID: "imp1", Price: 5.00, W: 300, H: 250, AdM: "ad", BURL: "https://dsp.example/bill?p=${AUCTION_PRICE}"},
+		BidType: adapters.BidTypeBanner,
+	}}}, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{DefaultTimeout: 500 * time.Millisecond, PriceIncrement: 0.01})
+	defer ex.Close()
+	pe, _ := NewHMACPriceEncrypter([]byte("encryption-key"), []byte("integrity-key"))
+	ex.SetPriceEncrypter(pe)
+
+	bid := macroAuction(t, ex).BidResponse.SeatBid[0].Bid[0]
+	token := strings.TrimPrefix(bid.BURL, "https://dsp.example/bill?p=")
+	price, err := pe.Decrypt(token)
+	if err != nil {
+		t.Fatalf("failed to decrypt burl price %q: %v", token, err)
+	}
+	if price != 1.01 { // single bid clears at floor + increment
+		t.Errorf("expected encrypted clearing price 1.01, got %.2f", price)
+	}
+}
+
+func TestRunAuction_Macros_BidAdjustmentsCapPrice(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("winner", &mockAdapter{bids: []*adapters.TypedBid{{
+		Bid: &openrtb.Bid{
+			ID: "w1", ImpID: "imp1", Price: 2.00, W: 300, H: 250, AdM: "ad",
+			BURL: "https://dsp.example/bill?p=${AUCTION_PRICE}",
+		},
+		BidType: adapters.BidTypeBanner,
+	}}}, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{DefaultTimeout: 500 * time.Millisecond, AuctionType: FirstPriceAuction})
+	defer ex.Close()
+
+	// The winner's 2.00 ranks at 5.00 after the adjustment, but the buyer is never billed above its bid
+	resp, err := ex.RunAuction(requestAdjustmentsContext(), &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "auc-1",
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}, BidFloor: 1.00}},
+			Ext:  json.RawMessage(`{"prebid":{"bidadjustments":{"mediatype":{"*":{"winner":{"*":[{"adjtype":"static","value":5.00}]}}}}}}`),
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bid := resp.BidResponse.SeatBid[0].Bid[0]
+	if bid.Price != 5.00 {
+		t.Errorf("expected the bid to rank at the adjusted price 5.00, got %.2f", bid.Price)
+	}
+	if want := "https://dsp.example/bill?p=2"; bid.BURL != want {
+		t.Errorf("expected burl %s, got %s", want, bid.BURL)
+	}
+}
+
+func TestLossNotifier_Bounded(t *testing.T) {
+	release := make(chan struct{})
+	var hits int
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		mu.Lock()
+		hits++
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	n := newLossNotifier(&LossNotifyConfig{Enabled: true, Workers: 1, QueueSize: 2, Timeout: time.Second})
+	allowLoopbackLURLs(n)
+
+	if n.notify("ftp://example.com/loss") {
+		t.Error("expected non-HTTP URL to be rejected")
+	}
+
+	// One request in flight plus two queued; the rest are dropped without blocking
+	accepted := 0
+	for i := 0; i < 10; i++ {
+		if n.notify(server.URL) {
+			accepted++
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if accepted < 2 || accepted > 3 {
+		t.Errorf("expected 2-3 accepted notifications, got %d", accepted)
+	}
+
+	close(release)
+	n.close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if hits != accepted {
+		t.Errorf("expected %d notifications sent, got %d", accepted, hits)
+	}
+	if n.notify(server.URL) {
+		t.Error("expected notify after close to be rejected")
+	}
+}
+
+func TestLossNotifier_RefusesNonPublicHosts(t *testing.T) {
+	lurls := newLURLRecorder()
+	defer lurls.server.Close()
+
+	n := newLossNotifier(&LossNotifyConfig{Enabled: true, Workers: 1, QueueSize: 2, Timeout: time.Second})
+	if !n.notify(lurls.server.URL + "/loss") {
+		t.Fatal("expected the lurl to be queued")
+	}
+	n.close()
+	if queries := lurls.received(); len(queries) != 0 {
+		t.Errorf("expected the loopback lurl to be refused, got %v", queries)
+	}
+
+	for addr, public := range map[string]bool{
+		"127.0.0.1":       false,
+		"::1":             false,
+		"10.1.2.3":        false,
+		"192.168.0.10":    false,
+		"169.254.169.254": false,
+		"fe80::1":         false,
+		"0.0.0.0":         false,
+		"93.184.216.34":   true,
+		"2606:4700::1111": true,
+	} {
+		if got := isPublicIP(net.ParseIP(addr)); got != public {
+			t.Errorf("%s: expected public=%v, got %v", addr, public, got)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package exchange
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+const (
+	// MultiBidDefault is the number of bids returned per bidder per imp without multibid
+	MultiBidDefault = 1
+	// MultiBidMax is the most bids a bidder may return per imp (Prebid limit)
+	MultiBidMax = 9
+)
+
+// multiBidConfig is the resolved ext.prebid.multibid setting for one bidder
+type multiBidConfig struct {
+	maxBids int
+	prefix  string // targetbiddercodeprefix; empty means extra bids get no targeting
+}
+
+// targetBidderCode returns the targeting code for the nth (1-based) bid
+// The first bid keeps the bidder's own code; extra bids use prefix+n (e.g. rubicon2)
+func (c multiBidConfig) targetBidderCode(n int) string {
+	if c.prefix == "" {
+		return ""
+	}
+	return c.prefix + strconv.Itoa(n)
+}
+
+// parseMultiBid reads ext.prebid.multibid from the request, keyed by bidder code
+// Entries with "bidder" take precedence over entries listing the same bidder in "bidders"
+func parseMultiBid(req *openrtb.BidRequest) map[string]multiBidConfig {
+	if req == nil || len(req.Ext) == 0 {
+		return nil
+	}
+
+	var ext openrtb.BidRequestExt
+	if err := json.Unmarshal(req.Ext, &ext); err != nil || ext.Prebid == nil || len(ext.Prebid.MultiBid) == 0 {
+		return nil
+	}
+
+	configs := make(map[string]multiBidConfig)
+	for _, mb := range ext.Prebid.MultiBid {
+		if mb.MaxBids == nil {
+			logger.Log.Debug().Str("bidder", mb.Bidder).Msg("multibid entry missing maxbids, ignoring")
+			continue
+		}
+		maxBids := *mb.MaxBids
+		if maxBids < MultiBidDefault {
+			maxBids = MultiBidDefault
+		}
+		if maxBids > MultiBidMax {
+			maxBids = MultiBidMax
+		}
+
+		if mb.Bidder != "" {
+			configs[mb.Bidder] = multiBidConfig{maxBids: maxBids, prefix: mb.TargetBidderCodePrefix}
+			continue
+		}
+
+		// Shared entries never carry a targeting prefix
+		for _, bidder := range mb.Bidders {
+			if _, exists := configs[bidder]; !exists && bidder != "" {
+				configs[bidder] = multiBidConfig{maxBids: maxBids}
+			}
+		}
+	}
+
+	return configs
+}
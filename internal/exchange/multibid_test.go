@@ -0,0 +1,191 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+func TestParseMultiBid(t *testing.T) {
+	req := &openrtb.BidRequest{Ext: json.RawMessage(`{"prebid":{"multibid":[
+		{"bidder":"rubicon","maxbids":3,"targetbiddercodeprefix":"rubi"},
+		{"bidders":["rubicon","appnexus","pubmatic"],"maxbids":2,"targetbiddercodeprefix":"ignored"},
+		{"bidder":"pubmatic","maxbids":20,"targetbiddercodeprefix":"pm"},
+		{"bidder":"openx","maxbids":0},
+		{"bidder":"ix"}
+	]}}`)}
+
+	configs := parseMultiBid(req)
+
+	tests := []struct {
+		bidder  string
+		maxBids int
+		prefix  string
+	}{
+		{"rubicon", 3, "rubi"},          // bidder entry wins over bidders list
+		{"appnexus", 2, ""},             // bidders list never carries a prefix
+		{"pubmatic", MultiBidMax, "pm"}, // bidder entry overrides earlier list, maxbids clamped
+		{"openx", MultiBidDefault, ""},  // maxbids raised to the minimum
+	}
+	for _, tt := range tests {
+		cfg, ok := configs[tt.bidder]
+		if !ok {
+			t.Errorf("%s: expected multibid config", tt.bidder)
+			continue
+		}
+		if cfg.maxBids != tt.maxBids || cfg.prefix != tt.prefix {
+			t.Errorf("%s: expected maxbids=%d prefix=%q, got maxbids=%d prefix=%q",
+				tt.bidder, tt.maxBids, tt.prefix, cfg.maxBids, cfg.prefix)
+		}
+	}
+
+	if _, ok := configs["ix"]; ok {
+		t.Error("expected entry without maxbids to be ignored")
+	}
+}
+
+func TestParseMultiBid_NoConfig(t *testing.T) {
+	for _, ext := range []string{"", `{}`, `{"prebid":{}}`, `{invalid`} {
+		if configs := parseMultiBid(&openrtb.BidRequest{Ext: json.RawMessage(ext)}); len(configs) != 0 {
+			t.Errorf("ext %q: expected no multibid config, got %v", ext, configs)
+		}
+	}
+	if parseMultiBid(nil) != nil {
+		t.Error("expected nil for nil request")
+	}
+}
+
+func TestMultiBidConfig_TargetBidderCode(t *testing.T) {
+	cfg := multiBidConfig{maxBids: 3, prefix: "rubicon"}
+	if code := cfg.targetBidderCode(2); code != "rubicon2" {
+		t.Errorf("expected rubicon2, got %s", code)
+	}
+	if code := (multiBidConfig{maxBids: 3}).targetBidderCode(2); code != "" {
+		t.Errorf("expected no code without prefix, got %s", code)
+	}
+}
+
+func multiBidAuction(t *testing.T, ext string, demandType adapters.DemandType) map[string][]openrtb.Bid {
+	t.Helper()
+
+	registry := adapters.NewRegistry()
+	registry.Register("rubicon", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "r1", ImpID: "imp1", Price: 3.00, AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+		{Bid: &openrtb.Bid{ID: "r2", ImpID: "imp1", Price: 2.00, AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+		{Bid: &openrtb.Bid{ID: "r3", ImpID: "imp1", Price: 1.00, AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true, DemandType: demandType})
+
+	ex := New(registry, &Config{
+		DefaultTimeout: 500 * time.Millisecond,
+		AuctionType:    FirstPriceAuction,
+	})
+
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "multibid-req",
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+			Ext:  json.RawMessage(ext),
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	seats := make(map[string][]openrtb.Bid)
+	for _, sb := range resp.BidResponse.SeatBid {
+		seats[sb.Seat] = sb.Bid
+	}
+	return seats
+}
+
+func bidTargeting(t *testing.T, bid openrtb.Bid) *openrtb.ExtBidPrebid {
+	t.Helper()
+	var ext openrtb.BidExt
+	if err := json.Unmarshal(bid.Ext, &ext); err != nil || ext.Prebid == nil {
+		t.Fatalf("failed to parse bid ext: %v", err)
+	}
+	return ext.Prebid
+}
+
+func TestRunAuction_MultiBid_Publisher(t *testing.T) {
+	seats := multiBidAuction(t,
+		`{"prebid":{"multibid":[{"bidder":"rubicon","maxbids":2,"targetbiddercodeprefix":"rubicon"}]}}`,
+		adapters.DemandTypePublisher)
+
+	bids := seats["rubicon"]
+	if len(bids) != 2 {
+		t.Fatalf("expected 2 rubicon bids, got %d", len(bids))
+	}
+
+	first := bidTargeting(t, bids[0])
+	if first.Targeting["hb_pb_rubicon"] != "3.00" || first.Targeting["hb_pb"] != "3.00" {
+		t.Errorf("unexpected first bid targeting: %v", first.Targeting)
+	}
+
+	second := bidTargeting(t, bids[1])
+	if second.TargetBidderCode != "rubicon2" {
+		t.Errorf("expected targetbiddercode rubicon2, got %q", second.TargetBidderCode)
+	}
+	if second.Targeting["hb_pb_rubicon2"] != "2.00" || second.Targeting["hb_bidder_rubicon2"] != "rubicon2" {
+		t.Errorf("unexpected second bid targeting: %v", second.Targeting)
+	}
+	if _, ok := second.Targeting["hb_pb"]; ok {
+		t.Error("extra bids must not set unsuffixed hb_pb")
+	}
+}
+
+func TestRunAuction_MultiBid_NoPrefix(t *testing.T) {
+	seats := multiBidAuction(t,
+		`{"prebid":{"multibid":[{"bidders":["rubicon"],"maxbids":3}]}}`,
+		adapters.DemandTypePublisher)
+
+	bids := seats["rubicon"]
+	if len(bids) != 3 {
+		t.Fatalf("expected 3 rubicon bids, got %d", len(bids))
+	}
+	for _, bid := range bids[1:] {
+		if prebid := bidTargeting(t, bid); len(prebid.Targeting) != 0 {
+			t.Errorf("expected no targeting on extra bid %s, got %v", bid.ID, prebid.Targeting)
+		}
+	}
+}
+
+func TestRunAuction_MultiBid_Platform(t *testing.T) {
+	// Without multibid only the top platform bid is returned
+	seats := multiBidAuction(t, "", adapters.DemandTypePlatform)
+	if len(seats[adapters.PlatformSeatName]) != 1 {
+		t.Fatalf("expected 1 platform bid, got %d", len(seats[adapters.PlatformSeatName]))
+	}
+
+	// Multibid keyed by the bidder code does not unlock obfuscated platform demand
+	seats = multiBidAuction(t,
+		`{"prebid":{"multibid":[{"bidder":"rubicon","maxbids":3,"targetbiddercodeprefix":"rubicon"}]}}`,
+		adapters.DemandTypePlatform)
+	if len(seats[adapters.PlatformSeatName]) != 1 {
+		t.Errorf("expected 1 platform bid, got %d", len(seats[adapters.PlatformSeatName]))
+	}
+	if _, ok := seats["rubicon"]; ok {
+		t.Error("platform bidder code must not be exposed")
+	}
+
+	// Multibid keyed by the platform seat returns extra obfuscated bids
+	seats = multiBidAuction(t,
+		`{"prebid":{"multibid":[{"bidder":"thenexusengine","maxbids":2,"targetbiddercodeprefix":"tne"}]}}`,
+		adapters.DemandTypePlatform)
+	bids := seats[adapters.PlatformSeatName]
+	if len(bids) != 2 {
+		t.Fatalf("expected 2 platform bids, got %d", len(bids))
+	}
+	if bids[0].ID != "r1" || bids[1].ID != "r2" {
+		t.Errorf("expected bids in price order, got %s, %s", bids[0].ID, bids[1].ID)
+	}
+	second := bidTargeting(t, bids[1])
+	if second.Targeting["hb_pb_tne2"] != "2.00" || second.Targeting["hb_bidder_tne2"] != "tne2" {
+		t.Errorf("unexpected platform extra bid targeting: %v", second.Targeting)
+	}
+}
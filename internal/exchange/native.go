@@ -0,0 +1,148 @@
+package exchange
+
+import (
+	"context"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+	"github.com/thenexusengine/tne_springwire/pkg/native"
+)
+
+// NativeConfig controls how native requests and bids are handled
+// Native bids are rendered to HTML only for publishers with native rendering enabled.
+type NativeConfig struct {
+	Validate        bool   // Reject native bids whose adm does not satisfy the imp's native request
+	UpgradeRequests bool   // Send legacy Native 1.0/1.1 requests to bidders as 1.2
+	RenderTemplate  string // html/template source for rendered ads (empty = native.DefaultTemplate)
+}
+
+// DefaultNativeConfig returns the default native configuration
+func DefaultNativeConfig() *NativeConfig {
+	return &NativeConfig{
+		Validate:        true,
+		UpgradeRequests: true,
+	}
+}
+
+// prepareNativeRequests parses the native request of each imp, keyed by imp ID
+// Legacy requests are upgraded to Native 1.2 when UpgradeRequests is set; the upgraded native
+// objects are returned keyed by imp ID for upgradeNativeImps, and the caller's imps are left
+// untouched. Imps whose request does not parse are left out, so their bids are not validated.
+func (e *Exchange) prepareNativeRequests(req *openrtb.BidRequest) (requests map[string]*native.Request, upgraded map[string]*openrtb.Native) {
+	for i := range req.Imp {
+		imp := &req.Imp[i]
+		if imp.Native == nil || imp.Native.Request == "" {
+			continue
+		}
+		nreq, err := native.ParseRequest(imp.Native.Request)
+		if err != nil {
+			logger.Log.Debug().
+				Str("impID", imp.ID).
+				Err(err).
+				Msg("Unparseable native request, skipping native validation")
+			continue
+		}
+		if e.config.Native.UpgradeRequests && nreq.Upgrade() {
+			nat := *imp.Native
+			nat.Request = nreq.String()
+			nat.Ver = native.Version
+			if upgraded == nil {
+				upgraded = make(map[string]*openrtb.Native)
+			}
+			upgraded[imp.ID] = &nat
+		}
+		if requests == nil {
+			requests = make(map[string]*native.Request)
+		}
+		requests[imp.ID] = nreq
+	}
+	return requests, upgraded
+}
+
+// upgradeNativeImps swaps in the upgraded native objects on a bidder's cloned request
+func upgradeNativeImps(req *openrtb.BidRequest, upgraded map[string]*openrtb.Native) {
+	if len(upgraded) == 0 {
+		return
+	}
+	for i := range req.Imp {
+		if nat, ok := upgraded[req.Imp[i].ID]; ok {
+			req.Imp[i].Native = nat
+		}
+	}
+}
+
+// prepareNativeBid validates and optionally renders the adm of a native bid
+// The adm is checked against the native request of the bid's imp when validation is enabled.
+// When render is set a copy of the bid is returned with HTML from the render template as its
+// adm, reported as a banner since the publisher's page renders it as one; an adm that cannot
+// be rendered is returned as native JSON. Bids without an adm (served from the nurl) are
+// passed through.
+func (e *Exchange) prepareNativeBid(tb *adapters.TypedBid, bidderCode string, requests map[string]*native.Request, render bool) (*adapters.TypedBid, *BidValidationError) {
+	if tb.BidType != adapters.BidTypeNative || tb.Bid.AdM == "" {
+		return tb, nil
+	}
+	cfg := e.config.Native
+	bid := tb.Bid
+	nreq := requests[bid.ImpID]
+	validate := cfg.Validate && nreq != nil
+	if !validate && !render {
+		return tb, nil
+	}
+
+	resp, err := native.ParseResponse(bid.AdM)
+	if err == nil && validate {
+		err = resp.Validate(nreq)
+	}
+	if err != nil {
+		if validate {
+			return tb, &BidValidationError{
+				BidID:      bid.ID,
+				ImpID:      bid.ImpID,
+				BidderCode: bidderCode,
+				Reason:     "invalid native response: " + err.Error(),
+				StatusCode: openrtb.NonBidInvalidBid,
+			}
+		}
+		return tb, nil
+	}
+
+	if render {
+		html, err := native.Render(e.nativeTemplate, nreq, resp)
+		if err != nil {
+			logger.Log.Warn().
+				Str("bidder", bidderCode).
+				Str("bidID", bid.ID).
+				Err(err).
+				Msg("Native render failed, returning native JSON")
+			return tb, nil
+		}
+		rendered := *bid
+		rendered.AdM = html
+		typed := *tb
+		typed.Bid = &rendered
+		typed.BidType = adapters.BidTypeBanner
+		return &typed, nil
+	}
+	return tb, nil
+}
+
+// publisherNativeRender reports whether the request's publisher wants native bids rendered
+func publisherNativeRender(ctx context.Context) bool {
+	if pub := middleware.PublisherFromContext(ctx); pub != nil {
+		return extractNativeRender(pub)
+	}
+	return false
+}
+
+// extractNativeRender safely extracts the publisher's native rendering setting
+func extractNativeRender(v interface{}) bool {
+	type nativeRenderGetter interface {
+		GetNativeRender() bool
+	}
+	if getter, ok := v.(nativeRenderGetter); ok {
+		return getter.GetNativeRender()
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package exchange
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/native"
+)
+
+const (
+	testNativeRequest = `{"ver":"1.2","assets":[{"id":1,"required":1,"title":{"len":25}},{"id":2,"required":1,"img":{"type":3,"wmin":300,"hmin":150}}]}`
+	testNativeAdM     = `{"link":{"url":"https://adv.example.com"},"assets":[{"id":1,"title":{"text":"Summer Sale"}},{"id":2,"img":{"url":"https://cdn.example.com/main.jpg","w":600,"h":300}}]}`
+)
+
+// mockPublisherWithNativeRender is a publisher with native rendering enabled
+type mockPublisherWithNativeRender struct {
+	mockPublisherWithMultiplier
+}
+
+func (m *mockPublisherWithNativeRender) GetNativeRender() bool {
+	return true
+}
+
+func TestPrepareNativeRequests(t *testing.T) {
+	ex := New(adapters.NewRegistry(), nil)
+	legacy := &openrtb.Native{Request: `{"native":{"ver":"1.1","layout":3,"assets":[{"id":1,"title":{"len":25}}]}}`, Ver: "1.1"}
+	req := &openrtb.BidRequest{Imp: []openrtb.Imp{
+		{ID: "legacy", Native: legacy},
+		{ID: "current", Native: &openrtb.Native{Request: testNativeRequest, Ver: "1.2"}},
+		{ID: "broken", Native: &openrtb.Native{Request: "not json"}},
+		{ID: "banner", Banner: &openrtb.Banner{W: 300, H: 250}},
+	}}
+
+	requests, upgrades := ex.prepareNativeRequests(req)
+	if len(requests) != 2 || requests["legacy"] == nil || requests["current"] == nil {
+		t.Fatalf("expected parsed requests for legacy and current imps, got %v", requests)
+	}
+	if len(upgrades) != 1 {
+		t.Fatalf("expected only the legacy imp upgraded, got %v", upgrades)
+	}
+	if req.Imp[0].Native != legacy || legacy.Ver != "1.1" {
+		t.Error("expected the caller's imps left untouched")
+	}
+
+	// Each bidder's cloned request gets the upgraded native object
+	clone := ex.cloneRequestWithFPD(req, "appnexus", nil)
+	upgradeNativeImps(clone, upgrades)
+	upgraded := clone.Imp[0].Native
+	if upgraded.Ver != native.Version || strings.Contains(upgraded.Request, `"native"`) || !strings.Contains(upgraded.Request, `"context":2`) {
+		t.Errorf("expected 1.2 request sent to bidders, got ver=%s %s", upgraded.Ver, upgraded.Request)
+	}
+	if clone.Imp[1].Native.Request != testNativeRequest {
+		t.Errorf("expected 1.2 request unchanged, got %s", clone.Imp[1].Native.Request)
+	}
+	if req.Imp[0].Native != legacy {
+		t.Error("expected the caller's imp untouched by the bidder upgrade")
+	}
+
+	noUpgrade := New(adapters.NewRegistry(), &Config{Native: &NativeConfig{Validate: true}})
+	if _, upgrades := noUpgrade.prepareNativeRequests(req); upgrades != nil {
+		t.Errorf("expected no upgrades when disabled, got %v", upgrades)
+	}
+}
+
+func TestPrepareNativeBid(t *testing.T) {
+	ex := New(adapters.NewRegistry(), nil)
+	nreq, _ := native.ParseRequest(testNativeRequest)
+	requests := map[string]*native.Request{"imp1": nreq}
+
+	t.Run("valid bid passes", func(t *testing.T) {
+		tb := &adapters.TypedBid{Bid: &openrtb.Bid{ID: "b1", ImpID: "imp1", AdM: testNativeAdM}, BidType: adapters.BidTypeNative}
+		if got, err := ex.prepareNativeBid(tb, "appnexus", requests, false); err != nil || got != tb || tb.Bid.AdM != testNativeAdM {
+			t.Errorf("expected valid bid unchanged, got %v", err)
+		}
+	})
+
+	t.Run("invalid bid is rejected", func(t *testing.T) {
+		for name, adm := range map[string]string{
+			"not json":        "<div>banner</div>",
+			"missing title":   `{"link":{"url":"https://adv.example.com"},"assets":[{"id":2,"img":{"url":"https://cdn.example.com/main.jpg"}}]}`,
+			"image too small": strings.Replace(testNativeAdM, `"w":600`, `"w":100`, 1),
+		} {
+			tb := &adapters.TypedBid{Bid: &openrtb.Bid{ID: "b1", ImpID: "imp1", AdM: adm}, BidType: adapters.BidTypeNative}
+			_, err := ex.prepareNativeBid(tb, "appnexus", requests, false)
+			if err == nil || err.NonBidStatusCode() != openrtb.NonBidInvalidBid || !strings.HasPrefix(err.Reason, "invalid native response") {
+				t.Errorf("%s: expected invalid native error, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("imps without a parsed request are not validated", func(t *testing.T) {
+		tb := &adapters.TypedBid{Bid: &openrtb.Bid{ID: "b1", ImpID: "imp2", AdM: "<div></div>"}, BidType: adapters.BidTypeNative}
+		if _, err := ex.prepareNativeBid(tb, "appnexus", requests, false); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("render", func(t *testing.T) {
+		original := &adapters.TypedBid{Bid: &openrtb.Bid{ID: "b1", ImpID: "imp1", AdM: testNativeAdM}, BidType: adapters.BidTypeNative}
+		tb, err := ex.prepareNativeBid(original, "appnexus", requests, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if original.Bid.AdM != testNativeAdM || original.BidType != adapters.BidTypeNative {
+			t.Errorf("expected the adapter's bid left untouched, got %+v", original)
+		}
+		if tb.BidType != adapters.BidTypeBanner {
+			t.Errorf("expected the rendered bid reported as banner, got %s", tb.BidType)
+		}
+		if !strings.Contains(tb.Bid.AdM, `src="https://cdn.example.com/main.jpg"`) || !strings.Contains(tb.Bid.AdM, "Summer Sale") {
+			t.Errorf("expected rendered HTML, got %s", tb.Bid.AdM)
+		}
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		custom := New(adapters.NewRegistry(), &Config{Native: &NativeConfig{RenderTemplate: `<b>{{.Title}}</b>`}})
+		tb := &adapters.TypedBid{Bid: &openrtb.Bid{ID: "b1", ImpID: "imp1", AdM: testNativeAdM}, BidType: adapters.BidTypeNative}
+		if got, err := custom.prepareNativeBid(tb, "appnexus", requests, true); err != nil || got.Bid.AdM != "<b>Summer Sale</b>" {
+			t.Errorf("expected custom template render, got %s (%v)", got.Bid.AdM, err)
+		}
+	})
+}
+
+func TestRunAuction_NativeBids(t *testing.T) {
+	newRegistry := func() *adapters.Registry {
+		registry := adapters.NewRegistry()
+		registry.Register("appnexus", &mockAdapter{bids: []*adapters.TypedBid{
+			{Bid: &openrtb.Bid{ID: "a1", ImpID: "imp1", Price: 2.00, AdM: testNativeAdM}, BidType: adapters.BidTypeNative},
+		}}, adapters.BidderInfo{Enabled: true})
+		registry.Register("rubicon", &mockAdapter{bids: []*adapters.TypedBid{
+			{Bid: &openrtb.Bid{ID: "r1", ImpID: "imp1", Price: 5.00, AdM: `{"link":{"url":"https://adv.example.com"},"assets":[]}`}, BidType: adapters.BidTypeNative},
+		}}, adapters.BidderInfo{Enabled: true})
+		return registry
+	}
+	bidRequest := func() *openrtb.BidRequest {
+		return &openrtb.BidRequest{
+			ID:   "native-auction",
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Native: &openrtb.Native{Request: testNativeRequest, Ver: "1.2"}}},
+		}
+	}
+	winners := func(resp *AuctionResponse) []openrtb.Bid {
+		var bids []openrtb.Bid
+		for _, sb := range resp.BidResponse.SeatBid {
+			bids = append(bids, sb.Bid...)
+		}
+		return bids
+	}
+
+	ex := New(newRegistry(), &Config{DefaultTimeout: 500 * time.Millisecond})
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{BidRequest: bidRequest()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bids := winners(resp)
+	if len(bids) != 1 || bids[0].ID != "a1" || bids[0].AdM != testNativeAdM {
+		t.Fatalf("expected only the valid native bid a1 as JSON, got %+v", bids)
+	}
+	nonBids := resp.DebugInfo.NonBids["rubicon"]
+	if len(nonBids) != 1 || nonBids[0].StatusCode != openrtb.NonBidInvalidBid {
+		t.Errorf("expected invalid bid non-bid for rubicon, got %+v", resp.DebugInfo.NonBids)
+	}
+
+	// Publishers with native rendering get HTML
+	pub := &mockPublisherWithNativeRender{mockPublisherWithMultiplier{PublisherID: "pub1", BidMultiplier: 1.0}}
+	ctx := middleware.NewContextWithPublisher(context.Background(), pub)
+	ex = New(newRegistry(), &Config{DefaultTimeout: 500 * time.Millisecond})
+	resp, err = ex.RunAuction(ctx, &AuctionRequest{BidRequest: bidRequest()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bids = winners(resp)
+	if len(bids) != 1 || !strings.HasPrefix(bids[0].AdM, `<div class="native-ad"`) {
+		t.Fatalf("expected rendered native ad, got %+v", bids)
+	}
+	if ext := string(bids[0].Ext); !strings.Contains(ext, `"type":"banner"`) || !strings.Contains(ext, `"mediaType":"banner"`) {
+		t.Errorf("expected the rendered ad reported as banner, got %s", ext)
+	}
+	if adapterBid := resp.BidderResults["appnexus"].Bids[0].Bid; adapterBid.AdM != testNativeAdM {
+		t.Errorf("expected the bidder's result to keep its native JSON, got %s", adapterBid.AdM)
+	}
+}
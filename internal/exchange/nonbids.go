@@ -0,0 +1,152 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// recordNonBid records a bid, or an imp a bidder did not bid on, that did not take part in the auction
+// The non-bid is kept for ext.seatnonbid, counted per bidder and reason, and sent to the event stream
+// with the floor of its imp
+func (e *Exchange) recordNonBid(ctx context.Context, response *AuctionResponse, auctionID, publisherID string, floors impFloorMap, bidderCode string, nonBid openrtb.NonBid) {
+	response.DebugInfo.AddNonBid(bidderCode, nonBid)
+
+	e.configMu.RLock()
+	if e.metrics != nil {
+		e.metrics.RecordSeatNonBid(e.metricsLabel(ctx, bidderCode), nonBid.StatusCode.String())
+	}
+	e.configMu.RUnlock()
+
+	if e.eventRecorder != nil {
+		var bidCPM *float64
+		var reason string
+		if nonBid.Ext != nil {
+			price := nonBid.Ext.Prebid.Bid.Price
+			bidCPM = &price
+			reason = nonBid.Ext.Prebid.Reason
+		}
+		if reason == "" {
+			reason = nonBid.StatusCode.String()
+		}
+		e.eventRecorder.RecordNonBid(auctionID, bidderCode, nonBid.ImpID, int(nonBid.StatusCode), reason, bidCPM, floors.floor(nonBid.ImpID), publisherID)
+	}
+}
+
+// impFloorMap maps imp IDs to the floor signalled to bidders
+type impFloorMap map[string]float64
+
+func buildImpFloors(imps []openrtb.Imp) impFloorMap {
+	floors := make(impFloorMap, len(imps))
+	for _, imp := range imps {
+		if imp.BidFloor > 0 {
+			floors[imp.ID] = imp.BidFloor
+		}
+	}
+	return floors
+}
+
+// floor returns the imp's floor, or nil when it has none
+func (m impFloorMap) floor(impID string) *float64 {
+	floor, ok := m[impID]
+	if !ok {
+		return nil
+	}
+	return &floor
+}
+
+// bidderNonBids returns a non-bid for every imp the bidder did not bid on
+// The code reflects why: timeout, privacy filtering, throttling, an open circuit breaker, bidder error or a plain no bid
+func bidderNonBids(result *BidderResult, imps []openrtb.Imp) []openrtb.NonBid {
+	if result == nil {
+		return nil
+	}
+
+	code := openrtb.NonBidNoBid
+	switch {
+	case result.TimedOut:
+		code = openrtb.NonBidTimeout
+	case result.PrivacyFiltered:
+		code = openrtb.NonBidPrivacy
+	case result.Throttled:
+		code = openrtb.NonBidThrottled
+	case result.Unhealthy:
+		code = openrtb.NonBidBidderUnhealthy
+	case len(result.Errors) > 0 && len(result.Bids) == 0:
+		code = openrtb.NonBidBidderError
+	}
+
+	bidImps := make(map[string]struct{}, len(result.Bids))
+	for _, tb := range result.Bids {
+		if tb != nil && tb.Bid != nil {
+			bidImps[tb.Bid.ImpID] = struct{}{}
+		}
+	}
+
+	var nonBids []openrtb.NonBid
+	for _, imp := range imps {
+		if _, ok := bidImps[imp.ID]; ok {
+			continue
+		}
+		nonBids = append(nonBids, openrtb.NonBid{ImpID: imp.ID, StatusCode: code})
+	}
+	return nonBids
+}
+
+// lostBidStatus returns the non-bid code for a bid that lost to the impression's winner
+func lostBidStatus(winner, vb ValidatedBid) openrtb.NonBidStatusCode {
+	if winner.DealTier > vb.DealTier {
+		return openrtb.NonBidLostToDealBid
+	}
+	return openrtb.NonBidLostToHigherBid
+}
+
+// seatNonBids groups non-bids by response seat, ordered by seat
+// Platform demand is reported under the platform seat so the real bidder is not exposed
+func (e *Exchange) seatNonBids(ctx context.Context, nonBids map[string][]openrtb.NonBid) []openrtb.SeatNonBid {
+	if len(nonBids) == 0 {
+		return nil
+	}
+
+	bidders := make([]string, 0, len(nonBids))
+	for bidderCode := range nonBids {
+		bidders = append(bidders, bidderCode)
+	}
+	sort.Strings(bidders)
+
+	bySeat := make(map[string][]openrtb.NonBid)
+	for _, bidderCode := range bidders {
+		seat := bidderCode
+		if e.bidderDemandType(ctx, bidderCode) != adapters.DemandTypePublisher {
+			seat = adapters.PlatformSeatName
+		}
+		bySeat[seat] = append(bySeat[seat], nonBids[bidderCode]...)
+	}
+
+	seats := make([]string, 0, len(bySeat))
+	for seat := range bySeat {
+		seats = append(seats, seat)
+	}
+	sort.Strings(seats)
+
+	result := make([]openrtb.SeatNonBid, 0, len(seats))
+	for _, seat := range seats {
+		result = append(result, openrtb.SeatNonBid{Seat: seat, NonBid: bySeat[seat]})
+	}
+	return result
+}
+
+// returnAllBidStatus reports whether the request sets ext.prebid.returnallbidstatus
+func returnAllBidStatus(req *openrtb.BidRequest) bool {
+	if req == nil || len(req.Ext) == 0 {
+		return false
+	}
+	var ext openrtb.BidRequestExt
+	if err := json.Unmarshal(req.Ext, &ext); err != nil || ext.Prebid == nil {
+		return false
+	}
+	return ext.Prebid.ReturnAllBidStatus
+}
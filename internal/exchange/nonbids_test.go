@@ -0,0 +1,198 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// nonBidMetrics captures seat non-bid metrics
+type nonBidMetrics struct {
+	mockMetricsRecorder
+	nonBids map[string]int // bidder|reason -> count
+}
+
+func (m *nonBidMetrics) RecordSeatNonBid(bidder, reason string) {
+	m.nonBids[bidder+"|"+reason]++
+}
+
+func TestBidderNonBids(t *testing.T) {
+	imps := []openrtb.Imp{{ID: "imp1"}, {ID: "imp2"}}
+	bidOnImp1 := []*adapters.TypedBid{{Bid: &openrtb.Bid{ID: "b1", ImpID: "imp1"}}}
+
+	tests := []struct {
+		name   string
+		result *BidderResult
+		want   openrtb.NonBidStatusCode
+		count  int
+	}{
+		{"no bids", &BidderResult{}, openrtb.NonBidNoBid, 2},
+		{"partial bids", &BidderResult{Bids: bidOnImp1}, openrtb.NonBidNoBid, 1},
+		{"timeout", &BidderResult{TimedOut: true, Errors: []error{context.DeadlineExceeded}}, openrtb.NonBidTimeout, 2},
+		{"privacy", &BidderResult{PrivacyFiltered: true, Errors: []error{errors.New("no consent")}}, openrtb.NonBidPrivacy, 2},
+		{"throttled", &BidderResult{Throttled: true, Errors: []error{errors.New("throttled: qps limit reached")}}, openrtb.NonBidThrottled, 2},
+		{"unhealthy", &BidderResult{Unhealthy: true, Errors: []error{errors.New("bidder unhealthy: circuit breaker open")}}, openrtb.NonBidBidderUnhealthy, 2},
+		{"error", &BidderResult{Errors: []error{errors.New("bad response")}}, openrtb.NonBidBidderError, 2},
+		{"error with bids", &BidderResult{Bids: bidOnImp1, Errors: []error{errors.New("one bad bid")}}, openrtb.NonBidNoBid, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nonBids := bidderNonBids(tt.result, imps)
+			if len(nonBids) != tt.count {
+				t.Fatalf("expected %d non-bids, got %+v", tt.count, nonBids)
+			}
+			for _, nb := range nonBids {
+				if nb.StatusCode != tt.want {
+					t.Errorf("expected code %d, got %d", tt.want, nb.StatusCode)
+				}
+				if nb.ImpID == "imp1" && len(tt.result.Bids) > 0 {
+					t.Error("imp with a bid reported as non-bid")
+				}
+			}
+		})
+	}
+
+	if nonBids := bidderNonBids(nil, imps); nonBids != nil {
+		t.Errorf("expected nil for nil result, got %+v", nonBids)
+	}
+}
+
+func TestBuildImpFloors(t *testing.T) {
+	floors := buildImpFloors([]openrtb.Imp{{ID: "imp1", BidFloor: 0.50}, {ID: "imp2", BidFloor: 2.00}, {ID: "imp3"}})
+	if f := floors.floor("imp2"); f == nil || *f != 2.00 {
+		t.Errorf("expected imp2 floor 2.00, got %v", f)
+	}
+	if f := floors.floor("imp1"); f == nil || *f != 0.50 {
+		t.Errorf("expected imp1 floor 0.50, got %v", f)
+	}
+	if f := floors.floor("imp3"); f != nil {
+		t.Errorf("expected no floor for imp3, got %v", *f)
+	}
+}
+
+func TestReturnAllBidStatus(t *testing.T) {
+	tests := []struct {
+		ext  string
+		want bool
+	}{
+		{"", false},
+		{`{"prebid":{}}`, false},
+		{`{"prebid":{"returnallbidstatus":true}}`, true},
+		{`{"prebid":`, false},
+	}
+	for _, tt := range tests {
+		req := &openrtb.BidRequest{Ext: json.RawMessage(tt.ext)}
+		if got := returnAllBidStatus(req); got != tt.want {
+			t.Errorf("returnAllBidStatus(%q) = %v, want %v", tt.ext, got, tt.want)
+		}
+	}
+}
+
+func TestNonBidStatusCode_String(t *testing.T) {
+	if s := openrtb.NonBidBelowFloor.String(); s != "below_floor" {
+		t.Errorf("expected below_floor, got %s", s)
+	}
+	if s := openrtb.NonBidStatusCode(999).String(); s != "unknown" {
+		t.Errorf("expected unknown, got %s", s)
+	}
+}
+
+func TestRunAuction_SeatNonBid(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("appnexus", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "a1", ImpID: "imp1", Price: 2.00, AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true, DemandType: adapters.DemandTypePlatform})
+	registry.Register("pubmatic", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "p1", ImpID: "imp1", Price: 1.80, AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true, DemandType: adapters.DemandTypePlatform})
+	registry.Register("rubicon", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "r0", ImpID: "imp1", Price: 0.50, AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+		{Bid: &openrtb.Bid{ID: "r1", ImpID: "imp1", Price: 1.50, AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+		{Bid: &openrtb.Bid{ID: "r1", ImpID: "imp1", Price: 1.60, AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true, DemandType: adapters.DemandTypePublisher})
+	registry.Register("openx", &mockAdapter{bidsErr: errors.New("bad response")},
+		adapters.BidderInfo{Enabled: true, DemandType: adapters.DemandTypePublisher})
+
+	ex := New(registry, &Config{
+		DefaultTimeout: 500 * time.Millisecond,
+		AuctionType:    FirstPriceAuction,
+	})
+	metrics := &nonBidMetrics{nonBids: make(map[string]int)}
+	ex.SetMetrics(metrics)
+
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "nonbid-auction",
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}, BidFloor: 1.00}},
+			Ext:  json.RawMessage(`{"prebid":{"returnallbidstatus":true}}`),
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]openrtb.NonBidStatusCode{
+		"pubmatic": openrtb.NonBidLostToHigherBid,
+		"rubicon":  openrtb.NonBidBelowFloor,
+		"openx":    openrtb.NonBidBidderError,
+	}
+	for bidder, code := range want {
+		nonBids := resp.DebugInfo.NonBids[bidder]
+		if len(nonBids) == 0 || nonBids[0].StatusCode != code {
+			t.Errorf("expected %s non-bid %d, got %+v", bidder, code, nonBids)
+		}
+		if metrics.nonBids[bidder+"|"+code.String()] != 1 {
+			t.Errorf("expected metric for %s %s, got %v", bidder, code, metrics.nonBids)
+		}
+	}
+	if _, ok := resp.DebugInfo.NonBids["appnexus"]; ok {
+		t.Errorf("winning bidder should have no non-bids, got %+v", resp.DebugInfo.NonBids["appnexus"])
+	}
+
+	// rubicon's third bid reuses a bid ID
+	if rubicon := resp.DebugInfo.NonBids["rubicon"]; len(rubicon) != 2 || rubicon[1].StatusCode != openrtb.NonBidDuplicateBid {
+		t.Errorf("expected below floor then duplicate for rubicon, got %+v", rubicon)
+	}
+
+	// Platform demand is reported under the platform seat
+	seats := make(map[string]int)
+	for _, snb := range resp.SeatNonBid {
+		seats[snb.Seat] = len(snb.NonBid)
+	}
+	if seats[adapters.PlatformSeatName] != 1 || seats["rubicon"] != 2 || seats["openx"] != 1 {
+		t.Errorf("unexpected seat non-bids: %v", seats)
+	}
+	if _, ok := seats["pubmatic"]; ok {
+		t.Error("platform bidder exposed in seatnonbid")
+	}
+}
+
+func TestRunAuction_SeatNonBidNotRequested(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("appnexus", &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{DefaultTimeout: 500 * time.Millisecond})
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "nonbid-off",
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.SeatNonBid != nil {
+		t.Errorf("expected no seatnonbid without returnallbidstatus, got %+v", resp.SeatNonBid)
+	}
+	if nb := resp.DebugInfo.NonBids["appnexus"]; len(nb) != 1 || nb[0].StatusCode != openrtb.NonBidNoBid {
+		t.Errorf("expected no-bid recorded for debug, got %+v", nb)
+	}
+}
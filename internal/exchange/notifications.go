@@ -0,0 +1,169 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// LossNotifyConfig configures server-fired loss notifications (lurl)
+type LossNotifyConfig struct {
+	Enabled   bool
+	Workers   int           // Concurrent notification requests
+	QueueSize int           // Pending notifications; new ones are dropped when full
+	Timeout   time.Duration // Per-request timeout
+}
+
+// DefaultLossNotifyConfig returns the default loss notification configuration
+func DefaultLossNotifyConfig() *LossNotifyConfig {
+	return &LossNotifyConfig{
+		Enabled:   true,
+		Workers:   4,
+		QueueSize: 1000,
+		Timeout:   2 * time.Second,
+	}
+}
+
+// lossNotifier fires loss notification URLs from a bounded worker pool
+// Notifications never block the auction: when the queue is full they are dropped.
+// Workers start on the first notification.
+type lossNotifier struct {
+	client  *http.Client
+	timeout time.Duration
+	workers int
+	queue   chan string
+	start   sync.Once
+	wg      sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// newLossNotifier creates a loss notifier
+func newLossNotifier(config *LossNotifyConfig) *lossNotifier {
+	defaults := DefaultLossNotifyConfig()
+	workers := config.Workers
+	if workers <= 0 {
+		workers = defaults.Workers
+	}
+	queueSize := config.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaults.QueueSize
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaults.Timeout
+	}
+
+	// lurls come from bidder responses, so they may only reach public addresses
+	dialer := &net.Dialer{Timeout: timeout, Control: publicAddressOnly}
+	transport := &http.Transport{
+		DialContext:     dialer.DialContext,
+		MaxIdleConns:    workers,
+		IdleConnTimeout: 90 * time.Second,
+	}
+
+	return &lossNotifier{
+		client:  &http.Client{Timeout: timeout, Transport: transport},
+		timeout: timeout,
+		workers: workers,
+		queue:   make(chan string, queueSize),
+	}
+}
+
+// publicAddressOnly refuses connections to loopback, private, link-local and other non-public
+// addresses, such as cloud metadata endpoints. It runs after DNS resolution and on every
+// redirect, so a public hostname resolving to an internal address is refused too.
+func publicAddressOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("non-public address %s refused", host)
+	}
+	return nil
+}
+
+// isPublicIP reports whether an address is routable on the public internet
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsUnspecified()
+}
+
+// notify queues a loss notification URL
+// Returns false if the URL was dropped (invalid, queue full or notifier closed)
+func (n *lossNotifier) notify(url string) bool {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return false
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.closed {
+		return false
+	}
+
+	n.start.Do(func() {
+		for i := 0; i < n.workers; i++ {
+			n.wg.Add(1)
+			go n.worker()
+		}
+	})
+
+	select {
+	case n.queue <- url:
+		return true
+	default:
+		logger.Log.Debug().Msg("loss notification queue full, dropping lurl")
+		return false
+	}
+}
+
+func (n *lossNotifier) worker() {
+	defer n.wg.Done()
+	for url := range n.queue {
+		n.fire(url)
+	}
+}
+
+// fire sends one loss notification; failures are logged and not retried
+func (n *lossNotifier) fire(url string) {
+	ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		logger.Log.Debug().Err(err).Msg("invalid loss notification URL")
+		return
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		logger.Log.Debug().Err(err).Msg("loss notification failed")
+		return
+	}
+	resp.Body.Close()
+}
+
+// close stops accepting notifications and waits for queued ones to be sent
+func (n *lossNotifier) close() {
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return
+	}
+	n.closed = true
+	close(n.queue)
+	n.mu.Unlock()
+
+	n.wg.Wait()
+}
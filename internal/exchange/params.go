@@ -0,0 +1,57 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// filterInvalidParams drops imps whose params fail the bidder's params schema
+// Imps without params for the bidder are kept. Returns an error per dropped imp.
+func filterInvalidParams(req *openrtb.BidRequest, bidderCode string, awi adapters.AdapterWithInfo) []error {
+	var errs []error
+	kept := req.Imp[:0]
+	for i := range req.Imp {
+		params := adapters.ImpBidderParams(&req.Imp[i], bidderCode)
+		if params == nil {
+			kept = append(kept, req.Imp[i])
+			continue
+		}
+		if err := awi.ValidateParams(params); err != nil {
+			logger.Log.Warn().
+				Str("bidder", bidderCode).
+				Str("request_id", req.ID).
+				Str("imp_id", req.Imp[i].ID).
+				Err(err).
+				Msg("Dropping imp with invalid bidder params")
+			errs = append(errs, fmt.Errorf("imp %s dropped: invalid %s params: %w", req.Imp[i].ID, bidderCode, err))
+			continue
+		}
+		kept = append(kept, req.Imp[i])
+	}
+	req.Imp = kept
+	return errs
+}
+
+// publisherBidderParams returns the request publisher's stored params for a bidder
+func publisherBidderParams(ctx context.Context, bidderCode string) map[string]interface{} {
+	if pub := middleware.PublisherFromContext(ctx); pub != nil {
+		return extractBidderParams(pub, bidderCode)
+	}
+	return nil
+}
+
+// extractBidderParams safely extracts the publisher's stored params for a bidder
+func extractBidderParams(v interface{}, bidderCode string) map[string]interface{} {
+	type bidderParamsGetter interface {
+		GetBidderParams(bidderCode string) map[string]interface{}
+	}
+	if getter, ok := v.(bidderParamsGetter); ok {
+		return getter.GetBidderParams(bidderCode)
+	}
+	return nil
+}
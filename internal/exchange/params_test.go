@@ -0,0 +1,118 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// impAdapter records the imp IDs of the requests it receives
+type impAdapter struct {
+	mockAdapter
+	mu     sync.Mutex
+	impIDs []string
+}
+
+func (a *impAdapter) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	a.mu.Lock()
+	for _, imp := range request.Imp {
+		a.impIDs = append(a.impIDs, imp.ID)
+	}
+	a.mu.Unlock()
+	return a.mockAdapter.MakeRequests(request, reqInfo)
+}
+
+const rubiconParamsSchema = `{
+	"type": "object",
+	"properties": {
+		"accountId": {"type": "integer"},
+		"siteId": {"type": "integer"},
+		"zoneId": {"type": "integer"}
+	},
+	"required": ["accountId", "siteId", "zoneId"]
+}`
+
+func TestRunAuction_InvalidBidderParams(t *testing.T) {
+	rubicon := &impAdapter{}
+	registry := adapters.NewRegistry()
+	if err := registry.Register("rubicon", rubicon, adapters.BidderInfo{
+		Enabled:      true,
+		ParamsSchema: json.RawMessage(rubiconParamsSchema),
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ex := New(registry, &Config{DefaultTimeout: 200 * time.Millisecond})
+
+	run := func(imps ...openrtb.Imp) *BidderResult {
+		t.Helper()
+		resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+			BidRequest: &openrtb.BidRequest{ID: "params-auction", Site: testSite(), Imp: imps},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp.BidderResults["rubicon"]
+	}
+	imp := func(id, ext string) openrtb.Imp {
+		return openrtb.Imp{ID: id, Banner: &openrtb.Banner{W: 300, H: 250}, Ext: json.RawMessage(ext)}
+	}
+
+	// The invalid imp is dropped; the valid imp and the imp without params are still sent
+	result := run(
+		imp("valid", `{"rubicon":{"accountId":1,"siteId":2,"zoneId":3}}`),
+		imp("missing-zone", `{"rubicon":{"accountId":1,"siteId":2}}`),
+		imp("no-params", `{}`),
+	)
+	if strings.Join(rubicon.impIDs, ",") != "valid,no-params" {
+		t.Errorf("expected invalid imp to be dropped, bidder got %v", rubicon.impIDs)
+	}
+	if result == nil || len(result.Errors) == 0 || !strings.Contains(result.Errors[0].Error(), "imp missing-zone dropped: invalid rubicon params: zoneId: required") {
+		t.Errorf("expected params error for dropped imp, got %+v", result)
+	}
+
+	// With every imp invalid the bidder is not called
+	rubicon.impIDs = nil
+	result = run(imp("bad-type", `{"prebid":{"bidder":{"rubicon":{"accountId":"1","siteId":2,"zoneId":3}}}}`))
+	if len(rubicon.impIDs) != 0 {
+		t.Errorf("expected bidder not to be called, got %v", rubicon.impIDs)
+	}
+	if result == nil || len(result.Errors) != 1 {
+		t.Errorf("expected one params error, got %+v", result)
+	}
+}
+
+// mockPublisherWithBidderParams is a publisher with stored bidder params
+type mockPublisherWithBidderParams struct {
+	mockPublisherWithMultiplier
+	BidderParams map[string]interface{}
+}
+
+func (m *mockPublisherWithBidderParams) GetBidderParams(bidderCode string) map[string]interface{} {
+	params, _ := m.BidderParams[bidderCode].(map[string]interface{})
+	return params
+}
+
+func TestPublisherBidderParams(t *testing.T) {
+	pub := &mockPublisherWithBidderParams{
+		mockPublisherWithMultiplier: mockPublisherWithMultiplier{PublisherID: "pub1", BidMultiplier: 1.0},
+		BidderParams:                map[string]interface{}{"rubicon": map[string]interface{}{"siteId": 42}},
+	}
+	ctx := middleware.NewContextWithPublisher(context.Background(), pub)
+
+	if params := publisherBidderParams(ctx, "rubicon"); params["siteId"] != 42 {
+		t.Errorf("expected stored rubicon params, got %v", params)
+	}
+	if params := publisherBidderParams(ctx, "appnexus"); params != nil {
+		t.Errorf("expected no params for unconfigured bidder, got %v", params)
+	}
+	if params := publisherBidderParams(context.Background(), "rubicon"); params != nil {
+		t.Errorf("expected no params without a publisher, got %v", params)
+	}
+}
@@ -0,0 +1,61 @@
+package exchange
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestHMACPriceEncrypter_RoundTrip(t *testing.T) {
+	pe, err := NewHMACPriceEncrypter([]byte("encryption-key"), []byte("integrity-key"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, price := range []float64{0, 0.01, 1.5, 3.01, 1234.567891} {
+		encrypted, err := pe.Encrypt(price)
+		if err != nil {
+			t.Fatalf("encrypt %.6f: %v", price, err)
+		}
+		if len(encrypted) != 38 {
+			t.Errorf("expected 38 character web-safe token, got %d (%s)", len(encrypted), encrypted)
+		}
+		decrypted, err := pe.Decrypt(encrypted)
+		if err != nil {
+			t.Fatalf("decrypt %.6f: %v", price, err)
+		}
+		if decrypted != price {
+			t.Errorf("expected %.6f, got %.6f", price, decrypted)
+		}
+	}
+
+	// A fresh IV per call means the same price never encrypts the same way twice
+	a, _ := pe.Encrypt(1.00)
+	b, _ := pe.Encrypt(1.00)
+	if a == b {
+		t.Error("expected different ciphertexts for repeated prices")
+	}
+}
+
+func TestHMACPriceEncrypter_Invalid(t *testing.T) {
+	pe, _ := NewHMACPriceEncrypter([]byte("encryption-key"), []byte("integrity-key"))
+	other, _ := NewHMACPriceEncrypter([]byte("encryption-key"), []byte("other-integrity-key"))
+
+	encrypted, _ := pe.Encrypt(2.50)
+	if _, err := other.Decrypt(encrypted); err != ErrPriceSignatureMismatch {
+		t.Errorf("expected signature mismatch with wrong integrity key, got %v", err)
+	}
+
+	data, _ := base64.RawURLEncoding.DecodeString(encrypted)
+	data[priceIVSize] ^= 0xFF
+	if _, err := pe.Decrypt(base64.RawURLEncoding.EncodeToString(data)); err != ErrPriceSignatureMismatch {
+		t.Errorf("expected signature mismatch for tampered price, got %v", err)
+	}
+
+	if _, err := pe.Decrypt("not-a-price"); err != ErrInvalidEncryptedPrice {
+		t.Errorf("expected invalid price error, got %v", err)
+	}
+
+	if _, err := NewHMACPriceEncrypter(nil, []byte("key")); err == nil {
+		t.Error("expected error for missing encryption key")
+	}
+}
@@ -0,0 +1,227 @@
+package exchange
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/floors"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// RateLimitConfig controls how bidder rate limits shape traffic
+// The last ReserveFraction of each limit is kept for high-value requests, those with a deal the
+// publisher has configured or a server-side floor rule of at least HighValueFloor, so a bidder
+// near its cap still sees the best traffic.
+type RateLimitConfig struct {
+	ReserveFraction float64 // Share of each limit only high-value requests may use [0-1)
+	HighValueFloor  float64 // Floor rule (CPM) at or above which a request is high value
+}
+
+// DefaultRateLimitConfig returns the default rate limit configuration
+func DefaultRateLimitConfig() *RateLimitConfig {
+	return &RateLimitConfig{
+		ReserveFraction: 0.2,
+		HighValueFloor:  1.0,
+	}
+}
+
+// DailyCounter counts requests per bidder and day, shared across instances (e.g. *redis.Client)
+type DailyCounter interface {
+	IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+}
+
+// Throttle reasons, used as the limit label in metrics
+const (
+	throttleQPS        = "qps"
+	throttleDaily      = "daily"
+	throttleConcurrent = "concurrent"
+)
+
+const (
+	dailyCounterPrefix = "tne_catalyst:bidder_daily:"
+	dailyCounterTTL    = 48 * time.Hour
+)
+
+// rateLimitedAdapter is implemented by adapters with their own rate limits (e.g. ortb.GenericAdapter)
+type rateLimitedAdapter interface {
+	GetRateLimits() adapters.RateLimits
+}
+
+// bidderRateLimits returns the rate limits enforced for a bidder
+// Limits from the adapter config take precedence over the adapter's own.
+func bidderRateLimits(awi adapters.AdapterWithInfo) adapters.RateLimits {
+	if !awi.Info.RateLimits.IsZero() {
+		return awi.Info.RateLimits
+	}
+	if rl, ok := awi.Adapter.(rateLimitedAdapter); ok {
+		return rl.GetRateLimits()
+	}
+	return adapters.RateLimits{}
+}
+
+// bidderLimiterState tracks one bidder's QPS tokens, requests in flight and daily count
+type bidderLimiterState struct {
+	tokens     float64
+	refilledAt time.Time
+	inFlight   int
+	day        string
+	dailyCount int64 // used when there is no shared DailyCounter
+}
+
+// rateLimiter enforces per-bidder QPS (token bucket), daily and concurrency caps
+type rateLimiter struct {
+	config  *RateLimitConfig
+	now     func() time.Time
+	mu      sync.Mutex
+	bidders map[string]*bidderLimiterState
+	counter DailyCounter // nil counts per instance
+}
+
+func newRateLimiter(config *RateLimitConfig) *rateLimiter {
+	return &rateLimiter{
+		config:  config,
+		now:     time.Now,
+		bidders: make(map[string]*bidderLimiterState),
+	}
+}
+
+// setCounter shares daily counts through the counter; nil counts per instance
+func (r *rateLimiter) setCounter(counter DailyCounter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counter = counter
+}
+
+// isHighValue reports whether a request may use the reserved part of a bidder's limits
+// Only server-side data counts: deals the publisher has configured floors for, and floor rules
+// from stored or fetched rule sets. imp.bidfloor and imp.pmp are caller-controlled and would
+// let any request claim the reserve.
+func (r *rateLimiter) isHighValue(req *openrtb.BidRequest, dealFloors map[string]float64, floorResult *floors.Result) bool {
+	for i := range req.Imp {
+		imp := &req.Imp[i]
+		if imp.PMP == nil {
+			continue
+		}
+		for _, deal := range imp.PMP.Deals {
+			if _, ok := dealFloors[deal.ID]; ok && deal.ID != "" {
+				return true
+			}
+		}
+	}
+
+	if r.config.HighValueFloor <= 0 || floorResult == nil || !floorResult.Applied {
+		return false
+	}
+	if floorResult.Location != floors.LocationAccount && floorResult.Location != floors.LocationFetch {
+		return false
+	}
+	// FloorRuleValue is the matched rule before the request's floorMin is applied
+	for _, imp := range floorResult.Imps {
+		if imp.FloorRuleValue >= r.config.HighValueFloor {
+			return true
+		}
+	}
+	return false
+}
+
+// reserved returns the part of a limit a request may not use
+func (r *rateLimiter) reserved(limit int, highValue bool) int {
+	if highValue {
+		return 0
+	}
+	return int(float64(limit) * r.config.ReserveFraction)
+}
+
+// acquire admits a request to the bidder or returns the limit it hit
+// An admitted request must call release when the bidder call completes.
+func (r *rateLimiter) acquire(ctx context.Context, bidderCode string, limits adapters.RateLimits, highValue bool) (release func(), limit string) {
+	if limits.IsZero() {
+		return func() {}, ""
+	}
+
+	r.mu.Lock()
+	now := r.now()
+	state, ok := r.bidders[bidderCode]
+	if !ok {
+		state = &bidderLimiterState{tokens: float64(limits.QPS), refilledAt: now}
+		r.bidders[bidderCode] = state
+	}
+
+	if limits.Concurrent > 0 && state.inFlight >= limits.Concurrent-r.reserved(limits.Concurrent, highValue) {
+		r.mu.Unlock()
+		return nil, throttleConcurrent
+	}
+
+	if limits.QPS > 0 {
+		burst := float64(limits.QPS)
+		state.tokens += now.Sub(state.refilledAt).Seconds() * burst
+		if state.tokens > burst {
+			state.tokens = burst
+		}
+		state.refilledAt = now
+		if state.tokens < float64(1+r.reserved(limits.QPS, highValue)) {
+			r.mu.Unlock()
+			return nil, throttleQPS
+		}
+		state.tokens--
+	}
+	state.inFlight++
+	counter := r.counter
+	r.mu.Unlock()
+
+	undo := func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		state.inFlight--
+		if limits.QPS > 0 {
+			state.tokens++
+		}
+	}
+
+	if limits.Daily > 0 && !r.countDaily(ctx, counter, bidderCode, state, now, limits.Daily-r.reserved(limits.Daily, highValue)) {
+		undo()
+		return nil, throttleDaily
+	}
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		state.inFlight--
+	}, ""
+}
+
+// countDaily counts the request against the bidder's daily limit, returning false when over it
+// A shared counter that fails falls back to the per-instance count.
+func (r *rateLimiter) countDaily(ctx context.Context, counter DailyCounter, bidderCode string, state *bidderLimiterState, now time.Time, allowed int) bool {
+	day := now.UTC().Format("2006-01-02")
+
+	if counter != nil {
+		key := dailyCounterPrefix + bidderCode + ":" + day
+		count, err := counter.IncrBy(ctx, key, 1, dailyCounterTTL)
+		if err == nil {
+			if count <= int64(allowed) {
+				return true
+			}
+			if _, err := counter.IncrBy(ctx, key, -1, dailyCounterTTL); err != nil {
+				logger.Log.Warn().Err(err).Str("bidder", bidderCode).Msg("Failed to release bidder daily count")
+			}
+			return false
+		}
+		logger.Log.Warn().Err(err).Str("bidder", bidderCode).Msg("Shared bidder daily count failed, counting locally")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if state.day != day {
+		state.day = day
+		state.dailyCount = 0
+	}
+	if state.dailyCount >= int64(allowed) {
+		return false
+	}
+	state.dailyCount++
+	return true
+}
@@ -0,0 +1,317 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/floors"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/redis"
+)
+
+// fixedClockLimiter returns a rate limiter whose clock only moves when the test advances it
+func fixedClockLimiter(config *RateLimitConfig) (*rateLimiter, *time.Time) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	r := newRateLimiter(config)
+	r.now = func() time.Time { return now }
+	return r, &now
+}
+
+// admit acquires n times and returns how many were admitted and the last limit hit
+func admit(r *rateLimiter, n int, limits adapters.RateLimits, highValue bool) (int, string) {
+	admitted, last := 0, ""
+	for i := 0; i < n; i++ {
+		if _, limit := r.acquire(context.Background(), "rubicon", limits, highValue); limit != "" {
+			last = limit
+			continue
+		}
+		admitted++
+	}
+	return admitted, last
+}
+
+func TestRateLimiter_QPS(t *testing.T) {
+	r, now := fixedClockLimiter(&RateLimitConfig{ReserveFraction: 0.2})
+	limits := adapters.RateLimits{QPS: 10}
+
+	// Low-value requests may use 8 of the 10 tokens, high-value requests the rest
+	if n, limit := admit(r, 10, limits, false); n != 8 || limit != throttleQPS {
+		t.Errorf("expected 8 low-value requests admitted, got %d (%s)", n, limit)
+	}
+	if n, _ := admit(r, 3, limits, true); n != 2 {
+		t.Errorf("expected 2 high-value requests admitted from the reserve, got %d", n)
+	}
+
+	// Tokens refill at the QPS rate
+	*now = now.Add(500 * time.Millisecond)
+	if n, _ := admit(r, 10, limits, true); n != 5 {
+		t.Errorf("expected 5 requests admitted after half a second, got %d", n)
+	}
+}
+
+func TestRateLimiter_Concurrent(t *testing.T) {
+	r, _ := fixedClockLimiter(&RateLimitConfig{ReserveFraction: 0.25})
+	limits := adapters.RateLimits{Concurrent: 4}
+
+	var releases []func()
+	for i := 0; i < 3; i++ {
+		release, limit := r.acquire(context.Background(), "rubicon", limits, false)
+		if limit != "" {
+			t.Fatalf("request %d unexpectedly throttled: %s", i, limit)
+		}
+		releases = append(releases, release)
+	}
+	if _, limit := r.acquire(context.Background(), "rubicon", limits, false); limit != throttleConcurrent {
+		t.Errorf("expected low-value request throttled at the reserve, got %q", limit)
+	}
+	release, limit := r.acquire(context.Background(), "rubicon", limits, true)
+	if limit != "" {
+		t.Fatalf("expected high-value request to use the reserve, got %q", limit)
+	}
+	if _, limit := r.acquire(context.Background(), "rubicon", limits, true); limit != throttleConcurrent {
+		t.Errorf("expected request throttled at the limit, got %q", limit)
+	}
+
+	release()
+	releases[0]()
+	if _, limit := r.acquire(context.Background(), "rubicon", limits, false); limit != "" {
+		t.Errorf("expected request admitted after release, got %q", limit)
+	}
+}
+
+func TestRateLimiter_DailyLocal(t *testing.T) {
+	r, now := fixedClockLimiter(&RateLimitConfig{ReserveFraction: 0.2})
+	limits := adapters.RateLimits{Daily: 10}
+
+	if n, limit := admit(r, 10, limits, false); n != 8 || limit != throttleDaily {
+		t.Errorf("expected 8 low-value requests admitted, got %d (%s)", n, limit)
+	}
+	if n, _ := admit(r, 5, limits, true); n != 2 {
+		t.Errorf("expected 2 high-value requests admitted, got %d", n)
+	}
+
+	// The count resets at the start of the UTC day
+	*now = now.Add(12 * time.Hour)
+	if n, _ := admit(r, 5, limits, false); n != 5 {
+		t.Errorf("expected requests admitted the next day, got %d", n)
+	}
+}
+
+func TestRateLimiter_DailyShared(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mr.Close()
+	client, err := redis.New("redis://" + mr.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// Two exchange instances share the count
+	limits := adapters.RateLimits{Daily: 6}
+	first, _ := fixedClockLimiter(&RateLimitConfig{})
+	second, _ := fixedClockLimiter(&RateLimitConfig{})
+	first.setCounter(client)
+	second.setCounter(client)
+
+	if n, _ := admit(first, 4, limits, false); n != 4 {
+		t.Errorf("expected 4 requests admitted on the first instance, got %d", n)
+	}
+	if n, limit := admit(second, 4, limits, false); n != 2 || limit != throttleDaily {
+		t.Errorf("expected 2 requests admitted on the second instance, got %d (%s)", n, limit)
+	}
+	// Throttled requests are not counted
+	if count, _ := mr.Get("tne_catalyst:bidder_daily:rubicon:2026-10-18"); count != "6" {
+		t.Errorf("expected shared count 6, got %s", count)
+	}
+}
+
+// failingCounter is a shared counter that is unavailable
+type failingCounter struct{}
+
+func (failingCounter) IncrBy(context.Context, string, int64, time.Duration) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestRateLimiter_DailyCounterFailure(t *testing.T) {
+	r, _ := fixedClockLimiter(&RateLimitConfig{})
+	r.setCounter(failingCounter{})
+
+	if n, limit := admit(r, 5, adapters.RateLimits{Daily: 3}, false); n != 3 || limit != throttleDaily {
+		t.Errorf("expected the local count to enforce the limit, got %d (%s)", n, limit)
+	}
+}
+
+func TestRateLimiter_IsHighValue(t *testing.T) {
+	r := newRateLimiter(&RateLimitConfig{HighValueFloor: 2.0})
+	dealImp := []openrtb.Imp{{ID: "1", PMP: &openrtb.PMP{Deals: []openrtb.Deal{{ID: "deal-1"}}}}}
+	ruleFloors := func(location string, value float64) *floors.Result {
+		return &floors.Result{
+			Applied:  true,
+			Location: location,
+			Imps:     map[string]*floors.ImpFloor{"1": {FloorRule: "banner", FloorRuleValue: value, FloorValue: value}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		imps        []openrtb.Imp
+		dealFloors  map[string]float64
+		floorResult *floors.Result
+		want        bool
+	}{
+		{"no floor", []openrtb.Imp{{ID: "1"}}, nil, nil, false},
+		{"request floor", []openrtb.Imp{{ID: "1", BidFloor: 5.0}}, nil, nil, false},
+		{"request deal", dealImp, nil, nil, false},
+		{"publisher deal", dealImp, map[string]float64{"deal-1": 0}, nil, true},
+		{"other publisher deal", dealImp, map[string]float64{"deal-2": 3.0}, nil, false},
+		{"low stored rule", []openrtb.Imp{{ID: "1"}}, nil, ruleFloors(floors.LocationAccount, 0.5), false},
+		{"high stored rule", []openrtb.Imp{{ID: "1"}}, nil, ruleFloors(floors.LocationAccount, 2.0), true},
+		{"high fetched rule", []openrtb.Imp{{ID: "1"}}, nil, ruleFloors(floors.LocationFetch, 3.0), true},
+		{"high request rule", []openrtb.Imp{{ID: "1"}}, nil, ruleFloors(floors.LocationRequest, 3.0), false},
+		{"floorMin only", []openrtb.Imp{{ID: "1"}}, nil, &floors.Result{
+			Applied:  true,
+			Location: floors.LocationAccount,
+			Imps:     map[string]*floors.ImpFloor{"1": {FloorValue: 5.0, FloorMin: 5.0}},
+		}, false},
+	}
+	for _, tt := range tests {
+		if got := r.isHighValue(&openrtb.BidRequest{Imp: tt.imps}, tt.dealFloors, tt.floorResult); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// rateLimitedMockAdapter is a mock adapter with its own rate limits
+type rateLimitedMockAdapter struct {
+	mockAdapter
+	limits adapters.RateLimits
+}
+
+func (m *rateLimitedMockAdapter) GetRateLimits() adapters.RateLimits {
+	return m.limits
+}
+
+func TestBidderRateLimits(t *testing.T) {
+	own := &rateLimitedMockAdapter{limits: adapters.RateLimits{QPS: 50}}
+
+	if got := bidderRateLimits(adapters.AdapterWithInfo{Adapter: own}); got.QPS != 50 {
+		t.Errorf("expected the adapter's own limits, got %+v", got)
+	}
+	configured := adapters.AdapterWithInfo{Adapter: own, Info: adapters.BidderInfo{RateLimits: adapters.RateLimits{Daily: 1000}}}
+	if got := bidderRateLimits(configured); got != (adapters.RateLimits{Daily: 1000}) {
+		t.Errorf("expected configured limits to take precedence, got %+v", got)
+	}
+	if got := bidderRateLimits(adapters.AdapterWithInfo{Adapter: &mockAdapter{}}); !got.IsZero() {
+		t.Errorf("expected no limits, got %+v", got)
+	}
+}
+
+// throttleMetrics captures throttle and seat non-bid metrics
+type throttleMetrics struct {
+	nonBidMetrics
+	throttled map[string]int // bidder|limit -> count
+}
+
+func (m *throttleMetrics) RecordBidderThrottled(bidder, limit string) {
+	m.throttled[bidder+"|"+limit]++
+}
+
+func TestRunAuction_ThrottledBidder(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("rubicon", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "r1", ImpID: "imp1", Price: 1.50, AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true, RateLimits: adapters.RateLimits{Daily: 1}})
+
+	ex := New(registry, &Config{
+		DefaultTimeout: 500 * time.Millisecond,
+		RateLimit:      &RateLimitConfig{},
+	})
+	metrics := &throttleMetrics{nonBidMetrics: nonBidMetrics{nonBids: make(map[string]int)}, throttled: make(map[string]int)}
+	ex.SetMetrics(metrics)
+
+	run := func() *AuctionResponse {
+		resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+			BidRequest: &openrtb.BidRequest{
+				ID:   "throttle-auction",
+				Site: testSite(),
+				Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+				Ext:  json.RawMessage(`{"prebid":{"returnallbidstatus":true}}`),
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp
+	}
+
+	if resp := run(); len(resp.BidResponse.SeatBid) != 1 {
+		t.Fatalf("expected rubicon to bid before reaching its limit, got %+v", resp.BidResponse.SeatBid)
+	}
+
+	resp := run()
+	if len(resp.BidResponse.SeatBid) != 0 {
+		t.Errorf("expected no bids once throttled, got %+v", resp.BidResponse.SeatBid)
+	}
+	if nonBids := resp.DebugInfo.NonBids["rubicon"]; len(nonBids) != 1 || nonBids[0].StatusCode != openrtb.NonBidThrottled {
+		t.Errorf("expected throttled non-bid, got %+v", nonBids)
+	}
+	if metrics.throttled["rubicon|daily"] != 1 {
+		t.Errorf("expected daily throttle metric, got %v", metrics.throttled)
+	}
+	if metrics.nonBids["rubicon|throttled"] != 1 {
+		t.Errorf("expected throttled seat non-bid metric, got %v", metrics.nonBids)
+	}
+}
+
+func TestRunAuction_ThrottledAliasSharesCoreLimits(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("rubicon", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "r1", ImpID: "imp1", Price: 1.50, AdM: "ad", W: 300, H: 250}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true, RateLimits: adapters.RateLimits{Daily: 1}})
+
+	ex := New(registry, &Config{
+		DefaultTimeout: 500 * time.Millisecond,
+		RateLimit:      &RateLimitConfig{},
+	})
+
+	run := func(bidder string) *AuctionResponse {
+		resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+			BidRequest: &openrtb.BidRequest{
+				ID:   "throttle-alias-auction",
+				Site: testSite(),
+				Imp: []openrtb.Imp{{
+					ID:     "imp1",
+					Banner: &openrtb.Banner{W: 300, H: 250},
+					Ext:    json.RawMessage(`{"prebid":{"bidder":{"` + bidder + `":{}}}}`),
+				}},
+				Ext: json.RawMessage(`{"prebid":{"returnallbidstatus":true,"aliases":{"rubi2":"rubicon"}}}`),
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp
+	}
+
+	if resp := run("rubicon"); len(resp.BidResponse.SeatBid) != 1 {
+		t.Fatalf("expected rubicon to bid before reaching its limit, got %+v", resp.BidResponse.SeatBid)
+	}
+
+	// The alias calls the same demand, so it must not get a fresh daily budget
+	resp := run("rubi2")
+	if len(resp.BidResponse.SeatBid) != 0 {
+		t.Errorf("expected the alias to be throttled with its core bidder, got %+v", resp.BidResponse.SeatBid)
+	}
+	if nonBids := resp.DebugInfo.NonBids["rubi2"]; len(nonBids) != 1 || nonBids[0].StatusCode != openrtb.NonBidThrottled {
+		t.Errorf("expected throttled non-bid for the alias, got %+v", nonBids)
+	}
+}
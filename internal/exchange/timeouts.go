@@ -0,0 +1,191 @@
+package exchange
+
+import (
+	"context"
+	"math"
+	"net/http/httptrace"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+)
+
+// TMaxConfig controls the tmax sent to each bidder
+// Outgoing tmax is the time left before the bidder's deadline, minus the upstream buffer and
+// the bidder's network latency, so a bidder that answers within tmax still arrives in time.
+type TMaxConfig struct {
+	UpstreamBuffer time.Duration // Reserved to finish the auction and respond upstream
+	NetworkBuffer  time.Duration // Network latency assumed until a bidder's RTT has been observed
+	RTTPercentile  float64       // Observed connection RTT percentile used as network latency (0-1]
+	RTTSamples     int           // Connection RTT samples kept per bidder
+	MinTMax        time.Duration // Lowest tmax sent to a bidder
+}
+
+// DefaultTMaxConfig returns the default tmax configuration
+func DefaultTMaxConfig() *TMaxConfig {
+	return &TMaxConfig{
+		UpstreamBuffer: 50 * time.Millisecond,
+		NetworkBuffer:  20 * time.Millisecond,
+		RTTPercentile:  0.9,
+		RTTSamples:     100,
+		MinTMax:        10 * time.Millisecond,
+	}
+}
+
+// timeoutAdapter is implemented by adapters with their own configured timeout (e.g. ortb.GenericAdapter)
+type timeoutAdapter interface {
+	GetTimeout() time.Duration
+}
+
+// bidderTimeout returns the timeout configured for a bidder, or 0 when none is set
+// Config.BidderTimeouts (bidders.timeout_ms) takes precedence over the alias timeout,
+// which takes precedence over the adapter's own endpoint timeout.
+func (e *Exchange) bidderTimeout(bidderCode string, awi adapters.AdapterWithInfo) time.Duration {
+	if t := e.config.BidderTimeouts[bidderCode]; t > 0 {
+		return t
+	}
+	if awi.Info.Timeout > 0 {
+		return awi.Info.Timeout
+	}
+	if ta, ok := awi.Adapter.(timeoutAdapter); ok {
+		return ta.GetTimeout()
+	}
+	return 0
+}
+
+// bidderContext limits the auction context to the bidder's configured timeout
+// Returns the context, the effective timeout and a cancel function that must be called.
+func (e *Exchange) bidderContext(ctx context.Context, bidderCode string, awi adapters.AdapterWithInfo, timeout time.Duration) (context.Context, time.Duration, context.CancelFunc) {
+	if t := e.bidderTimeout(bidderCode, awi); t > 0 && t < timeout {
+		bidderCtx, cancel := context.WithTimeout(ctx, t)
+		return bidderCtx, t, cancel
+	}
+	return ctx, timeout, func() {}
+}
+
+// outgoingTMax returns the tmax in milliseconds to send to a bidder
+func (e *Exchange) outgoingTMax(ctx context.Context, bidderCode string, timeout time.Duration) int {
+	remaining := timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		remaining = time.Until(deadline)
+	}
+
+	cfg := e.tmaxConfig()
+	network, ok := e.rtt.percentile(bidderCode, cfg.RTTPercentile)
+	if !ok {
+		network = cfg.NetworkBuffer
+	}
+
+	tmax := remaining - cfg.UpstreamBuffer - network
+	if tmax < cfg.MinTMax {
+		tmax = cfg.MinTMax
+	}
+	return int(tmax / time.Millisecond)
+}
+
+// tmaxConfig returns the tmax configuration with defaults applied
+func (e *Exchange) tmaxConfig() *TMaxConfig {
+	if e.config.TMax == nil {
+		return DefaultTMaxConfig()
+	}
+	return e.config.TMax
+}
+
+// rttTracker keeps a window of observed connection round-trip times per bidder
+type rttTracker struct {
+	mu      sync.RWMutex
+	size    int
+	windows map[string]*rttWindow
+}
+
+// rttWindow is a fixed-size ring of RTT samples
+type rttWindow struct {
+	samples []time.Duration
+	next    int
+}
+
+// newRTTTracker creates an RTT tracker keeping size samples per bidder
+func newRTTTracker(size int) *rttTracker {
+	if size <= 0 {
+		size = DefaultTMaxConfig().RTTSamples
+	}
+	return &rttTracker{size: size, windows: make(map[string]*rttWindow)}
+}
+
+// observe records an RTT sample for a bidder
+func (t *rttTracker) observe(bidderCode string, rtt time.Duration) {
+	if t == nil || rtt <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	w, ok := t.windows[bidderCode]
+	if !ok {
+		w = &rttWindow{samples: make([]time.Duration, 0, t.size)}
+		t.windows[bidderCode] = w
+	}
+	if len(w.samples) < t.size {
+		w.samples = append(w.samples, rtt)
+		return
+	}
+	w.samples[w.next] = rtt
+	w.next = (w.next + 1) % t.size
+}
+
+// percentile returns the p-th percentile (0-1] of a bidder's RTT samples
+// Returns false when no samples have been observed
+func (t *rttTracker) percentile(bidderCode string, p float64) (time.Duration, bool) {
+	if t == nil {
+		return 0, false
+	}
+	t.mu.RLock()
+	w, ok := t.windows[bidderCode]
+	if !ok || len(w.samples) == 0 {
+		t.mu.RUnlock()
+		return 0, false
+	}
+	sorted := make([]time.Duration, len(w.samples))
+	copy(sorted, w.samples)
+	t.mu.RUnlock()
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	if p <= 0 || p > 1 {
+		p = 1
+	}
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx], true
+}
+
+// withRTTTrace returns a context that records connection RTT for the bidder's HTTP requests
+// The TCP connect time of new connections approximates one network round trip; reused
+// connections add no sample.
+func (t *rttTracker) withRTTTrace(ctx context.Context, bidderCode string) context.Context {
+	if t == nil {
+		return ctx
+	}
+
+	var mu sync.Mutex
+	starts := make(map[string]time.Time)
+	trace := &httptrace.ClientTrace{
+		ConnectStart: func(network, addr string) {
+			mu.Lock()
+			starts[network+addr] = time.Now()
+			mu.Unlock()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			mu.Lock()
+			start, ok := starts[network+addr]
+			delete(starts, network+addr)
+			mu.Unlock()
+			if ok && err == nil {
+				t.observe(bidderCode, time.Since(start))
+			}
+		},
+	}
+	return httptrace.WithClientTrace(ctx, trace)
+}
@@ -0,0 +1,176 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// tmaxAdapter records the tmax of the requests it receives
+type tmaxAdapter struct {
+	mockAdapter
+	mu      sync.Mutex
+	tmax    int
+	timeout time.Duration
+}
+
+func (a *tmaxAdapter) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	a.mu.Lock()
+	a.tmax = request.TMax
+	a.mu.Unlock()
+	return a.mockAdapter.MakeRequests(request, reqInfo)
+}
+
+func (a *tmaxAdapter) GetTimeout() time.Duration {
+	return a.timeout
+}
+
+func TestRTTTracker_Percentile(t *testing.T) {
+	tracker := newRTTTracker(10)
+	if _, ok := tracker.percentile("appnexus", 0.9); ok {
+		t.Error("expected no percentile without samples")
+	}
+
+	for i := 1; i <= 10; i++ {
+		tracker.observe("appnexus", time.Duration(i)*time.Millisecond)
+	}
+	if p, _ := tracker.percentile("appnexus", 0.9); p != 9*time.Millisecond {
+		t.Errorf("expected p90 9ms, got %v", p)
+	}
+	if p, _ := tracker.percentile("appnexus", 0.5); p != 5*time.Millisecond {
+		t.Errorf("expected p50 5ms, got %v", p)
+	}
+
+	// The window keeps the most recent samples
+	for i := 0; i < 10; i++ {
+		tracker.observe("appnexus", 100*time.Millisecond)
+	}
+	if p, _ := tracker.percentile("appnexus", 0.1); p != 100*time.Millisecond {
+		t.Errorf("expected old samples to be replaced, got %v", p)
+	}
+
+	var nilTracker *rttTracker
+	nilTracker.observe("appnexus", time.Millisecond)
+	if _, ok := nilTracker.percentile("appnexus", 0.9); ok {
+		t.Error("expected nil tracker to have no samples")
+	}
+}
+
+func TestRTTTracker_Trace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tracker := newRTTTracker(10)
+	client := adapters.NewHTTPClient(time.Second)
+	ctx := tracker.withRTTTrace(context.Background(), "appnexus")
+	if _, err := client.Do(ctx, &adapters.RequestData{Method: "POST", URI: server.URL, Body: []byte("{}")}, time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := tracker.percentile("appnexus", 0.9); !ok {
+		t.Error("expected connection RTT to be observed")
+	}
+}
+
+func TestBidderTimeout(t *testing.T) {
+	adapter := &tmaxAdapter{timeout: 300 * time.Millisecond}
+	ex := New(adapters.NewRegistry(), &Config{
+		BidderTimeouts: map[string]time.Duration{"dbbidder": 200 * time.Millisecond},
+	})
+
+	tests := []struct {
+		name   string
+		bidder string
+		awi    adapters.AdapterWithInfo
+		want   time.Duration
+	}{
+		{"config timeout wins", "dbbidder", adapters.AdapterWithInfo{Adapter: adapter, Info: adapters.BidderInfo{Timeout: 100 * time.Millisecond}}, 200 * time.Millisecond},
+		{"alias timeout", "alias", adapters.AdapterWithInfo{Adapter: adapter, Info: adapters.BidderInfo{Timeout: 100 * time.Millisecond}}, 100 * time.Millisecond},
+		{"adapter timeout", "ortb", adapters.AdapterWithInfo{Adapter: adapter}, 300 * time.Millisecond},
+		{"no timeout", "plain", adapters.AdapterWithInfo{Adapter: &mockAdapter{}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ex.bidderTimeout(tt.bidder, tt.awi); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// A bidder timeout longer than the auction does not extend it
+	_, timeout, cancel := ex.bidderContext(context.Background(), "ortb", adapters.AdapterWithInfo{Adapter: adapter}, 250*time.Millisecond)
+	defer cancel()
+	if timeout != 250*time.Millisecond {
+		t.Errorf("expected auction timeout, got %v", timeout)
+	}
+}
+
+func TestOutgoingTMax(t *testing.T) {
+	ex := New(adapters.NewRegistry(), &Config{
+		TMax: &TMaxConfig{UpstreamBuffer: 50 * time.Millisecond, NetworkBuffer: 20 * time.Millisecond},
+	})
+
+	// Without a deadline the timeout is the budget; no RTT observed uses the network buffer
+	if tmax := ex.outgoingTMax(context.Background(), "appnexus", 500*time.Millisecond); tmax != 430 {
+		t.Errorf("expected 430, got %d", tmax)
+	}
+
+	// Observed RTT replaces the network buffer
+	for i := 0; i < 10; i++ {
+		ex.rtt.observe("appnexus", 40*time.Millisecond)
+	}
+	if tmax := ex.outgoingTMax(context.Background(), "appnexus", 500*time.Millisecond); tmax != 410 {
+		t.Errorf("expected 410, got %d", tmax)
+	}
+
+	// The remaining deadline is used when set
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if tmax := ex.outgoingTMax(ctx, "other", 500*time.Millisecond); tmax > 130 || tmax < 100 {
+		t.Errorf("expected about 130 from the deadline, got %d", tmax)
+	}
+
+	// tmax never drops below the minimum
+	if tmax := ex.outgoingTMax(context.Background(), "other", 30*time.Millisecond); tmax != 10 {
+		t.Errorf("expected minimum tmax 10, got %d", tmax)
+	}
+}
+
+func TestRunAuction_PerBidderTMax(t *testing.T) {
+	fast := &tmaxAdapter{}
+	slow := &tmaxAdapter{}
+	registry := adapters.NewRegistry()
+	registry.Register("fast", fast, adapters.BidderInfo{Enabled: true})
+	registry.Register("slow", slow, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{
+		DefaultTimeout: 800 * time.Millisecond,
+		BidderTimeouts: map[string]time.Duration{"fast": 200 * time.Millisecond},
+		TMax:           &TMaxConfig{UpstreamBuffer: 50 * time.Millisecond, NetworkBuffer: 20 * time.Millisecond},
+	})
+
+	if _, err := ex.RunAuction(context.Background(), &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "tmax-auction",
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+		},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fast.tmax <= 0 || fast.tmax > 130 {
+		t.Errorf("expected fast bidder tmax at most 130, got %d", fast.tmax)
+	}
+	if slow.tmax <= 130 || slow.tmax > 730 {
+		t.Errorf("expected slow bidder tmax near 730, got %d", slow.tmax)
+	}
+}
@@ -0,0 +1,130 @@
+package exchange
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+	"github.com/thenexusengine/tne_springwire/pkg/vast"
+)
+
+// MacroVASTEvent is replaced with the tracking event name in VASTConfig.TrackingURL
+const MacroVASTEvent = "${VAST_EVENT}"
+
+// VASTConfig controls how the VAST markup of video bids is checked and modified
+// Impression and tracking URLs are only added to bidders that allow VAST modification
+// (BidderInfo.ModifyingVastXmlAllowed) and to the wrappers built for nurl-only bids.
+// Auction macros (${AUCTION_ID}, ${AUCTION_PRICE}, ...) in the URLs are expanded with the bid's.
+type VASTConfig struct {
+	Validate       bool     // Reject video bids whose VAST is malformed or incomplete
+	ImpressionURL  string   // Added as an Impression of every ad
+	TrackingURL    string   // Added for each of TrackingEvents, with ${VAST_EVENT} set to the event
+	TrackingEvents []string // Linear tracking events reported to TrackingURL
+}
+
+// DefaultVASTConfig returns the default VAST configuration
+func DefaultVASTConfig() *VASTConfig {
+	return &VASTConfig{
+		Validate:       true,
+		TrackingEvents: []string{"start", "firstQuartile", "midpoint", "thirdQuartile", "complete"},
+	}
+}
+
+// trackers returns the URLs to inject, or an empty Trackers when none are configured
+func (c *VASTConfig) trackers() vast.Trackers {
+	var t vast.Trackers
+	if c.ImpressionURL != "" {
+		t.Impressions = []string{c.ImpressionURL}
+	}
+	if c.TrackingURL != "" && len(c.TrackingEvents) > 0 {
+		t.Events = make(map[string][]string, len(c.TrackingEvents))
+		for _, event := range c.TrackingEvents {
+			t.Events[event] = []string{strings.ReplaceAll(c.TrackingURL, MacroVASTEvent, event)}
+		}
+	}
+	return t
+}
+
+// prepareVideoBid wraps, validates and annotates the VAST of a video bid
+// Bids with only a nurl get a wrapper VAST loading the nurl. The bid's duration and primary
+// category are filled in from the VAST and bid.cat when the adapter did not set them, and
+// our trackers are injected when allowed. The changes are made on a copy of the bid, which
+// is returned, so the adapter's bid keeps the bidder's markup. Returns an error when
+// validation is enabled and the VAST is invalid.
+func (e *Exchange) prepareVideoBid(ctx context.Context, tb *adapters.TypedBid, bidderCode string) (*adapters.TypedBid, *BidValidationError) {
+	if tb.BidType != adapters.BidTypeVideo {
+		return tb, nil
+	}
+	cfg := e.config.VAST
+	typed := *tb
+	bid := *tb.Bid
+	typed.Bid = &bid
+
+	// Our own wrapper can always carry our trackers
+	modifiable := false
+	if bid.AdM == "" && bid.NURL != "" {
+		bid.AdM = vast.WrapURL(adapters.PlatformSeatName, bid.NURL)
+		modifiable = true
+	} else if awi, ok := e.lookupBidder(ctx, bidderCode); ok {
+		modifiable = awi.Info.ModifyingVastXmlAllowed
+	}
+
+	doc, err := vast.Parse(bid.AdM)
+	if err == nil && cfg.Validate {
+		err = doc.Validate()
+	}
+	if err != nil {
+		if cfg.Validate {
+			return tb, &BidValidationError{
+				BidID:      bid.ID,
+				ImpID:      bid.ImpID,
+				BidderCode: bidderCode,
+				Reason:     "invalid VAST: " + err.Error(),
+				StatusCode: openrtb.NonBidInvalidBid,
+			}
+		}
+		return &typed, nil
+	}
+
+	video := adapters.BidVideo{}
+	if tb.BidVideo != nil {
+		video = *tb.BidVideo
+	}
+	if video.Duration == 0 {
+		if d, ok := doc.Duration(); ok {
+			video.Duration = int(math.Round(d.Seconds()))
+		}
+	}
+	if video.PrimaryCategory == "" && len(bid.Cat) > 0 {
+		video.PrimaryCategory = bid.Cat[0]
+	}
+	typed.BidVideo = &video
+
+	if modifiable {
+		adm, err := vast.InjectTrackers(bid.AdM, cfg.trackers())
+		if err != nil {
+			logger.Log.Debug().
+				Str("bidder", bidderCode).
+				Str("bidID", bid.ID).
+				Err(err).
+				Msg("VAST tracker injection failed, keeping bidder markup")
+		} else {
+			bid.AdM = adm
+		}
+	}
+	return &typed, nil
+}
+
+// bidVideoExt returns the ext.prebid.video of a bid, or nil when it has no video info
+func bidVideoExt(tb *adapters.TypedBid) *openrtb.ExtBidPrebidVideo {
+	if tb.BidVideo == nil || (tb.BidVideo.Duration == 0 && tb.BidVideo.PrimaryCategory == "") {
+		return nil
+	}
+	return &openrtb.ExtBidPrebidVideo{
+		Duration:        tb.BidVideo.Duration,
+		PrimaryCategory: tb.BidVideo.PrimaryCategory,
+	}
+}
@@ -0,0 +1,198 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/vast"
+)
+
+const testInlineVAST = `<VAST version="3.0"><Ad><InLine><AdSystem>dsp</AdSystem><Impression><![CDATA[https://dsp.example.com/imp]]></Impression>` +
+	`<Creatives><Creative><Linear><Duration>00:00:15</Duration><MediaFiles><MediaFile><![CDATA[https://cdn.example.com/a.mp4]]></MediaFile></MediaFiles></Linear></Creative></Creatives></InLine></Ad></VAST>`
+
+func TestVASTConfig_Trackers(t *testing.T) {
+	if tr := DefaultVASTConfig().trackers(); len(tr.Impressions) != 0 || len(tr.Events) != 0 {
+		t.Errorf("expected no trackers by default, got %+v", tr)
+	}
+
+	cfg := &VASTConfig{
+		ImpressionURL:  "https://ex.example.com/imp?a=${AUCTION_ID}",
+		TrackingURL:    "https://ex.example.com/ev?e=${VAST_EVENT}",
+		TrackingEvents: []string{"start", "complete"},
+	}
+	tr := cfg.trackers()
+	if len(tr.Impressions) != 1 || tr.Impressions[0] != cfg.ImpressionURL {
+		t.Errorf("unexpected impressions: %v", tr.Impressions)
+	}
+	if tr.Events["start"][0] != "https://ex.example.com/ev?e=start" || tr.Events["complete"][0] != "https://ex.example.com/ev?e=complete" {
+		t.Errorf("unexpected events: %v", tr.Events)
+	}
+}
+
+func TestPrepareVideoBid(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("appnexus", &mockAdapter{}, adapters.BidderInfo{Enabled: true, ModifyingVastXmlAllowed: true})
+	registry.Register("rubicon", &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{VAST: &VASTConfig{
+		Validate:       true,
+		ImpressionURL:  "https://ex.example.com/imp?bid=${AUCTION_BID_ID}",
+		TrackingURL:    "https://ex.example.com/ev?e=${VAST_EVENT}",
+		TrackingEvents: []string{"start"},
+	}})
+	ctx := context.Background()
+
+	t.Run("non-video bids are untouched", func(t *testing.T) {
+		tb := &adapters.TypedBid{Bid: &openrtb.Bid{ID: "b1", AdM: "<div></div>"}, BidType: adapters.BidTypeBanner}
+		if got, err := ex.prepareVideoBid(ctx, tb, "appnexus"); err != nil || got != tb || tb.Bid.AdM != "<div></div>" || tb.BidVideo != nil {
+			t.Errorf("expected banner bid unchanged, got %+v (%v)", got, err)
+		}
+	})
+
+	t.Run("duration, category and trackers", func(t *testing.T) {
+		original := &adapters.TypedBid{Bid: &openrtb.Bid{ID: "b1", AdM: testInlineVAST, Cat: []string{"IAB19"}}, BidType: adapters.BidTypeVideo}
+		tb, verr := ex.prepareVideoBid(ctx, original, "appnexus")
+		if verr != nil {
+			t.Fatalf("unexpected error: %v", verr)
+		}
+		if original.Bid.AdM != testInlineVAST || original.BidVideo != nil {
+			t.Errorf("expected the adapter's bid left untouched, got %+v", original)
+		}
+		if again, _ := ex.prepareVideoBid(ctx, original, "appnexus"); again.Bid.AdM != tb.Bid.AdM {
+			t.Error("expected a second pass to inject the trackers once")
+		}
+		if tb.BidVideo == nil || tb.BidVideo.Duration != 15 || tb.BidVideo.PrimaryCategory != "IAB19" {
+			t.Errorf("expected 15s IAB19 video info, got %+v", tb.BidVideo)
+		}
+		doc, err := vast.Parse(tb.Bid.AdM)
+		if err != nil {
+			t.Fatalf("injected VAST does not parse: %v", err)
+		}
+		inline := doc.Ads[0].InLine
+		if len(inline.Impressions) != 2 || inline.Impressions[1] != "https://ex.example.com/imp?bid=${AUCTION_BID_ID}" {
+			t.Errorf("expected our impression, got %v", inline.Impressions)
+		}
+		if tracking := inline.Creatives[0].Linear.Tracking; len(tracking) != 1 || tracking[0].URL != "https://ex.example.com/ev?e=start" {
+			t.Errorf("expected start tracking, got %+v", tracking)
+		}
+	})
+
+	t.Run("adapter video info is kept", func(t *testing.T) {
+		tb := &adapters.TypedBid{
+			Bid:      &openrtb.Bid{ID: "b1", AdM: testInlineVAST, Cat: []string{"IAB19"}},
+			BidType:  adapters.BidTypeVideo,
+			BidVideo: &adapters.BidVideo{Duration: 30, PrimaryCategory: "IAB1"},
+		}
+		if got, err := ex.prepareVideoBid(ctx, tb, "appnexus"); err != nil || *got.BidVideo != (adapters.BidVideo{Duration: 30, PrimaryCategory: "IAB1"}) {
+			t.Errorf("expected adapter video info kept, got %+v (%v)", got.BidVideo, err)
+		}
+	})
+
+	t.Run("no injection without permission", func(t *testing.T) {
+		tb, err := ex.prepareVideoBid(ctx, &adapters.TypedBid{Bid: &openrtb.Bid{ID: "b1", AdM: testInlineVAST}, BidType: adapters.BidTypeVideo}, "rubicon")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tb.Bid.AdM != testInlineVAST {
+			t.Errorf("expected markup unchanged, got %s", tb.Bid.AdM)
+		}
+		if tb.BidVideo == nil || tb.BidVideo.Duration != 15 {
+			t.Errorf("expected duration extracted, got %+v", tb.BidVideo)
+		}
+	})
+
+	t.Run("nurl-only bid is wrapped", func(t *testing.T) {
+		nurl := "https://dsp.example.com/vast?price=${AUCTION_PRICE}"
+		original := &adapters.TypedBid{Bid: &openrtb.Bid{ID: "b1", NURL: nurl}, BidType: adapters.BidTypeVideo}
+		tb, verr := ex.prepareVideoBid(ctx, original, "rubicon")
+		if verr != nil {
+			t.Fatalf("unexpected error: %v", verr)
+		}
+		if original.Bid.AdM != "" {
+			t.Errorf("expected the wrapper set on a copy, got %s", original.Bid.AdM)
+		}
+		doc, err := vast.Parse(tb.Bid.AdM)
+		if err != nil {
+			t.Fatalf("wrapper does not parse: %v", err)
+		}
+		wrapper := doc.Ads[0].Wrapper
+		if wrapper == nil || wrapper.VASTAdTagURI != nurl {
+			t.Fatalf("expected wrapper loading the nurl, got %+v", doc.Ads[0])
+		}
+		if len(wrapper.Impressions) != 1 {
+			t.Errorf("expected our impression in our own wrapper, got %v", wrapper.Impressions)
+		}
+		if tb.Bid.NURL != nurl {
+			t.Errorf("expected nurl kept, got %q", tb.Bid.NURL)
+		}
+	})
+
+	t.Run("invalid VAST is rejected", func(t *testing.T) {
+		for name, adm := range map[string]string{
+			"not xml":       "https://dsp.example.com/vast",
+			"no media file": strings.Replace(testInlineVAST, "<MediaFiles><MediaFile><![CDATA[https://cdn.example.com/a.mp4]]></MediaFile></MediaFiles>", "", 1),
+		} {
+			tb := &adapters.TypedBid{Bid: &openrtb.Bid{ID: "b1", ImpID: "imp1", AdM: adm}, BidType: adapters.BidTypeVideo}
+			_, err := ex.prepareVideoBid(ctx, tb, "appnexus")
+			if err == nil || err.NonBidStatusCode() != openrtb.NonBidInvalidBid || !strings.HasPrefix(err.Reason, "invalid VAST") {
+				t.Errorf("%s: expected invalid VAST error, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("validation disabled", func(t *testing.T) {
+		lenient := New(registry, &Config{VAST: &VASTConfig{}})
+		tb := &adapters.TypedBid{Bid: &openrtb.Bid{ID: "b1", AdM: "https://dsp.example.com/vast"}, BidType: adapters.BidTypeVideo}
+		if _, err := lenient.prepareVideoBid(ctx, tb, "appnexus"); err != nil {
+			t.Errorf("expected no error with validation disabled, got %v", err)
+		}
+	})
+}
+
+func TestRunAuction_VideoBids(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("appnexus", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "a1", ImpID: "imp1", Price: 2.00, AdM: testInlineVAST, Cat: []string{"IAB19"}}, BidType: adapters.BidTypeVideo},
+	}}, adapters.BidderInfo{Enabled: true})
+	registry.Register("rubicon", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "r1", ImpID: "imp1", Price: 5.00, AdM: "<VAST version=\"3.0\"></VAST>"}, BidType: adapters.BidTypeVideo},
+	}}, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{DefaultTimeout: 500 * time.Millisecond})
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "video-auction",
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Video: &openrtb.Video{W: 640, H: 360, Mimes: []string{"video/mp4"}}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var winners []openrtb.Bid
+	for _, sb := range resp.BidResponse.SeatBid {
+		winners = append(winners, sb.Bid...)
+	}
+	if len(winners) != 1 || winners[0].ID != "a1" {
+		t.Fatalf("expected only the valid VAST bid a1, got %+v", winners)
+	}
+
+	nonBids := resp.DebugInfo.NonBids["rubicon"]
+	if len(nonBids) != 1 || nonBids[0].StatusCode != openrtb.NonBidInvalidBid {
+		t.Errorf("expected invalid bid non-bid for rubicon, got %+v", resp.DebugInfo.NonBids)
+	}
+
+	var ext openrtb.BidExt
+	if err := json.Unmarshal(winners[0].Ext, &ext); err != nil {
+		t.Fatalf("invalid bid ext: %v", err)
+	}
+	if ext.Prebid == nil || ext.Prebid.Video == nil || *ext.Prebid.Video != (openrtb.ExtBidPrebidVideo{Duration: 15, PrimaryCategory: "IAB19"}) {
+		t.Errorf("expected ext.prebid.video, got %+v", ext.Prebid)
+	}
+}
@@ -0,0 +1,191 @@
+package floors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// publisherIDPlaceholder is replaced with the publisher ID in Config.FetchURL
+const publisherIDPlaceholder = "{publisherId}"
+
+// Fetcher periodically downloads floor rule files per publisher
+// Fetches run in the background: the first auction for a publisher sees
+// "inprogress" and later auctions use the cached rules until they go stale.
+// At most Config.FetchMaxPublishers publishers are cached; the least recently
+// used is evicted to make room for a new one.
+type Fetcher struct {
+	config *Config
+	client *http.Client
+
+	mu      sync.RWMutex
+	entries map[string]*fetchEntry // keyed by publisher ID
+	now     func() time.Time
+}
+
+// fetchEntry is the cached rule file for one publisher
+type fetchEntry struct {
+	rules     *Rules
+	status    string
+	fetchedAt time.Time
+	usedAt    time.Time
+	inFlight  bool
+}
+
+// NewFetcher creates a rule file fetcher
+func NewFetcher(config *Config) *Fetcher {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	timeout := config.FetchTimeout
+	if timeout <= 0 {
+		timeout = DefaultConfig().FetchTimeout
+	}
+	return &Fetcher{
+		config:  config,
+		client:  &http.Client{Timeout: timeout},
+		entries: make(map[string]*fetchEntry),
+		now:     time.Now,
+	}
+}
+
+// Get returns the cached rules for the publisher and the fetch status
+// A background fetch is started when nothing is cached or the cached rules are stale
+func (f *Fetcher) Get(publisherID string) (*Rules, string) {
+	if f.config.FetchURL == "" || publisherID == "" {
+		return nil, FetchStatusNone
+	}
+
+	f.mu.Lock()
+	entry, ok := f.entries[publisherID]
+	if !ok {
+		if len(f.entries) >= f.maxPublishers() {
+			f.evictLeastRecentlyUsed()
+		}
+		entry = &fetchEntry{status: FetchStatusInProgress}
+		f.entries[publisherID] = entry
+	}
+	entry.usedAt = f.now()
+	stale := entry.fetchedAt.IsZero() || f.now().Sub(entry.fetchedAt) >= f.interval()
+	startFetch := stale && !entry.inFlight
+	if startFetch {
+		entry.inFlight = true
+	}
+	rules, status := entry.rules, entry.status
+	f.mu.Unlock()
+
+	if startFetch {
+		go f.refresh(publisherID)
+	}
+
+	return rules, status
+}
+
+// refresh fetches the rule file and updates the cache
+// On failure the previous rules are kept so a flaky endpoint doesn't drop floors
+func (f *Fetcher) refresh(publisherID string) {
+	rules, err := f.fetch(publisherID)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entry := f.entries[publisherID]
+	if entry == nil {
+		return // evicted while the fetch was running
+	}
+	entry.inFlight = false
+	entry.fetchedAt = f.now()
+
+	if err != nil {
+		entry.status = FetchStatusError
+		if errors.Is(err, context.DeadlineExceeded) {
+			entry.status = FetchStatusTimeout
+		}
+		logger.Log.Warn().
+			Err(err).
+			Str("publisher_id", publisherID).
+			Msg("floors: rule file fetch failed")
+		return
+	}
+
+	entry.rules = rules
+	entry.status = FetchStatusSuccess
+}
+
+// fetch downloads and validates one publisher's rule file
+func (f *Fetcher) fetch(publisherID string) (*Rules, error) {
+	fetchURL := strings.ReplaceAll(f.config.FetchURL, publisherIDPlaceholder, url.PathEscape(publisherID))
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.client.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fetchURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	maxBytes := f.config.FetchMaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultConfig().FetchMaxBytes
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule file: %w", err)
+	}
+	if int64(len(body)) > maxBytes {
+		return nil, fmt.Errorf("rule file exceeds %d bytes", maxBytes)
+	}
+
+	rules, err := ParseRules(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rule file: %w", err)
+	}
+	if !rules.hasData() {
+		return nil, errors.New("rule file has no model groups")
+	}
+	return rules, nil
+}
+
+// evictLeastRecentlyUsed drops the cached publisher used longest ago; the caller holds f.mu
+func (f *Fetcher) evictLeastRecentlyUsed() {
+	var oldestID string
+	var oldest time.Time
+	for id, entry := range f.entries {
+		if oldestID == "" || entry.usedAt.Before(oldest) {
+			oldestID, oldest = id, entry.usedAt
+		}
+	}
+	delete(f.entries, oldestID)
+}
+
+func (f *Fetcher) maxPublishers() int {
+	if f.config.FetchMaxPublishers <= 0 {
+		return DefaultConfig().FetchMaxPublishers
+	}
+	return f.config.FetchMaxPublishers
+}
+
+func (f *Fetcher) interval() time.Duration {
+	if f.config.FetchInterval <= 0 {
+		return DefaultConfig().FetchInterval
+	}
+	return f.config.FetchInterval
+}
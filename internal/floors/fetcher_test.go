@@ -0,0 +1,162 @@
+package floors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const fetchedRules = `{"data":{"modelGroups":[{"modelVersion":"fetched","schema":{"fields":["mediaType"]},"values":{"*":2.00}}]}}`
+
+func waitForStatus(t *testing.T, f *Fetcher, publisherID, status string) *Rules {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		f.mu.RLock()
+		entry := f.entries[publisherID]
+		done := entry != nil && !entry.inFlight && entry.status == status
+		var rules *Rules
+		if entry != nil {
+			rules = entry.rules
+		}
+		f.mu.RUnlock()
+		if done {
+			return rules
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for fetch status %s", status)
+	return nil
+}
+
+func TestFetcher_Get(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(fetchedRules))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.FetchURL = server.URL + "/floors/{publisherId}.json"
+	f := NewFetcher(config)
+
+	rules, status := f.Get("pub 1")
+	if rules != nil || status != FetchStatusInProgress {
+		t.Errorf("expected first call to be in progress, got %v %s", rules, status)
+	}
+
+	waitForStatus(t, f, "pub 1", FetchStatusSuccess)
+	rules, status = f.Get("pub 1")
+	if status != FetchStatusSuccess || !rules.hasData() {
+		t.Fatalf("expected fetched rules, got %v %s", rules, status)
+	}
+	if len(paths) != 1 || paths[0] != "/floors/pub 1.json" {
+		t.Errorf("unexpected fetch paths: %v", paths)
+	}
+}
+
+func TestFetcher_ErrorKeepsRules(t *testing.T) {
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(fetchedRules))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.FetchURL = server.URL
+	f := NewFetcher(config)
+	now := time.Now()
+	f.now = func() time.Time { return now }
+
+	f.Get("pub1")
+	waitForStatus(t, f, "pub1", FetchStatusSuccess)
+
+	// Stale rules trigger a refresh which fails
+	fail.Store(true)
+	now = now.Add(config.FetchInterval)
+	f.Get("pub1")
+	rules := waitForStatus(t, f, "pub1", FetchStatusError)
+	if !rules.hasData() {
+		t.Error("expected previous rules to be kept after a failed refresh")
+	}
+}
+
+func TestFetcher_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"too large", `{"data":{"modelGroups":[]},"pad":"` + strings.Repeat("x", 200) + `"}`},
+		{"no model groups", `{"data":{}}`},
+		{"malformed", `{not json`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			config := DefaultConfig()
+			config.FetchURL = server.URL
+			config.FetchMaxBytes = 100
+			f := NewFetcher(config)
+
+			f.Get("pub1")
+			if rules := waitForStatus(t, f, "pub1", FetchStatusError); rules != nil {
+				t.Errorf("expected no rules, got %+v", rules)
+			}
+		})
+	}
+}
+
+func TestFetcher_Disabled(t *testing.T) {
+	f := NewFetcher(DefaultConfig())
+	if rules, status := f.Get("pub1"); rules != nil || status != FetchStatusNone {
+		t.Errorf("expected no fetch without URL, got %v %s", rules, status)
+	}
+}
+
+func TestFetcher_EvictsLeastRecentlyUsed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fetchedRules))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.FetchURL = server.URL + "/{publisherId}"
+	config.FetchMaxPublishers = 2
+	f := NewFetcher(config)
+	now := time.Now()
+	f.now = func() time.Time { return now }
+
+	for _, id := range []string{"pub1", "pub2"} {
+		f.Get(id)
+		waitForStatus(t, f, id, FetchStatusSuccess)
+		now = now.Add(time.Second)
+	}
+	f.Get("pub1") // pub2 is now the least recently used
+	now = now.Add(time.Second)
+	f.Get("pub3")
+	waitForStatus(t, f, "pub3", FetchStatusSuccess)
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if len(f.entries) != 2 {
+		t.Errorf("expected 2 cached publishers, got %d", len(f.entries))
+	}
+	if _, ok := f.entries["pub2"]; ok {
+		t.Error("expected pub2 to be evicted")
+	}
+	if _, ok := f.entries["pub1"]; !ok {
+		t.Error("expected recently used pub1 to be kept")
+	}
+}
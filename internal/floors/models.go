@@ -0,0 +1,210 @@
+// Package floors implements a Prebid floors-module compatible price floor engine
+package floors
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Schema field names supported in rule schemas
+const (
+	FieldMediaType  = "mediaType"
+	FieldSize       = "size"
+	FieldDomain     = "domain"
+	FieldGPTSlot    = "gptSlot"
+	FieldCountry    = "country"
+	FieldDeviceType = "deviceType"
+)
+
+// Location reports where the applied rule set came from
+const (
+	LocationRequest = "request"
+	LocationAccount = "account"
+	LocationFetch   = "fetch"
+	LocationNoData  = "noData"
+)
+
+// Fetch status values signalled in ext.prebid.floors.fetchStatus
+const (
+	FetchStatusNone       = "none"
+	FetchStatusSuccess    = "success"
+	FetchStatusError      = "error"
+	FetchStatusInProgress = "inprogress"
+	FetchStatusTimeout    = "timeout"
+)
+
+// WildcardValue matches any value for a schema field
+const WildcardValue = "*"
+
+// DefaultDelimiter separates schema field values in rule keys
+const DefaultDelimiter = "|"
+
+// Config holds price floors configuration
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// Currency is the only floor currency accepted (rule sets in other currencies are skipped)
+	Currency string `json:"currency"`
+	// EnforceRate is the default percentage of auctions where floors are enforced (0-100)
+	EnforceRate int `json:"enforce_rate"`
+	// FetchURL is a rule file URL; "{publisherId}" is replaced with the publisher ID. Empty disables fetching
+	FetchURL string `json:"fetch_url,omitempty"`
+	// FetchInterval is how often fetched rule files are refreshed
+	FetchInterval time.Duration `json:"fetch_interval,omitempty"`
+	// FetchTimeout bounds a single rule file fetch
+	FetchTimeout time.Duration `json:"fetch_timeout,omitempty"`
+	// FetchMaxBytes caps the size of a fetched rule file
+	FetchMaxBytes int64 `json:"fetch_max_bytes,omitempty"`
+	// FetchMaxPublishers caps the publishers with cached rule files; the least recently used are evicted
+	FetchMaxPublishers int `json:"fetch_max_publishers,omitempty"`
+}
+
+// DefaultConfig returns the default floors configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Enabled:            true,
+		Currency:           "USD",
+		EnforceRate:        100,
+		FetchInterval:      5 * time.Minute,
+		FetchTimeout:       3 * time.Second,
+		FetchMaxBytes:      1 << 20, // 1MB
+		FetchMaxPublishers: 10000,
+	}
+}
+
+// Rules is the Prebid floors object, used for ext.prebid.floors, stored
+// publisher rules and fetched rule files
+type Rules struct {
+	Enabled     *bool        `json:"enabled,omitempty"`
+	FloorMin    float64      `json:"floorMin,omitempty"`
+	FloorMinCur string       `json:"floorMinCur,omitempty"`
+	SkipRate    int          `json:"skipRate,omitempty"`
+	Enforcement *Enforcement `json:"enforcement,omitempty"`
+	Data        *Data        `json:"data,omitempty"`
+
+	// Signalling fields set by the exchange
+	Location     string `json:"location,omitempty"`
+	FetchStatus  string `json:"fetchStatus,omitempty"`
+	Skipped      *bool  `json:"skipped,omitempty"`
+	ModelVersion string `json:"modelVersion,omitempty"`
+}
+
+// Enforcement controls whether the exchange rejects bids below the floor
+type Enforcement struct {
+	EnforcePBS  *bool `json:"enforcePBS,omitempty"`
+	EnforceRate *int  `json:"enforceRate,omitempty"`
+}
+
+// Data holds the rule model groups
+type Data struct {
+	Currency            string       `json:"currency,omitempty"`
+	SkipRate            int          `json:"skipRate,omitempty"`
+	FloorsSchemaVersion int          `json:"floorsSchemaVersion,omitempty"`
+	ModelTimestamp      int64        `json:"modelTimestamp,omitempty"`
+	FloorProvider       string       `json:"floorProvider,omitempty"`
+	ModelGroups         []ModelGroup `json:"modelGroups,omitempty"`
+
+	// Schema version 1 carries a single model inline
+	ModelVersion string             `json:"modelVersion,omitempty"`
+	Schema       *Schema            `json:"schema,omitempty"`
+	Values       map[string]float64 `json:"values,omitempty"`
+	Default      float64            `json:"default,omitempty"`
+}
+
+// ModelGroup is one weighted rule model used for A/B testing
+type ModelGroup struct {
+	Currency     string             `json:"currency,omitempty"`
+	ModelWeight  *int               `json:"modelWeight,omitempty"`
+	ModelVersion string             `json:"modelVersion,omitempty"`
+	SkipRate     int                `json:"skipRate,omitempty"`
+	Schema       Schema             `json:"schema"`
+	Values       map[string]float64 `json:"values"`
+	Default      float64            `json:"default,omitempty"`
+}
+
+// Schema describes the fields that make up a rule key
+type Schema struct {
+	Fields    []string `json:"fields"`
+	Delimiter string   `json:"delimiter,omitempty"`
+}
+
+// ImpFloor is the floor decision for a single impression, signalled in imp.ext.prebid.floors
+type ImpFloor struct {
+	FloorRule      string  `json:"floorRule,omitempty"`
+	FloorRuleValue float64 `json:"floorRuleValue,omitempty"`
+	FloorValue     float64 `json:"floorValue"`
+	FloorMin       float64 `json:"floorMin,omitempty"`
+}
+
+// Result describes how floors were applied to a request
+type Result struct {
+	Applied      bool
+	Skipped      bool
+	Enforced     bool
+	Location     string
+	FetchStatus  string
+	ModelVersion string
+	Imps         map[string]*ImpFloor // keyed by imp ID; only imps whose floor was set
+	// RequestFloors holds imp.bidfloor as sent by the publisher, before floors were applied
+	RequestFloors map[string]float64
+}
+
+// ParseRules decodes a floors object, returning nil for empty input
+func ParseRules(data []byte) (*Rules, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	rules.normalize()
+	return &rules, nil
+}
+
+// normalize lowercases rule keys so matching is case-insensitive
+func (r *Rules) normalize() {
+	if r.Data == nil {
+		return
+	}
+	r.Data.Values = lowerKeys(r.Data.Values)
+	for i := range r.Data.ModelGroups {
+		r.Data.ModelGroups[i].Values = lowerKeys(r.Data.ModelGroups[i].Values)
+	}
+}
+
+func lowerKeys(values map[string]float64) map[string]float64 {
+	if len(values) == 0 {
+		return values
+	}
+	lowered := make(map[string]float64, len(values))
+	for k, v := range values {
+		lowered[strings.ToLower(k)] = v
+	}
+	return lowered
+}
+
+// modelGroups returns the rule set's model groups, normalising schema version 1
+func (d *Data) modelGroups() []ModelGroup {
+	if d == nil {
+		return nil
+	}
+	if len(d.ModelGroups) > 0 {
+		return d.ModelGroups
+	}
+	if d.Schema != nil && len(d.Values) > 0 {
+		return []ModelGroup{{
+			Currency:     d.Currency,
+			ModelVersion: d.ModelVersion,
+			Schema:       *d.Schema,
+			Values:       d.Values,
+			Default:      d.Default,
+		}}
+	}
+	return nil
+}
+
+// hasData returns true if the rule set contains at least one usable model group
+func (r *Rules) hasData() bool {
+	return r != nil && len(r.Data.modelGroups()) > 0
+}
@@ -0,0 +1,530 @@
+package floors
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// Processor resolves floor rules for a bid request and applies them to impressions
+type Processor struct {
+	config   atomic.Value // Stores *Config
+	fetcher  atomic.Value // Stores *Fetcher
+	randIntn func(n int) int
+}
+
+// NewProcessor creates a new floors processor with the given configuration
+func NewProcessor(config *Config) *Processor {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	p := &Processor{randIntn: rand.Intn}
+	p.config.Store(config)
+	return p
+}
+
+// getConfig returns the current configuration atomically
+func (p *Processor) getConfig() *Config {
+	cfg, ok := p.config.Load().(*Config)
+	if !ok || cfg == nil {
+		return &Config{}
+	}
+	return cfg
+}
+
+// UpdateConfig replaces the processor configuration
+func (p *Processor) UpdateConfig(config *Config) {
+	if config != nil {
+		p.config.Store(config)
+	}
+}
+
+// SetFetcher sets the rule file fetcher used as the highest-priority floor source
+func (p *Processor) SetFetcher(f *Fetcher) {
+	p.fetcher.Store(f)
+}
+
+func (p *Processor) getFetcher() *Fetcher {
+	f, _ := p.fetcher.Load().(*Fetcher)
+	return f
+}
+
+// Apply resolves the floor rules for the request and sets imp.bidfloor on matching impressions
+// Rule sources in priority order: fetched rule file, ext.prebid.floors, publisher stored rules.
+// floorMin and enforcement in ext.prebid.floors always override the selected source.
+// The decision is signalled to bidders in ext.prebid.floors and imp.ext.prebid.floors.
+// Returns nil when floors are disabled for the request.
+func (p *Processor) Apply(req *openrtb.BidRequest, publisherID string, stored *Rules) *Result {
+	config := p.getConfig()
+	if !config.Enabled || req == nil {
+		return nil
+	}
+
+	requestRules := requestFloors(req)
+	if requestRules != nil && requestRules.Enabled != nil && !*requestRules.Enabled {
+		return nil
+	}
+	if stored != nil && stored.Enabled != nil && !*stored.Enabled {
+		return nil
+	}
+
+	result := &Result{
+		Location:      LocationNoData,
+		FetchStatus:   FetchStatusNone,
+		Imps:          make(map[string]*ImpFloor),
+		RequestFloors: make(map[string]float64, len(req.Imp)),
+	}
+
+	// Pick the rule set to use
+	var selected *Rules
+	if fetcher := p.getFetcher(); fetcher != nil && publisherID != "" {
+		fetched, status := fetcher.Get(publisherID)
+		result.FetchStatus = status
+		if fetched.hasData() {
+			selected = fetched
+			result.Location = LocationFetch
+		}
+	}
+	if selected == nil && requestRules.hasData() {
+		selected = requestRules
+		result.Location = LocationRequest
+	}
+	if selected == nil && stored.hasData() {
+		selected = stored
+		result.Location = LocationAccount
+	}
+
+	// Nothing configured anywhere - leave the request untouched
+	if selected == nil && requestRules == nil && stored == nil && result.FetchStatus == FetchStatusNone {
+		return nil
+	}
+
+	// Resolve controls: the selected source, then stored defaults, overridden by the request
+	controls := mergeControls(config, stored, selected, requestRules)
+
+	// Select a model group and decide whether to skip floors for this auction
+	var group *ModelGroup
+	if selected != nil {
+		group = p.selectModelGroup(selected.Data.modelGroups())
+		if group != nil {
+			currency := firstNonEmpty(group.Currency, selected.Data.Currency, "USD")
+			if config.Currency != "" && !strings.EqualFold(currency, config.Currency) {
+				logger.Log.Debug().
+					Str("currency", currency).
+					Str("expected", config.Currency).
+					Msg("floors: rule set currency not supported, ignoring rules")
+				group = nil
+				result.Location = LocationNoData
+			} else {
+				result.ModelVersion = group.ModelVersion
+			}
+		}
+	}
+
+	skipRate := controls.skipRate
+	if group != nil {
+		skipRate = firstPositive(group.SkipRate, selected.Data.SkipRate, skipRate)
+	}
+	if skipRate > 0 && p.randIntn(100) < skipRate {
+		result.Skipped = true
+	}
+
+	for i := range req.Imp {
+		imp := &req.Imp[i]
+		result.RequestFloors[imp.ID] = imp.BidFloor
+		if result.Skipped {
+			continue
+		}
+
+		decision := &ImpFloor{FloorMin: controls.floorMin}
+		matched := false
+		if group != nil {
+			if rule, value, ok := group.match(impFieldValues(req, imp, group.Schema.Fields)); ok {
+				decision.FloorRule = rule
+				decision.FloorRuleValue = value
+				decision.FloorValue = value
+				matched = true
+			} else if group.Default > 0 {
+				decision.FloorValue = group.Default
+				matched = true
+			}
+		}
+		if !matched {
+			decision.FloorValue = imp.BidFloor
+		}
+		if decision.FloorValue < controls.floorMin {
+			decision.FloorValue = controls.floorMin
+		}
+		if !matched && decision.FloorValue == imp.BidFloor {
+			continue // Nothing to change for this impression
+		}
+
+		decision.FloorValue = math.Round(decision.FloorValue*10000) / 10000
+		imp.BidFloor = decision.FloorValue
+		imp.BidFloorCur = firstNonEmpty(config.Currency, "USD")
+		imp.Ext = setFloorsExt(imp.Ext, decision)
+		result.Imps[imp.ID] = decision
+	}
+
+	result.Applied = len(result.Imps) > 0
+	result.Enforced = result.Applied && controls.enforcePBS &&
+		(controls.enforceRate >= 100 || p.randIntn(100) < controls.enforceRate)
+
+	req.Ext = setFloorsExt(req.Ext, signalRules(selected, requestRules, controls, result))
+
+	return result
+}
+
+// controls holds the resolved floorMin, skipRate and enforcement settings
+type controls struct {
+	floorMin    float64
+	skipRate    int
+	enforcePBS  bool
+	enforceRate int
+}
+
+// mergeControls layers floor controls: config defaults, stored rules, selected rules, request rules
+func mergeControls(config *Config, layers ...*Rules) controls {
+	c := controls{enforcePBS: true, enforceRate: config.EnforceRate}
+	for _, r := range layers {
+		if r == nil {
+			continue
+		}
+		if r.FloorMin > 0 {
+			c.floorMin = r.FloorMin
+		}
+		if r.SkipRate > 0 {
+			c.skipRate = r.SkipRate
+		}
+		if r.Enforcement != nil {
+			if r.Enforcement.EnforcePBS != nil {
+				c.enforcePBS = *r.Enforcement.EnforcePBS
+			}
+			if r.Enforcement.EnforceRate != nil {
+				c.enforceRate = *r.Enforcement.EnforceRate
+			}
+		}
+	}
+	return c
+}
+
+// selectModelGroup picks a model group at random, weighted by modelWeight (default 1)
+func (p *Processor) selectModelGroup(groups []ModelGroup) *ModelGroup {
+	if len(groups) == 0 {
+		return nil
+	}
+	if len(groups) == 1 {
+		return &groups[0]
+	}
+
+	total := 0
+	for _, g := range groups {
+		total += groupWeight(g)
+	}
+	pick := p.randIntn(total)
+	for i := range groups {
+		pick -= groupWeight(groups[i])
+		if pick < 0 {
+			return &groups[i]
+		}
+	}
+	return &groups[len(groups)-1]
+}
+
+func groupWeight(g ModelGroup) int {
+	if g.ModelWeight == nil || *g.ModelWeight <= 0 {
+		return 1
+	}
+	return *g.ModelWeight
+}
+
+// match finds the most specific rule for the field values
+// Candidates with fewer wildcards win; ties prefer wildcards in later fields
+func (g *ModelGroup) match(values []string) (string, float64, bool) {
+	if len(values) == 0 || len(values) > maxSchemaFields {
+		return "", 0, false
+	}
+	delimiter := g.Schema.Delimiter
+	if delimiter == "" {
+		delimiter = DefaultDelimiter
+	}
+
+	candidate := make([]string, len(values))
+	for _, mask := range wildcardMasks[len(values)] {
+		for i, v := range values {
+			if mask&(1<<(len(values)-1-i)) != 0 || v == "" {
+				candidate[i] = WildcardValue
+			} else {
+				candidate[i] = v
+			}
+		}
+		key := strings.Join(candidate, delimiter)
+		if value, ok := g.Values[key]; ok {
+			return key, value, true
+		}
+	}
+	return "", 0, false
+}
+
+// maxSchemaFields caps the number of fields in a rule schema
+const maxSchemaFields = 6
+
+// wildcardMasks lists, per field count, the wildcard bitmasks in match priority order
+// Bit (n-1-i) set means field i is replaced with "*"
+var wildcardMasks = buildWildcardMasks(maxSchemaFields)
+
+func buildWildcardMasks(maxFields int) [][]int {
+	masks := make([][]int, maxFields+1)
+	for n := 1; n <= maxFields; n++ {
+		list := make([]int, 0, 1<<n)
+		for m := 0; m < 1<<n; m++ {
+			list = append(list, m)
+		}
+		sort.SliceStable(list, func(a, b int) bool {
+			ca, cb := bitCount(list[a]), bitCount(list[b])
+			if ca != cb {
+				return ca < cb
+			}
+			return list[a] < list[b]
+		})
+		masks[n] = list
+	}
+	return masks
+}
+
+func bitCount(v int) int {
+	count := 0
+	for v != 0 {
+		count += v & 1
+		v >>= 1
+	}
+	return count
+}
+
+// impFieldValues returns the lowercase request values for each schema field
+// An empty value matches only wildcard rules
+func impFieldValues(req *openrtb.BidRequest, imp *openrtb.Imp, fields []string) []string {
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		var v string
+		switch field {
+		case FieldMediaType:
+			v = impMediaType(imp)
+		case FieldSize:
+			v = impSize(imp)
+		case FieldDomain:
+			v = requestDomain(req)
+		case FieldGPTSlot:
+			v = impGPTSlot(imp)
+		case FieldCountry:
+			if req.Device != nil && req.Device.Geo != nil {
+				v = req.Device.Geo.Country
+			}
+		case FieldDeviceType:
+			v = deviceType(req.Device)
+		}
+		values[i] = strings.ToLower(v)
+	}
+	return values
+}
+
+// impMediaType returns the single media type of the impression, or "" when it has several
+func impMediaType(imp *openrtb.Imp) string {
+	types := make([]string, 0, 4)
+	if imp.Banner != nil {
+		types = append(types, "banner")
+	}
+	if imp.Video != nil {
+		types = append(types, "video")
+	}
+	if imp.Native != nil {
+		types = append(types, "native")
+	}
+	if imp.Audio != nil {
+		types = append(types, "audio")
+	}
+	if len(types) != 1 {
+		return ""
+	}
+	return types[0]
+}
+
+// impSize returns the single size of the impression, or "" when it has several
+func impSize(imp *openrtb.Imp) string {
+	if imp.Banner != nil {
+		if len(imp.Banner.Format) == 1 {
+			return fmt.Sprintf("%dx%d", imp.Banner.Format[0].W, imp.Banner.Format[0].H)
+		}
+		if len(imp.Banner.Format) == 0 && imp.Banner.W > 0 && imp.Banner.H > 0 {
+			return fmt.Sprintf("%dx%d", imp.Banner.W, imp.Banner.H)
+		}
+		return ""
+	}
+	if imp.Video != nil && imp.Video.W > 0 && imp.Video.H > 0 {
+		return fmt.Sprintf("%dx%d", imp.Video.W, imp.Video.H)
+	}
+	return ""
+}
+
+// requestDomain returns the site or app domain
+func requestDomain(req *openrtb.BidRequest) string {
+	if req.Site != nil {
+		if req.Site.Domain != "" {
+			return req.Site.Domain
+		}
+		if req.Site.Publisher != nil {
+			return req.Site.Publisher.Domain
+		}
+	}
+	if req.App != nil {
+		if req.App.Domain != "" {
+			return req.App.Domain
+		}
+		if req.App.Publisher != nil {
+			return req.App.Publisher.Domain
+		}
+	}
+	return ""
+}
+
+// impGPTSlot returns the GAM ad slot (imp.ext.data.adserver.adslot) or imp.ext.data.pbadslot
+func impGPTSlot(imp *openrtb.Imp) string {
+	if len(imp.Ext) == 0 {
+		return ""
+	}
+	var ext struct {
+		Data struct {
+			AdServer struct {
+				Name   string `json:"name"`
+				AdSlot string `json:"adslot"`
+			} `json:"adserver"`
+			PBAdSlot string `json:"pbadslot"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(imp.Ext, &ext); err != nil {
+		return ""
+	}
+	if strings.EqualFold(ext.Data.AdServer.Name, "gam") && ext.Data.AdServer.AdSlot != "" {
+		return ext.Data.AdServer.AdSlot
+	}
+	return ext.Data.PBAdSlot
+}
+
+// deviceType maps OpenRTB device.devicetype to the floors values phone, tablet or desktop
+func deviceType(device *openrtb.Device) string {
+	if device == nil {
+		return ""
+	}
+	switch device.DeviceType {
+	case 1, 4: // Mobile/Tablet, Phone
+		return "phone"
+	case 5: // Tablet
+		return "tablet"
+	case 2, 3, 6, 7: // PC, Connected TV, Connected Device, Set Top Box
+		return "desktop"
+	default:
+		return ""
+	}
+}
+
+// requestFloors parses ext.prebid.floors from the request
+func requestFloors(req *openrtb.BidRequest) *Rules {
+	if len(req.Ext) == 0 {
+		return nil
+	}
+	var ext struct {
+		Prebid struct {
+			Floors json.RawMessage `json:"floors"`
+		} `json:"prebid"`
+	}
+	if err := json.Unmarshal(req.Ext, &ext); err != nil {
+		return nil
+	}
+	rules, err := ParseRules(ext.Prebid.Floors)
+	if err != nil {
+		logger.Log.Debug().Err(err).Msg("floors: invalid ext.prebid.floors, ignoring")
+		return nil
+	}
+	return rules
+}
+
+// signalRules builds the ext.prebid.floors object sent to bidders
+func signalRules(selected, request *Rules, c controls, result *Result) *Rules {
+	signal := &Rules{}
+	if selected != nil {
+		signal.Data = selected.Data
+	} else if request != nil {
+		signal.Data = request.Data
+	}
+	enforcePBS := c.enforcePBS
+	enforceRate := c.enforceRate
+	skipped := result.Skipped
+	signal.FloorMin = c.floorMin
+	signal.SkipRate = c.skipRate
+	signal.Enforcement = &Enforcement{EnforcePBS: &enforcePBS, EnforceRate: &enforceRate}
+	signal.Location = result.Location
+	signal.FetchStatus = result.FetchStatus
+	signal.Skipped = &skipped
+	signal.ModelVersion = result.ModelVersion
+	return signal
+}
+
+// setFloorsExt writes value to ext.prebid.floors, preserving all other ext fields
+func setFloorsExt(ext json.RawMessage, value interface{}) json.RawMessage {
+	root := make(map[string]json.RawMessage)
+	if len(ext) > 0 {
+		if err := json.Unmarshal(ext, &root); err != nil {
+			return ext // Leave malformed ext untouched
+		}
+	}
+
+	prebid := make(map[string]json.RawMessage)
+	if raw, ok := root["prebid"]; ok {
+		if err := json.Unmarshal(raw, &prebid); err != nil {
+			return ext
+		}
+	}
+
+	floorsJSON, err := json.Marshal(value)
+	if err != nil {
+		return ext
+	}
+	prebid["floors"] = floorsJSON
+
+	prebidJSON, err := json.Marshal(prebid)
+	if err != nil {
+		return ext
+	}
+	root["prebid"] = prebidJSON
+
+	out, err := json.Marshal(root)
+	if err != nil {
+		return ext
+	}
+	return out
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func firstPositive(values ...int) int {
+	for _, v := range values {
+		if v > 0 {
+			return v
+		}
+	}
+	return 0
+}
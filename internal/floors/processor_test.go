@@ -0,0 +1,268 @@
+package floors
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+func floorsRequest(ext string) *openrtb.BidRequest {
+	return &openrtb.BidRequest{
+		ID:     "floors-req",
+		Site:   &openrtb.Site{Domain: "example.com"},
+		Device: &openrtb.Device{DeviceType: 2, Geo: &openrtb.Geo{Country: "USA"}},
+		Imp: []openrtb.Imp{
+			{ID: "banner", Banner: &openrtb.Banner{W: 300, H: 250}, BidFloor: 0.10},
+			{ID: "video", Video: &openrtb.Video{W: 640, H: 480}},
+			{ID: "multi", Banner: &openrtb.Banner{Format: []openrtb.Format{{W: 300, H: 250}, {W: 728, H: 90}}}},
+		},
+		Ext: json.RawMessage(ext),
+	}
+}
+
+func mustRules(t *testing.T, raw string) *Rules {
+	t.Helper()
+	rules, err := ParseRules([]byte(raw))
+	if err != nil {
+		t.Fatalf("failed to parse rules: %v", err)
+	}
+	return rules
+}
+
+func TestApply_RuleMatching(t *testing.T) {
+	p := NewProcessor(DefaultConfig())
+	stored := mustRules(t, `{"data":{"modelGroups":[{
+		"modelVersion":"m1",
+		"schema":{"fields":["mediaType","size","country"]},
+		"values":{
+			"banner|300x250|usa":1.50,
+			"banner|300x250|*":1.00,
+			"*|300x250|usa":0.90,
+			"video|*|*":4.00,
+			"*|*|*":0.25
+		}
+	}]}}`)
+
+	req := floorsRequest("")
+	result := p.Apply(req, "pub1", stored)
+	if result == nil || !result.Applied {
+		t.Fatal("expected floors to be applied")
+	}
+	if result.Location != LocationAccount || result.ModelVersion != "m1" {
+		t.Errorf("unexpected location/model: %s/%s", result.Location, result.ModelVersion)
+	}
+	if !result.Enforced {
+		t.Error("expected floors to be enforced by default")
+	}
+
+	tests := []struct {
+		impID string
+		rule  string
+		floor float64
+	}{
+		{"banner", "banner|300x250|usa", 1.50}, // exact match wins
+		{"video", "video|*|*", 4.00},           // wildcards in later fields
+		{"multi", "*|*|*", 0.25},               // several sizes only match wildcards
+	}
+	for i, tt := range tests {
+		decision := result.Imps[tt.impID]
+		if decision == nil {
+			t.Fatalf("%s: expected floor decision", tt.impID)
+		}
+		if decision.FloorRule != tt.rule || decision.FloorValue != tt.floor {
+			t.Errorf("%s: expected %s=%.2f, got %s=%.2f", tt.impID, tt.rule, tt.floor, decision.FloorRule, decision.FloorValue)
+		}
+		if req.Imp[i].BidFloor != tt.floor || req.Imp[i].BidFloorCur != "USD" {
+			t.Errorf("%s: expected imp.bidfloor %.2f USD, got %.2f %s", tt.impID, tt.floor, req.Imp[i].BidFloor, req.Imp[i].BidFloorCur)
+		}
+	}
+	if result.RequestFloors["banner"] != 0.10 {
+		t.Errorf("expected original request floor 0.10, got %.2f", result.RequestFloors["banner"])
+	}
+}
+
+func TestApply_WildcardPriority(t *testing.T) {
+	group := &ModelGroup{
+		Schema: Schema{Fields: []string{FieldMediaType, FieldSize}},
+		Values: map[string]float64{"*|300x250": 2.00, "banner|*": 1.00},
+	}
+	// Same number of wildcards: the wildcard in the later field is preferred
+	rule, value, ok := group.match([]string{"banner", "300x250"})
+	if !ok || rule != "banner|*" || value != 1.00 {
+		t.Errorf("expected banner|* = 1.00, got %s = %.2f (ok=%v)", rule, value, ok)
+	}
+}
+
+func TestApply_DefaultAndFloorMin(t *testing.T) {
+	p := NewProcessor(DefaultConfig())
+	req := floorsRequest(`{"prebid":{"floors":{"floorMin":0.75,"data":{"modelGroups":[{
+		"schema":{"fields":["mediaType"]},
+		"values":{"video":3.00},
+		"default":0.50
+	}]}}}}`)
+
+	result := p.Apply(req, "pub1", nil)
+	if result.Location != LocationRequest {
+		t.Errorf("expected request location, got %s", result.Location)
+	}
+	if got := result.Imps["banner"]; got == nil || got.FloorValue != 0.75 || got.FloorMin != 0.75 {
+		t.Errorf("expected default raised to floorMin 0.75, got %+v", got)
+	}
+	if got := result.Imps["video"]; got == nil || got.FloorValue != 3.00 {
+		t.Errorf("expected video floor 3.00, got %+v", got)
+	}
+}
+
+func TestApply_ModelGroupWeights(t *testing.T) {
+	stored := mustRules(t, `{"data":{"modelGroups":[
+		{"modelVersion":"a","modelWeight":20,"schema":{"fields":["mediaType"]},"values":{"*":1.00}},
+		{"modelVersion":"b","modelWeight":80,"schema":{"fields":["mediaType"]},"values":{"*":2.00}}
+	]}}`)
+
+	for _, tt := range []struct {
+		pick    int
+		version string
+	}{{0, "a"}, {19, "a"}, {20, "b"}, {99, "b"}} {
+		p := NewProcessor(DefaultConfig())
+		p.randIntn = func(int) int { return tt.pick }
+		result := p.Apply(floorsRequest(""), "pub1", stored)
+		if result.ModelVersion != tt.version {
+			t.Errorf("pick %d: expected model %s, got %s", tt.pick, tt.version, result.ModelVersion)
+		}
+	}
+}
+
+func TestApply_SkipRate(t *testing.T) {
+	stored := mustRules(t, `{"skipRate":50,"data":{"modelGroups":[
+		{"schema":{"fields":["mediaType"]},"values":{"*":1.00}}
+	]}}`)
+
+	p := NewProcessor(DefaultConfig())
+	p.randIntn = func(int) int { return 10 }
+	req := floorsRequest("")
+	result := p.Apply(req, "pub1", stored)
+	if !result.Skipped || result.Applied || result.Enforced {
+		t.Errorf("expected skipped auction, got %+v", result)
+	}
+	if req.Imp[0].BidFloor != 0.10 {
+		t.Errorf("expected imp.bidfloor unchanged, got %.2f", req.Imp[0].BidFloor)
+	}
+
+	p.randIntn = func(int) int { return 60 }
+	if result := p.Apply(floorsRequest(""), "pub1", stored); result.Skipped || !result.Applied {
+		t.Errorf("expected floors applied, got %+v", result)
+	}
+}
+
+func TestApply_Enforcement(t *testing.T) {
+	stored := mustRules(t, `{"data":{"modelGroups":[{"schema":{"fields":["mediaType"]},"values":{"*":1.00}}]}}`)
+	p := NewProcessor(DefaultConfig())
+
+	// Request enforcement overrides the stored rules
+	req := floorsRequest(`{"prebid":{"floors":{"enforcement":{"enforcePBS":false}}}}`)
+	result := p.Apply(req, "pub1", stored)
+	if !result.Applied || result.Enforced {
+		t.Errorf("expected applied but not enforced, got %+v", result)
+	}
+
+	// Enforce rate samples auctions
+	p.randIntn = func(int) int { return 40 }
+	req = floorsRequest(`{"prebid":{"floors":{"enforcement":{"enforceRate":30}}}}`)
+	if result := p.Apply(req, "pub1", stored); result.Enforced {
+		t.Error("expected auction outside enforce rate not to be enforced")
+	}
+}
+
+func TestApply_CurrencyMismatch(t *testing.T) {
+	stored := mustRules(t, `{"data":{"currency":"EUR","modelGroups":[{"schema":{"fields":["mediaType"]},"values":{"*":1.00}}]}}`)
+	p := NewProcessor(DefaultConfig())
+
+	req := floorsRequest("")
+	result := p.Apply(req, "pub1", stored)
+	if result.Applied || result.Location != LocationNoData {
+		t.Errorf("expected rules in another currency to be ignored, got %+v", result)
+	}
+	if req.Imp[0].BidFloor != 0.10 {
+		t.Errorf("expected imp.bidfloor unchanged, got %.2f", req.Imp[0].BidFloor)
+	}
+}
+
+func TestApply_Disabled(t *testing.T) {
+	stored := mustRules(t, `{"data":{"modelGroups":[{"schema":{"fields":["mediaType"]},"values":{"*":1.00}}]}}`)
+
+	config := DefaultConfig()
+	config.Enabled = false
+	if result := NewProcessor(config).Apply(floorsRequest(""), "pub1", stored); result != nil {
+		t.Error("expected nil result when floors are disabled")
+	}
+
+	req := floorsRequest(`{"prebid":{"floors":{"enabled":false}}}`)
+	if result := NewProcessor(DefaultConfig()).Apply(req, "pub1", stored); result != nil {
+		t.Error("expected nil result when request disables floors")
+	}
+
+	// No floor data anywhere leaves the request untouched
+	req = floorsRequest(`{"prebid":{"debug":true}}`)
+	if result := NewProcessor(DefaultConfig()).Apply(req, "pub1", nil); result != nil {
+		t.Error("expected nil result without floor data")
+	}
+	if string(req.Ext) != `{"prebid":{"debug":true}}` {
+		t.Errorf("expected ext untouched, got %s", req.Ext)
+	}
+}
+
+func TestApply_Signalling(t *testing.T) {
+	stored := mustRules(t, `{"data":{"modelGroups":[{"modelVersion":"m1","schema":{"fields":["mediaType"]},"values":{"banner":1.00}}]}}`)
+	req := floorsRequest(`{"prebid":{"debug":true},"custom":1}`)
+
+	NewProcessor(DefaultConfig()).Apply(req, "pub1", stored)
+
+	var ext struct {
+		Custom int `json:"custom"`
+		Prebid struct {
+			Debug  bool  `json:"debug"`
+			Floors Rules `json:"floors"`
+		} `json:"prebid"`
+	}
+	if err := json.Unmarshal(req.Ext, &ext); err != nil {
+		t.Fatalf("failed to parse ext: %v", err)
+	}
+	if ext.Custom != 1 || !ext.Prebid.Debug {
+		t.Error("expected existing ext fields to be preserved")
+	}
+	if ext.Prebid.Floors.Location != LocationAccount || ext.Prebid.Floors.ModelVersion != "m1" {
+		t.Errorf("unexpected floors signal: %+v", ext.Prebid.Floors)
+	}
+
+	var impExt struct {
+		Prebid struct {
+			Floors ImpFloor `json:"floors"`
+		} `json:"prebid"`
+	}
+	if err := json.Unmarshal(req.Imp[0].Ext, &impExt); err != nil {
+		t.Fatalf("failed to parse imp ext: %v", err)
+	}
+	if impExt.Prebid.Floors.FloorRule != "banner" || impExt.Prebid.Floors.FloorValue != 1.00 {
+		t.Errorf("unexpected imp floors signal: %+v", impExt.Prebid.Floors)
+	}
+}
+
+func TestImpFieldValues(t *testing.T) {
+	req := &openrtb.BidRequest{
+		App:    &openrtb.App{Publisher: &openrtb.Publisher{Domain: "Example.com"}},
+		Device: &openrtb.Device{DeviceType: 5},
+	}
+	imp := &openrtb.Imp{
+		Banner: &openrtb.Banner{Format: []openrtb.Format{{W: 320, H: 50}}},
+		Ext:    json.RawMessage(`{"data":{"adserver":{"name":"gam","adslot":"/1234/Home"},"pbadslot":"home"}}`),
+	}
+
+	got := impFieldValues(req, imp, []string{FieldMediaType, FieldSize, FieldDomain, FieldGPTSlot, FieldDeviceType, FieldCountry})
+	want := []string{"banner", "320x50", "example.com", "/1234/home", "tablet", ""}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("field %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
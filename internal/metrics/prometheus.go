@@ -51,6 +51,23 @@ type Metrics struct {
 	PlatformMarginTotal  *prometheus.CounterVec   // Platform revenue (difference)
 	MarginPercentage     *prometheus.HistogramVec // Margin % distribution
 	FloorAdjustments     *prometheus.CounterVec   // Floor price adjustments
+
+	// Bid adjustment metrics
+	BidAdjustmentsTotal   *prometheus.CounterVec // Bids whose price was adjusted
+	BidAdjustmentOriginal *prometheus.CounterVec // Bid value before adjustment
+	BidAdjustmentAdjusted *prometheus.CounterVec // Bid value after adjustment
+
+	// Seat non-bid metrics
+	SeatNonBids *prometheus.CounterVec // Bids and imps that did not take part in the auction
+
+	// Bidder rate limit metrics
+	BidderThrottled *prometheus.CounterVec // Bidder calls skipped at a QPS, daily or concurrency cap
+
+	// Bidder health metrics
+	BidderCircuitState *prometheus.GaugeVec // Per-bidder circuit breaker state
+
+	// Bidder traffic metrics
+	BidderBytes *prometheus.CounterVec // Bytes sent to and received from bidders on the wire
 }
 
 // NewMetrics creates and registers all Prometheus metrics
@@ -284,6 +301,72 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{"publisher"},
 		),
+
+		// Bid adjustment metrics
+		BidAdjustmentsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "bid_adjustments_total",
+				Help:      "Number of bids whose price was changed by bid adjustments",
+			},
+			[]string{"publisher", "bidder", "media_type"},
+		),
+		BidAdjustmentOriginal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "bid_adjustment_original_total",
+				Help:      "Total bid value in currency units before bid adjustments",
+			},
+			[]string{"publisher", "bidder", "media_type"},
+		),
+		BidAdjustmentAdjusted: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "bid_adjustment_adjusted_total",
+				Help:      "Total bid value in currency units after bid adjustments",
+			},
+			[]string{"publisher", "bidder", "media_type"},
+		),
+
+		// Seat non-bid metrics
+		SeatNonBids: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "seat_nonbids_total",
+				Help:      "Bids rejected or imps not bid on, by bidder and seat-non-bid reason",
+			},
+			[]string{"bidder", "reason"},
+		),
+
+		// Bidder rate limit metrics
+		BidderThrottled: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "bidder_throttled_total",
+				Help:      "Bidder calls skipped because the bidder reached a rate limit, by limit (qps, daily, concurrent)",
+			},
+			[]string{"bidder", "limit"},
+		),
+
+		// Bidder health metrics
+		BidderCircuitState: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "bidder_circuit_breaker_state",
+				Help:      "Bidder circuit breaker state (0=closed, 1=open, 2=half-open)",
+			},
+			[]string{"bidder"},
+		),
+
+		// Bidder traffic metrics
+		BidderBytes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "bidder_bytes_total",
+				Help:      "Bytes sent to and received from bidders on the wire (after compression), by direction (sent, received)",
+			},
+			[]string{"bidder", "direction"},
+		),
 	}
 
 	// Register all metrics
@@ -314,6 +397,13 @@ func NewMetrics(namespace string) *Metrics {
 		m.PlatformMarginTotal,
 		m.MarginPercentage,
 		m.FloorAdjustments,
+		m.BidAdjustmentsTotal,
+		m.BidAdjustmentOriginal,
+		m.BidAdjustmentAdjusted,
+		m.SeatNonBids,
+		m.BidderThrottled,
+		m.BidderCircuitState,
+		m.BidderBytes,
 	)
 
 	return m
@@ -452,3 +542,44 @@ func (m *Metrics) RecordMargin(publisher, bidder, mediaType string, originalPric
 func (m *Metrics) RecordFloorAdjustment(publisher string) {
 	m.FloorAdjustments.WithLabelValues(publisher).Inc()
 }
+
+// RecordBidAdjustment records a bid price changed by bid adjustment factors or rules
+// originalPrice: the bid price from the bidder
+// adjustedPrice: the price that entered the auction
+func (m *Metrics) RecordBidAdjustment(publisher, bidder, mediaType string, originalPrice, adjustedPrice float64) {
+	m.BidAdjustmentsTotal.WithLabelValues(publisher, bidder, mediaType).Inc()
+	m.BidAdjustmentOriginal.WithLabelValues(publisher, bidder, mediaType).Add(originalPrice)
+	m.BidAdjustmentAdjusted.WithLabelValues(publisher, bidder, mediaType).Add(adjustedPrice)
+}
+
+// RecordSeatNonBid records a bid or imp that did not take part in the auction
+// reason is the seat-non-bid reason name (e.g. "below_floor", "timeout")
+func (m *Metrics) RecordSeatNonBid(bidder, reason string) {
+	m.SeatNonBids.WithLabelValues(bidder, reason).Inc()
+}
+
+// RecordBidderThrottled records a bidder call skipped at a rate limit
+// limit is the cap that was reached: "qps", "daily" or "concurrent"
+func (m *Metrics) RecordBidderThrottled(bidder, limit string) {
+	m.BidderThrottled.WithLabelValues(bidder, limit).Inc()
+}
+
+// SetBidderCircuitState sets a bidder's circuit breaker state metric
+func (m *Metrics) SetBidderCircuitState(bidder, state string) {
+	var value float64
+	switch state {
+	case "closed":
+		value = 0
+	case "open":
+		value = 1
+	case "half-open":
+		value = 2
+	}
+	m.BidderCircuitState.WithLabelValues(bidder).Set(value)
+}
+
+// RecordBidderBytes records the bytes of a bidder request and its response on the wire
+func (m *Metrics) RecordBidderBytes(bidder string, sent, received int) {
+	m.BidderBytes.WithLabelValues(bidder, "sent").Add(float64(sent))
+	m.BidderBytes.WithLabelValues(bidder, "received").Add(float64(received))
+}
@@ -1021,3 +1021,96 @@ func TestCreateTestMetrics_DefaultNamespace(t *testing.T) {
 
 	_ = m // Silence unused variable warning
 }
+
+func TestRecordBidAdjustment(t *testing.T) {
+	labels := []string{"publisher", "bidder", "media_type"}
+	m := &Metrics{
+		BidAdjustmentsTotal:   prometheus.NewCounterVec(prometheus.CounterOpts{Name: "bid_adjustments_total"}, labels),
+		BidAdjustmentOriginal: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "bid_adjustment_original_total"}, labels),
+		BidAdjustmentAdjusted: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "bid_adjustment_adjusted_total"}, labels),
+	}
+
+	m.RecordBidAdjustment("pub1", "rubicon", "banner", 2.00, 1.80)
+	m.RecordBidAdjustment("pub1", "rubicon", "banner", 1.00, 0.90)
+
+	if v := testutil.ToFloat64(m.BidAdjustmentsTotal.WithLabelValues("pub1", "rubicon", "banner")); v != 2 {
+		t.Errorf("expected 2 adjustments, got %v", v)
+	}
+	if v := testutil.ToFloat64(m.BidAdjustmentOriginal.WithLabelValues("pub1", "rubicon", "banner")); v != 3.00 {
+		t.Errorf("expected original total 3.00, got %v", v)
+	}
+	if v := testutil.ToFloat64(m.BidAdjustmentAdjusted.WithLabelValues("pub1", "rubicon", "banner")); v < 2.6999 || v > 2.7001 {
+		t.Errorf("expected adjusted total 2.70, got %v", v)
+	}
+}
+
+func TestRecordBidderThrottled(t *testing.T) {
+	m := &Metrics{
+		BidderThrottled: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "bidder_throttled_total"}, []string{"bidder", "limit"}),
+	}
+
+	m.RecordBidderThrottled("rubicon", "qps")
+	m.RecordBidderThrottled("rubicon", "qps")
+	m.RecordBidderThrottled("rubicon", "daily")
+
+	if v := testutil.ToFloat64(m.BidderThrottled.WithLabelValues("rubicon", "qps")); v != 2 {
+		t.Errorf("expected 2 qps throttles, got %v", v)
+	}
+	if v := testutil.ToFloat64(m.BidderThrottled.WithLabelValues("rubicon", "daily")); v != 1 {
+		t.Errorf("expected 1 daily throttle, got %v", v)
+	}
+}
+
+func TestSetBidderCircuitState(t *testing.T) {
+	m := &Metrics{
+		BidderCircuitState: prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "bidder_circuit_breaker_state"}, []string{"bidder"}),
+	}
+
+	m.SetBidderCircuitState("rubicon", "open")
+	m.SetBidderCircuitState("appnexus", "half-open")
+
+	if v := testutil.ToFloat64(m.BidderCircuitState.WithLabelValues("rubicon")); v != 1 {
+		t.Errorf("expected rubicon open (1), got %v", v)
+	}
+	if v := testutil.ToFloat64(m.BidderCircuitState.WithLabelValues("appnexus")); v != 2 {
+		t.Errorf("expected appnexus half-open (2), got %v", v)
+	}
+
+	m.SetBidderCircuitState("rubicon", "closed")
+	if v := testutil.ToFloat64(m.BidderCircuitState.WithLabelValues("rubicon")); v != 0 {
+		t.Errorf("expected rubicon closed (0), got %v", v)
+	}
+}
+
+func TestRecordBidderBytes(t *testing.T) {
+	m := &Metrics{
+		BidderBytes: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "bidder_bytes_total"}, []string{"bidder", "direction"}),
+	}
+
+	m.RecordBidderBytes("pubmatic", 400, 1200)
+	m.RecordBidderBytes("pubmatic", 350, 0)
+
+	if v := testutil.ToFloat64(m.BidderBytes.WithLabelValues("pubmatic", "sent")); v != 750 {
+		t.Errorf("expected 750 bytes sent, got %v", v)
+	}
+	if v := testutil.ToFloat64(m.BidderBytes.WithLabelValues("pubmatic", "received")); v != 1200 {
+		t.Errorf("expected 1200 bytes received, got %v", v)
+	}
+}
+
+func TestRecordSeatNonBid(t *testing.T) {
+	m := &Metrics{
+		SeatNonBids: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "seat_nonbids_total"}, []string{"bidder", "reason"}),
+	}
+
+	m.RecordSeatNonBid("rubicon", "below_floor")
+	m.RecordSeatNonBid("rubicon", "below_floor")
+	m.RecordSeatNonBid("appnexus", "timeout")
+
+	if v := testutil.ToFloat64(m.SeatNonBids.WithLabelValues("rubicon", "below_floor")); v != 2 {
+		t.Errorf("expected 2 below_floor non-bids, got %v", v)
+	}
+	if v := testutil.ToFloat64(m.SeatNonBids.WithLabelValues("appnexus", "timeout")); v != 1 {
+		t.Errorf("expected 1 timeout non-bid, got %v", v)
+	}
+}
@@ -690,6 +690,49 @@ func CheckVendorConsentStatic(consentString string, gvlID int) bool {
 	return exists && hasConsent
 }
 
+// CheckPurposeConsentStatic checks if a TCF v2 consent string grants a specific purpose
+// Purpose IDs are 1-based per the TCF specification
+func CheckPurposeConsentStatic(consentString string, purpose int) bool {
+	if consentString == "" || purpose <= 0 {
+		return false
+	}
+
+	m := &PrivacyMiddleware{}
+	tcfData, err := m.parseTCFv2String(consentString)
+	if err != nil || tcfData == nil {
+		return false
+	}
+
+	return len(m.checkPurposeConsents(tcfData, []int{purpose})) == 0
+}
+
+// AllowsIdentifierStorage reports whether the request permits storing and reading
+// a first-party identifier on the user's device (e.g. a SharedID cookie)
+// COPPA, a US Privacy opt-out, or GDPR without Purpose 1 consent all deny storage
+func AllowsIdentifierStorage(req *openrtb.BidRequest) bool {
+	if req == nil {
+		return false
+	}
+
+	if req.Regs != nil {
+		if req.Regs.COPPA == 1 {
+			return false
+		}
+		if len(req.Regs.USPrivacy) >= 3 && req.Regs.USPrivacy[2] == 'Y' {
+			return false
+		}
+		if req.Regs.GDPR != nil && *req.Regs.GDPR == 1 {
+			consentString := ""
+			if req.User != nil {
+				consentString = req.User.Consent
+			}
+			return CheckPurposeConsentStatic(consentString, PurposeStorageAccess)
+		}
+	}
+
+	return true
+}
+
 // DetectRegulationFromGeo determines which privacy regulation applies based on geo
 // This is a standalone function for use in the exchange during auction
 // Pass either device.geo or user.geo
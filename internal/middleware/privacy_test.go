@@ -720,3 +720,51 @@ func TestPrivacyMiddleware_NoAnonymizationWithoutGDPR(t *testing.T) {
 		t.Errorf("Expected original IP without GDPR, got %q", modifiedReq.Device.IP)
 	}
 }
+
+func TestAllowsIdentifierStorage(t *testing.T) {
+	gdprOn := 1
+	gdprOff := 0
+	validConsent := "CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA"
+
+	tests := []struct {
+		name string
+		req  *openrtb.BidRequest
+		want bool
+	}{
+		{"nil request", nil, false},
+		{"no regs", &openrtb.BidRequest{}, true},
+		{"gdpr not applicable", &openrtb.BidRequest{Regs: &openrtb.Regs{GDPR: &gdprOff}}, true},
+		{"coppa", &openrtb.BidRequest{Regs: &openrtb.Regs{COPPA: 1}}, false},
+		{"ccpa opt-out", &openrtb.BidRequest{Regs: &openrtb.Regs{USPrivacy: "1YYN"}}, false},
+		{"ccpa no opt-out", &openrtb.BidRequest{Regs: &openrtb.Regs{USPrivacy: "1YNN"}}, true},
+		{"gdpr without consent", &openrtb.BidRequest{Regs: &openrtb.Regs{GDPR: &gdprOn}}, false},
+		{"gdpr invalid consent", &openrtb.BidRequest{
+			Regs: &openrtb.Regs{GDPR: &gdprOn},
+			User: &openrtb.User{Consent: "invalid"},
+		}, false},
+		{"gdpr consent without purpose 1", &openrtb.BidRequest{
+			Regs: &openrtb.Regs{GDPR: &gdprOn},
+			User: &openrtb.User{Consent: validConsent},
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AllowsIdentifierStorage(tt.req); got != tt.want {
+				t.Errorf("AllowsIdentifierStorage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPurposeConsentStatic_Invalid(t *testing.T) {
+	if CheckPurposeConsentStatic("", PurposeStorageAccess) {
+		t.Error("Expected empty consent to deny purpose")
+	}
+	if CheckPurposeConsentStatic("not-a-consent-string", PurposeStorageAccess) {
+		t.Error("Expected invalid consent to deny purpose")
+	}
+	if CheckPurposeConsentStatic("CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA", 0) {
+		t.Error("Expected purpose 0 to be rejected")
+	}
+}
@@ -17,11 +17,12 @@ type BidRequest struct {
 	WSeat  []string        `json:"wseat,omitempty"` // Allowed buyer seats
 	BSeat  []string        `json:"bseat,omitempty"` // Blocked buyer seats
 	AllImp int             `json:"allimps,omitempty"`
-	Cur    []string        `json:"cur,omitempty"`   // Allowed currencies
-	WLang  []string        `json:"wlang,omitempty"` // Allowed languages
-	BCat   []string        `json:"bcat,omitempty"`  // Blocked categories
-	BAdv   []string        `json:"badv,omitempty"`  // Blocked advertisers
-	BApp   []string        `json:"bapp,omitempty"`  // Blocked apps
+	Cur    []string        `json:"cur,omitempty"`    // Allowed currencies
+	WLang  []string        `json:"wlang,omitempty"`  // Allowed languages
+	BCat   []string        `json:"bcat,omitempty"`   // Blocked categories
+	BAdv   []string        `json:"badv,omitempty"`   // Blocked advertisers
+	BApp   []string        `json:"bapp,omitempty"`   // Blocked apps
+	CatTax int             `json:"cattax,omitempty"` // Taxonomy of bcat (OpenRTB 2.6; 0/1 = IAB Content Category 1.0)
 	Source *Source         `json:"source,omitempty"`
 	Regs   *Regs           `json:"regs,omitempty"`
 	Ext    json.RawMessage `json:"ext,omitempty"`
@@ -390,3 +391,73 @@ type Regs struct {
 	GPPSID    []int           `json:"gpp_sid,omitempty"`
 	Ext       json.RawMessage `json:"ext,omitempty"`
 }
+
+// BidRequestExt represents PBS-specific request extensions
+type BidRequestExt struct {
+	Prebid *ExtRequestPrebid `json:"prebid,omitempty"`
+}
+
+// ExtRequestPrebid represents the ext.prebid fields read by the exchange
+type ExtRequestPrebid struct {
+	MultiBid             []ExtMultiBid            `json:"multibid,omitempty"`
+	BidAdjustmentFactors *ExtBidAdjustmentFactors `json:"bidadjustmentfactors,omitempty"`
+	BidAdjustments       *ExtBidAdjustments       `json:"bidadjustments,omitempty"`
+	ReturnAllBidStatus   bool                     `json:"returnallbidstatus,omitempty"` // Return ext.seatnonbid in the response
+	Aliases              map[string]string        `json:"aliases,omitempty"`            // Alias -> core bidder code
+	AliasGVLIDs          map[string]int           `json:"aliasgvlids,omitempty"`        // Alias -> GVL vendor ID
+}
+
+// ExtMultiBid represents an ext.prebid.multibid entry
+// Bidder with TargetBidderCodePrefix sets targeting for extra bids; Bidders shares
+// MaxBids across several bidders without targeting for extra bids
+type ExtMultiBid struct {
+	Bidder                 string   `json:"bidder,omitempty"`
+	Bidders                []string `json:"bidders,omitempty"`
+	MaxBids                *int     `json:"maxbids,omitempty"`
+	TargetBidderCodePrefix string   `json:"targetbiddercodeprefix,omitempty"`
+}
+
+// ExtBidAdjustmentFactors represents ext.prebid.bidadjustmentfactors
+// Keys are bidder codes with a price multiplier; "mediatypes" holds per media type overrides
+type ExtBidAdjustmentFactors struct {
+	Bidders    map[string]float64
+	MediaTypes map[string]map[string]float64
+}
+
+// UnmarshalJSON splits bidder factors from the nested "mediatypes" object
+// Malformed values are ignored rather than failing the whole ext.prebid object
+func (f *ExtBidAdjustmentFactors) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	f.Bidders = make(map[string]float64, len(raw))
+	for key, value := range raw {
+		if key == "mediatypes" {
+			var mediaTypes map[string]map[string]float64
+			if err := json.Unmarshal(value, &mediaTypes); err == nil {
+				f.MediaTypes = mediaTypes
+			}
+			continue
+		}
+		var factor float64
+		if err := json.Unmarshal(value, &factor); err == nil {
+			f.Bidders[key] = factor
+		}
+	}
+	return nil
+}
+
+// ExtBidAdjustments represents ext.prebid.bidadjustments rules
+// MediaType is keyed by media type, then bidder code, then deal ID; "*" matches any value
+type ExtBidAdjustments struct {
+	MediaType map[string]map[string]map[string][]ExtBidAdjustment `json:"mediatype,omitempty"`
+}
+
+// ExtBidAdjustment is a single adjustment applied to a bid price
+// AdjType is "multiplier", "cpm" (fixed fee subtracted) or "static" (price replaced)
+type ExtBidAdjustment struct {
+	AdjType  string  `json:"adjtype"`
+	Value    float64 `json:"value"`
+	Currency string  `json:"currency,omitempty"`
+}
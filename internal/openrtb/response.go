@@ -38,6 +38,7 @@ type Bid struct {
 	CRID           string          `json:"crid,omitempty"`
 	Tactic         string          `json:"tactic,omitempty"`
 	Cat            []string        `json:"cat,omitempty"`
+	CatTax         int             `json:"cattax,omitempty"` // Taxonomy of cat (OpenRTB 2.6; 0/1 = IAB Content Category 1.0)
 	Attr           []int           `json:"attr,omitempty"`
 	API            int             `json:"api,omitempty"`
 	Protocol       int             `json:"protocol,omitempty"`
@@ -85,6 +86,98 @@ type BidResponseExt struct {
 	Warnings           map[string][]ExtBidderMessage `json:"warnings,omitempty"`
 	TMMaxRequest       int                           `json:"tmaxrequest,omitempty"`
 	Prebid             *ExtBidResponsePrebid         `json:"prebid,omitempty"`
+	SeatNonBid         []SeatNonBid                  `json:"seatnonbid,omitempty"`
+}
+
+// NonBidStatusCode explains why a bidder's bid, or an imp it did not bid on, did not take part in the auction
+// 1xx: no bid from the bidder, 2xx: price and auction outcome, 3xx: filtered by the exchange
+type NonBidStatusCode int
+
+const (
+	NonBidNoBid       NonBidStatusCode = 100 // Bidder returned no bid for the imp
+	NonBidTimeout     NonBidStatusCode = 101 // Bidder did not respond in time
+	NonBidBidderError NonBidStatusCode = 102 // Bidder request or response failed
+
+	NonBidBelowFloor      NonBidStatusCode = 200 // Bid below the imp, deal or minimum floor
+	NonBidLostToHigherBid NonBidStatusCode = 201 // Bid lost the auction to a higher bid
+	NonBidLostToDealBid   NonBidStatusCode = 202 // Bid lost the auction to a higher priority deal
+
+	NonBidCreativeFiltered NonBidStatusCode = 300 // Bid blocked by bcat, badv, battr or bapp
+	NonBidPrivacy          NonBidStatusCode = 301 // Bidder not called for lack of consent
+	NonBidInvalidBid       NonBidStatusCode = 302 // Bid failed OpenRTB or deal validation
+	NonBidDuplicateBid     NonBidStatusCode = 303 // Bid ID already seen in the auction
+	NonBidThrottled        NonBidStatusCode = 304 // Bidder not called because it reached a rate limit
+	NonBidBidderUnhealthy  NonBidStatusCode = 305 // Bidder not called because its circuit breaker is open
+)
+
+// String returns the snake_case reason name used in metrics and analytics
+func (c NonBidStatusCode) String() string {
+	switch c {
+	case NonBidNoBid:
+		return "no_bid"
+	case NonBidTimeout:
+		return "timeout"
+	case NonBidBidderError:
+		return "bidder_error"
+	case NonBidBelowFloor:
+		return "below_floor"
+	case NonBidLostToHigherBid:
+		return "lost_to_higher_bid"
+	case NonBidLostToDealBid:
+		return "lost_to_deal_bid"
+	case NonBidCreativeFiltered:
+		return "creative_filtered"
+	case NonBidPrivacy:
+		return "privacy"
+	case NonBidInvalidBid:
+		return "invalid_bid"
+	case NonBidDuplicateBid:
+		return "duplicate_bid"
+	case NonBidThrottled:
+		return "throttled"
+	case NonBidBidderUnhealthy:
+		return "bidder_unhealthy"
+	default:
+		return "unknown"
+	}
+}
+
+// SeatNonBid lists the bids a seat returned that did not take part in the auction
+type SeatNonBid struct {
+	Seat   string   `json:"seat"`
+	NonBid []NonBid `json:"nonbid"`
+}
+
+// NonBid describes one rejected bid
+type NonBid struct {
+	ImpID      string           `json:"impid"`
+	StatusCode NonBidStatusCode `json:"statuscode"`
+	Ext        *NonBidExt       `json:"ext,omitempty"`
+}
+
+// NonBidExt carries details of the rejected bid
+type NonBidExt struct {
+	Prebid NonBidExtPrebid `json:"prebid"`
+}
+
+// NonBidExtPrebid holds the rejected bid and the reason it was rejected
+type NonBidExtPrebid struct {
+	Bid    NonBidBid `json:"bid"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// NonBidBid is the subset of the rejected bid reported back
+type NonBidBid struct {
+	ID      string   `json:"id,omitempty"`
+	Price   float64  `json:"price"`
+	ADomain []string `json:"adomain,omitempty"`
+	Cat     []string `json:"cat,omitempty"`
+	Attr    []int    `json:"attr,omitempty"`
+	Bundle  string   `json:"bundle,omitempty"`
+	CRID    string   `json:"crid,omitempty"`
+	DealID  string   `json:"dealid,omitempty"`
+	W       int      `json:"w,omitempty"`
+	H       int      `json:"h,omitempty"`
 }
 
 // ExtBidderMessage represents bidder message
@@ -106,12 +199,13 @@ type BidExt struct {
 
 // ExtBidPrebid represents prebid bid extension
 type ExtBidPrebid struct {
-	Cache     *ExtBidPrebidCache  `json:"cache,omitempty"`
-	Targeting map[string]string   `json:"targeting,omitempty"`
-	Type      string              `json:"type,omitempty"`
-	Video     *ExtBidPrebidVideo  `json:"video,omitempty"`
-	Events    *ExtBidPrebidEvents `json:"events,omitempty"`
-	Meta      *ExtBidPrebidMeta   `json:"meta,omitempty"`
+	Cache            *ExtBidPrebidCache  `json:"cache,omitempty"`
+	Targeting        map[string]string   `json:"targeting,omitempty"`
+	TargetBidderCode string              `json:"targetbiddercode,omitempty"` // Multibid targeting code for extra bids
+	Type             string              `json:"type,omitempty"`
+	Video            *ExtBidPrebidVideo  `json:"video,omitempty"`
+	Events           *ExtBidPrebidEvents `json:"events,omitempty"`
+	Meta             *ExtBidPrebidMeta   `json:"meta,omitempty"`
 }
 
 // ExtBidPrebidCache represents cache info
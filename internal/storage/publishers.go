@@ -13,17 +13,24 @@ import (
 
 // Publisher represents a publisher configuration from the database
 type Publisher struct {
-	ID             string                 `json:"id"`
-	PublisherID    string                 `json:"publisher_id"`
-	Name           string                 `json:"name"`
-	AllowedDomains string                 `json:"allowed_domains"`
-	BidderParams   map[string]interface{} `json:"bidder_params"`
-	BidMultiplier  float64                `json:"bid_multiplier"` // Revenue share multiplier (1.0000-10.0000). Bid divided by this. 1.05 = ~5% platform cut
-	Status         string                 `json:"status"`
-	CreatedAt      time.Time              `json:"created_at"`
-	UpdatedAt      time.Time              `json:"updated_at"`
-	Notes          string                 `json:"notes,omitempty"`
-	ContactEmail   string                 `json:"contact_email,omitempty"`
+	ID                         string                 `json:"id"`
+	PublisherID                string                 `json:"publisher_id"`
+	Name                       string                 `json:"name"`
+	AllowedDomains             string                 `json:"allowed_domains"`
+	BidderParams               map[string]interface{} `json:"bidder_params"`
+	BidMultiplier              float64                `json:"bid_multiplier"`                          // Revenue share multiplier (1.0000-10.0000). Bid divided by this. 1.05 = ~5% platform cut
+	DealFloors                 map[string]float64     `json:"deal_floors,omitempty"`                   // Minimum CPM per PMP deal ID
+	PriceFloors                json.RawMessage        `json:"price_floors,omitempty"`                  // Prebid floors-module rule set
+	BidAdjustments             json.RawMessage        `json:"bid_adjustments,omitempty"`               // Bid adjustment rules (ext.prebid.bidadjustments schema)
+	AuctionType                int                    `json:"auction_type,omitempty"`                  // OpenRTB "at" used when the request omits it (0 = exchange default)
+	Blocklists                 json.RawMessage        `json:"blocklists,omitempty"`                    // Blocked categories, advertiser domains, creative attributes and apps
+	NativeRender               bool                   `json:"native_render,omitempty"`                 // Return native bids as rendered HTML instead of native JSON
+	AllowRequestBidAdjustments bool                   `json:"allow_request_bid_adjustments,omitempty"` // Apply ext.prebid.bidadjustments and bidadjustmentfactors from requests
+	Status                     string                 `json:"status"`
+	CreatedAt                  time.Time              `json:"created_at"`
+	UpdatedAt                  time.Time              `json:"updated_at"`
+	Notes                      string                 `json:"notes,omitempty"`
+	ContactEmail               string                 `json:"contact_email,omitempty"`
 }
 
 // GetAllowedDomains returns the allowed domains string (for middleware interface)
@@ -36,6 +43,47 @@ func (p *Publisher) GetBidMultiplier() float64 {
 	return p.BidMultiplier
 }
 
+// GetDealFloors returns the per-deal floor prices (for exchange interface)
+func (p *Publisher) GetDealFloors() map[string]float64 {
+	return p.DealFloors
+}
+
+// GetPriceFloors returns the stored floor rule set (for exchange interface)
+func (p *Publisher) GetPriceFloors() json.RawMessage {
+	return p.PriceFloors
+}
+
+// GetBidAdjustments returns the stored bid adjustment rules (for exchange interface)
+func (p *Publisher) GetBidAdjustments() json.RawMessage {
+	return p.BidAdjustments
+}
+
+// GetAuctionType returns the publisher's auction type (for exchange interface)
+func (p *Publisher) GetAuctionType() int {
+	return p.AuctionType
+}
+
+// GetBlocklists returns the stored response blocklists (for exchange interface)
+func (p *Publisher) GetBlocklists() json.RawMessage {
+	return p.Blocklists
+}
+
+// GetNativeRender returns whether native bids are rendered to HTML (for exchange interface)
+func (p *Publisher) GetNativeRender() bool {
+	return p.NativeRender
+}
+
+// GetAllowRequestBidAdjustments returns whether requests may adjust bid prices (for exchange interface)
+func (p *Publisher) GetAllowRequestBidAdjustments() bool {
+	return p.AllowRequestBidAdjustments
+}
+
+// GetBidderParams returns the stored params of one bidder, or nil (for exchange interface)
+func (p *Publisher) GetBidderParams(bidderCode string) map[string]interface{} {
+	params, _ := p.BidderParams[bidderCode].(map[string]interface{})
+	return params
+}
+
 // GetPublisherID returns the publisher ID (for exchange interface)
 func (p *Publisher) GetPublisherID() string {
 	return p.PublisherID
@@ -61,13 +109,14 @@ func (s *PublisherStore) GetByPublisherID(ctx context.Context, publisherID strin
 func (s *PublisherStore) getByPublisherIDConcrete(ctx context.Context, publisherID string) (*Publisher, error) {
 	query := `
 		SELECT id, publisher_id, name, allowed_domains, bidder_params, bid_multiplier,
-		       status, created_at, updated_at, notes, contact_email
+		       deal_floors, price_floors, bid_adjustments, auction_type, blocklists, native_render, allow_request_bid_adjustments, status, created_at, updated_at, notes, contact_email
 		FROM publishers
 		WHERE publisher_id = $1 AND status = 'active'
 	`
 
 	var p Publisher
-	var bidderParamsJSON []byte
+	var bidderParamsJSON, dealFloorsJSON, priceFloorsJSON, bidAdjustmentsJSON, blocklistsJSON []byte
+	var auctionType sql.NullInt64
 
 	err := s.db.QueryRowContext(ctx, query, publisherID).Scan(
 		&p.ID,
@@ -76,6 +125,13 @@ func (s *PublisherStore) getByPublisherIDConcrete(ctx context.Context, publisher
 		&p.AllowedDomains,
 		&bidderParamsJSON,
 		&p.BidMultiplier,
+		&dealFloorsJSON,
+		&priceFloorsJSON,
+		&bidAdjustmentsJSON,
+		&auctionType,
+		&blocklistsJSON,
+		&p.NativeRender,
+		&p.AllowRequestBidAdjustments,
 		&p.Status,
 		&p.CreatedAt,
 		&p.UpdatedAt,
@@ -97,6 +153,17 @@ func (s *PublisherStore) getByPublisherIDConcrete(ctx context.Context, publisher
 		}
 	}
 
+	// Parse JSONB deal_floors
+	if len(dealFloorsJSON) > 0 {
+		if err := json.Unmarshal(dealFloorsJSON, &p.DealFloors); err != nil {
+			return nil, fmt.Errorf("failed to parse deal_floors: %w", err)
+		}
+	}
+	p.PriceFloors = nullableJSON(priceFloorsJSON)
+	p.BidAdjustments = nullableJSON(bidAdjustmentsJSON)
+	p.AuctionType = int(auctionType.Int64)
+	p.Blocklists = nullableJSON(blocklistsJSON)
+
 	return &p, nil
 }
 
@@ -104,7 +171,7 @@ func (s *PublisherStore) getByPublisherIDConcrete(ctx context.Context, publisher
 func (s *PublisherStore) List(ctx context.Context) ([]*Publisher, error) {
 	query := `
 		SELECT id, publisher_id, name, allowed_domains, bidder_params, bid_multiplier,
-		       status, created_at, updated_at, notes, contact_email
+		       deal_floors, price_floors, bid_adjustments, auction_type, blocklists, native_render, allow_request_bid_adjustments, status, created_at, updated_at, notes, contact_email
 		FROM publishers
 		WHERE status = 'active'
 		ORDER BY publisher_id
@@ -119,7 +186,8 @@ func (s *PublisherStore) List(ctx context.Context) ([]*Publisher, error) {
 	publishers := make([]*Publisher, 0, 100)
 	for rows.Next() {
 		var p Publisher
-		var bidderParamsJSON []byte
+		var bidderParamsJSON, dealFloorsJSON, priceFloorsJSON, bidAdjustmentsJSON, blocklistsJSON []byte
+		var auctionType sql.NullInt64
 
 		err := rows.Scan(
 			&p.ID,
@@ -128,6 +196,13 @@ func (s *PublisherStore) List(ctx context.Context) ([]*Publisher, error) {
 			&p.AllowedDomains,
 			&bidderParamsJSON,
 			&p.BidMultiplier,
+			&dealFloorsJSON,
+			&priceFloorsJSON,
+			&bidAdjustmentsJSON,
+			&auctionType,
+			&blocklistsJSON,
+			&p.NativeRender,
+			&p.AllowRequestBidAdjustments,
 			&p.Status,
 			&p.CreatedAt,
 			&p.UpdatedAt,
@@ -145,6 +220,17 @@ func (s *PublisherStore) List(ctx context.Context) ([]*Publisher, error) {
 			}
 		}
 
+		// Parse JSONB deal_floors
+		if len(dealFloorsJSON) > 0 {
+			if err := json.Unmarshal(dealFloorsJSON, &p.DealFloors); err != nil {
+				return nil, fmt.Errorf("failed to parse deal_floors: %w", err)
+			}
+		}
+		p.PriceFloors = nullableJSON(priceFloorsJSON)
+		p.BidAdjustments = nullableJSON(bidAdjustmentsJSON)
+		p.AuctionType = int(auctionType.Int64)
+		p.Blocklists = nullableJSON(blocklistsJSON)
+
 		publishers = append(publishers, &p)
 	}
 
@@ -166,8 +252,9 @@ func (s *PublisherStore) Create(ctx context.Context, p *Publisher) error {
 
 	query := `
 		INSERT INTO publishers (
-			publisher_id, name, allowed_domains, bidder_params, bid_multiplier, status, notes, contact_email
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			publisher_id, name, allowed_domains, bidder_params, bid_multiplier, deal_floors, price_floors,
+			bid_adjustments, auction_type, blocklists, native_render, allow_request_bid_adjustments, status, notes, contact_email
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 		RETURNING id, created_at, updated_at
 	`
 
@@ -176,12 +263,24 @@ func (s *PublisherStore) Create(ctx context.Context, p *Publisher) error {
 		return fmt.Errorf("failed to marshal bidder_params: %w", err)
 	}
 
+	dealFloorsJSON, err := marshalDealFloors(p.DealFloors)
+	if err != nil {
+		return err
+	}
+
 	err = s.db.QueryRowContext(ctx, query,
 		p.PublisherID,
 		p.Name,
 		p.AllowedDomains,
 		bidderParamsJSON,
 		p.BidMultiplier,
+		dealFloorsJSON,
+		jsonArg(p.PriceFloors),
+		jsonArg(p.BidAdjustments),
+		auctionTypeArg(p.AuctionType),
+		jsonArg(p.Blocklists),
+		p.NativeRender,
+		p.AllowRequestBidAdjustments,
 		status,
 		p.Notes,
 		p.ContactEmail,
@@ -199,8 +298,10 @@ func (s *PublisherStore) Update(ctx context.Context, p *Publisher) error {
 	query := `
 		UPDATE publishers
 		SET name = $1, allowed_domains = $2, bidder_params = $3,
-		    bid_multiplier = $4, status = $5, notes = $6, contact_email = $7
-		WHERE publisher_id = $8
+		    bid_multiplier = $4, deal_floors = $5, price_floors = $6, bid_adjustments = $7, auction_type = $8,
+		    blocklists = $9, native_render = $10, allow_request_bid_adjustments = $11, status = $12, notes = $13,
+		    contact_email = $14
+		WHERE publisher_id = $15
 	`
 
 	bidderParamsJSON, err := json.Marshal(p.BidderParams)
@@ -208,11 +309,23 @@ func (s *PublisherStore) Update(ctx context.Context, p *Publisher) error {
 		return fmt.Errorf("failed to marshal bidder_params: %w", err)
 	}
 
+	dealFloorsJSON, err := marshalDealFloors(p.DealFloors)
+	if err != nil {
+		return err
+	}
+
 	result, err := s.db.ExecContext(ctx, query,
 		p.Name,
 		p.AllowedDomains,
 		bidderParamsJSON,
 		p.BidMultiplier,
+		dealFloorsJSON,
+		jsonArg(p.PriceFloors),
+		jsonArg(p.BidAdjustments),
+		auctionTypeArg(p.AuctionType),
+		jsonArg(p.Blocklists),
+		p.NativeRender,
+		p.AllowRequestBidAdjustments,
 		p.Status,
 		p.Notes,
 		p.ContactEmail,
@@ -300,6 +413,34 @@ func (s *PublisherStore) GetBidderParams(ctx context.Context, publisherID, bidde
 	}, nil
 }
 
+// marshalDealFloors encodes deal floors for the JSONB column, storing an empty object when unset
+func marshalDealFloors(floors map[string]float64) ([]byte, error) {
+	if floors == nil {
+		floors = map[string]float64{}
+	}
+	data, err := json.Marshal(floors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal deal_floors: %w", err)
+	}
+	return data, nil
+}
+
+// nullableJSON returns nil for empty or JSON null values so the column is stored as NULL
+func nullableJSON(data []byte) []byte {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	return data
+}
+
+// jsonArg converts an optional JSON value into a query argument, using NULL when unset
+func jsonArg(data []byte) interface{} {
+	if data = nullableJSON(data); data == nil {
+		return nil
+	}
+	return data
+}
+
 // NewDBConnection creates a new database connection
 func NewDBConnection(host, port, user, password, dbname, sslmode string) (*sql.DB, error) {
 	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
@@ -325,3 +466,11 @@ func NewDBConnection(host, port, user, password, dbname, sslmode string) (*sql.D
 
 	return db, nil
 }
+
+// auctionTypeArg returns the auction type as a query argument, NULL when unset
+func auctionTypeArg(at int) interface{} {
+	if at == 0 {
+		return nil
+	}
+	return at
+}
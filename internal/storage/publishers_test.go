@@ -66,7 +66,7 @@ func TestPublisherStore_GetByPublisherID_Success(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{
 		"id", "publisher_id", "name", "allowed_domains", "bidder_params",
-		"bid_multiplier", "status", "created_at", "updated_at", "notes", "contact_email",
+		"bid_multiplier", "deal_floors", "price_floors", "bid_adjustments", "auction_type", "blocklists", "native_render", "allow_request_bid_adjustments", "status", "created_at", "updated_at", "notes", "contact_email",
 	}).AddRow(
 		expectedPublisher.ID,
 		expectedPublisher.PublisherID,
@@ -74,6 +74,13 @@ func TestPublisherStore_GetByPublisherID_Success(t *testing.T) {
 		expectedPublisher.AllowedDomains,
 		bidderParamsJSON,
 		expectedPublisher.BidMultiplier,
+		[]byte(`{}`),
+		nil,
+		nil,
+		nil,
+		nil,
+		false,
+		false,
 		expectedPublisher.Status,
 		expectedPublisher.CreatedAt,
 		expectedPublisher.UpdatedAt,
@@ -182,7 +189,7 @@ func TestPublisherStore_GetByPublisherID_InvalidJSON(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{
 		"id", "publisher_id", "name", "allowed_domains", "bidder_params",
-		"bid_multiplier", "status", "created_at", "updated_at", "notes", "contact_email",
+		"bid_multiplier", "deal_floors", "price_floors", "bid_adjustments", "auction_type", "blocklists", "native_render", "allow_request_bid_adjustments", "status", "created_at", "updated_at", "notes", "contact_email",
 	}).AddRow(
 		"1",
 		"pub-123",
@@ -190,6 +197,13 @@ func TestPublisherStore_GetByPublisherID_InvalidJSON(t *testing.T) {
 		"example.com",
 		[]byte("{invalid json}"), // Invalid JSON
 		1.05,
+		[]byte(`{}`),
+		nil,
+		nil,
+		nil,
+		nil,
+		false,
+		false,
 		"active",
 		time.Now(),
 		time.Now(),
@@ -234,13 +248,14 @@ func TestPublisherStore_List_Success(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{
 		"id", "publisher_id", "name", "allowed_domains", "bidder_params",
-		"bid_multiplier", "status", "created_at", "updated_at", "notes", "contact_email",
+		"bid_multiplier", "deal_floors", "price_floors", "bid_adjustments", "auction_type", "blocklists", "native_render", "allow_request_bid_adjustments", "status", "created_at", "updated_at", "notes", "contact_email",
 	}).AddRow(
 		pub1.ID, pub1.PublisherID, pub1.Name, pub1.AllowedDomains, bidderParamsJSON1,
-		pub1.BidMultiplier, pub1.Status, pub1.CreatedAt, pub1.UpdatedAt, pub1.Notes, pub1.ContactEmail,
+		pub1.BidMultiplier, []byte(`{}`), nil, nil, nil, nil, false, false, pub1.Status, pub1.CreatedAt, pub1.UpdatedAt, pub1.Notes, pub1.ContactEmail,
 	).AddRow(
 		pub2.ID, pub2.PublisherID, pub2.Name, pub2.AllowedDomains, bidderParamsJSON2,
-		pub2.BidMultiplier, pub2.Status, pub2.CreatedAt, pub2.UpdatedAt, pub2.Notes, pub2.ContactEmail,
+		pub2.BidMultiplier, []byte(`{"deal-1":2.5}`), []byte(`{"floorMin":0.5}`),
+		[]byte(`{"mediatype":{"*":{"*":{"*":[{"adjtype":"multiplier","value":0.9}]}}}}`), int64(2), []byte(`{"badv":["blocked.com"]}`), true, true, pub2.Status, pub2.CreatedAt, pub2.UpdatedAt, pub2.Notes, pub2.ContactEmail,
 	)
 
 	mock.ExpectQuery("SELECT (.+) FROM publishers WHERE status").
@@ -261,6 +276,30 @@ func TestPublisherStore_List_Success(t *testing.T) {
 	if publishers[1].PublisherID != "pub-2" {
 		t.Errorf("Expected 'pub-2', got '%s'", publishers[1].PublisherID)
 	}
+	if publishers[1].DealFloors["deal-1"] != 2.5 {
+		t.Errorf("Expected deal-1 floor 2.5, got %v", publishers[1].DealFloors)
+	}
+	if string(publishers[1].PriceFloors) != `{"floorMin":0.5}` {
+		t.Errorf("Expected stored price floors, got %s", publishers[1].PriceFloors)
+	}
+	if publishers[1].BidAdjustments == nil || publishers[0].BidAdjustments != nil {
+		t.Errorf("Expected bid adjustments only on pub-2, got %s / %s", publishers[0].BidAdjustments, publishers[1].BidAdjustments)
+	}
+	if publishers[1].AuctionType != 2 || publishers[0].AuctionType != 0 {
+		t.Errorf("Expected auction types 0 and 2, got %d and %d", publishers[0].AuctionType, publishers[1].AuctionType)
+	}
+	if string(publishers[1].Blocklists) != `{"badv":["blocked.com"]}` || publishers[0].Blocklists != nil {
+		t.Errorf("Expected blocklists only on pub-2, got %s / %s", publishers[0].Blocklists, publishers[1].Blocklists)
+	}
+	if !publishers[1].NativeRender || publishers[0].NativeRender {
+		t.Errorf("Expected native rendering only on pub-2")
+	}
+	if !publishers[1].AllowRequestBidAdjustments || publishers[0].AllowRequestBidAdjustments {
+		t.Errorf("Expected request bid adjustments allowed only on pub-2")
+	}
+	if publishers[0].PriceFloors != nil {
+		t.Errorf("Expected nil price floors, got %s", publishers[0].PriceFloors)
+	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Unfulfilled expectations: %v", err)
@@ -279,7 +318,7 @@ func TestPublisherStore_List_Empty(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{
 		"id", "publisher_id", "name", "allowed_domains", "bidder_params",
-		"bid_multiplier", "status", "created_at", "updated_at", "notes", "contact_email",
+		"bid_multiplier", "deal_floors", "price_floors", "bid_adjustments", "auction_type", "blocklists", "native_render", "allow_request_bid_adjustments", "status", "created_at", "updated_at", "notes", "contact_email",
 	})
 
 	mock.ExpectQuery("SELECT (.+) FROM publishers WHERE status").
@@ -337,10 +376,10 @@ func TestPublisherStore_List_ScanError(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{
 		"id", "publisher_id", "name", "allowed_domains", "bidder_params",
-		"bid_multiplier", "status", "created_at", "updated_at", "notes", "contact_email",
+		"bid_multiplier", "deal_floors", "price_floors", "bid_adjustments", "auction_type", "blocklists", "native_render", "allow_request_bid_adjustments", "status", "created_at", "updated_at", "notes", "contact_email",
 	}).AddRow(
 		"1", "pub-1", "Test", "example.com", []byte("{invalid}"),
-		1.05, "active", time.Now(), time.Now(), "notes", "test@example.com",
+		1.05, []byte(`{}`), nil, nil, nil, nil, false, false, "active", time.Now(), time.Now(), "notes", "test@example.com",
 	)
 
 	mock.ExpectQuery("SELECT (.+) FROM publishers WHERE status").
@@ -382,6 +421,13 @@ func TestPublisherStore_Create_Success(t *testing.T) {
 			publisher.AllowedDomains,
 			sqlmock.AnyArg(), // bidder_params JSON
 			publisher.BidMultiplier,
+			[]byte(`{}`),
+			nil,
+			nil,
+			nil,
+			nil,
+			false,
+			false,
 			publisher.Status,
 			publisher.Notes,
 			publisher.ContactEmail,
@@ -425,6 +471,13 @@ func TestPublisherStore_Create_DefaultBidMultiplier(t *testing.T) {
 			publisher.AllowedDomains,
 			sqlmock.AnyArg(), // bidder_params JSON
 			1.0,              // Should be defaulted to 1.0
+			sqlmock.AnyArg(), // deal_floors JSON
+			sqlmock.AnyArg(), // price_floors JSON
+			sqlmock.AnyArg(), // bid_adjustments JSON
+			sqlmock.AnyArg(), // auction_type
+			sqlmock.AnyArg(), // blocklists JSON
+			sqlmock.AnyArg(), // native_render
+			sqlmock.AnyArg(), // allow_request_bid_adjustments
 			publisher.Status,
 			publisher.Notes,
 			publisher.ContactEmail,
@@ -483,6 +536,8 @@ func TestPublisherStore_Create_QueryError(t *testing.T) {
 		WithArgs(
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 		).
 		WillReturnError(errors.New("database error"))
 
@@ -515,6 +570,13 @@ func TestPublisherStore_Update_Success(t *testing.T) {
 			publisher.AllowedDomains,
 			sqlmock.AnyArg(), // bidder_params JSON
 			publisher.BidMultiplier,
+			[]byte(`{}`),
+			nil,
+			nil,
+			nil,
+			nil,
+			false,
+			false,
 			publisher.Status,
 			publisher.Notes,
 			publisher.ContactEmail,
@@ -548,6 +610,8 @@ func TestPublisherStore_Update_NotFound(t *testing.T) {
 		WithArgs(
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 		).
 		WillReturnResult(sqlmock.NewResult(0, 0)) // 0 rows affected
 
@@ -599,6 +663,8 @@ func TestPublisherStore_Update_QueryError(t *testing.T) {
 		WithArgs(
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 		).
 		WillReturnError(errors.New("database error"))
 
@@ -817,4 +883,12 @@ func TestPublisher_GetterMethods(t *testing.T) {
 	if publisher.GetBidMultiplier() != 1.05 {
 		t.Errorf("Expected 1.05, got %f", publisher.GetBidMultiplier())
 	}
+
+	if params := publisher.GetBidderParams("appnexus"); params["placementId"] != 12345 {
+		t.Errorf("Expected appnexus placementId 12345, got %v", params)
+	}
+
+	if params := publisher.GetBidderParams("openx"); params != nil {
+		t.Errorf("Expected nil params for unconfigured bidder, got %v", params)
+	}
 }
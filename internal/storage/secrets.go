@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/thenexusengine/tne_springwire/pkg/secrets"
+)
+
+// SecretStore provides database operations for encrypted secrets
+// Values are sealed with the master key before they are stored (see secrets.Cipher), so the
+// table never holds plaintext credentials. Used as the store of a secrets.EncryptedProvider.
+type SecretStore struct {
+	db *sql.DB
+}
+
+// NewSecretStore creates a new secret store
+func NewSecretStore(db *sql.DB) *SecretStore {
+	return &SecretStore{db: db}
+}
+
+// GetSecret retrieves a secret's ciphertext; returns secrets.ErrNotFound for unknown names
+func (s *SecretStore) GetSecret(ctx context.Context, name string) ([]byte, error) {
+	query := `SELECT ciphertext FROM secrets WHERE name = $1`
+
+	var ciphertext []byte
+	err := s.db.QueryRowContext(ctx, query, name).Scan(&ciphertext)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, secrets.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query secret: %w", err)
+	}
+	return ciphertext, nil
+}
+
+// PutSecret creates or replaces a secret's ciphertext (e.g. to rotate a credential)
+func (s *SecretStore) PutSecret(ctx context.Context, name string, ciphertext []byte) error {
+	query := `
+		INSERT INTO secrets (name, ciphertext)
+		VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE
+		SET ciphertext = EXCLUDED.ciphertext, updated_at = NOW()
+	`
+
+	if _, err := s.db.ExecContext(ctx, query, name, ciphertext); err != nil {
+		return fmt.Errorf("failed to store secret: %w", err)
+	}
+	return nil
+}
+
+// Redacted returns a copy of the bidder safe for admin responses and logs
+// Values of credential-like http_headers (see secrets.IsSensitiveName) are replaced unless
+// they are secret:// references.
+func (b *Bidder) Redacted() *Bidder {
+	redacted := *b
+	if b.HTTPHeaders != nil {
+		redacted.HTTPHeaders = make(map[string]interface{}, len(b.HTTPHeaders))
+		for name, value := range b.HTTPHeaders {
+			if s, ok := value.(string); ok && secrets.IsSensitiveName(name) {
+				value = secrets.Redact(s)
+			}
+			redacted.HTTPHeaders[name] = value
+		}
+	}
+	return &redacted
+}
+
+// ResolveHTTPHeaders returns the bidder's http_headers with secret:// references resolved
+func (b *Bidder) ResolveHTTPHeaders(ctx context.Context, resolver *secrets.Resolver) (map[string]string, error) {
+	headers := make(map[string]string, len(b.HTTPHeaders))
+	for name, value := range b.HTTPHeaders {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		resolved, err := resolver.Resolve(ctx, s)
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", name, err)
+		}
+		headers[name] = resolved
+	}
+	return headers, nil
+}
+
+// BidderHeaders supplies the http_headers of stored bidders for outgoing bidder requests
+// References are resolved on every call, so a rotated secret is used once the resolver's
+// cache expires.
+type BidderHeaders struct {
+	bidders  map[string]*Bidder
+	resolver *secrets.Resolver
+}
+
+// NewBidderHeaders creates a header source for the given bidders, keyed by bidder code
+func NewBidderHeaders(bidders []*Bidder, resolver *secrets.Resolver) *BidderHeaders {
+	byCode := make(map[string]*Bidder, len(bidders))
+	for _, b := range bidders {
+		if len(b.HTTPHeaders) > 0 {
+			byCode[b.BidderCode] = b
+		}
+	}
+	return &BidderHeaders{bidders: byCode, resolver: resolver}
+}
+
+// BidderHeaders returns the bidder's resolved http_headers, or nil for bidders without any
+func (h *BidderHeaders) BidderHeaders(ctx context.Context, bidderCode string) (map[string]string, error) {
+	b, ok := h.bidders[bidderCode]
+	if !ok {
+		return nil, nil
+	}
+	return b.ResolveHTTPHeaders(ctx, h.resolver)
+}
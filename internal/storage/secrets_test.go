@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/thenexusengine/tne_springwire/pkg/secrets"
+)
+
+func TestSecretStore_GetSecret(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	store := NewSecretStore(db)
+	ctx := context.Background()
+
+	mock.ExpectQuery("SELECT ciphertext FROM secrets WHERE name").
+		WithArgs("dsp.token").
+		WillReturnRows(sqlmock.NewRows([]string{"ciphertext"}).AddRow([]byte{1, 2, 3}))
+	got, err := store.GetSecret(ctx, "dsp.token")
+	if err != nil || len(got) != 3 {
+		t.Errorf("Expected ciphertext, got %v (%v)", got, err)
+	}
+
+	mock.ExpectQuery("SELECT ciphertext FROM secrets WHERE name").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"ciphertext"}))
+	if _, err := store.GetSecret(ctx, "missing"); !errors.Is(err, secrets.ErrNotFound) {
+		t.Errorf("Expected secrets.ErrNotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestSecretStore_PutSecret(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	store := NewSecretStore(db)
+
+	mock.ExpectExec("INSERT INTO secrets (.+) ON CONFLICT").
+		WithArgs("dsp.token", []byte{1, 2, 3}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := store.PutSecret(context.Background(), "dsp.token", []byte{1, 2, 3}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestBidder_Redacted(t *testing.T) {
+	b := createTestBidder("appnexus")
+	b.HTTPHeaders = map[string]interface{}{
+		"X-API-Key":     "plaintext-key",
+		"Authorization": "secret://appnexus.auth",
+		"X-Region":      "eu",
+	}
+
+	redacted := b.Redacted()
+	if redacted.HTTPHeaders["X-API-Key"] != secrets.Redacted {
+		t.Errorf("Expected plaintext key redacted, got %v", redacted.HTTPHeaders["X-API-Key"])
+	}
+	if redacted.HTTPHeaders["Authorization"] != "secret://appnexus.auth" {
+		t.Errorf("Expected reference kept, got %v", redacted.HTTPHeaders["Authorization"])
+	}
+	if redacted.HTTPHeaders["X-Region"] != "eu" {
+		t.Errorf("Expected non-sensitive header kept, got %v", redacted.HTTPHeaders["X-Region"])
+	}
+	if b.HTTPHeaders["X-API-Key"] != "plaintext-key" {
+		t.Error("Expected original bidder unchanged")
+	}
+}
+
+func TestBidder_ResolveHTTPHeaders(t *testing.T) {
+	t.Setenv("SECRET_APPNEXUS_AUTH", "Bearer abc123")
+	resolver := secrets.NewResolver(&secrets.EnvProvider{Prefix: "SECRET_"}, time.Minute)
+
+	b := createTestBidder("appnexus")
+	b.HTTPHeaders = map[string]interface{}{
+		"Authorization": "secret://appnexus.auth",
+		"X-Region":      "eu",
+	}
+	headers, err := b.ResolveHTTPHeaders(context.Background(), resolver)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if headers["Authorization"] != "Bearer abc123" || headers["X-Region"] != "eu" {
+		t.Errorf("Unexpected headers: %v", headers)
+	}
+
+	b.HTTPHeaders["X-Other"] = "secret://missing"
+	if _, err := b.ResolveHTTPHeaders(context.Background(), resolver); err == nil {
+		t.Error("Expected error for unresolvable reference")
+	}
+}
+
+func TestBidderHeaders(t *testing.T) {
+	t.Setenv("SECRET_APPNEXUS_AUTH", "Bearer abc123")
+	resolver := secrets.NewResolver(&secrets.EnvProvider{Prefix: "SECRET_"}, time.Minute)
+
+	appnexus := createTestBidder("appnexus")
+	appnexus.HTTPHeaders = map[string]interface{}{"Authorization": "secret://appnexus.auth"}
+	rubicon := createTestBidder("rubicon")
+	rubicon.HTTPHeaders = nil
+	source := NewBidderHeaders([]*Bidder{appnexus, rubicon}, resolver)
+
+	headers, err := source.BidderHeaders(context.Background(), "appnexus")
+	if err != nil || headers["Authorization"] != "Bearer abc123" {
+		t.Errorf("Expected resolved appnexus headers, got %v (%v)", headers, err)
+	}
+	for _, code := range []string{"rubicon", "unknown"} {
+		if headers, err := source.BidderHeaders(context.Background(), code); err != nil || headers != nil {
+			t.Errorf("Expected no headers for %s, got %v (%v)", code, headers, err)
+		}
+	}
+}
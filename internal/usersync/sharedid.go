@@ -0,0 +1,204 @@
+// Package usersync provides user ID synchronization for bidders
+package usersync
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+const (
+	// SharedIDCookieName is the default name of the first-party ID cookie
+	SharedIDCookieName = "sharedid"
+	// SharedIDSource is the EID source used for the first-party ID (Prebid SharedID/PubCommonID)
+	SharedIDSource = "pubcid.org"
+	// SharedIDDefaultTTL is the default first-party ID cookie lifetime (1 year)
+	SharedIDDefaultTTL = 365 * 24 * time.Hour
+	// SharedIDAType is the EID agent type for a device-level cookie ID (OpenRTB 2.6 atype=1)
+	SharedIDAType = 1
+)
+
+// SharedIDConfig controls minting and persistence of the first-party ID
+type SharedIDConfig struct {
+	// Enabled turns first-party ID generation on or off
+	Enabled bool
+	// CookieName is the cookie that stores the ID
+	CookieName string
+	// Source is the EID source the ID is published under
+	Source string
+	// TTL is how long the cookie lives after the last auction that touched it
+	TTL time.Duration
+	// RotateAfter mints a fresh ID once the current one is this old (0 = never rotate)
+	RotateAfter time.Duration
+}
+
+// DefaultSharedIDConfig returns the default first-party ID configuration
+func DefaultSharedIDConfig() *SharedIDConfig {
+	return &SharedIDConfig{
+		Enabled:     true,
+		CookieName:  SharedIDCookieName,
+		Source:      SharedIDSource,
+		TTL:         SharedIDDefaultTTL,
+		RotateAfter: 0,
+	}
+}
+
+// SharedID is a first-party identifier persisted in a cookie
+type SharedID struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+}
+
+// SharedIDManager reads, mints, rotates and writes first-party ID cookies
+type SharedIDManager struct {
+	config *SharedIDConfig
+	now    func() time.Time
+}
+
+// NewSharedIDManager creates a first-party ID manager
+func NewSharedIDManager(config *SharedIDConfig) *SharedIDManager {
+	if config == nil {
+		config = DefaultSharedIDConfig()
+	}
+	if config.CookieName == "" {
+		config.CookieName = SharedIDCookieName
+	}
+	if config.Source == "" {
+		config.Source = SharedIDSource
+	}
+	if config.TTL <= 0 {
+		config.TTL = SharedIDDefaultTTL
+	}
+	return &SharedIDManager{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// IsEnabled returns true if first-party ID generation is enabled
+func (m *SharedIDManager) IsEnabled() bool {
+	return m.config.Enabled
+}
+
+// Source returns the EID source the ID is published under
+func (m *SharedIDManager) Source() string {
+	return m.config.Source
+}
+
+// Read returns the first-party ID stored in the request cookie, or nil if absent or invalid
+func (m *SharedIDManager) Read(r *http.Request) *SharedID {
+	cookie, err := r.Cookie(m.config.CookieName)
+	if err != nil || cookie.Value == "" {
+		return nil
+	}
+
+	decoded, err := base64.URLEncoding.DecodeString(cookie.Value)
+	if err != nil {
+		return nil
+	}
+
+	var sid SharedID
+	if err := json.Unmarshal(decoded, &sid); err != nil || sid.ID == "" {
+		return nil
+	}
+	return &sid
+}
+
+// Resolve returns the ID to use for this request, minting a new one when none exists
+// or the existing one is due for rotation. minted reports whether a new ID was created.
+func (m *SharedIDManager) Resolve(r *http.Request) (sid *SharedID, minted bool, err error) {
+	now := m.now().UTC()
+
+	if existing := m.Read(r); existing != nil {
+		if m.config.RotateAfter <= 0 || now.Sub(existing.Created) < m.config.RotateAfter {
+			return existing, false, nil
+		}
+	}
+
+	id, err := GenerateSharedID()
+	if err != nil {
+		return nil, false, err
+	}
+	return &SharedID{ID: id, Created: now}, true, nil
+}
+
+// ToHTTPCookie converts the ID to a cookie, extending its expiry by the configured TTL
+func (m *SharedIDManager) ToHTTPCookie(sid *SharedID, domain string) (*http.Cookie, error) {
+	data, err := json.Marshal(sid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Cookie{
+		Name:     m.config.CookieName,
+		Value:    base64.URLEncoding.EncodeToString(data),
+		Path:     "/",
+		Domain:   domain,
+		Expires:  m.now().Add(m.config.TTL),
+		MaxAge:   int(m.config.TTL.Seconds()),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteNoneMode,
+	}, nil
+}
+
+// ExpireCookie returns a cookie that removes any stored first-party ID
+func (m *SharedIDManager) ExpireCookie(domain string) *http.Cookie {
+	return &http.Cookie{
+		Name:     m.config.CookieName,
+		Value:    "",
+		Path:     "/",
+		Domain:   domain,
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteNoneMode,
+	}
+}
+
+// Apply adds the ID to the request as user.id (if unset) and as an EID (if the
+// source is not already present). Returns true if the request was modified.
+func (m *SharedIDManager) Apply(req *openrtb.BidRequest, sid *SharedID) bool {
+	if req == nil || sid == nil || sid.ID == "" {
+		return false
+	}
+
+	if req.User == nil {
+		req.User = &openrtb.User{}
+	}
+
+	modified := false
+	if req.User.ID == "" {
+		req.User.ID = sid.ID
+		modified = true
+	}
+
+	for _, eid := range req.User.EIDs {
+		if eid.Source == m.config.Source {
+			return modified
+		}
+	}
+
+	req.User.EIDs = append(req.User.EIDs, openrtb.EID{
+		Source: m.config.Source,
+		UIDs:   []openrtb.UID{{ID: sid.ID, AType: SharedIDAType}},
+	})
+	return true
+}
+
+// GenerateSharedID creates a random RFC 4122 version 4 UUID
+func GenerateSharedID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate shared id: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
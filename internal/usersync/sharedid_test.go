@@ -0,0 +1,192 @@
+package usersync
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+var uuidV4Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestGenerateSharedID(t *testing.T) {
+	id1, err := GenerateSharedID()
+	if err != nil {
+		t.Fatalf("GenerateSharedID failed: %v", err)
+	}
+	id2, _ := GenerateSharedID()
+
+	if !uuidV4Pattern.MatchString(id1) {
+		t.Errorf("Expected UUIDv4 format, got %s", id1)
+	}
+	if id1 == id2 {
+		t.Error("Expected unique IDs")
+	}
+}
+
+func TestNewSharedIDManager_Defaults(t *testing.T) {
+	m := NewSharedIDManager(&SharedIDConfig{Enabled: true})
+
+	if m.config.CookieName != SharedIDCookieName {
+		t.Errorf("Expected cookie name %s, got %s", SharedIDCookieName, m.config.CookieName)
+	}
+	if m.Source() != SharedIDSource {
+		t.Errorf("Expected source %s, got %s", SharedIDSource, m.Source())
+	}
+	if m.config.TTL != SharedIDDefaultTTL {
+		t.Errorf("Expected TTL %v, got %v", SharedIDDefaultTTL, m.config.TTL)
+	}
+
+	if !NewSharedIDManager(nil).IsEnabled() {
+		t.Error("Expected default config to be enabled")
+	}
+}
+
+func TestSharedIDManager_ResolveMintsAndReuses(t *testing.T) {
+	m := NewSharedIDManager(DefaultSharedIDConfig())
+
+	// No cookie - mint
+	req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+	sid, minted, err := m.Resolve(req)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if !minted {
+		t.Error("Expected new ID to be minted")
+	}
+
+	// Round-trip through cookie - reuse
+	cookie, err := m.ToHTTPCookie(sid, "example.com")
+	if err != nil {
+		t.Fatalf("ToHTTPCookie failed: %v", err)
+	}
+	req = httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+	req.AddCookie(cookie)
+
+	again, minted, err := m.Resolve(req)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if minted {
+		t.Error("Expected existing ID to be reused")
+	}
+	if again.ID != sid.ID {
+		t.Errorf("Expected ID %s, got %s", sid.ID, again.ID)
+	}
+}
+
+func TestSharedIDManager_Rotate(t *testing.T) {
+	m := NewSharedIDManager(&SharedIDConfig{Enabled: true, RotateAfter: 24 * time.Hour})
+	now := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	old := &SharedID{ID: "old-id", Created: now.Add(-48 * time.Hour)}
+	cookie, _ := m.ToHTTPCookie(old, "")
+	req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+	req.AddCookie(cookie)
+
+	sid, minted, err := m.Resolve(req)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if !minted || sid.ID == "old-id" {
+		t.Error("Expected ID older than RotateAfter to be replaced")
+	}
+	if !sid.Created.Equal(now) {
+		t.Errorf("Expected created %v, got %v", now, sid.Created)
+	}
+
+	fresh := &SharedID{ID: "fresh-id", Created: now.Add(-time.Hour)}
+	cookie, _ = m.ToHTTPCookie(fresh, "")
+	req = httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+	req.AddCookie(cookie)
+
+	sid, minted, _ = m.Resolve(req)
+	if minted || sid.ID != "fresh-id" {
+		t.Error("Expected ID younger than RotateAfter to be kept")
+	}
+}
+
+func TestSharedIDManager_ReadInvalid(t *testing.T) {
+	m := NewSharedIDManager(DefaultSharedIDConfig())
+
+	for _, value := range []string{"not-base64!!", "bm90LWpzb24", "e30"} {
+		req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+		req.AddCookie(&http.Cookie{Name: SharedIDCookieName, Value: value})
+		if sid := m.Read(req); sid != nil {
+			t.Errorf("Expected nil for cookie value %q, got %+v", value, sid)
+		}
+	}
+}
+
+func TestSharedIDManager_CookieAttributes(t *testing.T) {
+	m := NewSharedIDManager(&SharedIDConfig{Enabled: true, TTL: 30 * 24 * time.Hour})
+
+	cookie, err := m.ToHTTPCookie(&SharedID{ID: "abc", Created: time.Now()}, "example.com")
+	if err != nil {
+		t.Fatalf("ToHTTPCookie failed: %v", err)
+	}
+	if cookie.Name != SharedIDCookieName || cookie.Domain != "example.com" {
+		t.Errorf("Unexpected cookie name/domain: %s %s", cookie.Name, cookie.Domain)
+	}
+	if cookie.MaxAge != 30*24*60*60 {
+		t.Errorf("Expected MaxAge of 30 days, got %d", cookie.MaxAge)
+	}
+	if !cookie.Secure || !cookie.HttpOnly || cookie.SameSite != http.SameSiteNoneMode {
+		t.Error("Expected Secure, HttpOnly, SameSite=None cookie")
+	}
+
+	expired := m.ExpireCookie("example.com")
+	if expired.MaxAge >= 0 || expired.Value != "" {
+		t.Error("Expected expire cookie to have negative MaxAge and empty value")
+	}
+}
+
+func TestSharedIDManager_Apply(t *testing.T) {
+	m := NewSharedIDManager(DefaultSharedIDConfig())
+	sid := &SharedID{ID: "shared-123", Created: time.Now()}
+
+	// Empty request gets user.id and EID
+	req := &openrtb.BidRequest{ID: "req-1"}
+	if !m.Apply(req, sid) {
+		t.Error("Expected request to be modified")
+	}
+	if req.User == nil || req.User.ID != "shared-123" {
+		t.Fatal("Expected user.id to be set")
+	}
+	if len(req.User.EIDs) != 1 || req.User.EIDs[0].Source != SharedIDSource {
+		t.Fatalf("Expected pubcid.org EID, got %+v", req.User.EIDs)
+	}
+	if uid := req.User.EIDs[0].UIDs[0]; uid.ID != "shared-123" || uid.AType != SharedIDAType {
+		t.Errorf("Unexpected UID: %+v", uid)
+	}
+
+	// Existing user.id is kept, EID still added
+	req = &openrtb.BidRequest{User: &openrtb.User{ID: "publisher-id"}}
+	m.Apply(req, sid)
+	if req.User.ID != "publisher-id" {
+		t.Errorf("Expected publisher user.id to be preserved, got %s", req.User.ID)
+	}
+	if len(req.User.EIDs) != 1 {
+		t.Errorf("Expected 1 EID, got %d", len(req.User.EIDs))
+	}
+
+	// Existing pubcid EID from the page is not duplicated
+	req = &openrtb.BidRequest{User: &openrtb.User{
+		ID:   "publisher-id",
+		EIDs: []openrtb.EID{{Source: SharedIDSource, UIDs: []openrtb.UID{{ID: "page-id"}}}},
+	}}
+	if m.Apply(req, sid) {
+		t.Error("Expected request to be unmodified")
+	}
+	if len(req.User.EIDs) != 1 || req.User.EIDs[0].UIDs[0].ID != "page-id" {
+		t.Error("Expected page-provided pubcid EID to be preserved")
+	}
+
+	if m.Apply(nil, sid) || m.Apply(&openrtb.BidRequest{}, nil) {
+		t.Error("Expected nil inputs to be ignored")
+	}
+}
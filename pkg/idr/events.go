@@ -46,7 +46,7 @@ type EventRecorder struct {
 type BidEvent struct {
 	AuctionID   string   `json:"auction_id"`
 	BidderCode  string   `json:"bidder_code"`
-	EventType   string   `json:"event_type"` // "bid_response" or "win"
+	EventType   string   `json:"event_type"` // "bid_response", "win" or "nonbid"
 	LatencyMs   float64  `json:"latency_ms,omitempty"`
 	HadBid      bool     `json:"had_bid,omitempty"`
 	BidCPM      *float64 `json:"bid_cpm,omitempty"`
@@ -60,6 +60,9 @@ type BidEvent struct {
 	TimedOut    bool     `json:"timed_out,omitempty"`
 	HadError    bool     `json:"had_error,omitempty"`
 	ErrorMsg    string   `json:"error_message,omitempty"`
+	ImpID       string   `json:"imp_id,omitempty"`
+	StatusCode  int      `json:"status_code,omitempty"` // Seat-non-bid status code
+	Reason      string   `json:"reason,omitempty"`      // Seat-non-bid reason name
 }
 
 // NewEventRecorder creates a new event recorder with a bounded worker pool
@@ -177,32 +180,7 @@ func (r *EventRecorder) RecordBidResponse(
 		ErrorMsg:    errorMsg,
 	}
 
-	r.totalEvents.Add(1)
-
-	r.mu.Lock()
-	r.buffer = append(r.buffer, event)
-	shouldFlush := len(r.buffer) >= r.bufferSize
-	var eventsToFlush []BidEvent
-	if shouldFlush {
-		eventsToFlush = r.buffer
-		r.buffer = make([]BidEvent, 0, r.bufferSize)
-	}
-	r.mu.Unlock()
-
-	// Queue flush if buffer was full (non-blocking send)
-	if eventsToFlush != nil {
-		batchSize := int64(len(eventsToFlush))
-		select {
-		case r.flushQueue <- eventsToFlush:
-			// Queued successfully
-			r.flushedEvents.Add(batchSize)
-		default:
-			// Queue full - drop events rather than block or leak goroutines
-			// Track dropped events for monitoring/alerting
-			r.droppedEvents.Add(batchSize)
-			r.droppedBatches.Add(1)
-		}
-	}
+	r.record(event)
 }
 
 // RecordWin records a win event
@@ -228,6 +206,35 @@ func (r *EventRecorder) RecordWin(
 		PublisherID: publisherID,
 	}
 
+	r.record(event)
+}
+
+// RecordNonBid records a bid, or an imp not bid on, that did not take part in the auction
+func (r *EventRecorder) RecordNonBid(
+	auctionID string,
+	bidderCode string,
+	impID string,
+	statusCode int,
+	reason string,
+	bidCPM *float64,
+	floorPrice *float64,
+	publisherID string,
+) {
+	r.record(BidEvent{
+		AuctionID:   auctionID,
+		BidderCode:  bidderCode,
+		EventType:   "nonbid",
+		ImpID:       impID,
+		StatusCode:  statusCode,
+		Reason:      reason,
+		BidCPM:      bidCPM,
+		FloorPrice:  floorPrice,
+		PublisherID: publisherID,
+	})
+}
+
+// record buffers an event and queues a flush when the buffer is full
+func (r *EventRecorder) record(event BidEvent) {
 	r.totalEvents.Add(1)
 
 	r.mu.Lock()
@@ -167,6 +167,30 @@ func TestRecordWin(t *testing.T) {
 	}
 }
 
+func TestRecordNonBid(t *testing.T) {
+	recorder := NewEventRecorder("http://localhost:8000", 100)
+	defer recorder.Close()
+
+	cpm, floor := 0.80, 1.00
+	recorder.RecordNonBid("auction-123", "rubicon", "imp-1", 200, "below_floor", &cpm, &floor, "pub-789")
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.buffer) != 1 {
+		t.Fatalf("Expected 1 buffered event, got %d", len(recorder.buffer))
+	}
+	event := recorder.buffer[0]
+	if event.EventType != "nonbid" || event.StatusCode != 200 || event.Reason != "below_floor" || event.ImpID != "imp-1" {
+		t.Errorf("Unexpected non-bid event: %+v", event)
+	}
+	if event.BidCPM == nil || *event.BidCPM != 0.80 {
+		t.Errorf("Expected bid CPM 0.80, got %v", event.BidCPM)
+	}
+	if event.FloorPrice == nil || *event.FloorPrice != 1.00 {
+		t.Errorf("Expected floor 1.00, got %v", event.FloorPrice)
+	}
+}
+
 func TestFlush_EmptyBuffer(t *testing.T) {
 	recorder := NewEventRecorder("http://localhost:8000", 100)
 	defer recorder.Close()
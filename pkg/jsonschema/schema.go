@@ -0,0 +1,490 @@
+// Package jsonschema validates JSON documents against a subset of JSON Schema (draft-07)
+// Supported keywords cover what bidder parameter schemas need: type, properties, required,
+// additionalProperties, enum, numeric and length bounds, pattern, items, and the
+// allOf/anyOf/oneOf/not combinators. Unknown keywords are rejected at compile time so
+// schema mistakes surface at startup rather than being silently ignored.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is a compiled JSON Schema
+type Schema struct {
+	types                []string
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema // nil allows any property
+	noAdditional         bool    // additionalProperties: false
+	enum                 []interface{}
+	minimum              *float64
+	maximum              *float64
+	exclusiveMinimum     *float64
+	exclusiveMaximum     *float64
+	minLength            *int
+	maxLength            *int
+	pattern              *regexp.Regexp
+	items                *Schema
+	minItems             *int
+	maxItems             *int
+	allOf                []*Schema
+	anyOf                []*Schema
+	oneOf                []*Schema
+	not                  *Schema
+}
+
+// ValidationError describes why a document does not match the schema
+type ValidationError struct {
+	Path    string // JSON path of the failing value; empty for the document root
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// annotation keywords that do not affect validation
+var annotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true,
+	"description": true, "default": true, "examples": true,
+}
+
+var validTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true,
+	"integer": true, "boolean": true, "null": true,
+}
+
+// Compile parses a JSON Schema document
+func Compile(data []byte) (*Schema, error) {
+	var v interface{}
+	if err := decode(data, &v); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+	return compile(v, "")
+}
+
+// MustCompile is like Compile but panics on error
+func MustCompile(data []byte) *Schema {
+	s, err := Compile(data)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func compile(v interface{}, path string) (*Schema, error) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: schema must be an object", schemaPath(path))
+	}
+
+	s := &Schema{}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		val := obj[key]
+		at := path + "/" + key
+		var err error
+		switch key {
+		case "type":
+			s.types, err = compileTypes(val, at)
+		case "properties":
+			props, ok := val.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: must be an object", at)
+			}
+			s.properties = make(map[string]*Schema, len(props))
+			for name, prop := range props {
+				if s.properties[name], err = compile(prop, at+"/"+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			s.required, err = compileStrings(val, at)
+		case "additionalProperties":
+			if b, ok := val.(bool); ok {
+				s.noAdditional = !b
+			} else {
+				s.additionalProperties, err = compile(val, at)
+			}
+		case "enum":
+			list, ok := val.([]interface{})
+			if !ok || len(list) == 0 {
+				return nil, fmt.Errorf("%s: must be a non-empty array", at)
+			}
+			s.enum = list
+		case "minimum":
+			s.minimum, err = compileNumber(val, at)
+		case "maximum":
+			s.maximum, err = compileNumber(val, at)
+		case "exclusiveMinimum":
+			s.exclusiveMinimum, err = compileNumber(val, at)
+		case "exclusiveMaximum":
+			s.exclusiveMaximum, err = compileNumber(val, at)
+		case "minLength":
+			s.minLength, err = compileCount(val, at)
+		case "maxLength":
+			s.maxLength, err = compileCount(val, at)
+		case "minItems":
+			s.minItems, err = compileCount(val, at)
+		case "maxItems":
+			s.maxItems, err = compileCount(val, at)
+		case "pattern":
+			str, ok := val.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be a string", at)
+			}
+			if s.pattern, err = regexp.Compile(str); err != nil {
+				return nil, fmt.Errorf("%s: %w", at, err)
+			}
+		case "items":
+			s.items, err = compile(val, at)
+		case "allOf":
+			s.allOf, err = compileList(val, at)
+		case "anyOf":
+			s.anyOf, err = compileList(val, at)
+		case "oneOf":
+			s.oneOf, err = compileList(val, at)
+		case "not":
+			s.not, err = compile(val, at)
+		default:
+			if !annotations[key] {
+				return nil, fmt.Errorf("%s: unsupported keyword", at)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func compileTypes(v interface{}, at string) ([]string, error) {
+	var types []string
+	switch t := v.(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		var err error
+		if types, err = compileStrings(t, at); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%s: must be a string or array", at)
+	}
+	for _, t := range types {
+		if !validTypes[t] {
+			return nil, fmt.Errorf("%s: unknown type %q", at, t)
+		}
+	}
+	return types, nil
+}
+
+func compileStrings(v interface{}, at string) ([]string, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: must be an array", at)
+	}
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		str, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: must contain only strings", at)
+		}
+		out = append(out, str)
+	}
+	return out, nil
+}
+
+func compileNumber(v interface{}, at string) (*float64, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("%s: must be a number", at)
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", at, err)
+	}
+	return &f, nil
+}
+
+func compileCount(v interface{}, at string) (*int, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("%s: must be an integer", at)
+	}
+	i, err := n.Int64()
+	if err != nil || i < 0 {
+		return nil, fmt.Errorf("%s: must be a non-negative integer", at)
+	}
+	c := int(i)
+	return &c, nil
+}
+
+func compileList(v interface{}, at string) ([]*Schema, error) {
+	list, ok := v.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("%s: must be a non-empty array", at)
+	}
+	out := make([]*Schema, 0, len(list))
+	for i, item := range list {
+		s, err := compile(item, fmt.Sprintf("%s/%d", at, i))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func schemaPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+// Validate checks a JSON document against the schema
+// Returns a *ValidationError for the first mismatch found
+func (s *Schema) Validate(data []byte) error {
+	var v interface{}
+	if err := decode(data, &v); err != nil {
+		return &ValidationError{Message: "invalid JSON: " + err.Error()}
+	}
+	return s.validate(v, "")
+}
+
+func (s *Schema) validate(v interface{}, path string) error {
+	if len(s.types) > 0 && !matchesType(v, s.types) {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("expected %s, got %s", strings.Join(s.types, " or "), typeOf(v))}
+	}
+
+	if len(s.enum) > 0 {
+		found := false
+		for _, allowed := range s.enum {
+			if equal(v, allowed) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &ValidationError{Path: path, Message: "value is not one of the allowed values"}
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if err := s.validateObject(val, path); err != nil {
+			return err
+		}
+	case []interface{}:
+		if err := s.validateArray(val, path); err != nil {
+			return err
+		}
+	case string:
+		n := utf8.RuneCountInString(val)
+		if s.minLength != nil && n < *s.minLength {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("must be at least %d characters", *s.minLength)}
+		}
+		if s.maxLength != nil && n > *s.maxLength {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("must be at most %d characters", *s.maxLength)}
+		}
+		if s.pattern != nil && !s.pattern.MatchString(val) {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("does not match pattern %q", s.pattern.String())}
+		}
+	case json.Number:
+		if err := s.validateNumber(val, path); err != nil {
+			return err
+		}
+	}
+
+	for _, sub := range s.allOf {
+		if err := sub.validate(v, path); err != nil {
+			return err
+		}
+	}
+	if len(s.anyOf) > 0 {
+		var firstErr error
+		matched := false
+		for _, sub := range s.anyOf {
+			err := sub.validate(v, path)
+			if err == nil {
+				matched = true
+				break
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if !matched {
+			return &ValidationError{Path: path, Message: "does not match any allowed form: " + firstErr.Error()}
+		}
+	}
+	if len(s.oneOf) > 0 {
+		matches := 0
+		var firstErr error
+		for _, sub := range s.oneOf {
+			if err := sub.validate(v, path); err == nil {
+				matches++
+			} else if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if matches == 0 {
+			return &ValidationError{Path: path, Message: "does not match any allowed form: " + firstErr.Error()}
+		}
+		if matches > 1 {
+			return &ValidationError{Path: path, Message: "matches more than one allowed form"}
+		}
+	}
+	if s.not != nil && s.not.validate(v, path) == nil {
+		return &ValidationError{Path: path, Message: "matches a disallowed form"}
+	}
+	return nil
+}
+
+func (s *Schema) validateObject(obj map[string]interface{}, path string) error {
+	for _, name := range s.required {
+		if _, ok := obj[name]; !ok {
+			return &ValidationError{Path: joinPath(path, name), Message: "required"}
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if prop, ok := s.properties[name]; ok {
+			if err := prop.validate(obj[name], joinPath(path, name)); err != nil {
+				return err
+			}
+			continue
+		}
+		if s.noAdditional {
+			return &ValidationError{Path: joinPath(path, name), Message: "unknown property"}
+		}
+		if s.additionalProperties != nil {
+			if err := s.additionalProperties.validate(obj[name], joinPath(path, name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateArray(arr []interface{}, path string) error {
+	if s.minItems != nil && len(arr) < *s.minItems {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must have at least %d items", *s.minItems)}
+	}
+	if s.maxItems != nil && len(arr) > *s.maxItems {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must have at most %d items", *s.maxItems)}
+	}
+	if s.items != nil {
+		for i, item := range arr {
+			if err := s.items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateNumber(n json.Number, path string) error {
+	f, err := n.Float64()
+	if err != nil {
+		return &ValidationError{Path: path, Message: "invalid number"}
+	}
+	if s.minimum != nil && f < *s.minimum {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must be >= %v", *s.minimum)}
+	}
+	if s.maximum != nil && f > *s.maximum {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must be <= %v", *s.maximum)}
+	}
+	if s.exclusiveMinimum != nil && f <= *s.exclusiveMinimum {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must be > %v", *s.exclusiveMinimum)}
+	}
+	if s.exclusiveMaximum != nil && f >= *s.exclusiveMaximum {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("must be < %v", *s.exclusiveMaximum)}
+	}
+	return nil
+}
+
+func matchesType(v interface{}, types []string) bool {
+	actual := typeOf(v)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func typeOf(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case json.Number:
+		if f, err := val.Float64(); err == nil && f == math.Trunc(f) && !strings.ContainsAny(val.String(), ".eE") {
+			return "integer"
+		}
+		return "number"
+	}
+	return "unknown"
+}
+
+// equal compares decoded JSON values, treating numbers by value
+func equal(a, b interface{}) bool {
+	an, aok := a.(json.Number)
+	bn, bok := b.(json.Number)
+	if aok && bok {
+		af, aerr := an.Float64()
+		bf, berr := bn.Float64()
+		return aerr == nil && berr == nil && af == bf
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func decode(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return fmt.Errorf("unexpected data after JSON value")
+	}
+	return nil
+}
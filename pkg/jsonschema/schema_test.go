@@ -0,0 +1,122 @@
+package jsonschema
+
+import (
+	"errors"
+	"testing"
+)
+
+const paramsSchema = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"title": "Test Params",
+	"type": "object",
+	"properties": {
+		"placementId": {"type": "integer", "minimum": 1},
+		"member": {"type": "string", "minLength": 1},
+		"invCode": {"type": "string", "pattern": "^[a-z_]+$"},
+		"siteId": {"type": ["integer", "string"]},
+		"size": {"type": "array", "items": {"type": "integer"}, "minItems": 2, "maxItems": 2},
+		"priceType": {"enum": ["gross", "net"]},
+		"reserve": {"type": "number", "exclusiveMinimum": 0, "maximum": 100},
+		"publisher": {
+			"type": "object",
+			"properties": {"id": {"type": "string"}},
+			"required": ["id"],
+			"additionalProperties": false
+		}
+	},
+	"anyOf": [
+		{"required": ["placementId"]},
+		{"required": ["member", "invCode"]}
+	]
+}`
+
+func TestValidate(t *testing.T) {
+	schema, err := Compile([]byte(paramsSchema))
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{"placement only", `{"placementId": 13232354}`, ""},
+		{"member and invCode", `{"member": "958", "invCode": "my_placement"}`, ""},
+		{"integer with zero fraction", `{"placementId": 5.0}`, "placementId: expected integer, got number"},
+		{"string or integer", `{"placementId": 1, "siteId": "abc"}`, ""},
+		{"wrong type", `{"placementId": "123"}`, "placementId: expected integer, got string"},
+		{"below minimum", `{"placementId": 0}`, "placementId: must be >= 1"},
+		{"no allowed form", `{"member": "958"}`, "does not match any allowed form: placementId: required"},
+		{"pattern", `{"member": "958", "invCode": "Bad-Code"}`, `invCode: does not match pattern "^[a-z_]+$"`},
+		{"array items", `{"placementId": 1, "size": [300, "250"]}`, "size[1]: expected integer, got string"},
+		{"array length", `{"placementId": 1, "size": [300]}`, "size: must have at least 2 items"},
+		{"enum", `{"placementId": 1, "priceType": "list"}`, "priceType: value is not one of the allowed values"},
+		{"exclusive minimum", `{"placementId": 1, "reserve": 0}`, "reserve: must be > 0"},
+		{"nested required", `{"placementId": 1, "publisher": {}}`, "publisher.id: required"},
+		{"additional property", `{"placementId": 1, "publisher": {"id": "p", "name": "x"}}`, "publisher.name: unknown property"},
+		{"not an object", `[1]`, "expected object, got array"},
+		{"invalid JSON", `{"placementId":`, "invalid JSON: unexpected EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tt.doc))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+			var ve *ValidationError
+			if err != nil && !errors.As(err, &ve) {
+				t.Errorf("expected *ValidationError, got %T", err)
+			}
+		})
+	}
+}
+
+func TestValidate_OneOfAndNot(t *testing.T) {
+	schema := MustCompile([]byte(`{
+		"oneOf": [{"type": "string"}, {"type": "integer"}, {"type": "number"}],
+		"not": {"enum": ["blocked"]}
+	}`))
+
+	if err := schema.Validate([]byte(`"ok"`)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := schema.Validate([]byte(`1.5`)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := schema.Validate([]byte(`3`)); err == nil || err.Error() != "matches more than one allowed form" {
+		t.Errorf("expected oneOf ambiguity error, got %v", err)
+	}
+	if err := schema.Validate([]byte(`"blocked"`)); err == nil || err.Error() != "matches a disallowed form" {
+		t.Errorf("expected not error, got %v", err)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := map[string]string{
+		"invalid JSON":        `{"type":`,
+		"schema not object":   `"string"`,
+		"unknown keyword":     `{"type": "object", "propertyNames": {}}`,
+		"unknown type":        `{"type": "float"}`,
+		"bad required":        `{"required": "id"}`,
+		"bad pattern":         `{"pattern": "("}`,
+		"negative length":     `{"minLength": -1}`,
+		"empty anyOf":         `{"anyOf": []}`,
+		"nested unknown":      `{"properties": {"id": {"format": "uuid"}}}`,
+		"non-numeric minimum": `{"minimum": "1"}`,
+	}
+	for name, schema := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Compile([]byte(schema)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
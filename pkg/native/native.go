@@ -0,0 +1,298 @@
+// Package native parses, upgrades, validates and renders OpenRTB Native 1.0-1.2 ads
+// Requests and responses of any version are read into the Native 1.2 models; legacy
+// 1.0/1.1 requests are upgraded so bidders and validation only deal with 1.2.
+package native
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Version is the Native version requests are upgraded to
+const Version = "1.2"
+
+// Asset data types (Native 1.2 section 7.4)
+const (
+	DataTypeSponsored  = 1
+	DataTypeDesc       = 2
+	DataTypeRating     = 3
+	DataTypeLikes      = 4
+	DataTypeDownloads  = 5
+	DataTypePrice      = 6
+	DataTypeSalePrice  = 7
+	DataTypePhone      = 8
+	DataTypeAddress    = 9
+	DataTypeDesc2      = 10
+	DataTypeDisplayURL = 11
+	DataTypeCTAText    = 12
+)
+
+// Image asset types (Native 1.2 section 7.3)
+const (
+	ImageTypeIcon = 1
+	ImageTypeMain = 3
+)
+
+// Event tracking types and methods (Native 1.2 sections 7.6 and 7.7)
+const (
+	EventImpression      = 1
+	EventViewableMRC50   = 2
+	EventViewableMRC100  = 3
+	EventViewableVideo50 = 4
+
+	TrackingMethodImage = 1
+	TrackingMethodJS    = 2
+)
+
+// Context and placement types (Native 1.2 sections 7.1 and 7.3)
+const (
+	ContextContent = 1
+	ContextSocial  = 2
+	ContextProduct = 3
+
+	PlacementInFeed      = 1
+	PlacementAtomic      = 2
+	PlacementOutside     = 3
+	PlacementRecommended = 4
+)
+
+// Native 1.0/1.1 values replaced in 1.2
+const (
+	legacyWrapper        = "native" // 1.0 requests and 1.0/1.1 responses are wrapped in {"native": {...}}
+	legacyImageTypeLogo  = 2
+	legacyLayoutNewsFeed = 3
+	legacyLayoutChatList = 4
+	legacyAdUnitSearch   = 1
+	legacyAdUnitWidget   = 2
+	legacyAdUnitPromoted = 3
+	legacyAdUnitInAd     = 4
+	legacyAdUnitCustom   = 5
+)
+
+// Request is a Native 1.2 ad request (the imp.native.request string)
+type Request struct {
+	Ver            string                `json:"ver,omitempty"`
+	Context        int                   `json:"context,omitempty"`
+	ContextSubtype int                   `json:"contextsubtype,omitempty"`
+	PlcmtType      int                   `json:"plcmttype,omitempty"`
+	PlcmtCnt       int                   `json:"plcmtcnt,omitempty"`
+	Seq            int                   `json:"seq,omitempty"`
+	Assets         []Asset               `json:"assets"`
+	AURLSupport    int                   `json:"aurlsupport,omitempty"`
+	DURLSupport    int                   `json:"durlsupport,omitempty"`
+	EventTrackers  []EventTrackerRequest `json:"eventtrackers,omitempty"`
+	Privacy        int                   `json:"privacy,omitempty"`
+	Ext            json.RawMessage       `json:"ext,omitempty"`
+
+	// Native 1.0/1.1 fields, replaced by context and plcmttype
+	Layout int `json:"layout,omitempty"`
+	AdUnit int `json:"adunit,omitempty"`
+}
+
+// Asset is a requested ad element; exactly one of Title, Img, Video and Data is set
+type Asset struct {
+	ID       int             `json:"id"`
+	Required int             `json:"required,omitempty"`
+	Title    *TitleRequest   `json:"title,omitempty"`
+	Img      *ImageRequest   `json:"img,omitempty"`
+	Video    *VideoRequest   `json:"video,omitempty"`
+	Data     *DataRequest    `json:"data,omitempty"`
+	Ext      json.RawMessage `json:"ext,omitempty"`
+}
+
+// TitleRequest asks for a title of at most Len characters
+type TitleRequest struct {
+	Len int `json:"len"`
+}
+
+// ImageRequest asks for an image of an exact (W, H) or minimum (WMin, HMin) size
+type ImageRequest struct {
+	Type  int      `json:"type,omitempty"`
+	W     int      `json:"w,omitempty"`
+	WMin  int      `json:"wmin,omitempty"`
+	H     int      `json:"h,omitempty"`
+	HMin  int      `json:"hmin,omitempty"`
+	Mimes []string `json:"mimes,omitempty"`
+}
+
+// VideoRequest asks for a VAST video
+type VideoRequest struct {
+	Mimes       []string `json:"mimes"`
+	MinDuration int      `json:"minduration,omitempty"`
+	MaxDuration int      `json:"maxduration,omitempty"`
+	Protocols   []int    `json:"protocols,omitempty"`
+}
+
+// DataRequest asks for a data element (sponsor, description, CTA, ...) of at most Len characters
+type DataRequest struct {
+	Type int `json:"type"`
+	Len  int `json:"len,omitempty"`
+}
+
+// EventTrackerRequest lists the tracking methods supported for an event
+type EventTrackerRequest struct {
+	Event   int   `json:"event"`
+	Methods []int `json:"methods"`
+}
+
+// Response is a Native 1.2 ad response (the native bid's adm)
+type Response struct {
+	Ver           string          `json:"ver,omitempty"`
+	Assets        []AssetResponse `json:"assets,omitempty"`
+	AssetsURL     string          `json:"assetsurl,omitempty"`
+	DCOURL        string          `json:"dcourl,omitempty"`
+	Link          Link            `json:"link"`
+	ImpTrackers   []string        `json:"imptrackers,omitempty"` // Deprecated in 1.2 for eventtrackers
+	JSTracker     string          `json:"jstracker,omitempty"`   // Deprecated in 1.2 for eventtrackers
+	EventTrackers []EventTracker  `json:"eventtrackers,omitempty"`
+	Privacy       string          `json:"privacy,omitempty"`
+	Ext           json.RawMessage `json:"ext,omitempty"`
+}
+
+// AssetResponse is a returned ad element, matched to the request asset by ID
+type AssetResponse struct {
+	ID       int             `json:"id"`
+	Required int             `json:"required,omitempty"`
+	Title    *TitleResponse  `json:"title,omitempty"`
+	Img      *ImageResponse  `json:"img,omitempty"`
+	Video    *VideoResponse  `json:"video,omitempty"`
+	Data     *DataResponse   `json:"data,omitempty"`
+	Link     *Link           `json:"link,omitempty"`
+	Ext      json.RawMessage `json:"ext,omitempty"`
+}
+
+// TitleResponse is a title asset
+type TitleResponse struct {
+	Text string `json:"text"`
+	Len  int    `json:"len,omitempty"`
+}
+
+// ImageResponse is an image asset
+type ImageResponse struct {
+	Type int    `json:"type,omitempty"`
+	URL  string `json:"url"`
+	W    int    `json:"w,omitempty"`
+	H    int    `json:"h,omitempty"`
+}
+
+// VideoResponse is a video asset
+type VideoResponse struct {
+	VASTTag string `json:"vasttag"`
+}
+
+// DataResponse is a data asset
+type DataResponse struct {
+	Type  int    `json:"type,omitempty"`
+	Len   int    `json:"len,omitempty"`
+	Value string `json:"value"`
+}
+
+// Link is the click destination of the ad or of an asset
+type Link struct {
+	URL           string   `json:"url"`
+	ClickTrackers []string `json:"clicktrackers,omitempty"`
+	Fallback      string   `json:"fallback,omitempty"`
+}
+
+// EventTracker is a tracker the buyer wants fired for an event
+type EventTracker struct {
+	Event      int             `json:"event"`
+	Method     int             `json:"method"`
+	URL        string          `json:"url,omitempty"`
+	CustomData json.RawMessage `json:"customdata,omitempty"`
+}
+
+// ParseRequest parses a native request of any version into the 1.2 model
+// Native 1.0 requests wrapped in {"native": {...}} are unwrapped. The result is not upgraded;
+// call Upgrade to fill in the 1.2 fields.
+func ParseRequest(s string) (*Request, error) {
+	data, err := unwrap([]byte(s), legacyWrapper)
+	if err != nil {
+		return nil, fmt.Errorf("invalid native request: %w", err)
+	}
+	var req Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("invalid native request: %w", err)
+	}
+	if len(req.Assets) == 0 {
+		return nil, errors.New("invalid native request: no assets")
+	}
+	return &req, nil
+}
+
+// ParseResponse parses a native adm of any version into the 1.2 model
+// Native 1.0/1.1 responses wrapped in {"native": {...}} are unwrapped.
+func ParseResponse(adm string) (*Response, error) {
+	data, err := unwrap([]byte(adm), legacyWrapper)
+	if err != nil {
+		return nil, fmt.Errorf("invalid native response: %w", err)
+	}
+	var resp Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("invalid native response: %w", err)
+	}
+	return &resp, nil
+}
+
+// unwrap returns the object under key when data is a single-key legacy wrapper
+func unwrap(data []byte, key string) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return nil, errors.New("not a JSON object")
+	}
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, err
+	}
+	if inner, ok := wrapper[key]; ok && len(wrapper) == 1 {
+		return inner, nil
+	}
+	return data, nil
+}
+
+// Upgrade converts a Native 1.0/1.1 request to 1.2 in place
+// The legacy layout and adunit are mapped to context and plcmttype when those are unset,
+// and logo images (type 2, removed in 1.2) become icons. Returns whether anything changed.
+func (r *Request) Upgrade() bool {
+	changed := r.Ver != Version
+	r.Ver = Version
+
+	if r.Context == 0 && r.Layout != 0 {
+		r.Context = ContextContent
+		if r.Layout == legacyLayoutNewsFeed || r.Layout == legacyLayoutChatList {
+			r.Context = ContextSocial
+		}
+		changed = true
+	}
+	if r.PlcmtType == 0 && r.AdUnit != 0 {
+		switch r.AdUnit {
+		case legacyAdUnitSearch, legacyAdUnitPromoted:
+			r.PlcmtType = PlacementInFeed
+		case legacyAdUnitWidget:
+			r.PlcmtType = PlacementRecommended
+		case legacyAdUnitInAd:
+			r.PlcmtType = PlacementAtomic
+		case legacyAdUnitCustom:
+			r.PlcmtType = PlacementOutside
+		}
+		changed = changed || r.PlcmtType != 0
+	}
+	for i := range r.Assets {
+		if img := r.Assets[i].Img; img != nil && img.Type == legacyImageTypeLogo {
+			img.Type = ImageTypeIcon
+			changed = true
+		}
+	}
+	return changed
+}
+
+// String returns the request as the JSON sent in imp.native.request
+func (r *Request) String() string {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,91 @@
+package native
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testRequest = `{"ver":"1.2","context":1,"plcmttype":1,"assets":[
+	{"id":1,"required":1,"title":{"len":25}},
+	{"id":2,"required":1,"img":{"type":3,"wmin":300,"hmin":150}},
+	{"id":3,"img":{"type":1,"w":50,"h":50}},
+	{"id":4,"required":1,"data":{"type":2,"len":90}},
+	{"id":5,"data":{"type":12}}
+],"eventtrackers":[{"event":1,"methods":[1,2]}]}`
+
+func TestParseRequest(t *testing.T) {
+	req, err := ParseRequest(testRequest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Ver != "1.2" || len(req.Assets) != 5 || req.Assets[1].Img.WMin != 300 || len(req.EventTrackers) != 1 {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	for name, s := range map[string]string{
+		"not json":  "native please",
+		"no assets": `{"ver":"1.2","assets":[]}`,
+		"array":     `[{"id":1}]`,
+	} {
+		if _, err := ParseRequest(s); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseRequest_LegacyWrapper(t *testing.T) {
+	req, err := ParseRequest(`{"native":{"ver":"1.0","layout":3,"adunit":2,"assets":[{"id":1,"img":{"type":2,"w":80,"h":80}}]}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Ver != "1.0" || req.Layout != 3 || req.AdUnit != 2 || len(req.Assets) != 1 {
+		t.Errorf("expected unwrapped 1.0 request, got %+v", req)
+	}
+}
+
+func TestRequest_Upgrade(t *testing.T) {
+	req, _ := ParseRequest(`{"native":{"ver":"1.0","layout":3,"adunit":2,"assets":[{"id":1,"img":{"type":2,"w":80,"h":80}}]}}`)
+	if !req.Upgrade() {
+		t.Fatal("expected legacy request to change")
+	}
+	if req.Ver != Version || req.Context != ContextSocial || req.PlcmtType != PlacementRecommended {
+		t.Errorf("unexpected upgraded request: %+v", req)
+	}
+	if req.Assets[0].Img.Type != ImageTypeIcon {
+		t.Errorf("expected logo mapped to icon, got %d", req.Assets[0].Img.Type)
+	}
+
+	var out map[string]interface{}
+	if err := json.Unmarshal([]byte(req.String()), &out); err != nil {
+		t.Fatalf("invalid request JSON: %v", err)
+	}
+	if out["ver"] != "1.2" || out["native"] != nil || out["assets"] == nil {
+		t.Errorf("expected unwrapped 1.2 JSON, got %v", out)
+	}
+
+	// Explicit 1.2 fields are kept
+	req, _ = ParseRequest(`{"ver":"1.1","context":3,"layout":3,"assets":[{"id":1,"title":{"len":20}}]}`)
+	req.Upgrade()
+	if req.Context != ContextProduct {
+		t.Errorf("expected context kept, got %d", req.Context)
+	}
+
+	req, _ = ParseRequest(testRequest)
+	if req.Upgrade() {
+		t.Error("expected 1.2 request unchanged")
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	resp, err := ParseResponse(`{"native":{"ver":"1.1","link":{"url":"https://adv.example.com"},"assets":[{"id":1,"title":{"text":"Hi"}}],"imptrackers":["https://t.example.com/i"]}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Link.URL != "https://adv.example.com" || len(resp.Assets) != 1 || len(resp.ImpTrackers) != 1 {
+		t.Errorf("expected unwrapped 1.1 response, got %+v", resp)
+	}
+
+	if _, err := ParseResponse(`<div>banner</div>`); err == nil {
+		t.Error("expected error for HTML adm")
+	}
+}
@@ -0,0 +1,130 @@
+package native
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// DefaultTemplate renders a native ad as a simple card: image, title, description,
+// sponsor and call to action, all linking to the click URL
+const DefaultTemplate = `<div class="native-ad" style="font-family:sans-serif;max-width:100%">
+<a href="{{.ClickURL}}" target="_blank" rel="noopener" style="text-decoration:none;color:inherit"{{if .ClickTrackers}} onclick="{{range .ClickTrackers}}new Image().src={{.}};{{end}}"{{end}}>
+{{if .ImageURL}}<img src="{{.ImageURL}}" alt="" style="width:100%;height:auto;display:block">{{end}}
+<div style="display:flex;align-items:center;padding:8px 0">
+{{if .IconURL}}<img src="{{.IconURL}}" alt="" style="width:40px;height:40px;margin-right:8px">{{end}}
+<div>
+{{if .Title}}<div style="font-weight:bold">{{.Title}}</div>{{end}}
+{{if .Description}}<div>{{.Description}}</div>{{end}}
+{{if .Sponsored}}<div style="font-size:smaller;opacity:.7">{{.Sponsored}}</div>{{end}}
+</div>
+</div>
+{{if .CTA}}<span style="display:inline-block;padding:4px 12px;border:1px solid currentColor;border-radius:4px">{{.CTA}}</span>{{end}}
+</a>
+{{if .PrivacyURL}}<a href="{{.PrivacyURL}}" target="_blank" rel="noopener" style="font-size:smaller">AdChoices</a>{{end}}
+{{range .ImpressionURLs}}<img src="{{.}}" width="1" height="1" alt="" style="display:none">{{end}}
+{{range .ScriptURLs}}<script async src="{{.}}"></script>{{end}}
+{{.JSTracker}}
+</div>`
+
+// Ad is the view of a native response passed to render templates
+type Ad struct {
+	Title          string
+	Description    string
+	Sponsored      string
+	CTA            string
+	IconURL        string
+	ImageURL       string
+	ImageW         int
+	ImageH         int
+	Data           map[int]string // Data assets by data type (rating, price, phone, ...)
+	ClickURL       string
+	ClickTrackers  []string
+	PrivacyURL     string
+	ImpressionURLs []string      // Image pixels fired when the ad renders
+	ScriptURLs     []string      // JavaScript trackers (any event)
+	JSTracker      template.HTML // Legacy Native 1.1 jstracker markup
+}
+
+// ParseTemplate parses a render template, or DefaultTemplate when src is empty
+func ParseTemplate(src string) (*template.Template, error) {
+	if strings.TrimSpace(src) == "" {
+		src = DefaultTemplate
+	}
+	tmpl, err := template.New("native").Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid native template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// NewAd builds the render view of a response
+// Asset kinds come from the response and, when it leaves them out, from the request assets
+// with the same IDs; req may be nil. Images of unknown type are treated as the main image.
+func NewAd(req *Request, resp *Response) *Ad {
+	requested := make(map[int]*Asset)
+	if req != nil {
+		for i := range req.Assets {
+			requested[req.Assets[i].ID] = &req.Assets[i]
+		}
+	}
+
+	ad := &Ad{
+		Data:          make(map[int]string),
+		ClickURL:      resp.Link.URL,
+		ClickTrackers: resp.Link.ClickTrackers,
+		PrivacyURL:    resp.Privacy,
+		JSTracker:     template.HTML(resp.JSTracker), //nolint:gosec // buyer tracker markup, as served in the adm
+	}
+
+	for _, asset := range resp.Assets {
+		want := requested[asset.ID]
+		switch {
+		case asset.Title != nil:
+			ad.Title = asset.Title.Text
+		case asset.Img != nil:
+			imgType := asset.Img.Type
+			if imgType == 0 && want != nil && want.Img != nil {
+				imgType = want.Img.Type
+			}
+			if imgType == ImageTypeIcon || imgType == legacyImageTypeLogo {
+				ad.IconURL = asset.Img.URL
+			} else if ad.ImageURL == "" {
+				ad.ImageURL, ad.ImageW, ad.ImageH = asset.Img.URL, asset.Img.W, asset.Img.H
+			}
+		case asset.Data != nil:
+			dataType := asset.Data.Type
+			if dataType == 0 && want != nil && want.Data != nil {
+				dataType = want.Data.Type
+			}
+			ad.Data[dataType] = asset.Data.Value
+		}
+		if asset.Link != nil && ad.ClickURL == "" {
+			ad.ClickURL = asset.Link.URL
+		}
+	}
+	ad.Description = ad.Data[DataTypeDesc]
+	ad.Sponsored = ad.Data[DataTypeSponsored]
+	ad.CTA = ad.Data[DataTypeCTAText]
+
+	ad.ImpressionURLs = append(ad.ImpressionURLs, resp.ImpTrackers...)
+	for _, tracker := range resp.EventTrackers {
+		switch {
+		case tracker.URL == "":
+		case tracker.Method == TrackingMethodJS:
+			ad.ScriptURLs = append(ad.ScriptURLs, tracker.URL)
+		case tracker.Method == TrackingMethodImage && tracker.Event == EventImpression:
+			ad.ImpressionURLs = append(ad.ImpressionURLs, tracker.URL)
+		}
+	}
+	return ad
+}
+
+// Render renders a native response to HTML with tmpl
+func Render(tmpl *template.Template, req *Request, resp *Response) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, NewAd(req, resp)); err != nil {
+		return "", fmt.Errorf("native render failed: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,72 @@
+package native
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewAd(t *testing.T) {
+	req, _ := ParseRequest(testRequest)
+	resp, _ := ParseResponse(testResponse)
+
+	ad := NewAd(req, resp)
+	if ad.Title != "Summer Sale" || ad.Description != "Everything must go" || ad.CTA != "Shop now" {
+		t.Errorf("unexpected text assets: %+v", ad)
+	}
+	if ad.ImageURL != "https://cdn.example.com/main.jpg" || ad.ImageW != 600 {
+		t.Errorf("unexpected main image: %+v", ad)
+	}
+	// The icon's type comes from the request asset
+	if ad.IconURL != "https://cdn.example.com/icon.png" {
+		t.Errorf("expected icon from request asset type, got %q", ad.IconURL)
+	}
+	if ad.ClickURL != "https://adv.example.com/landing" || len(ad.ClickTrackers) != 1 {
+		t.Errorf("unexpected link: %+v", ad)
+	}
+	if len(ad.ImpressionURLs) != 1 || len(ad.ScriptURLs) != 1 {
+		t.Errorf("unexpected trackers: imp=%v js=%v", ad.ImpressionURLs, ad.ScriptURLs)
+	}
+}
+
+func TestRender(t *testing.T) {
+	req, _ := ParseRequest(testRequest)
+	resp, _ := ParseResponse(testResponse)
+	resp.Assets[0].Title.Text = `Summer <Sale> & "more"`
+
+	tmpl, err := ParseTemplate("")
+	if err != nil {
+		t.Fatalf("default template does not parse: %v", err)
+	}
+	html, err := Render(tmpl, req, resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`href="https://adv.example.com/landing"`,
+		`src="https://cdn.example.com/main.jpg"`,
+		`Summer &lt;Sale&gt; &amp; &#34;more&#34;`,
+		`Shop now`,
+		`<img src="https://t.example.com/imp" width="1" height="1"`,
+		`<script async src="https://t.example.com/omid.js"></script>`,
+		`new Image().src=&#34;https://t.example.com/click&#34;`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %q in rendered ad:\n%s", want, html)
+		}
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	tmpl, err := ParseTemplate(`<a href="{{.ClickURL}}">{{.Title}}</a>`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	html, _ := Render(tmpl, nil, &Response{Link: Link{URL: "https://adv.example.com"}, Assets: []AssetResponse{{ID: 1, Title: &TitleResponse{Text: "Hi"}}}})
+	if html != `<a href="https://adv.example.com">Hi</a>` {
+		t.Errorf("unexpected custom render: %s", html)
+	}
+
+	if _, err := ParseTemplate(`{{.Title`); err == nil {
+		t.Error("expected error for invalid template")
+	}
+}
@@ -0,0 +1,148 @@
+package native
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Validate checks a native response against the request it answers
+// Every required asset must be returned, returned assets must match a requested asset of the
+// same kind and meet its length and size constraints, and event trackers must use an event
+// and method the request supports. Returns the first problem found.
+func (r *Response) Validate(req *Request) error {
+	if r.Link.URL == "" && r.AssetsURL == "" && r.DCOURL == "" {
+		return errors.New("native response missing link url")
+	}
+
+	requested := make(map[int]*Asset, len(req.Assets))
+	for i := range req.Assets {
+		requested[req.Assets[i].ID] = &req.Assets[i]
+	}
+
+	returned := make(map[int]bool, len(r.Assets))
+	for i := range r.Assets {
+		asset := &r.Assets[i]
+		want, ok := requested[asset.ID]
+		if !ok {
+			return fmt.Errorf("native asset %d was not requested", asset.ID)
+		}
+		if err := validateAsset(asset, want); err != nil {
+			return fmt.Errorf("native asset %d: %w", asset.ID, err)
+		}
+		returned[asset.ID] = true
+	}
+
+	// Assets may be fetched from assetsurl or dcourl instead of being inline
+	if r.AssetsURL == "" && r.DCOURL == "" {
+		for _, asset := range req.Assets {
+			if asset.Required == 1 && !returned[asset.ID] {
+				return fmt.Errorf("native response missing required asset %d", asset.ID)
+			}
+		}
+	}
+
+	return validateEventTrackers(r.EventTrackers, req.EventTrackers)
+}
+
+// validateAsset checks a returned asset against the requested one
+func validateAsset(got *AssetResponse, want *Asset) error {
+	switch {
+	case want.Title != nil:
+		if got.Title == nil {
+			return errors.New("expected title")
+		}
+		if got.Title.Text == "" {
+			return errors.New("empty title")
+		}
+		if want.Title.Len > 0 && utf8.RuneCountInString(got.Title.Text) > want.Title.Len {
+			return fmt.Errorf("title longer than %d characters", want.Title.Len)
+		}
+
+	case want.Img != nil:
+		if got.Img == nil {
+			return errors.New("expected image")
+		}
+		if got.Img.URL == "" {
+			return errors.New("image missing url")
+		}
+		return validateImageSize(got.Img, want.Img)
+
+	case want.Video != nil:
+		if got.Video == nil {
+			return errors.New("expected video")
+		}
+		if got.Video.VASTTag == "" {
+			return errors.New("video missing vasttag")
+		}
+
+	case want.Data != nil:
+		if got.Data == nil {
+			return errors.New("expected data")
+		}
+		if got.Data.Type != 0 && got.Data.Type != want.Data.Type {
+			return fmt.Errorf("data type %d, requested %d", got.Data.Type, want.Data.Type)
+		}
+		if got.Data.Value == "" {
+			return errors.New("empty data value")
+		}
+		if want.Data.Len > 0 && utf8.RuneCountInString(got.Data.Value) > want.Data.Len {
+			return fmt.Errorf("data longer than %d characters", want.Data.Len)
+		}
+	}
+	return nil
+}
+
+// validateImageSize checks an image against the requested size
+// A minimum size allows any larger image; a width or height without a minimum is exact.
+// Images that do not report their size are accepted.
+func validateImageSize(got *ImageResponse, want *ImageRequest) error {
+	if got.W > 0 {
+		if want.WMin > 0 && got.W < want.WMin {
+			return fmt.Errorf("image width %d below minimum %d", got.W, want.WMin)
+		}
+		if want.WMin == 0 && want.W > 0 && got.W != want.W {
+			return fmt.Errorf("image width %d, requested %d", got.W, want.W)
+		}
+	}
+	if got.H > 0 {
+		if want.HMin > 0 && got.H < want.HMin {
+			return fmt.Errorf("image height %d below minimum %d", got.H, want.HMin)
+		}
+		if want.HMin == 0 && want.H > 0 && got.H != want.H {
+			return fmt.Errorf("image height %d, requested %d", got.H, want.H)
+		}
+	}
+	return nil
+}
+
+// validateEventTrackers checks returned trackers against the events and methods requested
+// A request without eventtrackers does not restrict them.
+func validateEventTrackers(got []EventTracker, want []EventTrackerRequest) error {
+	for _, tracker := range got {
+		if tracker.URL == "" {
+			return fmt.Errorf("event tracker for event %d missing url", tracker.Event)
+		}
+		if len(want) == 0 {
+			continue
+		}
+		if !trackerSupported(tracker, want) {
+			return fmt.Errorf("event tracker method %d for event %d was not requested", tracker.Method, tracker.Event)
+		}
+	}
+	return nil
+}
+
+func trackerSupported(tracker EventTracker, want []EventTrackerRequest) bool {
+	for _, w := range want {
+		if w.Event != tracker.Event {
+			continue
+		}
+		for _, method := range w.Methods {
+			if method == tracker.Method {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package native
+
+import (
+	"strings"
+	"testing"
+)
+
+const testResponse = `{"ver":"1.2","link":{"url":"https://adv.example.com/landing","clicktrackers":["https://t.example.com/click"]},
+"assets":[
+	{"id":1,"title":{"text":"Summer Sale"}},
+	{"id":2,"img":{"type":3,"url":"https://cdn.example.com/main.jpg","w":600,"h":300}},
+	{"id":3,"img":{"url":"https://cdn.example.com/icon.png","w":50,"h":50}},
+	{"id":4,"data":{"value":"Everything must go"}},
+	{"id":5,"data":{"type":12,"value":"Shop now"}}
+],
+"eventtrackers":[{"event":1,"method":1,"url":"https://t.example.com/imp"},{"event":1,"method":2,"url":"https://t.example.com/omid.js"}]}`
+
+func TestResponse_Validate(t *testing.T) {
+	req, err := ParseRequest(testRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := ParseResponse(testResponse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resp.Validate(req); err != nil {
+		t.Errorf("expected valid response, got %v", err)
+	}
+}
+
+func TestResponse_Validate_Errors(t *testing.T) {
+	req, _ := ParseRequest(testRequest)
+
+	tests := []struct {
+		name   string
+		modify func(r *Response)
+		want   string
+	}{
+		{"missing link", func(r *Response) { r.Link.URL = "" }, "missing link url"},
+		{"unrequested asset", func(r *Response) { r.Assets = append(r.Assets, AssetResponse{ID: 9, Title: &TitleResponse{Text: "x"}}) }, "asset 9 was not requested"},
+		{"missing required asset", func(r *Response) { r.Assets = r.Assets[1:] }, "missing required asset 1"},
+		{"title too long", func(r *Response) { r.Assets[0].Title.Text = strings.Repeat("a", 26) }, "title longer than 25"},
+		{"wrong kind", func(r *Response) { r.Assets[0] = AssetResponse{ID: 1, Data: &DataResponse{Value: "x"}} }, "expected title"},
+		{"image below minimum", func(r *Response) { r.Assets[1].Img.W = 200 }, "below minimum 300"},
+		{"image wrong exact size", func(r *Response) { r.Assets[2].Img.H = 60 }, "image height 60, requested 50"},
+		{"image without url", func(r *Response) { r.Assets[1].Img.URL = "" }, "image missing url"},
+		{"data wrong type", func(r *Response) { r.Assets[4].Data.Type = 1 }, "data type 1, requested 12"},
+		{"empty data", func(r *Response) { r.Assets[3].Data.Value = "" }, "empty data value"},
+		{"unrequested tracker event", func(r *Response) {
+			r.EventTrackers = append(r.EventTrackers, EventTracker{Event: EventViewableMRC50, Method: TrackingMethodImage, URL: "https://t.example.com/v"})
+		}, "method 1 for event 2 was not requested"},
+		{"tracker without url", func(r *Response) { r.EventTrackers[0].URL = "" }, "missing url"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := ParseResponse(testResponse)
+			tt.modify(resp)
+			if err := resp.Validate(req); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestResponse_Validate_AssetsURL(t *testing.T) {
+	req, _ := ParseRequest(testRequest)
+	resp := &Response{AssetsURL: "https://dsp.example.com/assets/1"}
+	if err := resp.Validate(req); err != nil {
+		t.Errorf("expected assets fetched from assetsurl to be accepted, got %v", err)
+	}
+}
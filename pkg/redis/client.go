@@ -126,6 +126,18 @@ func (c *Client) SMembers(ctx context.Context, key string) ([]string, error) {
 	return c.client.SMembers(ctx, key).Result()
 }
 
+// IncrBy adds delta to a counter and returns the new value
+// The key expires after ttl, which is refreshed on each call.
+func (c *Client) IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	pipe := c.client.TxPipeline()
+	incr := pipe.IncrBy(ctx, key, delta)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
 // Ping tests the connection
 func (c *Client) Ping(ctx context.Context) error {
 	return c.client.Ping(ctx).Err()
@@ -597,3 +597,31 @@ func TestClient_MultipleOperations(t *testing.T) {
 		t.Errorf("Expected 2 fields after delete, got %d", len(all))
 	}
 }
+
+func TestClient_IncrBy(t *testing.T) {
+	mr, redisURL := setupTestRedis(t)
+	defer mr.Close()
+
+	client, err := New(redisURL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	for want := int64(1); want <= 3; want++ {
+		got, err := client.IncrBy(ctx, "counter", 1, time.Hour)
+		if err != nil {
+			t.Fatalf("IncrBy failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("Expected %d, got %d", want, got)
+		}
+	}
+	if got, err := client.IncrBy(ctx, "counter", -1, time.Hour); err != nil || got != 2 {
+		t.Errorf("Expected 2 after decrement, got %d (%v)", got, err)
+	}
+	if ttl := mr.TTL("counter"); ttl != time.Hour {
+		t.Errorf("Expected 1h TTL, got %v", ttl)
+	}
+}
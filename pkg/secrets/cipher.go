@@ -0,0 +1,91 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the size of a master key (AES-256)
+const KeySize = 32
+
+// Cipher encrypts secret values with a local master key (AES-256-GCM)
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a cipher from a 32-byte master key
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// ParseKey decodes a base64 (standard or web-safe) master key
+func ParseKey(s string) ([]byte, error) {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if key, err := enc.DecodeString(s); err == nil {
+			if len(key) != KeySize {
+				return nil, fmt.Errorf("master key must be %d bytes, got %d", KeySize, len(key))
+			}
+			return key, nil
+		}
+	}
+	return nil, errors.New("master key is not valid base64")
+}
+
+// Seal encrypts a secret value; the random nonce is prepended to the ciphertext
+// name is authenticated with the value, so a ciphertext cannot be moved to another secret.
+func (c *Cipher) Seal(name, value string) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, []byte(value), []byte(name)), nil
+}
+
+// Open decrypts a value sealed for the named secret
+func (c *Cipher) Open(name string, ciphertext []byte) (string, error) {
+	size := c.aead.NonceSize()
+	if len(ciphertext) < size {
+		return "", errors.New("ciphertext too short")
+	}
+	plaintext, err := c.aead.Open(nil, ciphertext[:size], ciphertext[size:], []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret %s: %w", name, err)
+	}
+	return string(plaintext), nil
+}
+
+// CiphertextStore loads encrypted secret values (e.g. *storage.SecretStore)
+type CiphertextStore interface {
+	GetSecret(ctx context.Context, name string) ([]byte, error)
+}
+
+// EncryptedProvider reads secrets stored encrypted with a local master key
+type EncryptedProvider struct {
+	Store  CiphertextStore
+	Cipher *Cipher
+}
+
+// Get loads and decrypts a secret
+func (p *EncryptedProvider) Get(ctx context.Context, name string) (string, error) {
+	ciphertext, err := p.Store.GetSecret(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return p.Cipher.Open(name, ciphertext)
+}
@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+)
+
+type memoryStore map[string][]byte
+
+func (m memoryStore) GetSecret(_ context.Context, name string) ([]byte, error) {
+	ciphertext, ok := m[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return ciphertext, nil
+}
+
+func testCipher(t *testing.T) *Cipher {
+	t.Helper()
+	c, err := NewCipher(bytes.Repeat([]byte{7}, KeySize))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestCipher_SealOpen(t *testing.T) {
+	c := testCipher(t)
+
+	sealed, err := c.Seal("dsp.token", "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bytes.Contains(sealed, []byte("abc123")) {
+		t.Error("expected ciphertext not to contain the plaintext")
+	}
+
+	got, err := c.Open("dsp.token", sealed)
+	if err != nil || got != "abc123" {
+		t.Errorf("expected abc123, got %q (%v)", got, err)
+	}
+
+	if _, err := c.Open("other.token", sealed); err == nil {
+		t.Error("expected ciphertext of another secret to be rejected")
+	}
+	sealed[len(sealed)-1] ^= 1
+	if _, err := c.Open("dsp.token", sealed); err == nil {
+		t.Error("expected tampered ciphertext to be rejected")
+	}
+	if _, err := c.Open("dsp.token", []byte{1, 2}); err == nil {
+		t.Error("expected short ciphertext to be rejected")
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	key := bytes.Repeat([]byte{0xfb}, KeySize)
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding} {
+		got, err := ParseKey(enc.EncodeToString(key))
+		if err != nil || !bytes.Equal(got, key) {
+			t.Errorf("expected key decoded, got %v (%v)", got, err)
+		}
+	}
+	if _, err := ParseKey(base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+		t.Error("expected short key to be rejected")
+	}
+	if _, err := ParseKey("not base64!"); err == nil {
+		t.Error("expected invalid base64 to be rejected")
+	}
+	if _, err := NewCipher([]byte("short")); err == nil {
+		t.Error("expected NewCipher to reject a short key")
+	}
+}
+
+func TestEncryptedProvider(t *testing.T) {
+	c := testCipher(t)
+	sealed, _ := c.Seal("dsp.token", "abc123")
+	p := &EncryptedProvider{Store: memoryStore{"dsp.token": sealed}, Cipher: c}
+
+	got, err := p.Get(context.Background(), "dsp.token")
+	if err != nil || got != "abc123" {
+		t.Errorf("expected abc123, got %q (%v)", got, err)
+	}
+	if _, err := p.Get(context.Background(), "missing"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
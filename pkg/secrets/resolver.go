@@ -0,0 +1,83 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// DefaultTTL is how long a resolved secret is cached before it is looked up again
+const DefaultTTL = 5 * time.Minute
+
+type cachedSecret struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// Resolver resolves secret references through a Provider and caches the values
+// Cached values expire after the TTL, so rotated credentials are picked up without a
+// restart. When a lookup fails after the TTL, the last known value is kept.
+type Resolver struct {
+	provider Provider
+	ttl      time.Duration
+
+	mu    sync.RWMutex
+	cache map[string]cachedSecret
+}
+
+// NewResolver creates a resolver; ttl <= 0 uses DefaultTTL
+func NewResolver(provider Provider, ttl time.Duration) *Resolver {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Resolver{
+		provider: provider,
+		ttl:      ttl,
+		cache:    make(map[string]cachedSecret),
+	}
+}
+
+// Resolve returns the secret a reference points to; other values are returned unchanged
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	name, err := ParseReference(value)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.RLock()
+	cached, ok := r.cache[name]
+	r.mu.RUnlock()
+	if ok && time.Since(cached.fetchedAt) < r.ttl {
+		return cached.value, nil
+	}
+
+	secret, err := r.provider.Get(ctx, name)
+	if err != nil {
+		if ok {
+			logger.Log.Warn().
+				Err(err).
+				Str("secret", name).
+				Msg("Failed to refresh secret, using cached value")
+			return cached.value, nil
+		}
+		return "", fmt.Errorf("failed to resolve secret %s: %w", name, err)
+	}
+
+	r.mu.Lock()
+	r.cache[name] = cachedSecret{value: secret, fetchedAt: time.Now()}
+	r.mu.Unlock()
+	return secret, nil
+}
+
+// Refresh drops all cached values, so the next lookups read the provider (e.g. after a rotation)
+func (r *Resolver) Refresh() {
+	r.mu.Lock()
+	r.cache = make(map[string]cachedSecret)
+	r.mu.Unlock()
+}
@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingProvider returns its current value and counts lookups
+type countingProvider struct {
+	value string
+	err   error
+	calls int
+}
+
+func (p *countingProvider) Get(_ context.Context, _ string) (string, error) {
+	p.calls++
+	return p.value, p.err
+}
+
+func TestResolver_PlaintextPassthrough(t *testing.T) {
+	p := &countingProvider{value: "abc123"}
+	r := NewResolver(p, time.Minute)
+
+	got, err := r.Resolve(context.Background(), "plain-token")
+	if err != nil || got != "plain-token" {
+		t.Errorf("expected plaintext unchanged, got %q (%v)", got, err)
+	}
+	if p.calls != 0 {
+		t.Errorf("expected no provider lookups, got %d", p.calls)
+	}
+}
+
+func TestResolver_CachesUntilTTL(t *testing.T) {
+	p := &countingProvider{value: "abc123"}
+	r := NewResolver(p, time.Hour)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if got, err := r.Resolve(ctx, "secret://dsp.token"); err != nil || got != "abc123" {
+			t.Fatalf("expected abc123, got %q (%v)", got, err)
+		}
+	}
+	if p.calls != 1 {
+		t.Errorf("expected one provider lookup, got %d", p.calls)
+	}
+
+	// Rotation is picked up after a refresh
+	p.value = "def456"
+	r.Refresh()
+	if got, _ := r.Resolve(ctx, "secret://dsp.token"); got != "def456" {
+		t.Errorf("expected rotated value, got %q", got)
+	}
+}
+
+func TestResolver_ExpiredValueRefetched(t *testing.T) {
+	p := &countingProvider{value: "abc123"}
+	r := NewResolver(p, time.Millisecond)
+	ctx := context.Background()
+
+	r.Resolve(ctx, "secret://dsp.token")
+	time.Sleep(5 * time.Millisecond)
+	p.value = "def456"
+	if got, _ := r.Resolve(ctx, "secret://dsp.token"); got != "def456" {
+		t.Errorf("expected rotated value after TTL, got %q", got)
+	}
+
+	// A failed refresh keeps the last known value
+	time.Sleep(5 * time.Millisecond)
+	p.err = errors.New("provider down")
+	if got, err := r.Resolve(ctx, "secret://dsp.token"); err != nil || got != "def456" {
+		t.Errorf("expected stale value on refresh failure, got %q (%v)", got, err)
+	}
+}
+
+func TestResolver_Errors(t *testing.T) {
+	r := NewResolver(&countingProvider{err: ErrNotFound}, 0)
+
+	if _, err := r.Resolve(context.Background(), "secret://missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := r.Resolve(context.Background(), "secret://../x"); err == nil {
+		t.Error("expected invalid reference to be rejected")
+	}
+}
@@ -0,0 +1,133 @@
+// Package secrets resolves secret references (secret://name) in configuration
+// Credentials such as bidder auth tokens are stored as references and resolved from a
+// Provider when used, so plaintext values stay out of config files, the database and logs.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Scheme prefixes secret references
+const Scheme = "secret://"
+
+// Redacted replaces secret values in admin responses and logs
+const Redacted = "[REDACTED]"
+
+// ErrNotFound is returned by providers for unknown secrets
+var ErrNotFound = errors.New("secret not found")
+
+// Provider looks up secret values by name
+type Provider interface {
+	Get(ctx context.Context, name string) (string, error)
+}
+
+// IsReference reports whether a config value is a secret reference
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, Scheme)
+}
+
+// ParseReference returns the secret name of a reference
+// Names are letters, digits, '_', '-' and '.', and may not start with '.'.
+func ParseReference(value string) (string, error) {
+	if !IsReference(value) {
+		return "", fmt.Errorf("not a secret reference")
+	}
+	name := strings.TrimPrefix(value, Scheme)
+	if !validName(name) {
+		return "", fmt.Errorf("invalid secret name %q", name)
+	}
+	return name, nil
+}
+
+func validName(name string) bool {
+	if name == "" || name[0] == '.' {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Redact hides a plaintext secret value; empty values and references are shown as is
+func Redact(value string) string {
+	if value == "" || IsReference(value) {
+		return value
+	}
+	return Redacted
+}
+
+// IsSensitiveName reports whether a header or field name usually carries a credential
+func IsSensitiveName(name string) bool {
+	name = strings.ToLower(name)
+	switch name {
+	case "authorization", "proxy-authorization", "cookie":
+		return true
+	}
+	for _, word := range []string{"token", "secret", "password", "auth", "key"} {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// EnvProvider reads secrets from environment variables
+// The secret "dsp.token" is read from <Prefix>DSP_TOKEN: the name is upper-cased and
+// characters other than letters and digits become '_'.
+type EnvProvider struct {
+	Prefix string // e.g. "SECRET_"
+}
+
+// Get returns the value of the secret's environment variable
+func (p *EnvProvider) Get(_ context.Context, name string) (string, error) {
+	key := p.Prefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// FileProvider reads secrets from files in a directory, e.g. secrets mounted by the orchestrator
+// Files are read on every lookup, so a rotated mount is picked up without a restart. A single
+// trailing newline is removed.
+type FileProvider struct {
+	Dir string
+}
+
+// Get returns the contents of the secret's file
+func (p *FileProvider) Get(_ context.Context, name string) (string, error) {
+	if !validName(name) {
+		return "", fmt.Errorf("invalid secret name %q", name)
+	}
+	data, err := os.ReadFile(filepath.Join(p.Dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %s: %w", name, err)
+	}
+	value := strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	if value == "" {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
@@ -0,0 +1,101 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"secret://dsp.token", "dsp.token", false},
+		{"secret://DSP_KEY-2", "DSP_KEY-2", false},
+		{"plain-token", "", true},
+		{"secret://", "", true},
+		{"secret://../etc/passwd", "", true},
+		{"secret://a/b", "", true},
+		{"secret://.hidden", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseReference(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: expected error %v, got %v", tt.value, tt.wantErr, err)
+		}
+		if got != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.value, tt.want, got)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	if got := Redact("hunter2"); got != Redacted {
+		t.Errorf("expected plaintext redacted, got %q", got)
+	}
+	if got := Redact("secret://dsp.token"); got != "secret://dsp.token" {
+		t.Errorf("expected reference kept, got %q", got)
+	}
+	if got := Redact(""); got != "" {
+		t.Errorf("expected empty value kept, got %q", got)
+	}
+}
+
+func TestIsSensitiveName(t *testing.T) {
+	for _, name := range []string{"Authorization", "X-Api-Key", "x-auth-token", "Cookie", "X-Client-Secret"} {
+		if !IsSensitiveName(name) {
+			t.Errorf("expected %s to be sensitive", name)
+		}
+	}
+	for _, name := range []string{"X-OpenRTB-Version", "Content-Type", "X-Region"} {
+		if IsSensitiveName(name) {
+			t.Errorf("expected %s not to be sensitive", name)
+		}
+	}
+}
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("SECRET_DSP_TOKEN", "abc123")
+	p := &EnvProvider{Prefix: "SECRET_"}
+
+	got, err := p.Get(context.Background(), "dsp.token")
+	if err != nil || got != "abc123" {
+		t.Errorf("expected abc123, got %q (%v)", got, err)
+	}
+	if _, err := p.Get(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dsp.token")
+	if err := os.WriteFile(path, []byte("abc123\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p := &FileProvider{Dir: dir}
+
+	got, err := p.Get(context.Background(), "dsp.token")
+	if err != nil || got != "abc123" {
+		t.Errorf("expected abc123 without the trailing newline, got %q (%v)", got, err)
+	}
+
+	// Rotated mounts are read again
+	if err := os.WriteFile(path, []byte("def456"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := p.Get(context.Background(), "dsp.token"); got != "def456" {
+		t.Errorf("expected rotated value, got %q", got)
+	}
+
+	if _, err := p.Get(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := p.Get(context.Background(), "../dsp.token"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("expected invalid name error, got %v", err)
+	}
+}
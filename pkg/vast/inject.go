@@ -0,0 +1,180 @@
+package vast
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Trackers are URLs to add to every ad of a VAST document
+type Trackers struct {
+	Impressions []string            // Added as Impression elements of each InLine and Wrapper
+	Events      map[string][]string // Linear tracking event (start, midpoint, complete, ...) -> URLs
+}
+
+// insertion is markup to insert at a byte offset of the original document
+type insertion struct {
+	offset int64
+	markup string
+}
+
+// adPosition records where impressions go in one InLine or Wrapper
+type adPosition struct {
+	lastImpressionEnd int64 // after the last Impression, -1 if none
+	creativesStart    int64 // before Creatives, -1 if none
+}
+
+// linearPosition records where tracking events go in one Linear
+type linearPosition struct {
+	trackingEventsEnd int64 // before </TrackingEvents>, -1 if none
+}
+
+// InjectTrackers adds impression and linear tracking URLs to every ad in the document
+// Impressions follow the ad's existing impressions (or precede its creatives); tracking events
+// are appended to each linear creative's TrackingEvents, which is created when missing.
+func InjectTrackers(adm string, t Trackers) (string, error) {
+	if len(t.Impressions) == 0 && len(t.Events) == 0 {
+		return adm, nil
+	}
+
+	impressions := impressionMarkup(t.Impressions)
+	tracking := trackingMarkup(t.Events)
+
+	dec := xml.NewDecoder(strings.NewReader(adm))
+	var (
+		stack      []string
+		ads        []*adPosition
+		linears    []*linearPosition
+		inserts    []insertion
+		sawRoot    bool
+		injectedAd bool
+	)
+	parent := func() string {
+		if len(stack) == 0 {
+			return ""
+		}
+		return stack[len(stack)-1]
+	}
+
+	for {
+		start := dec.InputOffset()
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return adm, fmt.Errorf("invalid VAST XML: %w", err)
+		}
+
+		switch el := tok.(type) {
+		case xml.StartElement:
+			name := el.Name.Local
+			if !sawRoot {
+				if name != "VAST" {
+					return adm, fmt.Errorf("invalid VAST XML: root element <%s>", name)
+				}
+				sawRoot = true
+			}
+			switch {
+			case (name == "InLine" || name == "Wrapper") && parent() == "Ad":
+				ads = append(ads, &adPosition{lastImpressionEnd: -1, creativesStart: -1})
+			case name == "Creatives" && len(ads) > 0 && (parent() == "InLine" || parent() == "Wrapper"):
+				if ad := ads[len(ads)-1]; ad.creativesStart < 0 {
+					ad.creativesStart = start
+				}
+			case name == "Linear" && parent() == "Creative":
+				linears = append(linears, &linearPosition{trackingEventsEnd: -1})
+			}
+			stack = append(stack, name)
+
+		case xml.EndElement:
+			name := el.Name.Local
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			switch {
+			case name == "Impression" && len(ads) > 0 && (parent() == "InLine" || parent() == "Wrapper"):
+				ads[len(ads)-1].lastImpressionEnd = dec.InputOffset()
+			case (name == "InLine" || name == "Wrapper") && parent() == "Ad":
+				ad := ads[len(ads)-1]
+				if impressions != "" {
+					offset := start
+					switch {
+					case ad.lastImpressionEnd >= 0:
+						offset = ad.lastImpressionEnd
+					case ad.creativesStart >= 0:
+						offset = ad.creativesStart
+					}
+					inserts = append(inserts, insertion{offset, impressions})
+				}
+				injectedAd = true
+			case name == "TrackingEvents" && parent() == "Linear" && len(linears) > 0:
+				linears[len(linears)-1].trackingEventsEnd = start
+			case name == "Linear" && parent() == "Creative" && len(linears) > 0:
+				linear := linears[len(linears)-1]
+				if tracking != "" {
+					if linear.trackingEventsEnd >= 0 {
+						inserts = append(inserts, insertion{linear.trackingEventsEnd, tracking})
+					} else {
+						inserts = append(inserts, insertion{start, "<TrackingEvents>" + tracking + "</TrackingEvents>"})
+					}
+				}
+			}
+		}
+	}
+
+	if !sawRoot {
+		return adm, errors.New("invalid VAST XML: no root element")
+	}
+	if !injectedAd {
+		return adm, errors.New("VAST has no ads")
+	}
+
+	// Insert from the end so earlier offsets stay valid
+	sort.SliceStable(inserts, func(i, j int) bool { return inserts[i].offset > inserts[j].offset })
+	out := adm
+	for _, ins := range inserts {
+		out = out[:ins.offset] + ins.markup + out[ins.offset:]
+	}
+	return out, nil
+}
+
+func impressionMarkup(urls []string) string {
+	var b strings.Builder
+	for _, u := range urls {
+		if u == "" {
+			continue
+		}
+		b.WriteString("<Impression>")
+		b.WriteString(cdata(u))
+		b.WriteString("</Impression>")
+	}
+	return b.String()
+}
+
+// trackingMarkup builds Tracking elements, ordered by event name for deterministic output
+func trackingMarkup(events map[string][]string) string {
+	names := make([]string, 0, len(events))
+	for name := range events {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		for _, u := range events[name] {
+			if u == "" {
+				continue
+			}
+			b.WriteString(`<Tracking event="`)
+			_ = xml.EscapeText(&b, []byte(name))
+			b.WriteString(`">`)
+			b.WriteString(cdata(u))
+			b.WriteString("</Tracking>")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,136 @@
+package vast
+
+import (
+	"strings"
+	"testing"
+)
+
+var testTrackers = Trackers{
+	Impressions: []string{"https://ex.example.com/imp?bid=${AUCTION_BID_ID}"},
+	Events: map[string][]string{
+		"start":    {"https://ex.example.com/ev?e=start"},
+		"complete": {"https://ex.example.com/ev?e=complete"},
+	},
+}
+
+func TestInjectTrackers_Inline(t *testing.T) {
+	out, err := InjectTrackers(inlineVAST, testTrackers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	v, err := Parse(out)
+	if err != nil {
+		t.Fatalf("injected VAST does not parse: %v", err)
+	}
+	if err := v.Validate(); err != nil {
+		t.Errorf("injected VAST is invalid: %v", err)
+	}
+	inline := v.Ads[0].InLine
+	if len(inline.Impressions) != 2 || inline.Impressions[1] != testTrackers.Impressions[0] {
+		t.Errorf("expected our impression after the bidder's, got %v", inline.Impressions)
+	}
+	tracking := inline.Creatives[0].Linear.Tracking
+	if len(tracking) != 3 || tracking[1].Event != "complete" || tracking[2].Event != "start" {
+		t.Errorf("expected our tracking events appended, got %+v", tracking)
+	}
+
+	// The original markup is kept as is
+	if !strings.Contains(out, `<Tracking event="start"><![CDATA[https://dsp.example.com/start]]></Tracking>`) ||
+		!strings.HasPrefix(out, `<?xml version="1.0" encoding="UTF-8"?>`) {
+		t.Errorf("expected original markup preserved:\n%s", out)
+	}
+}
+
+func TestInjectTrackers_WrapperWithoutTrackingEvents(t *testing.T) {
+	out, err := InjectTrackers(wrapperVAST, testTrackers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	v, err := Parse(out)
+	if err != nil {
+		t.Fatalf("injected VAST does not parse: %v", err)
+	}
+	wrapper := v.Ads[0].Wrapper
+	if len(wrapper.Impressions) != 1 {
+		t.Errorf("expected our impression, got %v", wrapper.Impressions)
+	}
+	// With no existing impression it goes before the creatives
+	if strings.Index(out, "<Impression>") > strings.Index(out, "<Creatives>") {
+		t.Errorf("expected impression before creatives:\n%s", out)
+	}
+	if tracking := wrapper.Creatives[0].Linear.Tracking; len(tracking) != 2 {
+		t.Errorf("expected TrackingEvents created, got %+v", tracking)
+	}
+}
+
+func TestInjectTrackers_WrapURL(t *testing.T) {
+	out, err := InjectTrackers(WrapURL("Nexus", "https://dsp.example.com/nurl"), testTrackers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, err := Parse(out)
+	if err != nil {
+		t.Fatalf("injected VAST does not parse: %v", err)
+	}
+	if wrapper := v.Ads[0].Wrapper; len(wrapper.Impressions) != 1 || len(wrapper.Creatives[0].Linear.Tracking) != 2 {
+		t.Errorf("expected impression and tracking in the nurl wrapper, got %+v", wrapper)
+	}
+}
+
+func TestInjectTrackers_MultipleAds(t *testing.T) {
+	pod := `<VAST version="3.0"><Ad sequence="1"><Wrapper><AdSystem>a</AdSystem><VASTAdTagURI>u1</VASTAdTagURI></Wrapper></Ad>` +
+		`<Ad sequence="2"><Wrapper><AdSystem>b</AdSystem><VASTAdTagURI>u2</VASTAdTagURI></Wrapper></Ad></VAST>`
+
+	out, err := InjectTrackers(pod, Trackers{Impressions: []string{"https://ex.example.com/imp"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, err := Parse(out)
+	if err != nil {
+		t.Fatalf("injected VAST does not parse: %v", err)
+	}
+	for i, ad := range v.Ads {
+		if len(ad.Wrapper.Impressions) != 1 {
+			t.Errorf("ad %d: expected our impression, got %v", i+1, ad.Wrapper.Impressions)
+		}
+	}
+}
+
+func TestInjectTrackers_IgnoresNestedMarkup(t *testing.T) {
+	// VAST inside CDATA (e.g. AdParameters) is not an element and must not be modified
+	adm := `<VAST version="3.0"><Ad><Wrapper><AdSystem>a</AdSystem><VASTAdTagURI>u</VASTAdTagURI>` +
+		`<Extensions><Extension><![CDATA[<Ad><Wrapper></Wrapper></Ad>]]></Extension></Extensions></Wrapper></Ad></VAST>`
+
+	out, err := InjectTrackers(adm, Trackers{Impressions: []string{"https://ex.example.com/imp"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Count(out, "<Impression>") != 1 {
+		t.Errorf("expected one impression, got:\n%s", out)
+	}
+}
+
+func TestInjectTrackers_Errors(t *testing.T) {
+	for name, adm := range map[string]string{
+		"not xml":  `https://dsp.example.com/vast`,
+		"html":     `<div>banner</div>`,
+		"no ads":   `<VAST version="3.0"></VAST>`,
+		"mismatch": `<VAST version="3.0"><Ad></Wrapper></VAST>`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			out, err := InjectTrackers(adm, testTrackers)
+			if err == nil {
+				t.Error("expected error")
+			}
+			if out != adm {
+				t.Error("expected markup unchanged on error")
+			}
+		})
+	}
+
+	if out, err := InjectTrackers(inlineVAST, Trackers{}); err != nil || out != inlineVAST {
+		t.Error("expected no trackers to be a no-op")
+	}
+}
@@ -0,0 +1,220 @@
+// Package vast parses, validates and modifies VAST 2.0-4.x video ad markup
+// Markup is modified in place at element boundaries found by the XML tokenizer, so the
+// bidder's original XML (CDATA, extensions, attributes) is preserved byte for byte.
+package vast
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VAST is the root of a VAST document
+type VAST struct {
+	XMLName xml.Name `xml:"VAST"`
+	Version string   `xml:"version,attr"`
+	Ads     []Ad     `xml:"Ad"`
+	Errors  []string `xml:"Error"`
+}
+
+// Ad is an inline ad or a wrapper pointing at another VAST document
+type Ad struct {
+	ID       string   `xml:"id,attr"`
+	Sequence string   `xml:"sequence,attr"`
+	InLine   *InLine  `xml:"InLine"`
+	Wrapper  *Wrapper `xml:"Wrapper"`
+}
+
+// InLine is an ad whose creatives are in the document
+type InLine struct {
+	AdSystem    string     `xml:"AdSystem"`
+	AdTitle     string     `xml:"AdTitle"`
+	Impressions []string   `xml:"Impression"`
+	Categories  []string   `xml:"Category"` // VAST 4
+	Creatives   []Creative `xml:"Creatives>Creative"`
+}
+
+// Wrapper is an ad that redirects to another VAST document
+type Wrapper struct {
+	AdSystem     string     `xml:"AdSystem"`
+	VASTAdTagURI string     `xml:"VASTAdTagURI"`
+	Impressions  []string   `xml:"Impression"`
+	Creatives    []Creative `xml:"Creatives>Creative"`
+}
+
+// Creative holds one of an ad's creatives
+type Creative struct {
+	ID     string  `xml:"id,attr"`
+	Linear *Linear `xml:"Linear"`
+}
+
+// Linear is a linear (in-stream) video creative
+type Linear struct {
+	Duration   string      `xml:"Duration"`
+	MediaFiles []MediaFile `xml:"MediaFiles>MediaFile"`
+	Tracking   []Tracking  `xml:"TrackingEvents>Tracking"`
+}
+
+// MediaFile is one rendition of a linear creative
+type MediaFile struct {
+	Delivery string `xml:"delivery,attr"`
+	Type     string `xml:"type,attr"`
+	Width    string `xml:"width,attr"`
+	Height   string `xml:"height,attr"`
+	URL      string `xml:",chardata"`
+}
+
+// Tracking is a linear tracking event URL
+type Tracking struct {
+	Event string `xml:"event,attr"`
+	URL   string `xml:",chardata"`
+}
+
+// Parse parses VAST markup
+func Parse(adm string) (*VAST, error) {
+	var v VAST
+	if err := xml.Unmarshal([]byte(adm), &v); err != nil {
+		return nil, fmt.Errorf("invalid VAST XML: %w", err)
+	}
+	return &v, nil
+}
+
+// Validate checks the document against the parts of VAST 2.0-4.x players rely on
+// Every ad must be an inline ad with an ad system, an impression and at least one creative,
+// or a wrapper with an ad system and a VASTAdTagURI. Linear creatives in inline ads need a
+// valid duration and at least one media file.
+func (v *VAST) Validate() error {
+	major, _, _ := strings.Cut(v.Version, ".")
+	if major != "2" && major != "3" && major != "4" {
+		return fmt.Errorf("unsupported VAST version %q", v.Version)
+	}
+	if len(v.Ads) == 0 {
+		return errors.New("VAST has no ads")
+	}
+
+	for i, ad := range v.Ads {
+		switch {
+		case ad.InLine != nil && ad.Wrapper != nil:
+			return fmt.Errorf("ad %d: both InLine and Wrapper", i+1)
+		case ad.InLine != nil:
+			if err := ad.InLine.validate(); err != nil {
+				return fmt.Errorf("ad %d: %w", i+1, err)
+			}
+		case ad.Wrapper != nil:
+			if strings.TrimSpace(ad.Wrapper.AdSystem) == "" {
+				return fmt.Errorf("ad %d: wrapper missing AdSystem", i+1)
+			}
+			if strings.TrimSpace(ad.Wrapper.VASTAdTagURI) == "" {
+				return fmt.Errorf("ad %d: wrapper missing VASTAdTagURI", i+1)
+			}
+		default:
+			return fmt.Errorf("ad %d: missing InLine or Wrapper", i+1)
+		}
+	}
+	return nil
+}
+
+func (in *InLine) validate() error {
+	if strings.TrimSpace(in.AdSystem) == "" {
+		return errors.New("inline missing AdSystem")
+	}
+	if len(in.Impressions) == 0 {
+		return errors.New("inline missing Impression")
+	}
+	if len(in.Creatives) == 0 {
+		return errors.New("inline has no creatives")
+	}
+	for j, creative := range in.Creatives {
+		if creative.Linear == nil {
+			continue
+		}
+		if _, err := ParseDuration(creative.Linear.Duration); err != nil {
+			return fmt.Errorf("creative %d: %w", j+1, err)
+		}
+		if len(creative.Linear.MediaFiles) == 0 {
+			return fmt.Errorf("creative %d: linear has no media files", j+1)
+		}
+	}
+	return nil
+}
+
+// Duration returns the duration of the first linear creative of the first inline ad
+// Wrappers do not carry a duration, so ok is false when the document only has wrappers.
+func (v *VAST) Duration() (d time.Duration, ok bool) {
+	for _, ad := range v.Ads {
+		if ad.InLine == nil {
+			continue
+		}
+		for _, creative := range ad.InLine.Creatives {
+			if creative.Linear == nil {
+				continue
+			}
+			if d, err := ParseDuration(creative.Linear.Duration); err == nil {
+				return d, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// ParseDuration parses a VAST duration (HH:MM:SS or HH:MM:SS.mmm)
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid VAST duration %q", s)
+	}
+
+	hours, err1 := strconv.Atoi(parts[0])
+	minutes, err2 := strconv.Atoi(parts[1])
+	seconds, millis, hasMillis := strings.Cut(parts[2], ".")
+	secs, err3 := strconv.Atoi(seconds)
+	if err1 != nil || err2 != nil || err3 != nil || hours < 0 || minutes < 0 || minutes > 59 || secs < 0 || secs > 59 {
+		return 0, fmt.Errorf("invalid VAST duration %q", s)
+	}
+
+	d := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(secs)*time.Second
+	if hasMillis {
+		ms, err := strconv.Atoi(millis)
+		if err != nil || ms < 0 || len(millis) > 3 {
+			return 0, fmt.Errorf("invalid VAST duration %q", s)
+		}
+		for i := len(millis); i < 3; i++ {
+			ms *= 10
+		}
+		d += time.Duration(ms) * time.Millisecond
+	}
+	return d, nil
+}
+
+// IsVAST reports whether markup looks like a VAST document rather than HTML or a URL
+func IsVAST(adm string) bool {
+	adm = strings.TrimSpace(adm)
+	if strings.HasPrefix(adm, "<?xml") {
+		if end := strings.Index(adm, "?>"); end >= 0 {
+			adm = strings.TrimSpace(adm[end+2:])
+		}
+	}
+	return strings.HasPrefix(adm, "<VAST")
+}
+
+// WrapURL returns a VAST 3.0 wrapper that loads the ad from adTagURI
+// Used for video bids that only return a nurl: the player fetches the markup from it. The
+// wrapper has an empty linear creative so tracking events can be added with InjectTrackers.
+func WrapURL(adSystem, adTagURI string) string {
+	var b strings.Builder
+	b.WriteString(`<VAST version="3.0"><Ad><Wrapper><AdSystem>`)
+	_ = xml.EscapeText(&b, []byte(adSystem))
+	b.WriteString(`</AdSystem><VASTAdTagURI>`)
+	b.WriteString(cdata(adTagURI))
+	b.WriteString(`</VASTAdTagURI><Creatives><Creative><Linear><TrackingEvents></TrackingEvents></Linear></Creative></Creatives></Wrapper></Ad></VAST>`)
+	return b.String()
+}
+
+// cdata wraps s in a CDATA section, splitting any "]]>" it contains
+func cdata(s string) string {
+	return "<![CDATA[" + strings.ReplaceAll(s, "]]>", "]]]]><![CDATA[>") + "]]>"
+}
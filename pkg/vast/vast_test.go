@@ -0,0 +1,169 @@
+package vast
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const inlineVAST = `<?xml version="1.0" encoding="UTF-8"?>
+<VAST version="3.0">
+  <Ad id="ad-1">
+    <InLine>
+      <AdSystem>DSP</AdSystem>
+      <AdTitle>Spring Sale</AdTitle>
+      <Impression><![CDATA[https://dsp.example.com/imp?id=1]]></Impression>
+      <Creatives>
+        <Creative id="c-1">
+          <Linear>
+            <Duration>00:00:15.500</Duration>
+            <TrackingEvents>
+              <Tracking event="start"><![CDATA[https://dsp.example.com/start]]></Tracking>
+            </TrackingEvents>
+            <MediaFiles>
+              <MediaFile delivery="progressive" type="video/mp4" width="640" height="360"><![CDATA[https://cdn.example.com/ad.mp4]]></MediaFile>
+            </MediaFiles>
+          </Linear>
+        </Creative>
+      </Creatives>
+    </InLine>
+  </Ad>
+</VAST>`
+
+const wrapperVAST = `<VAST version="4.2"><Ad id="w-1"><Wrapper><AdSystem>SSP</AdSystem><VASTAdTagURI><![CDATA[https://ssp.example.com/vast?id=9]]></VASTAdTagURI><Creatives><Creative><Linear></Linear></Creative></Creatives></Wrapper></Ad></VAST>`
+
+func TestParse_Inline(t *testing.T) {
+	v, err := Parse(inlineVAST)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.Version != "3.0" || len(v.Ads) != 1 || v.Ads[0].InLine == nil {
+		t.Fatalf("unexpected document: %+v", v)
+	}
+	inline := v.Ads[0].InLine
+	if inline.AdSystem != "DSP" || len(inline.Impressions) != 1 || inline.Impressions[0] != "https://dsp.example.com/imp?id=1" {
+		t.Errorf("unexpected inline: %+v", inline)
+	}
+	linear := inline.Creatives[0].Linear
+	if len(linear.MediaFiles) != 1 || strings.TrimSpace(linear.MediaFiles[0].URL) != "https://cdn.example.com/ad.mp4" {
+		t.Errorf("unexpected media files: %+v", linear.MediaFiles)
+	}
+	if len(linear.Tracking) != 1 || linear.Tracking[0].Event != "start" {
+		t.Errorf("unexpected tracking: %+v", linear.Tracking)
+	}
+	if err := v.Validate(); err != nil {
+		t.Errorf("expected valid VAST, got %v", err)
+	}
+	if d, ok := v.Duration(); !ok || d != 15500*time.Millisecond {
+		t.Errorf("expected 15.5s duration, got %v (%v)", d, ok)
+	}
+}
+
+func TestParse_Wrapper(t *testing.T) {
+	v, err := Parse(wrapperVAST)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.Ads[0].Wrapper == nil || v.Ads[0].Wrapper.VASTAdTagURI != "https://ssp.example.com/vast?id=9" {
+		t.Fatalf("unexpected wrapper: %+v", v.Ads[0])
+	}
+	if err := v.Validate(); err != nil {
+		t.Errorf("expected valid wrapper, got %v", err)
+	}
+	if _, ok := v.Duration(); ok {
+		t.Error("expected no duration for a wrapper")
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for name, adm := range map[string]string{
+		"html":      `<div>banner</div>`,
+		"truncated": `<VAST version="3.0"><Ad>`,
+		"empty":     ``,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse(adm); err == nil {
+				t.Error("expected parse error")
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		adm  string
+		want string
+	}{
+		{"unsupported version", `<VAST version="1.0"><Ad><Wrapper><AdSystem>x</AdSystem><VASTAdTagURI>u</VASTAdTagURI></Wrapper></Ad></VAST>`, "unsupported VAST version"},
+		{"no ads", `<VAST version="3.0"></VAST>`, "no ads"},
+		{"empty ad", `<VAST version="3.0"><Ad></Ad></VAST>`, "missing InLine or Wrapper"},
+		{"wrapper without uri", `<VAST version="3.0"><Ad><Wrapper><AdSystem>x</AdSystem></Wrapper></Ad></VAST>`, "missing VASTAdTagURI"},
+		{"inline without impression", `<VAST version="2.0"><Ad><InLine><AdSystem>x</AdSystem><Creatives><Creative/></Creatives></InLine></Ad></VAST>`, "missing Impression"},
+		{"inline without creatives", `<VAST version="2.0"><Ad><InLine><AdSystem>x</AdSystem><Impression>i</Impression></InLine></Ad></VAST>`, "no creatives"},
+		{"bad duration", `<VAST version="4.0"><Ad><InLine><AdSystem>x</AdSystem><Impression>i</Impression><Creatives><Creative><Linear><Duration>15s</Duration><MediaFiles><MediaFile>m</MediaFile></MediaFiles></Linear></Creative></Creatives></InLine></Ad></VAST>`, "invalid VAST duration"},
+		{"no media files", `<VAST version="4.0"><Ad><InLine><AdSystem>x</AdSystem><Impression>i</Impression><Creatives><Creative><Linear><Duration>00:00:30</Duration></Linear></Creative></Creatives></InLine></Ad></VAST>`, "no media files"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := Parse(tt.adm)
+			if err != nil {
+				t.Fatalf("unexpected parse error: %v", err)
+			}
+			if err := v.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"00:00:30", 30 * time.Second, true},
+		{"01:02:03", time.Hour + 2*time.Minute + 3*time.Second, true},
+		{" 00:00:15.5 ", 15500 * time.Millisecond, true},
+		{"00:00:15.250", 15250 * time.Millisecond, true},
+		{"00:00:60", 0, false},
+		{"00:30", 0, false},
+		{"00:00:15.5000", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v, ok=%v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIsVAST(t *testing.T) {
+	if !IsVAST(inlineVAST) || !IsVAST(wrapperVAST) {
+		t.Error("expected VAST documents to be recognised")
+	}
+	if IsVAST(`<div>banner</div>`) || IsVAST(`https://ssp.example.com/vast`) {
+		t.Error("expected non-VAST markup to be rejected")
+	}
+}
+
+func TestWrapURL(t *testing.T) {
+	adm := WrapURL("Nexus & Co", "https://dsp.example.com/win?p=${AUCTION_PRICE}&x=]]>")
+
+	v, err := Parse(adm)
+	if err != nil {
+		t.Fatalf("wrapper does not parse: %v", err)
+	}
+	if err := v.Validate(); err != nil {
+		t.Errorf("expected valid wrapper, got %v", err)
+	}
+	wrapper := v.Ads[0].Wrapper
+	if wrapper.AdSystem != "Nexus & Co" {
+		t.Errorf("expected escaped ad system, got %q", wrapper.AdSystem)
+	}
+	if wrapper.VASTAdTagURI != "https://dsp.example.com/win?p=${AUCTION_PRICE}&x=]]>" {
+		t.Errorf("expected nurl as VASTAdTagURI, got %q", wrapper.VASTAdTagURI)
+	}
+}